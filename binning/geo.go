@@ -2,6 +2,8 @@ package binning
 
 import (
 	"math"
+
+	"github.com/unchartedsoftware/veldt/geometry"
 )
 
 const (
@@ -11,6 +13,9 @@ const (
 	maxLat           = 85.0511287798066
 	degreesToRadians = math.Pi / 180.0 // Factor for changing degrees to radians
 	radiansToDegrees = 180.0 / math.Pi // Factor for changing radians to degrees
+	// MercatorExtent is the half width of the world in web mercator (EPSG:3857)
+	// meters.
+	MercatorExtent = 20037508.342789244
 )
 
 // LonLat represents a geographic point.
@@ -39,3 +44,47 @@ func LonLatToFractionalTile(lonLat *LonLat, level uint32) *FractionalTileCoord {
 		Z: level,
 	}
 }
+
+// FractionalTileToLonLat converts a floating point tile coordinate into a geographic coordinate.
+func FractionalTileToLonLat(tile *FractionalTileCoord) *LonLat {
+	pow2 := math.Pow(2, float64(tile.Z))
+	lon := tile.X/pow2*(maxLon*2) - maxLon
+	n := math.Pi * (2*tile.Y/pow2 - 1)
+	lat := math.Atan(math.Sinh(n)) * radiansToDegrees
+	return &LonLat{
+		Lon: lon,
+		Lat: lat,
+	}
+}
+
+// GetTileLonLatBounds returns the geographic bounds of the tile coordinate.
+func GetTileLonLatBounds(tile *TileCoord) *geometry.Bounds {
+	bottomLeft := FractionalTileToLonLat(&FractionalTileCoord{
+		X: float64(tile.X),
+		Y: float64(tile.Y),
+		Z: tile.Z,
+	})
+	topRight := FractionalTileToLonLat(&FractionalTileCoord{
+		X: float64(tile.X + 1),
+		Y: float64(tile.Y + 1),
+		Z: tile.Z,
+	})
+	return geometry.NewBounds(
+		bottomLeft.Lon,
+		topRight.Lon,
+		bottomLeft.Lat,
+		topRight.Lat)
+}
+
+// GetTileMercatorBounds returns the web mercator (EPSG:3857) bounds of the
+// tile coordinate in meters.
+func GetTileMercatorBounds(tile *TileCoord) *geometry.Bounds {
+	size := MercatorExtent * 2 / math.Pow(2, float64(tile.Z))
+	left := -MercatorExtent + float64(tile.X)*size
+	bottom := -MercatorExtent + float64(tile.Y)*size
+	return geometry.NewBounds(
+		left,
+		left+size,
+		bottom,
+		bottom+size)
+}
@@ -44,4 +44,47 @@ var _ = Describe("geo", func() {
 		})
 	})
 
+	Describe("FractionalTileToLonLat", func() {
+		It("should return a geographic coordinate", func() {
+			lonLat := binning.FractionalTileToLonLat(&binning.FractionalTileCoord{X: 0, Y: 0, Z: 0})
+			Expect(lonLat.Lon).To(BeNumerically("~", bottomLeft.Lon, epsilon))
+			Expect(lonLat.Lat).To(BeNumerically("~", bottomLeft.Lat, epsilon))
+
+			lonLat = binning.FractionalTileToLonLat(&binning.FractionalTileCoord{X: 1, Y: 1, Z: 1})
+			Expect(lonLat.Lon).To(BeNumerically("~", center.Lon, epsilon))
+			Expect(lonLat.Lat).To(BeNumerically("~", center.Lat, epsilon))
+
+			lonLat = binning.FractionalTileToLonLat(&binning.FractionalTileCoord{X: 2, Y: 2, Z: 1})
+			Expect(lonLat.Lon).To(BeNumerically("~", topRight.Lon, epsilon))
+			Expect(lonLat.Lat).To(BeNumerically("~", topRight.Lat, epsilon))
+		})
+		It("should invert LonLatToFractionalTile", func() {
+			lonLat := binning.NewLonLat(-79.38, 43.65)
+			tile := binning.LonLatToFractionalTile(lonLat, 12)
+			inverse := binning.FractionalTileToLonLat(tile)
+			Expect(inverse.Lon).To(BeNumerically("~", lonLat.Lon, epsilon))
+			Expect(inverse.Lat).To(BeNumerically("~", lonLat.Lat, epsilon))
+		})
+	})
+
+	Describe("GetTileLonLatBounds", func() {
+		It("should return the geographic bounds of a tile", func() {
+			bounds := binning.GetTileLonLatBounds(&binning.TileCoord{X: 1, Y: 1, Z: 1})
+			Expect(bounds.Left).To(BeNumerically("~", 0.0, epsilon))
+			Expect(bounds.Right).To(BeNumerically("~", 180.0, epsilon))
+			Expect(bounds.Bottom).To(BeNumerically("~", 0.0, epsilon))
+			Expect(bounds.Top).To(BeNumerically("~", topRight.Lat, epsilon))
+		})
+	})
+
+	Describe("GetTileMercatorBounds", func() {
+		It("should return the web mercator bounds of a tile", func() {
+			bounds := binning.GetTileMercatorBounds(&binning.TileCoord{X: 0, Y: 1, Z: 1})
+			Expect(bounds.Left).To(BeNumerically("~", -binning.MercatorExtent, epsilon))
+			Expect(bounds.Right).To(BeNumerically("~", 0.0, epsilon))
+			Expect(bounds.Bottom).To(BeNumerically("~", 0.0, epsilon))
+			Expect(bounds.Top).To(BeNumerically("~", binning.MercatorExtent, epsilon))
+		})
+	})
+
 })
@@ -0,0 +1,59 @@
+package binning
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+// DecodeGeohash returns the center of the cell represented by the provided
+// geohash.
+func DecodeGeohash(hash string) (*LonLat, error) {
+	if len(hash) == 0 {
+		return nil, fmt.Errorf("cannot decode empty geohash")
+	}
+	minLon, maxLon := -180.0, 180.0
+	minLat, maxLat := -90.0, 90.0
+	even := true
+	for _, c := range strings.ToLower(hash) {
+		index := strings.IndexRune(geohashAlphabet, c)
+		if index == -1 {
+			return nil, fmt.Errorf("geohash `%s` contains invalid character `%c`", hash, c)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := index&(1<<uint(bit)) != 0
+			if even {
+				mid := (minLon + maxLon) / 2
+				if set {
+					minLon = mid
+				} else {
+					maxLon = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if set {
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return &LonLat{
+		Lon: (minLon + maxLon) / 2,
+		Lat: (minLat + maxLat) / 2,
+	}, nil
+}
+
+// GeohashCellSize returns the width and height in degrees of a geohash cell
+// for the provided precision.
+func GeohashCellSize(precision int) (float64, float64) {
+	bits := uint(precision * 5)
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 360.0 / float64(uint64(1)<<lonBits), 180.0 / float64(uint64(1)<<latBits)
+}
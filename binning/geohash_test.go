@@ -0,0 +1,48 @@
+package binning_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+var _ = Describe("geohash", func() {
+
+	const (
+		epsilon = 0.0001
+	)
+
+	Describe("DecodeGeohash", func() {
+		It("should return the center of the geohash cell", func() {
+			lonLat, err := binning.DecodeGeohash("dpz83d")
+			Expect(err).To(BeNil())
+			Expect(lonLat.Lon).To(BeNumerically("~", -79.3817, 0.01))
+			Expect(lonLat.Lat).To(BeNumerically("~", 43.6512, 0.01))
+
+			lonLat, err = binning.DecodeGeohash("s")
+			Expect(err).To(BeNil())
+			Expect(lonLat.Lon).To(BeNumerically("~", 22.5, epsilon))
+			Expect(lonLat.Lat).To(BeNumerically("~", 22.5, epsilon))
+		})
+		It("should return an error for invalid geohashes", func() {
+			_, err := binning.DecodeGeohash("")
+			Expect(err).NotTo(BeNil())
+			_, err = binning.DecodeGeohash("abc")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GeohashCellSize", func() {
+		It("should return the cell dimensions in degrees", func() {
+			width, height := binning.GeohashCellSize(1)
+			Expect(width).To(BeNumerically("~", 45.0, epsilon))
+			Expect(height).To(BeNumerically("~", 45.0, epsilon))
+
+			width, height = binning.GeohashCellSize(2)
+			Expect(width).To(BeNumerically("~", 11.25, epsilon))
+			Expect(height).To(BeNumerically("~", 5.625, epsilon))
+		})
+	})
+
+})
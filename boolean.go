@@ -58,6 +58,52 @@ func (b *BinaryExpression) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// getBinary returns the binary expression, such that it is accessible through
+// the types embedding it.
+func (b *BinaryExpression) getBinary() *BinaryExpression {
+	return b
+}
+
+// NaryExpression represents a boolean expression joining any number of
+// queries with the same associative operator.
+type NaryExpression struct {
+	Op      string
+	Queries []Query
+}
+
+// Parse should parse through the provided JSON and populate the struct fields.
+func (n *NaryExpression) Parse(params map[string]interface{}) error {
+	// op
+	op, ok := json.GetString(params, "op")
+	if !ok {
+		return fmt.Errorf("`op` parameter missing from query")
+	}
+	if op != And && op != Or {
+		return fmt.Errorf("`op` parameter value is not recognized")
+	}
+	n.Op = op
+	// queries
+	q, ok := params["queries"]
+	if !ok {
+		return fmt.Errorf("`queries` parameter missing from query")
+	}
+	queries, ok := q.([]Query)
+	if !ok {
+		return fmt.Errorf("`queries` is not an array of query types")
+	}
+	if len(queries) < 2 {
+		return fmt.Errorf("`queries` must contain at least two queries")
+	}
+	n.Queries = queries
+	return nil
+}
+
+// getNary returns the n-ary expression, such that it is accessible through
+// the types embedding it.
+func (n *NaryExpression) getNary() *NaryExpression {
+	return n
+}
+
 // UnaryExpression represents a unary boolean expression.
 type UnaryExpression struct {
 	Query Query
@@ -87,3 +133,9 @@ func (u *UnaryExpression) Parse(params map[string]interface{}) error {
 	u.Op = op
 	return nil
 }
+
+// getUnary returns the unary expression, such that it is accessible through
+// the types embedding it.
+func (u *UnaryExpression) getUnary() *UnaryExpression {
+	return u
+}
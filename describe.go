@@ -0,0 +1,300 @@
+package veldt
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	schemaVersion = "http://json-schema.org/draft-07/schema#"
+)
+
+// Describer represents a tile, meta or query type which describes the
+// parameters accepted by its Parse method.
+type Describer interface {
+	Params() []json.Param
+}
+
+// Wrapper represents a tile or meta type which wraps another type, passing
+// its parameters through to the Parse method of the wrapped type.
+type Wrapper interface {
+	Unwrap() interface{}
+}
+
+// describeParams returns the parameters of the type. Types composed of
+// embedded types are described by the union of the parameters of the
+// embedded types, otherwise by their own Params method. Wrapper types are
+// additionally described by the parameters of the wrapped type. False is
+// returned if the type is not described.
+func describeParams(v interface{}) ([]json.Param, bool) {
+	params, ok := describeOwnParams(v)
+	if !ok {
+		return nil, false
+	}
+	wrapper, ok := v.(Wrapper)
+	if !ok {
+		return params, true
+	}
+	// the wrapped type must be described, otherwise any of its parameters
+	// would be rejected
+	wrapped, ok := describeParams(wrapper.Unwrap())
+	if !ok {
+		return nil, false
+	}
+	return unionParams(params, wrapped), true
+}
+
+func describeOwnParams(v interface{}) ([]json.Param, bool) {
+	params, ok := describeEmbedded(reflect.ValueOf(v))
+	if ok {
+		return params, true
+	}
+	describer, ok := v.(Describer)
+	if !ok {
+		return nil, false
+	}
+	return describer.Params(), true
+}
+
+func describeEmbedded(val reflect.Value) ([]json.Param, bool) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil, false
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct || !val.CanAddr() {
+		return nil, false
+	}
+	var params []json.Param
+	described := false
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		if !field.Anonymous || field.PkgPath != "" {
+			continue
+		}
+		embedded := val.Field(i)
+		switch embedded.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if embedded.IsNil() {
+				continue
+			}
+		default:
+			embedded = embedded.Addr()
+		}
+		ps, ok := describeParams(embedded.Interface())
+		if !ok {
+			continue
+		}
+		described = true
+		params = unionParams(params, ps)
+	}
+	return params, described
+}
+
+// unionParams appends the parameters of b which are not already in a.
+func unionParams(a []json.Param, b []json.Param) []json.Param {
+	names := make(map[string]bool, len(a))
+	for _, p := range a {
+		names[p.Name] = true
+	}
+	for _, p := range b {
+		if !names[p.Name] {
+			names[p.Name] = true
+			a = append(a, p)
+		}
+	}
+	return a
+}
+
+// validateParams checks the parameters against the description of the type,
+// if it is described.
+func validateParams(v interface{}, params map[string]interface{}) error {
+	described, ok := describeParams(v)
+	if !ok {
+		return nil
+	}
+	return json.ValidateParams(described, params)
+}
+
+// Describe returns a JSON Schema of the valid tile and meta requests for the
+// pipeline. Types which do not describe their parameters accept any object.
+func (p *Pipeline) Describe() (map[string]interface{}, error) {
+	tiles, err := p.describeTypes(p.tileIDs(), func(id string) (interface{}, error) {
+		return p.tiles[id]()
+	})
+	if err != nil {
+		return nil, err
+	}
+	metas, err := p.describeTypes(p.metaIDs(), func(id string) (interface{}, error) {
+		return p.metas[id]()
+	})
+	if err != nil {
+		return nil, err
+	}
+	queries, err := p.describeTypes(p.queryIDs(), func(id string) (interface{}, error) {
+		return p.queries[id]()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"$schema": schemaVersion,
+		"oneOf": []interface{}{
+			ref("tileRequest"),
+			ref("metaRequest"),
+		},
+		"definitions": map[string]interface{}{
+			"tileRequest": map[string]interface{}{
+				"type":     json.ObjectType,
+				"required": []string{"uri", "coord", "tile"},
+				"properties": map[string]interface{}{
+					"uri":   map[string]interface{}{"type": json.StringType},
+					"coord": coordSchema(),
+					"tile":  ref("tile"),
+					"query": ref("query"),
+				},
+			},
+			"metaRequest": map[string]interface{}{
+				"type":     json.ObjectType,
+				"required": []string{"uri", "meta"},
+				"properties": map[string]interface{}{
+					"uri":   map[string]interface{}{"type": json.StringType},
+					"meta":  ref("meta"),
+					"scope": json.ParamsSchema((&tile.Bivariate{}).Params()),
+					"coord": coordSchema(),
+					"query": ref("query"),
+				},
+			},
+			"tile":       oneOf(tiles),
+			"meta":       oneOf(metas),
+			"query":      querySchema(),
+			"expression": p.expressionSchema(queries),
+		},
+	}, nil
+}
+
+func (p *Pipeline) describeTypes(ids []string, ctor func(string) (interface{}, error)) ([]interface{}, error) {
+	schemas := make([]interface{}, len(ids))
+	for i, id := range ids {
+		v, err := ctor(id)
+		if err != nil {
+			return nil, err
+		}
+		schema := map[string]interface{}{
+			"type": json.ObjectType,
+		}
+		params, ok := describeParams(v)
+		if ok {
+			schema = json.ParamsSchema(params)
+		}
+		schemas[i] = map[string]interface{}{
+			"type":                 json.ObjectType,
+			"required":             []string{id},
+			"additionalProperties": false,
+			"properties": map[string]interface{}{
+				id: schema,
+			},
+		}
+	}
+	return schemas, nil
+}
+
+// querySchema returns the schema of a query, either textual or an expression.
+func querySchema() map[string]interface{} {
+	return map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "null"},
+			map[string]interface{}{"type": json.StringType},
+			ref("expression"),
+		},
+	}
+}
+
+// expressionSchema returns the schema of a query expression, which is either
+// a query or an array of expressions and operators.
+func (p *Pipeline) expressionSchema(queries []interface{}) map[string]interface{} {
+	ops := make([]interface{}, 0)
+	if p.binary != nil || p.nary != nil {
+		ops = append(ops, And, Or)
+	}
+	if p.unary != nil {
+		ops = append(ops, Not)
+	}
+	if len(ops) > 0 {
+		queries = append(queries, map[string]interface{}{
+			"type": json.ArrayType,
+			"items": map[string]interface{}{
+				"oneOf": []interface{}{
+					ref("expression"),
+					map[string]interface{}{"enum": ops},
+				},
+			},
+		})
+	}
+	return oneOf(queries)
+}
+
+func coordSchema() map[string]interface{} {
+	coord := map[string]interface{}{
+		"type":    json.IntegerType,
+		"minimum": 0,
+	}
+	return map[string]interface{}{
+		"type":     json.ObjectType,
+		"required": []string{"x", "y", "z"},
+		"properties": map[string]interface{}{
+			"x": coord,
+			"y": coord,
+			"z": coord,
+		},
+	}
+}
+
+func ref(definition string) map[string]interface{} {
+	return map[string]interface{}{
+		"$ref": "#/definitions/" + definition,
+	}
+}
+
+func oneOf(schemas []interface{}) map[string]interface{} {
+	if len(schemas) == 0 {
+		// nothing is valid
+		return map[string]interface{}{
+			"not": map[string]interface{}{},
+		}
+	}
+	return map[string]interface{}{
+		"oneOf": schemas,
+	}
+}
+
+func (p *Pipeline) tileIDs() []string {
+	ids := make([]string, 0, len(p.tiles))
+	for id := range p.tiles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (p *Pipeline) metaIDs() []string {
+	ids := make([]string, 0, len(p.metas))
+	for id := range p.metas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (p *Pipeline) queryIDs() []string {
+	ids := make([]string, 0, len(p.queries))
+	for id := range p.queries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,207 @@
+package veldt_test
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/query"
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+type topHitsStubTile struct {
+	tile.Bivariate
+	tile.TopHits
+	tile.Micro
+}
+
+func (t *topHitsStubTile) Parse(params map[string]interface{}) error {
+	err := t.TopHits.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = t.Micro.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Bivariate.Parse(params)
+}
+
+func (t *topHitsStubTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return nil, nil
+}
+
+type wrapperStubTile struct {
+	tile.Render
+	wrapped veldt.Tile
+}
+
+func (t *wrapperStubTile) Unwrap() interface{} {
+	return t.wrapped
+}
+
+func (t *wrapperStubTile) Parse(params map[string]interface{}) error {
+	err := t.Render.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.wrapped.Parse(params)
+}
+
+func (t *wrapperStubTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return nil, nil
+}
+
+type stubMeta struct{}
+
+func (m *stubMeta) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (m *stubMeta) Create(uri string) ([]byte, error) {
+	return nil, nil
+}
+
+var _ = Describe("Describe", func() {
+
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		pipeline = veldt.NewPipeline()
+		pipeline.Binary(func() (veldt.Query, error) {
+			return &veldt.BinaryExpression{}, nil
+		})
+		pipeline.Query("exists", func() (veldt.Query, error) {
+			return &query.Exists{}, nil
+		})
+		pipeline.Tile("micro", func() (veldt.Tile, error) {
+			return &topHitsStubTile{}, nil
+		})
+		pipeline.Tile("stub", func() (veldt.Tile, error) {
+			return &stubTile{}, nil
+		})
+		pipeline.Tile("wrapper", func() (veldt.Tile, error) {
+			return &wrapperStubTile{wrapped: &topHitsStubTile{}}, nil
+		})
+		pipeline.Tile("wrapper-stub", func() (veldt.Tile, error) {
+			return &wrapperStubTile{wrapped: &stubTile{}}, nil
+		})
+		pipeline.Meta("default", func() (veldt.Meta, error) {
+			return &stubMeta{}, nil
+		})
+	})
+
+	definition := func(name string) map[string]interface{} {
+		schema, err := pipeline.Describe()
+		Expect(err).To(BeNil())
+		definitions, ok := json.GetChild(schema, "definitions")
+		Expect(ok).To(Equal(true))
+		def, ok := json.GetChild(definitions, name)
+		Expect(ok).To(Equal(true))
+		return def
+	}
+
+	It("should describe composed tiles by the union of their embedded types", func() {
+		tiles := definition("tile")["oneOf"].([]interface{})
+		Expect(tiles).To(HaveLen(4))
+		micro, ok := json.GetChild(tiles[0].(map[string]interface{}), "properties", "micro")
+		Expect(ok).To(Equal(true))
+		Expect(micro["required"]).To(Equal([]string{"xField", "yField", "hitsCount"}))
+		properties := micro["properties"].(map[string]interface{})
+		Expect(properties).To(HaveKey("resolution"))
+		Expect(properties).To(HaveKey("lod"))
+		Expect(properties["sortOrder"]).To(Equal(map[string]interface{}{
+			"type":    "string",
+			"default": "desc",
+			"enum":    []interface{}{"desc", "asc"},
+		}))
+	})
+
+	It("should accept any parameters for types which are not described", func() {
+		tiles := definition("tile")["oneOf"].([]interface{})
+		stub, ok := json.GetChild(tiles[1].(map[string]interface{}), "properties", "stub")
+		Expect(ok).To(Equal(true))
+		Expect(stub).To(Equal(map[string]interface{}{"type": "object"}))
+		metas := definition("meta")["oneOf"].([]interface{})
+		Expect(metas).To(HaveLen(1))
+	})
+
+	It("should describe wrapper tiles by the union of their own and the wrapped types", func() {
+		tiles := definition("tile")["oneOf"].([]interface{})
+		wrapper, ok := json.GetChild(tiles[2].(map[string]interface{}), "properties", "wrapper")
+		Expect(ok).To(Equal(true))
+		Expect(wrapper["required"]).To(Equal([]string{"xField", "yField", "hitsCount"}))
+		properties := wrapper["properties"].(map[string]interface{})
+		Expect(properties).To(HaveKey("colorRamp"))
+		Expect(properties).To(HaveKey("resolution"))
+		Expect(properties).To(HaveKey("sortOrder"))
+	})
+
+	It("should accept any parameters for wrappers of types which are not described", func() {
+		tiles := definition("tile")["oneOf"].([]interface{})
+		wrapper, ok := json.GetChild(tiles[3].(map[string]interface{}), "properties", "wrapper-stub")
+		Expect(ok).To(Equal(true))
+		Expect(wrapper).To(Equal(map[string]interface{}{"type": "object"}))
+	})
+
+	It("should validate wrapper tile parameters against the wrapped type", func() {
+		request := func(id string, params string) error {
+			_, err := pipeline.NewTileRequest(map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"x": 0.0, "y": 0.0, "z": 0.0},
+				"tile": map[string]interface{}{
+					id: JSON(params),
+				},
+			})
+			return err
+		}
+		Expect(request("wrapper", `{"colorRamp": "hot", "xField": "x", "yField": "y", "projection": "mercator", "hitsCount": 10}`)).
+			To(BeNil())
+		Expect(request("wrapper", `{"colorRamp": "hot", "xField": "x", "yField": "y", "projection": "mercator"}`)).
+			To(MatchError(ContainSubstring("`hitsCount` parameter missing")))
+		Expect(request("wrapper-stub", `{"colorRamp": "hot", "anything": true}`)).
+			To(BeNil())
+	})
+
+	It("should describe query expressions with the registered operators", func() {
+		expression := definition("expression")["oneOf"].([]interface{})
+		Expect(expression).To(HaveLen(2))
+		exists, ok := json.GetChild(expression[0].(map[string]interface{}), "properties", "exists")
+		Expect(ok).To(Equal(true))
+		Expect(exists["required"]).To(Equal([]string{"field"}))
+		Expect(expression[1]).To(Equal(map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"oneOf": []interface{}{
+					map[string]interface{}{"$ref": "#/definitions/expression"},
+					map[string]interface{}{"enum": []interface{}{veldt.And, veldt.Or}},
+				},
+			},
+		}))
+	})
+
+	It("should validate tile parameters against the same descriptions", func() {
+		request := func(params string) error {
+			_, err := pipeline.NewTileRequest(map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"x": 0.0, "y": 0.0, "z": 0.0},
+				"tile": map[string]interface{}{
+					"micro": JSON(params),
+				},
+			})
+			return err
+		}
+		valid := `{"xField": "x", "yField": "y", "left": 0, "right": 1, "bottom": 0, "top": 1, "hitsCount": 10}`
+		Expect(request(valid)).To(BeNil())
+		Expect(request(`{"xField": "x", "yField": "y", "left": 0, "right": 1, "bottom": 0, "top": 1}`)).
+			To(MatchError(ContainSubstring("`hitsCount` parameter missing")))
+		Expect(request(`{"xField": "x", "yField": "y", "projection": "mercator", "hitsCount": 10, "sortOrder": "up"}`)).
+			To(MatchError(ContainSubstring("`sortOrder` parameter must be one of `desc`, `asc`")))
+		Expect(request(`{"xField": 1, "yField": "y", "projection": "mercator", "hitsCount": 10}`)).
+			To(MatchError(ContainSubstring("`xField` parameter is not of type `string`")))
+	})
+
+})
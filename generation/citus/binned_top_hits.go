@@ -0,0 +1,153 @@
+package citus
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// BinnedTopHits represents a citus implementation of the binned top hits
+// tile.
+type BinnedTopHits struct {
+	Bivariate
+	Tile
+	TopHits
+}
+
+// NewBinnedTopHits instantiates and returns a new tile struct.
+func NewBinnedTopHits(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		b := &BinnedTopHits{}
+		b.Config = cfg
+		return b, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (b *BinnedTopHits) Parse(params map[string]interface{}) error {
+	err := b.TopHits.Parse(params)
+	if err != nil {
+		return err
+	}
+	return b.Bivariate.Parse(params)
+}
+
+// AddAggs ranks the rows within each bin and returns the query selecting the
+// top hits of each bin.
+func (b *BinnedTopHits) AddAggs(coord *binning.TileCoord, query *Query) (string, error) {
+	xBucket, yBucket, err := b.Bivariate.GetBinExpressions(coord, query)
+	if err != nil {
+		return "", err
+	}
+	query.Select(fmt.Sprintf("%s AS x_bucket", xBucket))
+	query.Select(fmt.Sprintf("%s AS y_bucket", yBucket))
+	err = b.TopHits.AddIncludes(query)
+	if err != nil {
+		return "", err
+	}
+	orderBy, err := b.TopHits.GetOrderBy(query)
+	if err != nil {
+		return "", err
+	}
+	if orderBy != "" {
+		orderBy = " ORDER BY " + orderBy
+	}
+	// window aliases are not visible within the same select
+	query.Select(fmt.Sprintf("ROW_NUMBER() OVER (PARTITION BY %s, %s%s) AS bin_rank",
+		xBucket, yBucket, orderBy))
+	countArg := query.AddParameter(b.HitsCount)
+	return fmt.Sprintf("SELECT * FROM (%s) AS ranked WHERE bin_rank <= %s;",
+		query.GetQuery(true), countArg), nil
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (b *BinnedTopHits) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, citusQuery, err := b.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	citusQuery, err = b.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// add aggs
+	queryString, err := b.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// send query
+	res, err := client.Query(queryString, citusQuery.QueryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// bin hits, the rows of each bin are returned in rank order
+	bins := make([][]map[string]interface{}, b.Resolution*b.Resolution)
+	for res.Next() {
+		columnValues, err := res.Values()
+		if err != nil {
+			return nil, err
+		}
+		x, ok := castBin(columnValues[0])
+		if !ok {
+			return nil, fmt.Errorf("Error parsing x bin: %v", columnValues[0])
+		}
+		y, ok := castBin(columnValues[1])
+		if !ok {
+			return nil, fmt.Errorf("Error parsing y bin: %v", columnValues[1])
+		}
+		if x < 0 || x >= b.Resolution || y < 0 || y >= b.Resolution {
+			continue
+		}
+		index := x + b.Resolution*y
+		bins[index] = append(bins[index], b.TopHits.GetHit(columnValues[2:]))
+	}
+	if res.Err() != nil {
+		return nil, res.Err()
+	}
+
+	// bin width
+	binSize := binning.MaxTileResolution / float64(b.Resolution)
+	halfSize := float64(binSize / 2)
+
+	// convert to point array
+	points := make([]float32, len(bins)*2)
+	numPoints := 0
+	for i, bin := range bins {
+		if bin != nil {
+			x := float32(float64(i%b.Resolution)*binSize + halfSize)
+			y := float32(math.Floor(float64(i/b.Resolution))*binSize + halfSize)
+			points[numPoints*2] = x
+			points[numPoints*2+1] = y
+			numPoints++
+		}
+	}
+
+	//encode
+	return json.Marshal(map[string]interface{}{
+		"points": points[0 : numPoints*2],
+		"hits":   bins,
+	})
+}
+
+func castBin(val interface{}) (int, bool) {
+	switch v := val.(type) {
+	case int16:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	}
+	return 0, false
+}
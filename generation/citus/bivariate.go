@@ -15,25 +15,70 @@ type Bivariate struct {
 }
 
 // AddQuery adds the tiling query to the provided query object.
-func (b *Bivariate) AddQuery(coord *binning.TileCoord, query *Query) *Query {
+func (b *Bivariate) AddQuery(coord *binning.TileCoord, query *Query) (*Query, error) {
+	xField, err := query.Column(b.XField)
+	if err != nil {
+		return nil, err
+	}
+	yField, err := query.Column(b.YField)
+	if err != nil {
+		return nil, err
+	}
 	// get tile bounds
 	bounds := b.TileBounds(coord)
+	if b.Projection == tile.MercatorProjection {
+		// lon / lat bounds must not be truncated
+		minXArg := query.AddParameter(bounds.MinX())
+		maxXArg := query.AddParameter(bounds.MaxX())
+		query.Where(fmt.Sprintf("%s >= %s and %s < %s", xField, minXArg, xField, maxXArg))
+		minYArg := query.AddParameter(bounds.MinY())
+		maxYArg := query.AddParameter(bounds.MaxY())
+		query.Where(fmt.Sprintf("%s >= %s and %s < %s", yField, minYArg, yField, maxYArg))
+		return query, nil
+	}
 	// x
 	minXArg := query.AddParameter(int64(bounds.MinX()))
 	maxXArg := query.AddParameter(int64(bounds.MaxX()))
-	rangeQueryX := fmt.Sprintf("%s >= %s and %s < %s", b.XField, minXArg, b.XField, maxXArg)
+	rangeQueryX := fmt.Sprintf("%s >= %s and %s < %s", xField, minXArg, xField, maxXArg)
 	query.Where(rangeQueryX)
 	// y
 	minYArg := query.AddParameter(int64(bounds.MinY()))
 	maxYArg := query.AddParameter(int64(bounds.MaxY()))
-	rangeQueryY := fmt.Sprintf("%s >= %s and %s < %s", b.YField, minYArg, b.YField, maxYArg)
+	rangeQueryY := fmt.Sprintf("%s >= %s and %s < %s", yField, minYArg, yField, maxYArg)
 	query.Where(rangeQueryY)
 	// result
-	return query
+	return query, nil
 }
 
 // AddAggs adds the tiling aggregations to the provided query object.
-func (b *Bivariate) AddAggs(coord *binning.TileCoord, query *Query) *Query {
+func (b *Bivariate) AddAggs(coord *binning.TileCoord, query *Query) (*Query, error) {
+	xBucket, yBucket, err := b.GetBinExpressions(coord, query)
+	if err != nil {
+		return nil, err
+	}
+	query.Select(fmt.Sprintf("%s AS x_bucket", xBucket))
+	query.Select(fmt.Sprintf("%s AS y_bucket", yBucket))
+	query.GroupBy("x_bucket")
+	query.GroupBy("y_bucket")
+	// result
+	return query, nil
+}
+
+// GetBinExpressions adds the bin parameters to the query and returns the
+// expressions computing the x and y bin of each row.
+func (b *Bivariate) GetBinExpressions(coord *binning.TileCoord, query *Query) (string, string, error) {
+	xField, err := query.Column(b.XField)
+	if err != nil {
+		return "", "", err
+	}
+	yField, err := query.Column(b.YField)
+	if err != nil {
+		return "", "", err
+	}
+	if b.Projection == tile.MercatorProjection {
+		xBucket, yBucket := b.getMercatorBinExpressions(coord, query, xField, yField)
+		return xBucket, yBucket, nil
+	}
 	bounds := b.TileBounds(coord)
 	// bin
 	minX := int64(bounds.MinX())
@@ -44,17 +89,28 @@ func (b *Bivariate) AddAggs(coord *binning.TileCoord, query *Query) *Query {
 	minXArg := query.AddParameter(minX)
 	maxXArg := query.AddParameter(maxX)
 	bucketArg := query.AddParameter(b.Resolution)
-	queryString := fmt.Sprintf("width_bucket(%s, %s, %s, %s) - 1 AS x_bucket", b.XField, minXArg, maxXArg, bucketArg)
-	query.Select(queryString);
+	xBucket := fmt.Sprintf("width_bucket(%s, %s, %s, %s) - 1", xField, minXArg, maxXArg, bucketArg)
 	// y_bucket
 	minYArg := query.AddParameter(minY)
 	maxYArg := query.AddParameter(maxY)
-	queryString = fmt.Sprintf("width_bucket(%s, %s, %s, %s) - 1 AS y_bucket", b.YField, minYArg, maxYArg, bucketArg)
-	query.Select(queryString);
-	query.GroupBy("x_bucket");
-	query.GroupBy("y_bucket");
-	// result
-	return query
+	yBucket := fmt.Sprintf("width_bucket(%s, %s, %s, %s) - 1", yField, minYArg, maxYArg, bucketArg)
+	return xBucket, yBucket, nil
+}
+
+// getMercatorBinExpressions returns the bin expressions for lon / lat fields.
+// The latitude bins are not uniform, so they are bucketed against the
+// explicit bin boundaries.
+func (b *Bivariate) getMercatorBinExpressions(coord *binning.TileCoord, query *Query, xField string, yField string) (string, string) {
+	bounds := b.TileBounds(coord)
+	// x_bucket
+	minXArg := query.AddParameter(bounds.MinX())
+	maxXArg := query.AddParameter(bounds.MaxX())
+	bucketArg := query.AddParameter(b.Resolution)
+	xBucket := fmt.Sprintf("width_bucket(%s, %s, %s, %s) - 1", xField, minXArg, maxXArg, bucketArg)
+	// y_bucket
+	thresholdsArg := query.AddParameter(b.GetYBinBounds(coord))
+	yBucket := fmt.Sprintf("width_bucket(CAST(%s AS FLOAT8), CAST(%s AS FLOAT8[])) - 1", yField, thresholdsArg)
+	return xBucket, yBucket
 }
 
 // GetBins parses the resulting histograms into bins.
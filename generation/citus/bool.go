@@ -2,6 +2,7 @@ package citus
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/unchartedsoftware/veldt"
 )
@@ -51,6 +52,37 @@ func (e *BinaryExpression) Get(query *Query) (string, error) {
 	return res, nil
 }
 
+// NaryExpression represents an and/or boolean query over any number of
+// queries.
+type NaryExpression struct {
+	veldt.NaryExpression
+}
+
+// NewNaryExpression instantiates and returns a new n-ary expression.
+func NewNaryExpression() (veldt.Query, error) {
+	return &NaryExpression{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (e *NaryExpression) Get(query *Query) (string, error) {
+	if e.Op != veldt.And && e.Op != veldt.Or {
+		return "", fmt.Errorf("`%v` operator is not a valid n-ary operator", e.Op)
+	}
+	clauses := make([]string, len(e.Queries))
+	for i, q := range e.Queries {
+		queryString, ok := q.(QueryString)
+		if !ok {
+			return "", fmt.Errorf("`Queries` element is not of type citus.Query")
+		}
+		clause, err := queryString.Get(query)
+		if err != nil {
+			return "", err
+		}
+		clauses[i] = fmt.Sprintf("(%s)", clause)
+	}
+	return fmt.Sprintf("(%s)", strings.Join(clauses, fmt.Sprintf(" %s ", e.Op))), nil
+}
+
 // UnaryExpression represents a must_not boolean query.
 type UnaryExpression struct {
 	veldt.UnaryExpression
@@ -1,6 +1,7 @@
 package citus
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"runtime"
 	"sync"
@@ -10,7 +11,8 @@ import (
 )
 
 const (
-	timeout = time.Second * 60
+	timeout               = time.Second * 60
+	defaultMaxConnections = 16
 )
 
 var (
@@ -18,22 +20,56 @@ var (
 	clients = make(map[string]*pgx.ConnPool)
 )
 
-// Config defines the database details required to establish a connection.
+// Config defines the database and connection pool details required to
+// establish a connection.
 type Config struct {
 	Host     string
 	Port     uint16
 	Database string
 	User     string
 	Password string
+	// MaxConnections is the maximum number of connections in the pool. If
+	// zero, a default of 16 is used.
+	MaxConnections int
+	// AcquireTimeout is the maximum duration to wait for a connection when
+	// all connections in the pool are busy. If zero, there is no timeout.
+	AcquireTimeout time.Duration
+	// AllowedTables restricts the tables which may be queried. If nil, any
+	// table may be queried.
+	AllowedTables []string
+	// AllowedColumns restricts the columns which may be referenced by tile
+	// and query parameters. If nil, any column may be referenced.
+	AllowedColumns []string
+}
+
+// key returns the key of the connection pool for the config, which includes
+// every attribute of the config affecting the pool. The password is hashed
+// such that it is not held in the key.
+func (c *Config) key() string {
+	password := sha256.Sum256([]byte(c.Password))
+	return fmt.Sprintf("%s:%d/%s?user=%s&password=%x&maxConnections=%d&acquireTimeout=%s",
+		c.Host,
+		c.Port,
+		c.Database,
+		c.User,
+		password,
+		c.maxConnections(),
+		c.AcquireTimeout)
+}
+
+func (c *Config) maxConnections() int {
+	if c.MaxConnections == 0 {
+		return defaultMaxConnections
+	}
+	return c.MaxConnections
 }
 
 // NewClient return a citus client from the pool.
 func NewClient(cfg *Config) (*pgx.ConnPool, error) {
-	endpoint := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	key := cfg.key()
 	mutex.Lock()
-	client, ok := clients[endpoint]
+	client, ok := clients[key]
 	if !ok {
-		//TODO: Add configuration for connection parameters.
 		dbConfig := pgx.ConnConfig{
 			Host:     cfg.Host,
 			Port:     cfg.Port,
@@ -41,22 +77,47 @@ func NewClient(cfg *Config) (*pgx.ConnPool, error) {
 			User:     cfg.User,
 			Password: cfg.Password,
 		}
-
 		poolConfig := pgx.ConnPoolConfig{
 			ConnConfig:     dbConfig,
-			MaxConnections: 16,
+			MaxConnections: cfg.maxConnections(),
+			AcquireTimeout: cfg.AcquireTimeout,
 		}
-		//TODO: Need to close the pool eventually. Not sure how to hook that in.
 		c, err := pgx.NewConnPool(poolConfig)
 		if err != nil {
 			mutex.Unlock()
 			runtime.Gosched()
 			return nil, err
 		}
-		clients[endpoint] = c
+		clients[key] = c
 		client = c
 	}
 	mutex.Unlock()
 	runtime.Gosched()
 	return client, nil
 }
+
+// CloseClient closes the connection pool for the provided config, if one has
+// been opened. Subsequent calls to NewClient will open a new pool.
+func CloseClient(cfg *Config) {
+	key := cfg.key()
+	mutex.Lock()
+	client, ok := clients[key]
+	if ok {
+		delete(clients, key)
+	}
+	mutex.Unlock()
+	if ok {
+		client.Close()
+	}
+}
+
+// CloseClients closes all open connection pools.
+func CloseClients() {
+	mutex.Lock()
+	closing := clients
+	clients = make(map[string]*pgx.ConnPool)
+	mutex.Unlock()
+	for _, client := range closing {
+		client.Close()
+	}
+}
@@ -0,0 +1,46 @@
+package citus
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+
+	base := func() *Config {
+		return &Config{
+			Host:     "localhost",
+			Port:     5432,
+			Database: "db",
+			User:     "user",
+			Password: "secret",
+		}
+	}
+
+	It("should key pools by every attribute affecting the pool", func() {
+		cfg := base()
+		other := base()
+		other.MaxConnections = 4
+		Expect(other.key()).NotTo(Equal(cfg.key()))
+		other = base()
+		other.AcquireTimeout = time.Second
+		Expect(other.key()).NotTo(Equal(cfg.key()))
+		other = base()
+		other.Password = "other"
+		Expect(other.key()).NotTo(Equal(cfg.key()))
+	})
+
+	It("should share pools between configs with the same attributes", func() {
+		cfg := base()
+		other := base()
+		other.MaxConnections = defaultMaxConnections
+		other.AllowedTables = []string{"tweets"}
+		Expect(other.key()).To(Equal(cfg.key()))
+	})
+
+	It("should not hold the password in the key", func() {
+		Expect(base().key()).NotTo(ContainSubstring("secret"))
+	})
+})
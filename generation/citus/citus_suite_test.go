@@ -0,0 +1,13 @@
+package citus_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCitus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Citus Suite")
+}
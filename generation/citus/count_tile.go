@@ -27,6 +27,11 @@ func (t *Count) Parse(params map[string]interface{}) error {
 	return t.Bivariate.Parse(params)
 }
 
+// Empty returns the tile containing no data.
+func (t *Count) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return []byte(`{"count":0}`), nil
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
@@ -37,7 +42,10 @@ func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query)
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	citusQuery.Select("CAST(COUNT(*) AS FLOAT) AS value")
 	// send query
@@ -0,0 +1,105 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// CubeTile represents a citus implementation of the time-series cube tile.
+type CubeTile struct {
+	Bivariate
+	Frequency
+	Tile
+	tile.Cube
+}
+
+// NewCubeTile instantiates and returns a new tile struct.
+func NewCubeTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &CubeTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *CubeTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = t.Frequency.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Cube.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *CubeTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, citusQuery, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// add time range query
+	t.Frequency.FieldType, err = t.GetColumnType(client, citusQuery, t.FrequencyField)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery, err = t.Frequency.AddQuery(citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// add aggs
+	citusQuery, err = t.Bivariate.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := t.Frequency.BucketExpression(citusQuery)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery.Select(fmt.Sprintf("%s AS time_bucket", bucket))
+	citusQuery.GroupBy("time_bucket")
+	citusQuery.Select("COUNT(*) AS value")
+
+	// send query
+	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// parse the buckets
+	buckets := make([]tile.CubeBucket, 0)
+	for res.Next() {
+		var x, y int64
+		var timestamp int64
+		var count int64
+		err := res.Scan(&x, &y, &timestamp, &count)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing cube aggregation: %v", err)
+		}
+		buckets = append(buckets, tile.CubeBucket{
+			Bin:       t.Bivariate.GetXBin(coord, float64(x)) + t.Resolution*t.Bivariate.GetYBin(coord, float64(y)),
+			Timestamp: timestamp,
+			Count:     uint32(count),
+		})
+	}
+
+	// encode the result
+	return t.Cube.Encode(t.Resolution, buckets)
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/meta"
+	"github.com/unchartedsoftware/veldt/tile"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -17,6 +19,7 @@ import (
 type PropertyMeta struct {
 	Type    string           `json:"type"`
 	Extrema *binning.Extrema `json:"extrema,omitempty"`
+	meta.PropertyStatistics
 }
 
 func isNumeric(typ string) bool {
@@ -42,112 +45,421 @@ func isTimestamp(typ string) bool {
 		typ == "interval"
 }
 
-func getPropertyMeta(connPool *pgx.ConnPool, schema string, table string, column string, typ string) (*PropertyMeta, error) {
+func isText(typ string) bool {
+	return typ == "text" ||
+		typ == "character varying" ||
+		typ == "character"
+}
+
+func getPropertyMeta(connPool *pgx.ConnPool, stats *meta.Statistics, filter *Query, column string, typ string) (*PropertyMeta, error) {
 	p := PropertyMeta{
 		Type: typ,
 	}
 	// if field is 'ordinal', get the extrema
-	if isNumeric(typ) {
-		extrema, err := GetNumericExtrema(connPool, schema, table, column)
+	if isNumeric(typ) || isTimestamp(typ) {
+		extrema, err := GetExtrema(connPool, filter, column, typ)
 		if err != nil {
 			return nil, err
 		}
 		p.Extrema = extrema
-	} else if isTimestamp(typ) {
-		extrema, err := GetTimestampExtrema(connPool, schema, table, column)
+	}
+	err := getStatistics(connPool, stats, filter, column, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func getStatistics(connPool *pgx.ConnPool, stats *meta.Statistics, filter *Query, column string, p *PropertyMeta) error {
+	ordinal := isNumeric(p.Type) || isTimestamp(p.Type)
+	if stats.Cardinality || stats.Missing {
+		cardinality, missing, err := GetCounts(connPool, filter, column)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if stats.Cardinality {
+			p.Cardinality = &cardinality
+		}
+		if stats.Missing {
+			p.Missing = &missing
 		}
-		p.Extrema = extrema
 	}
-	return &p, nil
+	if ordinal && len(stats.Percentiles) > 0 {
+		percentiles, err := GetPercentiles(connPool, filter, column, p.Type, stats.Percentiles)
+		if err != nil {
+			return err
+		}
+		p.Percentiles = percentiles
+	}
+	if ordinal && stats.HistogramBuckets > 0 && p.Extrema != nil {
+		histogram, err := GetHistogram(connPool, filter, column, p.Type, p.Extrema, stats.HistogramBuckets)
+		if err != nil {
+			return err
+		}
+		p.Histogram = histogram
+	}
+	if isText(p.Type) && stats.TopValues > 0 {
+		values, err := GetTopValues(connPool, filter, column, stats.TopValues)
+		if err != nil {
+			return err
+		}
+		p.TopValues = values
+	}
+	return nil
 }
 
 // GetNumericExtrema returns the extrema of a numeric field for the provided table.
 func GetNumericExtrema(connPool *pgx.ConnPool, schema string, table string, column string) (*binning.Extrema, error) {
-	// query
-	queryString := fmt.Sprintf("SELECT CAST(MIN(%s) AS FLOAT) as min, CAST(MAX(%s) AS FLOAT) as max FROM %s.%s;", column, column, schema, table)
-	row := connPool.QueryRow(queryString)
+	filter, err := newTableQuery(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return GetExtrema(connPool, filter, column, "double precision")
+}
 
-	// Parse min & max values.
-	var min *float64
-	var max *float64
-	err := row.Scan(&min, &max)
+// GetTimestampExtrema returns the extrema of a timestamp field for the provided table.
+func GetTimestampExtrema(connPool *pgx.ConnPool, schema string, table string, column string) (*binning.Extrema, error) {
+	filter, err := newTableQuery(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return GetExtrema(connPool, filter, column, "timestamp")
+}
+
+// ExtremaQuery returns the query and arguments computing the extrema of an
+// ordinal column for the rows of the filter.
+func ExtremaQuery(filter *Query, column string, typ string) (string, []interface{}, error) {
+	query, column, err := newColumnQuery(filter, column)
+	if err != nil {
+		return "", nil, err
+	}
+	if isTimestamp(typ) {
+		query.Select(fmt.Sprintf("MIN(%s) as min", column))
+		query.Select(fmt.Sprintf("MAX(%s) as max", column))
+	} else {
+		query.Select(fmt.Sprintf("CAST(MIN(%s) AS FLOAT) as min", column))
+		query.Select(fmt.Sprintf("CAST(MAX(%s) AS FLOAT) as max", column))
+	}
+	return query.GetQuery(false), query.QueryArgs, nil
+}
+
+// GetExtrema returns the extrema of an ordinal column for the rows of the
+// filter. Timestamps are represented as seconds since the epoch.
+func GetExtrema(connPool *pgx.ConnPool, filter *Query, column string, typ string) (*binning.Extrema, error) {
+	queryString, args, err := ExtremaQuery(filter, column, typ)
 	if err != nil {
 		return nil, err
 	}
+	row := connPool.QueryRow(queryString, args...)
 
 	// it seems if the mapping exists, but no documents have the attribute, the min / max are null
 	// TODO: TEST THIS FOR CITUS!!!
+	if isTimestamp(typ) {
+		var min *time.Time
+		var max *time.Time
+		err = row.Scan(&min, &max)
+		if err != nil {
+			return nil, err
+		}
+		if min == nil || max == nil {
+			return nil, nil
+		}
+		return &binning.Extrema{
+			Min: float64(min.Unix()),
+			Max: float64(max.Unix()),
+		}, nil
+	}
+	var min *float64
+	var max *float64
+	err = row.Scan(&min, &max)
+	if err != nil {
+		return nil, err
+	}
 	if min == nil || max == nil {
 		return nil, nil
 	}
-
 	return &binning.Extrema{
 		Min: *min,
 		Max: *max,
 	}, nil
 }
 
-// GetTimestampExtrema returns the extrema of a timestamp field for the provided table.
-func GetTimestampExtrema(connPool *pgx.ConnPool, schema string, table string, column string) (*binning.Extrema, error) {
-	// query
-	queryString := fmt.Sprintf("SELECT MIN(%s) as min, MAX(%s) as max FROM %s.%s;", column, column, schema, table)
-	row := connPool.QueryRow(queryString)
+// CountsQuery returns the query and arguments counting the distinct and
+// missing values of a column for the rows of the filter.
+func CountsQuery(filter *Query, column string) (string, []interface{}, error) {
+	query, column, err := newColumnQuery(filter, column)
+	if err != nil {
+		return "", nil, err
+	}
+	query.Select(fmt.Sprintf("COUNT(DISTINCT %s) as cardinality", column))
+	query.Select(fmt.Sprintf("COUNT(*) - COUNT(%s) as missing", column))
+	return query.GetQuery(false), query.QueryArgs, nil
+}
 
-	// Parse min & max values.
-	var min *time.Time
-	var max *time.Time
-	err := row.Scan(&min, &max)
+// GetCounts returns the number of distinct values and the number of missing
+// values of a column for the rows of the filter.
+func GetCounts(connPool *pgx.ConnPool, filter *Query, column string) (uint64, uint64, error) {
+	queryString, args, err := CountsQuery(filter, column)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
+	var cardinality int64
+	var missing int64
+	err = connPool.QueryRow(queryString, args...).Scan(&cardinality, &missing)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint64(cardinality), uint64(missing), nil
+}
 
-	// it seems if the mapping exists, but no documents have the attribute, the min / max are null
-	// TODO: TEST THIS FOR CITUS!!!
-	if min == nil || max == nil {
+// PercentilesQuery returns the query and arguments computing the percentiles
+// of an ordinal column for the rows of the filter.
+func PercentilesQuery(filter *Query, column string, typ string, percentiles []float64) (string, []interface{}, error) {
+	query, column, err := newColumnQuery(filter, column)
+	if err != nil {
+		return "", nil, err
+	}
+	fractions := make([]float64, len(percentiles))
+	for i, p := range percentiles {
+		fractions[i] = p / 100
+	}
+	fractionsArg := query.AddParameter(fractions)
+	query.Select(fmt.Sprintf("percentile_cont(%s::float8[]) WITHIN GROUP (ORDER BY %s) as percentiles", fractionsArg, ordinalValue(column, typ)))
+	return query.GetQuery(false), query.QueryArgs, nil
+}
+
+// GetPercentiles returns the percentiles of an ordinal column for the rows
+// of the filter, keyed by percentile.
+func GetPercentiles(connPool *pgx.ConnPool, filter *Query, column string, typ string, percentiles []float64) (map[string]float64, error) {
+	queryString, args, err := PercentilesQuery(filter, column, typ, percentiles)
+	if err != nil {
+		return nil, err
+	}
+	var values []float64
+	err = connPool.QueryRow(queryString, args...).Scan(&values)
+	if err != nil {
+		return nil, err
+	}
+	// the percentiles are null if no rows have a value
+	if len(values) != len(percentiles) {
 		return nil, nil
 	}
+	res := make(map[string]float64, len(values))
+	for i, val := range values {
+		res[meta.PercentileKey(percentiles[i])] = val
+	}
+	return res, nil
+}
 
-	return &binning.Extrema{
-		Min: float64(min.Unix()),
-		Max: float64(max.Unix()),
+// HistogramQuery returns the query and arguments counting the values of an
+// ordinal column within evenly sized buckets spanning the extrema, for the
+// rows of the filter. The maximum is counted in the last bucket.
+func HistogramQuery(filter *Query, column string, typ string, extrema *binning.Extrema, buckets int) (string, []interface{}, error) {
+	query, column, err := newColumnQuery(filter, column)
+	if err != nil {
+		return "", nil, err
+	}
+	value := ordinalValue(column, typ)
+	if extrema.Min == extrema.Max {
+		// width_bucket requires distinct bounds, every value is in the first
+		// bucket
+		query.Select("1 as bucket")
+		query.Select(fmt.Sprintf("COUNT(%s) as count", value))
+		return query.GetQuery(false), query.QueryArgs, nil
+	}
+	minArg := query.AddParameter(extrema.Min)
+	maxArg := query.AddParameter(extrema.Max)
+	bucketsArg := query.AddParameter(buckets)
+	query.Select(fmt.Sprintf("LEAST(width_bucket(%s, %s, %s, %s), %s) as bucket", value, minArg, maxArg, bucketsArg, bucketsArg))
+	query.Select("COUNT(*) as count")
+	query.Where(fmt.Sprintf("%s IS NOT NULL", value))
+	query.GroupBy("bucket")
+	return query.GetQuery(false), query.QueryArgs, nil
+}
+
+// GetHistogram returns the histogram of an ordinal column for the rows of
+// the filter.
+func GetHistogram(connPool *pgx.ConnPool, filter *Query, column string, typ string, extrema *binning.Extrema, buckets int) (*meta.Histogram, error) {
+	queryString, args, err := HistogramQuery(filter, column, typ, extrema, buckets)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := connPool.Query(queryString, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := make([]uint64, buckets)
+	for rows.Next() {
+		var bucket int32
+		var count int64
+		err := rows.Scan(&bucket, &count)
+		if err != nil {
+			return nil, err
+		}
+		if bucket >= 1 && int(bucket) <= buckets {
+			counts[bucket-1] = uint64(count)
+		}
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return &meta.Histogram{
+		Min:    extrema.Min,
+		Max:    extrema.Max,
+		Counts: counts,
 	}, nil
 }
 
+// TopValuesQuery returns the query and arguments of the most frequent values
+// of a column for the rows of the filter.
+func TopValuesQuery(filter *Query, column string, count int) (string, []interface{}, error) {
+	query, column, err := newColumnQuery(filter, column)
+	if err != nil {
+		return "", nil, err
+	}
+	query.Select(fmt.Sprintf("%s as value", column))
+	query.Select("COUNT(*) as count")
+	query.Where(fmt.Sprintf("%s IS NOT NULL", column))
+	query.GroupBy(column)
+	query.OrderBy("count DESC")
+	query.OrderBy(column)
+	query.Limit(uint32(count))
+	return query.GetQuery(false), query.QueryArgs, nil
+}
+
+// GetTopValues returns the most frequent values of a text column for the
+// rows of the filter.
+func GetTopValues(connPool *pgx.ConnPool, filter *Query, column string, count int) ([]meta.ValueCount, error) {
+	queryString, args, err := TopValuesQuery(filter, column, count)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := connPool.Query(queryString, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := make([]meta.ValueCount, 0, count)
+	for rows.Next() {
+		var value string
+		var count int64
+		err := rows.Scan(&value, &count)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, meta.ValueCount{
+			Value: value,
+			Count: uint64(count),
+		})
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return values, nil
+}
+
+// ordinalValue returns the numeric value of a quoted ordinal column.
+// Timestamps are represented as seconds since the epoch, consistent with
+// their extrema.
+func ordinalValue(column string, typ string) string {
+	if isTimestamp(typ) {
+		return fmt.Sprintf("CAST(EXTRACT(EPOCH FROM %s) AS FLOAT)", column)
+	}
+	return fmt.Sprintf("CAST(%s AS FLOAT)", column)
+}
+
+// newTableQuery returns an unfiltered query of the schema qualified table.
+func newTableQuery(schema string, table string) (*Query, error) {
+	schema, err := QuoteIdentifier(schema)
+	if err != nil {
+		return nil, err
+	}
+	table, err = QuoteIdentifier(table)
+	if err != nil {
+		return nil, err
+	}
+	query, err := NewQuery()
+	if err != nil {
+		return nil, err
+	}
+	query.From(schema + "." + table)
+	return query, nil
+}
+
+// newColumnQuery returns a copy of the filter query, along with the quoted
+// column, such that each statistic can extend the filter independently.
+func newColumnQuery(filter *Query, column string) (*Query, string, error) {
+	column, err := filter.Column(column)
+	if err != nil {
+		return nil, "", err
+	}
+	return copyQuery(filter), column, nil
+}
+
+// copyQuery returns a copy of the query which may be extended without
+// modifying the original.
+func copyQuery(query *Query) *Query {
+	res := *query
+	res.QueryArgs = append([]interface{}{}, query.QueryArgs...)
+	res.WhereClauses = append([]string{}, query.WhereClauses...)
+	res.GroupByClauses = append([]string{}, query.GroupByClauses...)
+	res.Fields = append([]string{}, query.Fields...)
+	res.Tables = append([]string{}, query.Tables...)
+	res.OrderByClauses = append([]string{}, query.OrderByClauses...)
+	return &res
+}
+
 // DefaultMeta represents a meta data generator that produces default
-// metadata with property types and extrema.
+// metadata with property types and extrema, along with any requested
+// statistics.
 type DefaultMeta struct {
-	Config *Config
+	Tile
+	meta.Statistics
 }
 
 // NewDefaultMeta instantiates and returns a pointer to a new generator.
 func NewDefaultMeta(cfg *Config) veldt.MetaCtor {
 	return func() (veldt.Meta, error) {
-		return &DefaultMeta{
-			Config: cfg,
-		}, nil
+		m := &DefaultMeta{}
+		m.Config = cfg
+		return m, nil
 	}
 }
 
 // Parse parses the provided JSON object and populates the structs attributes.
 func (g *DefaultMeta) Parse(params map[string]interface{}) error {
-	return nil
+	return g.Statistics.Parse(params)
 }
 
 // Create generates metadata from the provided URI.
 func (g *DefaultMeta) Create(uri string) ([]byte, error) {
-	client, err := NewClient(g.Config)
+	return g.CreateScoped(uri, nil, nil, nil)
+}
+
+// CreateScoped generates metadata from the provided URI, restricted to the
+// rows matching the query and within the tile of the scope.
+func (g *DefaultMeta) CreateScoped(uri string, scope *tile.Bivariate, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create the filter, validated against the allow-list
+	client, filter, err := g.InitializeTile(uri, query)
 	if err != nil {
 		return nil, err
 	}
+	if scope != nil {
+		b := &Bivariate{Bivariate: *scope}
+		filter, err = b.AddQuery(coord, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	split := strings.Split(uri, ".")
 	if len(split) != 2 {
 		return nil, errors.New("incorrect format for table, expect 'schema.table'")
 	}
-	schemaInput := split[0]
-	tableInput := split[1]
+	// catalog names are stored folded, as the identifiers are quoted
+	schemaInput := foldIdentifier(split[0])
+	tableInput := foldIdentifier(split[1])
 
 	schemaQuery := "select table_schema as schema, table_name as table, column_name as column, data_type as typ from information_schema.columns where table_schema = $1 and table_name = $2;"
 	rows, err := client.Query(schemaQuery, schemaInput, tableInput)
@@ -167,7 +479,12 @@ func (g *DefaultMeta) Create(uri string) ([]byte, error) {
 			return nil, err
 		}
 
-		metaColumn, err := getPropertyMeta(client, schema, table, column, typ)
+		if _, err := filter.Column(column); err != nil {
+			// omit columns which may not be referenced
+			continue
+		}
+
+		metaColumn, err := getPropertyMeta(client, &g.Statistics, filter, column, typ)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,111 @@
+package citus_test
+
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("DefaultMeta", func() {
+
+	var filter *citus.Query
+
+	BeforeEach(func() {
+		var err error
+		filter, err = citus.NewQuery()
+		Expect(err).To(BeNil())
+		filter.From(`"public"."points"`)
+	})
+
+	Describe("ExtremaQuery", func() {
+		It("should cast numeric extrema", func() {
+			query, args, err := citus.ExtremaQuery(filter, "x", "integer")
+			Expect(err).To(BeNil())
+			Expect(query).To(Equal(`SELECT CAST(MIN("x") AS FLOAT) as min, CAST(MAX("x") AS FLOAT) as max FROM "public"."points";`))
+			Expect(args).To(BeEmpty())
+		})
+		It("should restrict the extrema to the tile of the scope", func() {
+			b := &citus.Bivariate{}
+			err := b.Parse(JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"left": 0,
+					"right": 256,
+					"bottom": 0,
+					"top": 256
+				}`))
+			Expect(err).To(BeNil())
+			filter, err = b.AddQuery(&binning.TileCoord{X: 1, Y: 0, Z: 1}, filter)
+			Expect(err).To(BeNil())
+			query, args, err := citus.ExtremaQuery(filter, "t", "timestamp")
+			Expect(err).To(BeNil())
+			Expect(query).To(Equal(`SELECT MIN("t") as min, MAX("t") as max FROM "public"."points" WHERE "x" >= $1 and "x" < $2 AND "y" >= $3 and "y" < $4;`))
+			Expect(args).To(Equal([]interface{}{
+				int64(128), int64(256), int64(0), int64(128),
+			}))
+		})
+	})
+
+	Describe("CountsQuery", func() {
+		It("should count the distinct and missing values", func() {
+			query, _, err := citus.CountsQuery(filter, "name")
+			Expect(err).To(BeNil())
+			Expect(query).To(Equal(`SELECT COUNT(DISTINCT "name") as cardinality, COUNT(*) - COUNT("name") as missing FROM "public"."points";`))
+		})
+	})
+
+	Describe("PercentilesQuery", func() {
+		It("should compute the percentiles as fractions", func() {
+			query, args, err := citus.PercentilesQuery(filter, "x", "integer", []float64{5, 50, 95})
+			Expect(err).To(BeNil())
+			Expect(query).To(Equal(`SELECT percentile_cont($1::float8[]) WITHIN GROUP (ORDER BY CAST("x" AS FLOAT)) as percentiles FROM "public"."points";`))
+			Expect(args).To(Equal([]interface{}{[]float64{0.05, 0.5, 0.95}}))
+		})
+		It("should order timestamps by seconds since the epoch", func() {
+			query, _, err := citus.PercentilesQuery(filter, "t", "timestamp", []float64{50})
+			Expect(err).To(BeNil())
+			Expect(query).To(ContainSubstring(`ORDER BY CAST(EXTRACT(EPOCH FROM "t") AS FLOAT)`))
+		})
+	})
+
+	Describe("HistogramQuery", func() {
+		It("should count the maximum in the last bucket", func() {
+			query, args, err := citus.HistogramQuery(filter, "x", "real", &binning.Extrema{Min: 0, Max: 10}, 4)
+			Expect(err).To(BeNil())
+			Expect(query).To(Equal(`SELECT LEAST(width_bucket(CAST("x" AS FLOAT), $1, $2, $3), $3) as bucket, COUNT(*) as count FROM "public"."points" WHERE CAST("x" AS FLOAT) IS NOT NULL GROUP BY bucket;`))
+			Expect(args).To(Equal([]interface{}{0.0, 10.0, 4}))
+		})
+		It("should count every value in the first bucket if the extrema are equal", func() {
+			query, args, err := citus.HistogramQuery(filter, "x", "real", &binning.Extrema{Min: 1, Max: 1}, 4)
+			Expect(err).To(BeNil())
+			Expect(query).To(Equal(`SELECT 1 as bucket, COUNT(CAST("x" AS FLOAT)) as count FROM "public"."points";`))
+			Expect(args).To(BeEmpty())
+		})
+	})
+
+	Describe("TopValuesQuery", func() {
+		It("should order the values by descending count", func() {
+			query, _, err := citus.TopValuesQuery(filter, "name", 5)
+			Expect(err).To(BeNil())
+			Expect(query).To(Equal(`SELECT "name" as value, COUNT(*) as count FROM "public"."points" WHERE "name" IS NOT NULL GROUP BY "name" ORDER BY count DESC, "name" LIMIT 5;`))
+		})
+		It("should not modify the filter", func() {
+			filter.Where(`"x" > $1`)
+			filter.AddParameter(1)
+			_, _, err := citus.TopValuesQuery(filter, "name", 5)
+			Expect(err).To(BeNil())
+			Expect(filter.WhereClauses).To(Equal([]string{`"x" > $1`}))
+			Expect(filter.QueryArgs).To(Equal([]interface{}{1}))
+		})
+		It("should reject columns outside of the allow-list", func() {
+			filter.Allow(nil, []string{"x"})
+			_, _, err := citus.TopValuesQuery(filter, "name", 5)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
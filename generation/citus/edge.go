@@ -0,0 +1,88 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Edge represents a citus implementation of the edge tile.
+type Edge struct {
+	tile.Edge
+}
+
+// AddQuery adds the tiling query to the provided query object.
+func (e *Edge) AddQuery(coord *binning.TileCoord, query *Query) (*Query, error) {
+	// get tile bounds
+	bounds := e.TileBounds(coord)
+	// Require at least 1 of the points, possibly both.
+	if e.RequireSrc || !e.RequireDst {
+		err := e.addRangeQuery(query, e.SrcXField, bounds.MinX(), bounds.MaxX())
+		if err != nil {
+			return nil, err
+		}
+		err = e.addRangeQuery(query, e.SrcYField, bounds.MinY(), bounds.MaxY())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if e.RequireDst {
+		err := e.addRangeQuery(query, e.DstXField, bounds.MinX(), bounds.MaxX())
+		if err != nil {
+			return nil, err
+		}
+		err = e.addRangeQuery(query, e.DstYField, bounds.MinY(), bounds.MaxY())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
+
+func (e *Edge) addRangeQuery(query *Query, field string, min float64, max float64) error {
+	column, err := query.Column(field)
+	if err != nil {
+		return err
+	}
+	var minArg, maxArg string
+	if e.Projection == tile.MercatorProjection {
+		// lon / lat bounds must not be truncated
+		minArg = query.AddParameter(min)
+		maxArg = query.AddParameter(max)
+	} else {
+		minArg = query.AddParameter(int64(min))
+		maxArg = query.AddParameter(int64(max))
+	}
+	query.Where(fmt.Sprintf("%s >= %s and %s < %s", column, minArg, column, maxArg))
+	return nil
+}
+
+// GetEdges converts the provided hits into an array of source x, y and weight
+// followed by destination x, y and weight, in tile coordinates.
+func (e *Edge) GetEdges(coord *binning.TileCoord, hits []map[string]interface{}) ([]float32, error) {
+	edges := make([]float32, len(hits)*6)
+	for i, hit := range hits {
+		// get hit x/y in tile coords
+		srcX, srcY, ok := e.GetSrcXY(coord, hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge source position from hit: %v", hit)
+		}
+		dstX, dstY, ok := e.GetDstXY(coord, hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge destination position from hit: %v", hit)
+		}
+		weight, ok := e.GetWeight(hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge weight from hit: %v", hit)
+		}
+		// add to edge array
+		edges[i*6] = float32(srcX)
+		edges[i*6+1] = float32(srcY)
+		edges[i*6+2] = float32(weight)
+		edges[i*6+3] = float32(dstX)
+		edges[i*6+4] = float32(dstY)
+		edges[i*6+5] = float32(weight)
+	}
+	return edges, nil
+}
@@ -19,5 +19,9 @@ func NewExists() (veldt.Query, error) {
 
 // Get adds the parameters to the query and returns the string representation.
 func (q *Exists) Get(query *Query) (string, error) {
-	return fmt.Sprintf("%s IS NOT NULL", q.Field), nil
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s IS NOT NULL", field), nil
 }
@@ -2,8 +2,8 @@ package citus
 
 import (
 	"fmt"
-	"math"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx"
 
@@ -13,6 +13,10 @@ import (
 // Frequency represents a tiling generator that produces heatmaps.
 type Frequency struct {
 	tile.Frequency
+	// FieldType is the postgres type of the frequency field. Timestamp and
+	// date fields are bucketed as dates, while any other field is interpreted
+	// as milliseconds since the epoch.
+	FieldType string
 }
 
 // FrequencyResult represents a single frequency result bucket.
@@ -21,113 +25,147 @@ type FrequencyResult struct {
 	Value  float64
 }
 
-// AddAggs adds the tiling aggregations to the provided query object.
-func (f *Frequency) AddAggs(query *Query) *Query {
-	//Bounds extension (empty buckets) will be done in the go code when parsing results
-	//Not 100% sure if we need to subtract the min value from the frequency field to
-	//set the initial bucket.
-	//Ex:
-	//	data starts at 3, with intervals of 5.
-	//	Should the first bucket be 0-5 or 3-8?
-
-	//Ignoring potential error. Should really be done in some kind of setup function.
-	intervalNum, _ := strconv.ParseFloat(f.Interval, 64)
-	intervalArg := query.AddParameter(intervalNum)
-	queryString := fmt.Sprintf("(%s / %s * %s)", f.FrequencyField, intervalArg, intervalArg)
-	query.GroupBy(queryString)
-	query.Select(fmt.Sprintf("%s as bucket", queryString))
-	query.Select("COUNT(*) as frequency")
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (f *Frequency) Parse(params map[string]interface{}) error {
+	err := f.Frequency.Parse(params)
+	if err != nil {
+		return err
+	}
+	_, err = tile.ParseInterval(f.Interval)
+	if err != nil {
+		return err
+	}
+	// validate the bounds
+	_, _, err = f.TimeBounds()
+	return err
+}
 
-	return query
+// IsTimestamp returns true if the frequency field is a timestamp or date
+// column.
+func (f *Frequency) IsTimestamp() bool {
+	return strings.HasPrefix(f.FieldType, "timestamp") || f.FieldType == "date"
+}
+
+// AddAggs adds the tiling aggregations to the provided query object. The
+// bucket is selected as the start of each interval in milliseconds since the
+// epoch.
+func (f *Frequency) AddAggs(query *Query) (*Query, error) {
+	bucket, err := f.BucketExpression(query)
+	if err != nil {
+		return nil, err
+	}
+	query.GroupBy(bucket)
+	query.Select(fmt.Sprintf("%s as bucket", bucket))
+	query.Select("COUNT(*) as frequency")
+	return query, nil
 }
 
-// AddQuery adds the tiling query to the provided query object.
-func (f *Frequency) AddQuery(query *Query) *Query {
-	//TODO: Need to cast the frequency fields to a numeric value most likely.
+// BucketExpression returns the expression which selects the start of the
+// interval containing each row, in milliseconds since the epoch. Calendar
+// intervals are truncated in the time zone of the tile.
+func (f *Frequency) BucketExpression(query *Query) (string, error) {
+	field, err := query.Column(f.FrequencyField)
+	if err != nil {
+		return "", err
+	}
+	interval, err := tile.ParseInterval(f.Interval)
+	if err != nil {
+		return "", err
+	}
+	anchor, err := f.Anchor()
+	if err != nil {
+		return "", err
+	}
+	var bucket string
+	if interval.IsCalendar() {
+		// truncate the wall time of the time zone, then convert back
+		unitArg := query.AddParameter(interval.Unit)
+		timeZoneArg := query.AddParameter(f.Location().String())
+		bucket = toMillisExpression(fmt.Sprintf("(date_trunc(%s, %s AT TIME ZONE %s) AT TIME ZONE %s)",
+			unitArg,
+			f.timestampExpression(field),
+			timeZoneArg,
+			timeZoneArg))
+	} else if f.IsTimestamp() {
+		// bin the timestamps relative to the anchor
+		intervalArg := query.AddParameter(fmt.Sprintf("%d milliseconds", millis(interval.Duration)))
+		originArg := query.AddParameter(tile.ToMillis(anchor))
+		bucket = toMillisExpression(fmt.Sprintf("date_bin(CAST(%s AS INTERVAL), %s, to_timestamp(%s / 1000.0))",
+			intervalArg,
+			f.timestampExpression(field),
+			originArg))
+	} else {
+		// bin the milliseconds relative to the anchor
+		intervalArg := query.AddParameter(millis(interval.Duration))
+		originArg := query.AddParameter(tile.ToMillis(anchor))
+		bucket = fmt.Sprintf("CAST(floor((%s - %s) / CAST(%s AS DOUBLE PRECISION)) * %s + %s AS BIGINT)",
+			field,
+			originArg,
+			intervalArg,
+			intervalArg,
+			originArg)
+	}
+	return bucket, nil
+}
 
+// AddQuery adds the tiling query to the provided query object. Numeric bounds
+// are interpreted as milliseconds since the epoch, and string bounds as dates.
+func (f *Frequency) AddQuery(query *Query) (*Query, error) {
+	field, err := query.Column(f.FrequencyField)
+	if err != nil {
+		return nil, err
+	}
 	if f.GTE != nil {
-		parameter := query.AddParameter(f.GTE)
-		query.Where(fmt.Sprintf("%s >= %s", f.FrequencyField, parameter))
+		query.Where(fmt.Sprintf("%s >= %s", field, f.boundParameter(query, f.GTE)))
 	}
 	if f.GT != nil {
-		parameter := query.AddParameter(f.GT)
-		query.Where(fmt.Sprintf("%s > %s", f.FrequencyField, parameter))
+		query.Where(fmt.Sprintf("%s > %s", field, f.boundParameter(query, f.GT)))
 	}
 	if f.LTE != nil {
-		parameter := query.AddParameter(f.LTE)
-		query.Where(fmt.Sprintf("%s <= %s", f.FrequencyField, parameter))
+		query.Where(fmt.Sprintf("%s <= %s", field, f.boundParameter(query, f.LTE)))
 	}
 	if f.LT != nil {
-		parameter := query.AddParameter(f.LT)
-		query.Where(fmt.Sprintf("%s < %s", f.FrequencyField, parameter))
+		query.Where(fmt.Sprintf("%s < %s", field, f.boundParameter(query, f.LT)))
 	}
-	return query
+	return query, nil
 }
 
 // GetBuckets returns the frequency buckets from the query results.
 func (f *Frequency) GetBuckets(rows *pgx.Rows) ([]*FrequencyResult, error) {
-	//Need to build all the buckets over the window since empty buckets are needed.
 	results := make(map[int64]float64)
-	//Parse the results. Build a map to fill in the buckets.
 	for rows.Next() {
 		var bucket int64
 		var frequency int
 		err := rows.Scan(&bucket, &frequency)
 		if err != nil {
-			return nil, fmt.Errorf("Error parsing top terms: %v", err)
+			return nil, fmt.Errorf("Error parsing frequency: %v", err)
 		}
 		results[bucket] = float64(frequency)
 	}
-
 	return f.CreateBuckets(results)
 }
 
-// CreateBuckets creates the frequency buckets, including the empty buckets as defined by the tile params.
+// CreateBuckets creates the frequency buckets, including the empty buckets as
+// defined by the tile params.
 func (f *Frequency) CreateBuckets(results map[int64]float64) ([]*FrequencyResult, error) {
-	//Find the min & max buckets.
-	min, max := int64(math.MaxInt64), int64(math.MinInt64)
-	for k := range results {
-		if k < min {
-			min = k
-		}
-		if k > max {
-			max = k
-		}
-	}
-
-	//Define the window limits.
-	windowStart, windowEnd := int64(0), int64(0)
-	if f.GT != nil {
-		windowStart = castFrequency(f.GT)
-	} else if f.GTE != nil {
-		windowStart = castFrequency(f.GTE)
-	} else {
-		windowStart = min
+	interval, err := tile.ParseInterval(f.Interval)
+	if err != nil {
+		return nil, err
 	}
-	if f.LT != nil {
-		windowEnd = castFrequency(f.LT)
-	} else if f.LTE != nil {
-		windowEnd = castFrequency(f.LTE)
-	} else {
-		windowEnd = max
+	starts := make([]int64, 0, len(results))
+	for bucket := range results {
+		starts = append(starts, bucket)
 	}
-
-	//Create the buckets.
-	intervalNum, err := strconv.ParseFloat(f.Interval, 64)
+	timestamps, err := f.GetBucketTimestamps(interval, starts)
 	if err != nil {
 		return nil, err
 	}
-
-	//May be off by 1 as result of type conversion.
-	numberOfBuckets := int64((windowEnd - windowStart)) / int64(intervalNum)
-	buckets := make([]*FrequencyResult, numberOfBuckets)
-	for i := range buckets {
-		//If value is not in the map, 0 will be returned as default value.
-		bucket := int64(float64(i)*intervalNum) + windowStart
-		frequency := results[bucket]
+	buckets := make([]*FrequencyResult, len(timestamps))
+	for i, timestamp := range timestamps {
+		// missing buckets are empty
 		buckets[i] = &FrequencyResult{
-			Bucket: bucket,
-			Value:  frequency,
+			Bucket: timestamp,
+			Value:  results[timestamp],
 		}
 	}
 	return buckets, nil
@@ -144,16 +182,44 @@ func (f *Frequency) encodeResult(frequency []*FrequencyResult) []map[string]inte
 	return buckets
 }
 
-func castFrequency(val interface{}) int64 {
-	numF, isNum := val.(float64)
-	if isNum {
-		return int64(numF)
+func millis(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
+
+// timestampExpression returns the frequency field as a `timestamptz`.
+// Timestamps without a time zone are interpreted as UTC.
+func (f *Frequency) timestampExpression(field string) string {
+	switch f.FieldType {
+	case "timestamp with time zone":
+		return field
+	case "timestamp without time zone":
+		return fmt.Sprintf("(%s AT TIME ZONE 'UTC')", field)
+	case "date":
+		return fmt.Sprintf("(CAST(%s AS TIMESTAMP) AT TIME ZONE 'UTC')", field)
+	}
+	return fmt.Sprintf("to_timestamp(%s / 1000.0)", field)
+}
+
+// boundParameter adds the range bound to the query, converted to the type of
+// the frequency field.
+func (f *Frequency) boundParameter(query *Query, val interface{}) string {
+	if f.IsTimestamp() {
+		return castTimeParameter(query, val)
+	}
+	if _, isStr := val.(string); isStr {
+		return toMillisExpression(fmt.Sprintf("CAST(%s AS TIMESTAMPTZ)", query.AddParameter(val)))
 	}
-	numI, isNum := val.(int64)
+	return query.AddParameter(val)
+}
+
+func castTimeParameter(query *Query, val interface{}) string {
+	num, isNum := val.(float64)
 	if isNum {
-		return numI
+		return fmt.Sprintf("to_timestamp(%s / 1000.0)", query.AddParameter(num))
 	}
-	// TODO: Figure out which types are allowed, and what to do if bad data is
-	// received.
-	return -1
+	return fmt.Sprintf("CAST(%s AS TIMESTAMPTZ)", query.AddParameter(val))
+}
+
+func toMillisExpression(timestamp string) string {
+	return fmt.Sprintf("CAST(EXTRACT(EPOCH FROM %s) * 1000 AS BIGINT)", timestamp)
 }
@@ -41,13 +41,26 @@ func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// add frequency query
-	citusQuery = t.Frequency.AddQuery(citusQuery)
+	t.Frequency.FieldType, err = t.GetColumnType(client, citusQuery, t.FrequencyField)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery, err = t.Frequency.AddQuery(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// add aggs
-	citusQuery = t.Frequency.AddAggs(citusQuery)
+	citusQuery, err = t.Frequency.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
 	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
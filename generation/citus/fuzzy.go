@@ -0,0 +1,35 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Fuzzy represents a citus fuzzy query. It requires the `fuzzystrmatch`
+// extension, which limits values to 255 characters.
+type Fuzzy struct {
+	query.Fuzzy
+}
+
+// NewFuzzy instantiates and returns a new query struct.
+func NewFuzzy() (veldt.Query, error) {
+	return &Fuzzy{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Fuzzy) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	column := fmt.Sprintf("CAST(%s AS TEXT)", field)
+	valueParam := query.AddParameter(q.Value)
+	if !q.CaseSensitive {
+		column = fmt.Sprintf("lower(%s)", column)
+		valueParam = fmt.Sprintf("lower(%s)", valueParam)
+	}
+	distanceParam := query.AddParameter(q.Distance)
+	return fmt.Sprintf("levenshtein(%s, %s) <= %s", column, valueParam, distanceParam), nil
+}
@@ -0,0 +1,58 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// GeoBBox represents a citus bounding box query. Lon / lat point fields are
+// PostGIS geometry columns in EPSG:4326.
+type GeoBBox struct {
+	query.GeoBBox
+}
+
+// NewGeoBBox instantiates and returns a new query struct.
+func NewGeoBBox() (veldt.Query, error) {
+	return &GeoBBox{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *GeoBBox) Get(query *Query) (string, error) {
+	if q.IsLonLat() {
+		field, err := query.Column(q.Field)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s && ST_MakeEnvelope(%s, %s, %s, %s, 4326)",
+			field,
+			query.AddParameter(q.Bounds.MinX()),
+			query.AddParameter(q.Bounds.MinY()),
+			query.AddParameter(q.Bounds.MaxX()),
+			query.AddParameter(q.Bounds.MaxY())), nil
+	}
+	return planarBounds(query, q.XField, q.YField,
+		q.Bounds.MinX(),
+		q.Bounds.MaxX(),
+		q.Bounds.MinY(),
+		q.Bounds.MaxY())
+}
+
+// planarBounds returns a clause checking that the data-space coordinate
+// columns are within the inclusive bounds.
+func planarBounds(query *Query, xField string, yField string, minX, maxX, minY, maxY float64) (string, error) {
+	x, err := query.Column(xField)
+	if err != nil {
+		return "", err
+	}
+	y, err := query.Column(yField)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s >= %s AND %s <= %s AND %s >= %s AND %s <= %s",
+		x, query.AddParameter(minX),
+		x, query.AddParameter(maxX),
+		y, query.AddParameter(minY),
+		y, query.AddParameter(maxY)), nil
+}
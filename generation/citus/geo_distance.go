@@ -0,0 +1,58 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// GeoDistance represents a citus distance query. Lon / lat point fields are
+// PostGIS geometry columns in EPSG:4326, with the distance in meters.
+type GeoDistance struct {
+	query.GeoDistance
+}
+
+// NewGeoDistance instantiates and returns a new query struct.
+func NewGeoDistance() (veldt.Query, error) {
+	return &GeoDistance{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *GeoDistance) Get(query *Query) (string, error) {
+	if q.IsLonLat() {
+		field, err := query.Column(q.Field)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ST_DWithin(%s::geography, ST_SetSRID(ST_MakePoint(%s, %s), 4326)::geography, %s)",
+			field,
+			query.AddParameter(q.Center.X),
+			query.AddParameter(q.Center.Y),
+			query.AddParameter(q.Distance)), nil
+	}
+	x, err := query.Column(q.XField)
+	if err != nil {
+		return "", err
+	}
+	y, err := query.Column(q.YField)
+	if err != nil {
+		return "", err
+	}
+	// the bounding box of the circle allows the use of column indices
+	bounds, err := planarBounds(query, q.XField, q.YField,
+		q.Center.X-q.Distance,
+		q.Center.X+q.Distance,
+		q.Center.Y-q.Distance,
+		q.Center.Y+q.Distance)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s AND point(%s, %s) <-> point(%s, %s) <= %s",
+		bounds,
+		x,
+		y,
+		query.AddParameter(q.Center.X),
+		query.AddParameter(q.Center.Y),
+		query.AddParameter(q.Distance)), nil
+}
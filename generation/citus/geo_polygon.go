@@ -0,0 +1,76 @@
+package citus
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// GeoPolygon represents a citus point in polygon query. Lon / lat point fields
+// are PostGIS geometry columns in EPSG:4326, data-space coordinate columns
+// are tested using the native postgres geometric types.
+type GeoPolygon struct {
+	query.GeoPolygon
+}
+
+// NewGeoPolygon instantiates and returns a new query struct.
+func NewGeoPolygon() (veldt.Query, error) {
+	return &GeoPolygon{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *GeoPolygon) Get(query *Query) (string, error) {
+	if q.IsLonLat() {
+		field, err := query.Column(q.Field)
+		if err != nil {
+			return "", err
+		}
+		// well-known text polygons must be closed
+		points := make([]string, 0, len(q.Points)+1)
+		for _, point := range q.Points {
+			points = append(points, formatFloat(point.X)+" "+formatFloat(point.Y))
+		}
+		points = append(points, points[0])
+		wkt := fmt.Sprintf("POLYGON((%s))", strings.Join(points, ", "))
+		return fmt.Sprintf("ST_Covers(ST_GeomFromText(%s, 4326), %s)",
+			query.AddParameter(wkt),
+			field), nil
+	}
+	x, err := query.Column(q.XField)
+	if err != nil {
+		return "", err
+	}
+	y, err := query.Column(q.YField)
+	if err != nil {
+		return "", err
+	}
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	points := make([]string, 0, len(q.Points))
+	for _, point := range q.Points {
+		points = append(points, "("+formatFloat(point.X)+","+formatFloat(point.Y)+")")
+		minX = math.Min(minX, point.X)
+		maxX = math.Max(maxX, point.X)
+		minY = math.Min(minY, point.Y)
+		maxY = math.Max(maxY, point.Y)
+	}
+	// the bounding box of the polygon allows the use of column indices
+	bounds, err := planarBounds(query, q.XField, q.YField, minX, maxX, minY, maxY)
+	if err != nil {
+		return "", err
+	}
+	polygon := fmt.Sprintf("(%s)", strings.Join(points, ","))
+	return fmt.Sprintf("%s AND %s::polygon @> point(%s, %s)",
+		bounds,
+		query.AddParameter(polygon),
+		x,
+		y), nil
+}
+
+func formatFloat(val float64) string {
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
@@ -21,6 +21,10 @@ func NewHas() (veldt.Query, error) {
 func (q *Has) Get(query *Query) (string, error) {
 	// Check that the array contains the values.
 	// Use the column && ARRAY[value1, value2] notation.
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
 	clause := ""
 
 	//Generate the array values.
@@ -30,6 +34,6 @@ func (q *Has) Get(query *Query) (string, error) {
 	}
 
 	//Remove the leading ", " from the array contents.
-	clause = fmt.Sprintf("%s && ARRAY[%s]", q.Field, clause[2:])
+	clause = fmt.Sprintf("%s && ARRAY[%s]", field, clause[2:])
 	return clause, nil
 }
@@ -27,6 +27,11 @@ func (h *HeatmapTile) Parse(params map[string]interface{}) error {
 	return h.Bivariate.Parse(params)
 }
 
+// Empty returns the tile containing no data.
+func (h *HeatmapTile) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return make([]byte, h.Resolution*h.Resolution*4), nil
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
@@ -37,10 +42,16 @@ func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Q
 	}
 
 	// add tiling query
-	citusQuery = h.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = h.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// add aggs
-	citusQuery = h.Bivariate.AddAggs(coord, citusQuery)
+	citusQuery, err = h.Bivariate.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	//May support AVG (& others) in the future. May as well make it a float for now.
 	citusQuery.Select("CAST(COUNT(*) AS FLOAT) AS value")
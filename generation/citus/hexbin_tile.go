@@ -0,0 +1,103 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// HexbinTile represents a citus implementation of the hexbin tile.
+type HexbinTile struct {
+	Bivariate
+	Tile
+	tile.Hexbin
+}
+
+// NewHexbinTile instantiates and returns a new tile struct.
+func NewHexbinTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		h := &HexbinTile{}
+		h.Config = cfg
+		return h, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (h *HexbinTile) Parse(params map[string]interface{}) error {
+	err := h.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return h.Hexbin.Parse(params)
+}
+
+// QueryBivariate returns the bivariate tile padded to cover the hexagons on the
+// boundaries of the tile coord, whose bounds are those of the data queried.
+func (h *HexbinTile) QueryBivariate(coord *binning.TileCoord) *tile.Bivariate {
+	return h.Bivariate.PaddedBivariate(coord, h.Hexbin.Padding(h.Resolution))
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (h *HexbinTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, citusQuery, err := h.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// pad the tile to cover the hexagons on its boundaries
+	padding := h.Hexbin.Padding(h.Resolution)
+	padded := &Bivariate{Bivariate: *h.QueryBivariate(coord)}
+
+	// add tiling query
+	citusQuery, err = padded.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// add aggs
+	citusQuery, err = padded.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	citusQuery.Select("CAST(COUNT(*) AS FLOAT) AS value")
+	if h.Metric != tile.CountMetric {
+		valueField, err := citusQuery.Column(h.ValueField)
+		if err != nil {
+			return nil, err
+		}
+		citusQuery.Select(fmt.Sprintf("CAST(COALESCE(SUM(%s), 0) AS FLOAT) AS sum", valueField))
+	} else {
+		citusQuery.Select("CAST(0 AS FLOAT) AS sum")
+	}
+
+	// send query
+	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// get the sub-bin values
+	counts := make([]float64, padded.Resolution*padded.Resolution)
+	sums := make([]float64, padded.Resolution*padded.Resolution)
+	for res.Next() {
+		var x, y int64
+		var count, sum float64
+		err := res.Scan(&x, &y, &count, &sum)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing histogram aggregation: %v", err)
+		}
+		index := padded.GetXBin(coord, float64(x)) + padded.Resolution*padded.GetYBin(coord, float64(y))
+		counts[index] += count
+		sums[index] += sum
+	}
+
+	// aggregate into hexagons
+	hexes := h.Hexbin.AggregateBins(coord, h.Resolution, padding, counts, sums)
+	return h.Hexbin.Encode(hexes), nil
+}
@@ -0,0 +1,89 @@
+package citus
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// MacroEdgeTile represents a citus implementation of the macro edge tile.
+type MacroEdgeTile struct {
+	Edge
+	Tile
+	TopHits
+	tile.MacroEdge
+}
+
+// NewMacroEdgeTile instantiates and returns a new tile struct.
+func NewMacroEdgeTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		e := &MacroEdgeTile{}
+		e.Config = cfg
+		return e, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (e *MacroEdgeTile) Parse(params map[string]interface{}) error {
+	err := e.Edge.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = e.TopHits.Parse(params)
+	if err != nil {
+		return err
+	}
+	// parse includes
+	e.TopHits.IncludeFields = e.MacroEdge.ParseIncludes(
+		e.TopHits.IncludeFields,
+		e.Edge.SrcXField,
+		e.Edge.SrcYField,
+		e.Edge.DstXField,
+		e.Edge.DstYField,
+		e.Edge.WeightField)
+	return e.MacroEdge.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (e *MacroEdgeTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, citusQuery, err := e.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	citusQuery, err = e.Edge.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// get aggs
+	citusQuery, err = e.TopHits.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// send query
+	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// get top hits
+	hits, err := e.TopHits.GetTopHits(res)
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to edge array
+	edges, err := e.Edge.GetEdges(coord, hits)
+	if err != nil {
+		return nil, err
+	}
+
+	// encode and return results
+	return e.MacroEdge.Encode(edges)
+}
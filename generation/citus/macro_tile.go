@@ -43,10 +43,16 @@ func (m *MacroTile) Create(uri string, coord *binning.TileCoord, query veldt.Que
 	}
 
 	// add tiling query
-	citusQuery = m.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = m.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// add aggs
-	citusQuery = m.Bivariate.AddAggs(coord, citusQuery)
+	citusQuery, err = m.Bivariate.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	citusQuery.Select("CAST(COUNT(*) AS FLOAT) AS value")
 
@@ -0,0 +1,55 @@
+package citus
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+var (
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+// MatchesString represents a citus string query. The match string is tested
+// as a case-insensitive substring with ILIKE, unless the query is flagged as a
+// regular expression, in which case it is tested as a case-insensitive
+// regular expression. The query matches if any of the fields match.
+type MatchesString struct {
+	query.MatchesString
+}
+
+// NewMatchesString instantiates and returns a new query struct.
+func NewMatchesString() (veldt.Query, error) {
+	return &MatchesString{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *MatchesString) Get(query *Query) (string, error) {
+	if len(q.Fields) == 0 {
+		return "", fmt.Errorf("`fields` must contain at least one field")
+	}
+	var operator, matchParam string
+	if q.Regex {
+		_, err := regexp.Compile(q.Match)
+		if err != nil {
+			return "", fmt.Errorf("`match` is not a valid regular expression: %v", err)
+		}
+		operator = "~*"
+		matchParam = query.AddParameter(q.Match)
+	} else {
+		operator = "ILIKE"
+		matchParam = query.AddParameter("%" + likeEscaper.Replace(q.Match) + "%")
+	}
+	clauses := make([]string, len(q.Fields))
+	for i, field := range q.Fields {
+		column, err := query.Column(field)
+		if err != nil {
+			return "", err
+		}
+		clauses[i] = fmt.Sprintf("CAST(%s AS TEXT) %s %s", column, operator, matchParam)
+	}
+	return fmt.Sprintf("(%s)", strings.Join(clauses, " OR ")), nil
+}
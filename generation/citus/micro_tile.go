@@ -57,10 +57,16 @@ func (m *MicroTile) Create(uri string, coord *binning.TileCoord, query veldt.Que
 	}
 
 	// add tiling query
-	citusQuery = m.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = m.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get aggs
-	citusQuery = m.TopHits.AddAggs(citusQuery)
+	citusQuery, err = m.TopHits.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
 	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
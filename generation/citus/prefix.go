@@ -0,0 +1,36 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Prefix represents a citus prefix query using LIKE, or ILIKE if
+// case-insensitive.
+type Prefix struct {
+	query.Prefix
+}
+
+// NewPrefix instantiates and returns a new query struct.
+func NewPrefix() (veldt.Query, error) {
+	return &Prefix{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Prefix) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	valueParam := query.AddParameter(likeEscaper.Replace(q.Value) + "%")
+	return fmt.Sprintf("CAST(%s AS TEXT) %s %s", field, likeOperator(q.CaseSensitive), valueParam), nil
+}
+
+func likeOperator(caseSensitive bool) string {
+	if caseSensitive {
+		return "LIKE"
+	}
+	return "ILIKE"
+}
@@ -4,6 +4,13 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
+)
+
+const (
+	// maxIdentifierLength is the maximum length in bytes of a postgres
+	// identifier.
+	maxIdentifierLength = 63
 )
 
 // QueryString represents a citus implementation of the veldt.Query interface.
@@ -20,6 +27,8 @@ type Query struct {
 	Tables         []string
 	OrderByClauses []string
 	RowLimit       uint32
+	allowedTables  map[string]bool
+	allowedColumns map[string]bool
 }
 
 // NewQuery instantiates and returns a new query object.
@@ -99,3 +108,76 @@ func (q *Query) OrderBy(clause string) {
 func (q *Query) Limit(limit uint32) {
 	q.RowLimit = limit
 }
+
+// Allow restricts the tables and columns which may be referenced by the
+// query. A nil slice allows any identifier of that kind.
+func (q *Query) Allow(tables []string, columns []string) {
+	q.allowedTables = toSet(tables)
+	q.allowedColumns = toSet(columns)
+}
+
+// Table validates the provided table name against the allow-list and returns
+// it as a quoted identifier. The name may be qualified by a schema.
+func (q *Query) Table(name string) (string, error) {
+	if q.allowedTables != nil && !q.allowedTables[foldIdentifier(name)] {
+		return "", fmt.Errorf("table `%s` is not allowed", name)
+	}
+	return QuoteIdentifier(name)
+}
+
+// Column validates the provided column name against the allow-list and
+// returns it as a quoted identifier. The name may be qualified by a table.
+func (q *Query) Column(name string) (string, error) {
+	if q.allowedColumns != nil && !q.allowedColumns[foldIdentifier(name)] {
+		return "", fmt.Errorf("column `%s` is not allowed", name)
+	}
+	return QuoteIdentifier(name)
+}
+
+// QuoteIdentifier validates the provided, optionally period delimited,
+// identifier and returns it with each part double quoted. Quoted identifiers
+// are case sensitive, so the identifier is first folded to lower case as
+// postgres does for unquoted identifiers. Identifiers therefore remain case
+// insensitive, and columns created with upper case quoted names can not be
+// referenced.
+func QuoteIdentifier(name string) (string, error) {
+	parts := strings.Split(foldIdentifier(name), ".")
+	for i, part := range parts {
+		if part == "" {
+			return "", fmt.Errorf("identifier `%s` contains an empty part", name)
+		}
+		if len(part) > maxIdentifierLength {
+			return "", fmt.Errorf("identifier `%s` exceeds %d bytes", name, maxIdentifierLength)
+		}
+		for _, r := range part {
+			if r == unicode.ReplacementChar || unicode.IsControl(r) {
+				return "", fmt.Errorf("identifier `%s` contains an invalid character", name)
+			}
+		}
+		parts[i] = `"` + strings.Replace(part, `"`, `""`, -1) + `"`
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// foldIdentifier folds the ASCII letters of the identifier to lower case, as
+// postgres does for unquoted identifiers.
+func foldIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r + ('a' - 'A')
+		}
+		return r
+	}, name)
+}
+
+// toSet returns the set of the folded identifiers.
+func toSet(values []string) map[string]bool {
+	if values == nil {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[foldIdentifier(value)] = true
+	}
+	return set
+}
@@ -0,0 +1,369 @@
+package citus_test
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var (
+	// matches a, optionally period delimited, quoted identifier
+	quoted = regexp.MustCompile(`"(?:[^"]|"")*"(?:\."(?:[^"]|"")*")*`)
+)
+
+// skeleton returns the structure of the query with all quoted identifiers
+// replaced by a placeholder.
+func skeleton(sql string) string {
+	return quoted.ReplaceAllString(sql, "IDENT")
+}
+
+// randomName returns a random string composed of characters commonly used to
+// break out of identifiers.
+func randomName(r *rand.Rand) string {
+	alphabet := []rune("abcXYZ_09 \"'`;-/*().,=$\\[]{}\t\nå∂ñ")
+	name := make([]rune, 1+r.Intn(16))
+	for i := range name {
+		name[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return string(name)
+}
+
+var _ = Describe("Query", func() {
+
+	Describe("QuoteIdentifier", func() {
+		It("should quote each part of the identifier", func() {
+			id, err := citus.QuoteIdentifier("public.points")
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal(`"public"."points"`))
+		})
+
+		It("should escape double quotes", func() {
+			id, err := citus.QuoteIdentifier(`x" = 1; DROP TABLE points; --`)
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal(`"x"" = 1; drop table points; --"`))
+		})
+
+		It("should fold the identifier to lower case as if it were unquoted", func() {
+			id, err := citus.QuoteIdentifier("Public.TweetCount")
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal(`"public"."tweetcount"`))
+			id, err = citus.QuoteIdentifier("Größe")
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal(`"größe"`))
+		})
+
+		It("should return an error for invalid identifiers", func() {
+			for _, name := range []string{"", "a..b", "a.", "a\x00b", strings.Repeat("a", 64)} {
+				_, err := citus.QuoteIdentifier(name)
+				Expect(err).NotTo(BeNil(), fmt.Sprintf("%q", name))
+			}
+		})
+	})
+
+	Describe("Allow", func() {
+		It("should only allow the listed tables and columns", func() {
+			query, _ := citus.NewQuery()
+			query.Allow([]string{"public.points"}, []string{"x"})
+			_, err := query.Table("public.points")
+			Expect(err).To(BeNil())
+			_, err = query.Table("public.users")
+			Expect(err).NotTo(BeNil())
+			_, err = query.Column("x")
+			Expect(err).To(BeNil())
+			_, err = query.Column("password")
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should match the allow-list case insensitively", func() {
+			query, _ := citus.NewQuery()
+			query.Allow([]string{"Public.Points"}, []string{"userName"})
+			table, err := query.Table("public.POINTS")
+			Expect(err).To(BeNil())
+			Expect(table).To(Equal(`"public"."points"`))
+			column, err := query.Column("UserName")
+			Expect(err).To(BeNil())
+			Expect(column).To(Equal(`"username"`))
+		})
+
+		It("should allow any identifier when no list is provided", func() {
+			query, _ := citus.NewQuery()
+			query.Allow(nil, nil)
+			_, err := query.Column("anything")
+			Expect(err).To(BeNil())
+		})
+
+		It("should reject query fields outside of the allow-list", func() {
+			query, _ := citus.NewQuery()
+			query.Allow(nil, []string{"x"})
+			q := &citus.Equals{}
+			q.Parse(JSON(`{"field": "password", "value": "secret"}`))
+			_, err := q.Get(query)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("NaryExpression", func() {
+		It("should join the queries without nesting", func() {
+			query, _ := citus.NewQuery()
+			queries := make([]veldt.Query, 3)
+			for i, field := range []string{"a", "b", "c"} {
+				q, _ := citus.NewExists()
+				q.(*citus.Exists).Field = field
+				queries[i] = q
+			}
+			nary, _ := citus.NewNaryExpression()
+			err := nary.Parse(map[string]interface{}{
+				"op":      veldt.Or,
+				"queries": queries,
+			})
+			Expect(err).To(BeNil())
+			sql, err := nary.(citus.QueryString).Get(query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(`(("a" IS NOT NULL) OR ("b" IS NOT NULL) OR ("c" IS NOT NULL))`))
+		})
+	})
+
+	Describe("Geo", func() {
+		It("should build a PostGIS envelope query for lon / lat fields", func() {
+			query, _ := citus.NewQuery()
+			q := &citus.GeoBBox{}
+			err := q.Parse(JSON(`{
+				"field": "location",
+				"bounds": { "left": -80.0, "right": -70.0, "bottom": 40.0, "top": 45.0 }
+			}`))
+			Expect(err).To(BeNil())
+			sql, err := q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(`"location" && ST_MakeEnvelope($1, $2, $3, $4, 4326)`))
+			Expect(query.QueryArgs).To(Equal([]interface{}{-80.0, 40.0, -70.0, 45.0}))
+		})
+
+		It("should build a range query for data-space fields", func() {
+			query, _ := citus.NewQuery()
+			q := &citus.GeoBBox{}
+			err := q.Parse(JSON(`{
+				"xField": "x",
+				"yField": "y",
+				"bounds": { "left": 10.0, "right": 0.0, "bottom": 0.0, "top": 10.0 }
+			}`))
+			Expect(err).To(BeNil())
+			sql, err := q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(`"x" >= $1 AND "x" <= $2 AND "y" >= $3 AND "y" <= $4`))
+			Expect(query.QueryArgs).To(Equal([]interface{}{0.0, 10.0, 0.0, 10.0}))
+		})
+
+		It("should build a closed well-known text polygon for lon / lat fields", func() {
+			query, _ := citus.NewQuery()
+			q := &citus.GeoPolygon{}
+			err := q.Parse(JSON(`{
+				"field": "location",
+				"points": [
+					{ "x": -80.0, "y": 40.0 },
+					{ "x": -70.5, "y": 40.0 },
+					{ "x": -75.0, "y": 45.0 }
+				]
+			}`))
+			Expect(err).To(BeNil())
+			sql, err := q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(`ST_Covers(ST_GeomFromText($1, 4326), "location")`))
+			Expect(query.QueryArgs).To(Equal([]interface{}{
+				"POLYGON((-80 40, -70.5 40, -75 45, -80 40))",
+			}))
+		})
+
+		It("should build a planar point in polygon query for data-space fields", func() {
+			query, _ := citus.NewQuery()
+			q := &citus.GeoPolygon{}
+			err := q.Parse(JSON(`{
+				"xField": "x",
+				"yField": "y",
+				"points": [
+					{ "x": 0.0, "y": 0.0 },
+					{ "x": 10.0, "y": 0.0 },
+					{ "x": 5.0, "y": 10.0 }
+				]
+			}`))
+			Expect(err).To(BeNil())
+			sql, err := q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(HaveSuffix(`AND $5::polygon @> point("x", "y")`))
+			Expect(query.QueryArgs[4]).To(Equal("((0,0),(10,0),(5,10))"))
+		})
+
+		It("should build a distance query for lon / lat and data-space fields", func() {
+			query, _ := citus.NewQuery()
+			q := &citus.GeoDistance{}
+			err := q.Parse(JSON(`{
+				"field": "location",
+				"center": { "x": -75.0, "y": 45.0 },
+				"distance": 1000.0
+			}`))
+			Expect(err).To(BeNil())
+			sql, err := q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(`ST_DWithin("location"::geography, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3)`))
+
+			query, _ = citus.NewQuery()
+			q = &citus.GeoDistance{}
+			err = q.Parse(JSON(`{
+				"xField": "x",
+				"yField": "y",
+				"center": { "x": 5.0, "y": 5.0 },
+				"distance": 2.0
+			}`))
+			Expect(err).To(BeNil())
+			sql, err = q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(HaveSuffix(`AND point("x", "y") <-> point($5, $6) <= $7`))
+			Expect(query.QueryArgs[:4]).To(Equal([]interface{}{3.0, 7.0, 3.0, 7.0}))
+		})
+
+		It("should reject geo fields outside of the allow-list", func() {
+			query, _ := citus.NewQuery()
+			query.Allow(nil, []string{"x", "y"})
+			q := &citus.GeoBBox{}
+			q.Parse(JSON(`{
+				"xField": "x",
+				"yField": "password",
+				"bounds": { "left": 0.0, "right": 1.0, "bottom": 0.0, "top": 1.0 }
+			}`))
+			_, err := q.Get(query)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Injection", func() {
+
+		// build returns the sql of a tile query built from the provided
+		// identifiers.
+		build := func(table string, field string) (string, error) {
+			query, _ := citus.NewQuery()
+			name, err := query.Table(table)
+			if err != nil {
+				return "", err
+			}
+			query.From(name)
+			// filter query
+			equals, _ := citus.NewEquals()
+			equals.(*citus.Equals).Field = field
+			equals.(*citus.Equals).Value = "a"
+			rng, _ := citus.NewRange()
+			rng.(*citus.Range).Field = field
+			rng.(*citus.Range).GTE = 1.0
+			rng.(*citus.Range).LT = 2.0
+			exists, _ := citus.NewExists()
+			exists.(*citus.Exists).Field = field
+			expr := &citus.BinaryExpression{}
+			expr.Left = equals
+			expr.Right = &citus.BinaryExpression{
+				BinaryExpression: veldt.BinaryExpression{
+					Left:  rng,
+					Right: exists,
+					Op:    veldt.Or,
+				},
+			}
+			expr.Op = veldt.And
+			clause, err := expr.Get(query)
+			if err != nil {
+				return "", err
+			}
+			query.Where(clause)
+			// tile query and aggs
+			bivariate := &citus.Bivariate{}
+			err = bivariate.Parse(map[string]interface{}{
+				"xField": field,
+				"yField": field,
+				"left":   0.0,
+				"right":  256.0,
+				"bottom": 0.0,
+				"top":    256.0,
+			})
+			if err != nil {
+				return "", err
+			}
+			coord := &binning.TileCoord{X: 0, Y: 0, Z: 0}
+			_, err = bivariate.AddQuery(coord, query)
+			if err != nil {
+				return "", err
+			}
+			_, err = bivariate.AddAggs(coord, query)
+			if err != nil {
+				return "", err
+			}
+			terms := &citus.TopTerms{}
+			terms.TermsField = field
+			terms.TermsCount = 10
+			_, err = terms.AddAggs(query)
+			if err != nil {
+				return "", err
+			}
+			frequency := &citus.Frequency{}
+			frequency.FrequencyField = field
+			frequency.Interval = "1000"
+			_, err = frequency.AddQuery(query)
+			if err != nil {
+				return "", err
+			}
+			_, err = frequency.AddAggs(query)
+			if err != nil {
+				return "", err
+			}
+			hits := &citus.TopHits{}
+			hits.IncludeFields = []string{field}
+			hits.SortField = field
+			_, err = hits.AddAggs(query)
+			if err != nil {
+				return "", err
+			}
+			return query.GetQuery(false), nil
+		}
+
+		It("should not allow request identifiers to alter the query structure", func() {
+			expected, err := build("public.points", "x")
+			Expect(err).To(BeNil())
+			r := rand.New(rand.NewSource(1))
+			accepted := 0
+			for i := 0; i < 2000; i++ {
+				table := randomName(r)
+				field := randomName(r)
+				sql, err := build(table, field)
+				if err != nil {
+					// rejected identifiers never reach the query
+					continue
+				}
+				accepted++
+				Expect(skeleton(sql)).To(Equal(skeleton(expected)),
+					fmt.Sprintf("table: %q, field: %q", table, field))
+			}
+			Expect(accepted).To(BeNumerically(">", 100))
+		})
+
+		It("should not allow request JSON to alter the query structure", func() {
+			expected, err := build("points", "x")
+			Expect(err).To(BeNil())
+			for _, payload := range []string{
+				`x; DROP TABLE points; --`,
+				`x\" = 1 OR \"1\" = \"1`,
+				`x) OR (1 = 1`,
+				`x/* comment */`,
+				`\"x\".\"y\"`,
+			} {
+				params := JSON(fmt.Sprintf(`{"field": "%s"}`, payload))
+				sql, err := build("points", params["field"].(string))
+				Expect(err).To(BeNil())
+				Expect(skeleton(sql)).To(Equal(skeleton(expected)), payload)
+			}
+		})
+	})
+})
@@ -0,0 +1,32 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Regexp represents a citus regular expression query, anchored at both ends.
+type Regexp struct {
+	query.Regexp
+}
+
+// NewRegexp instantiates and returns a new query struct.
+func NewRegexp() (veldt.Query, error) {
+	return &Regexp{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Regexp) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	operator := "~"
+	if !q.CaseSensitive {
+		operator = "~*"
+	}
+	valueParam := query.AddParameter("^(" + q.Value + ")$")
+	return fmt.Sprintf("CAST(%s AS TEXT) %s %s", field, operator, valueParam), nil
+}
@@ -0,0 +1,111 @@
+package citus_test
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var (
+	patternClause     = regexp.MustCompile(`^CAST\("name" AS TEXT\) (LIKE|ILIKE|~|~\*) \$1$`)
+	levenshteinClause = regexp.MustCompile(`^levenshtein\((lower\()?CAST\("name" AS TEXT\)\)?, (lower\()?\$1\)?\) <= \$2$`)
+)
+
+// likePattern returns an anchored go regular expression equivalent to the
+// postgres LIKE pattern.
+func likePattern(pattern string, caseSensitive bool) *regexp.Regexp {
+	expr := ""
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			expr += regexp.QuoteMeta(string(r))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			expr += ".*"
+		case r == '_':
+			expr += "."
+		default:
+			expr += regexp.QuoteMeta(string(r))
+		}
+	}
+	flags := "s"
+	if !caseSensitive {
+		flags += "i"
+	}
+	return regexp.MustCompile("^(?" + flags + ":" + expr + ")$")
+}
+
+// evaluate returns whether the where clause matches the value of the `name`
+// column.
+func evaluate(clause string, args []interface{}, value string) bool {
+	if match := patternClause.FindStringSubmatch(clause); match != nil {
+		pattern := args[0].(string)
+		switch match[1] {
+		case "LIKE":
+			return likePattern(pattern, true).MatchString(value)
+		case "ILIKE":
+			return likePattern(pattern, false).MatchString(value)
+		case "~":
+			return regexp.MustCompile("(?s)" + pattern).MatchString(value)
+		case "~*":
+			return regexp.MustCompile("(?si)" + pattern).MatchString(value)
+		}
+	}
+	if match := levenshteinClause.FindStringSubmatch(clause); match != nil {
+		target := args[0].(string)
+		if match[1] != "" {
+			value = strings.ToLower(value)
+		}
+		if match[2] != "" {
+			target = strings.ToLower(target)
+		}
+		return Levenshtein(target, value) <= args[1].(int)
+	}
+	Fail("unexpected clause `" + clause + "`")
+	return false
+}
+
+var _ = Describe("String queries", func() {
+
+	ctors := map[string]veldt.QueryCtor{
+		"prefix":   citus.NewPrefix,
+		"wildcard": citus.NewWildcard,
+		"regexp":   citus.NewRegexp,
+		"fuzzy":    citus.NewFuzzy,
+	}
+
+	for _, c := range StringQueryCases {
+		c := c
+		It("should conform: "+c.Name, func() {
+			q, err := ctors[c.Query]()
+			Expect(err).To(BeNil())
+			err = q.Parse(JSON(c.Params))
+			Expect(err).To(BeNil())
+			query, _ := citus.NewQuery()
+			clause, err := q.(citus.QueryString).Get(query)
+			for _, backend := range c.Unsupported {
+				if backend == "citus" {
+					Expect(err).NotTo(BeNil())
+					return
+				}
+			}
+			Expect(err).To(BeNil())
+			for _, value := range c.Matches {
+				Expect(evaluate(clause, query.QueryArgs, value)).To(BeTrue(), value)
+			}
+			for _, value := range c.Misses {
+				Expect(evaluate(clause, query.QueryArgs, value)).To(BeFalse(), value)
+			}
+		})
+	}
+
+})
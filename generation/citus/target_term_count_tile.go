@@ -42,10 +42,16 @@ func (t *TargetTermCountTile) Create(uri string, coord *binning.TileCoord, query
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get aggs
-	citusQuery = t.TargetTerms.AddAggs(citusQuery)
+	citusQuery, err = t.TargetTerms.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
 	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
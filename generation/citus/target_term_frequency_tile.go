@@ -32,6 +32,10 @@ func (t *TargetTermFrequencyTile) Parse(params map[string]interface{}) error {
 	if err != nil {
 		return err
 	}
+	err = t.Frequency.Parse(params)
+	if err != nil {
+		return err
+	}
 	return t.TargetTerms.Parse(params)
 }
 
@@ -45,12 +49,30 @@ func (t *TargetTermFrequencyTile) Create(uri string, coord *binning.TileCoord, q
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// add frequency query
+	t.Frequency.FieldType, err = t.GetColumnType(client, citusQuery, t.Frequency.FrequencyField)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery, err = t.Frequency.AddQuery(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get aggs
-	citusQuery.Select(t.Frequency.FrequencyField)
-	citusQuery = t.TargetTerms.AddAggs(citusQuery)
-	citusQuery = t.Frequency.AddAggs(citusQuery)
+	citusQuery, err = t.TargetTerms.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery, err = t.Frequency.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
 	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
@@ -72,7 +94,9 @@ func (t *TargetTermFrequencyTile) Create(uri string, coord *binning.TileCoord, q
 		if err != nil {
 			return nil, fmt.Errorf("Error parsing top terms: %v", err)
 		}
-		// TODO: May need to do some checking to see if things exist already.
+		if _, ok := rawResults[term]; !ok {
+			rawResults[term] = make(map[int64]float64)
+		}
 		rawResults[term][bucket] = float64(frequency)
 	}
 
@@ -14,7 +14,7 @@ type TargetTerms struct {
 }
 
 // AddQuery adds the tiling query to the provided query object.
-func (t *TargetTerms) AddQuery(query *Query) *Query {
+func (t *TargetTerms) AddQuery(query *Query) (*Query, error) {
 	//Want to keep only documents that have the specified terms.
 	//Use the already existing Has construct.
 	hasQuery := &Has{}
@@ -25,16 +25,23 @@ func (t *TargetTerms) AddQuery(query *Query) *Query {
 	}
 	hasQuery.Values = terms
 
-	clause, _ := hasQuery.Get(query)
+	clause, err := hasQuery.Get(query)
+	if err != nil {
+		return nil, err
+	}
 	query.Where(clause)
-	return query
+	return query, nil
 }
 
 // AddAggs adds the tiling aggregations to the provided query object.
-func (t *TargetTerms) AddAggs(query *Query) *Query {
+func (t *TargetTerms) AddAggs(query *Query) (*Query, error) {
+	field, err := query.Column(t.TermsField)
+	if err != nil {
+		return nil, err
+	}
 	//Count by term, only considering the specified terms.
 	//Assume the backing field is an array. Need to unpack that array and group by the terms.
-	query.Select(fmt.Sprintf("unnest(%s) AS term", t.TermsField))
+	query.Select(fmt.Sprintf("unnest(%s) AS term", field))
 
 	query.GroupBy("term")
 	query.Select("COUNT(*) as term_count")
@@ -48,7 +55,7 @@ func (t *TargetTerms) AddAggs(query *Query) *Query {
 	}
 	query.Where(fmt.Sprintf("term IN [%s]", clause[2:]))
 
-	return query
+	return query, nil
 }
 
 // GetTerms parses the result of the terms query into a map of term -> count.
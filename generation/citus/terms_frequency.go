@@ -14,22 +14,26 @@ type TermsFrequency struct {
 }
 
 // AddAggs adds the tiling aggregations to the provided query object.
-func (t *TermsFrequency) AddAggs(query *Query) *Query {
+func (t *TermsFrequency) AddAggs(query *Query) (*Query, error) {
+	field, err := query.Column(t.TermsField)
+	if err != nil {
+		return nil, err
+	}
 
 	//Count by term
 	if t.FieldType == "string" {
-		query.Select(fmt.Sprintf("%s AS term", t.TermsField))
-		query.Where(fmt.Sprintf("%s IS NOT NULL", t.TermsField))
+		query.Select(fmt.Sprintf("%s AS term", field))
+		query.Where(fmt.Sprintf("%s IS NOT NULL", field))
 	} else {
 		//Assume the backing field is an array. Need to unpack that array and group by the terms.
-		query.Select(fmt.Sprintf("unnest(%s) AS term", t.TermsField))
+		query.Select(fmt.Sprintf("unnest(%s) AS term", field))
 	}
 
 	query.GroupBy("term")
 	query.Select("COUNT(*) as term_count")
 	query.OrderBy("term_count desc")
 
-	return query
+	return query, nil
 }
 
 // GetTerms parses the result of the terms query into a map of term -> count.
@@ -40,10 +40,16 @@ func (t *TermsFrequencyCountTile) Create(uri string, coord *binning.TileCoord, q
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get aggs
-	citusQuery = t.TermsFrequency.AddAggs(citusQuery)
+	citusQuery, err = t.TermsFrequency.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
 	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
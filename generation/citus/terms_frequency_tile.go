@@ -31,6 +31,10 @@ func (t *TermsFrequencyTile) Parse(params map[string]interface{}) error {
 	if err != nil {
 		return err
 	}
+	err = t.Frequency.Parse(params)
+	if err != nil {
+		return err
+	}
 	return t.TermsFrequency.Parse(params)
 }
 
@@ -43,12 +47,30 @@ func (t *TermsFrequencyTile) Create(uri string, coord *binning.TileCoord, query
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// add frequency query
+	t.Frequency.FieldType, err = t.GetColumnType(client, citusQuery, t.Frequency.FrequencyField)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery, err = t.Frequency.AddQuery(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get aggs
-	citusQuery.Select(t.Frequency.FrequencyField)
-	citusQuery = t.TermsFrequency.AddAggs(citusQuery)
-	citusQuery = t.Frequency.AddAggs(citusQuery)
+	citusQuery, err = t.TermsFrequency.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery, err = t.Frequency.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
 	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
@@ -70,7 +92,9 @@ func (t *TermsFrequencyTile) Create(uri string, coord *binning.TileCoord, query
 		if err != nil {
 			return nil, fmt.Errorf("Error parsing top terms: %v", err)
 		}
-		// TODO: May need to do some checking to see if things exist already.
+		if _, ok := rawResults[term]; !ok {
+			rawResults[term] = make(map[int64]float64)
+		}
 		rawResults[term][bucket] = float64(frequency)
 	}
 
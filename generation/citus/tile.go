@@ -2,11 +2,17 @@ package citus
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/jackc/pgx"
 	"github.com/unchartedsoftware/veldt"
 )
 
+var (
+	columnTypesMutex = sync.Mutex{}
+	columnTypes      = make(map[string]string)
+)
+
 // Tile represents an citus tile type.
 type Tile struct {
 	Config *Config
@@ -19,6 +25,9 @@ func (t *Tile) CreateQuery(query veldt.Query) (*Query, error) {
 	if err != nil {
 		return nil, err
 	}
+	if t.Config != nil {
+		root.Allow(t.Config.AllowedTables, t.Config.AllowedColumns)
+	}
 
 	// add filter query
 	if query != nil {
@@ -51,6 +60,47 @@ func (t *Tile) InitializeTile(uri string, query veldt.Query) (*pgx.ConnPool, *Qu
 	if err != nil {
 		return nil, nil, err
 	}
-	citusQuery.From(uri)
+	table, err := citusQuery.Table(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	citusQuery.From(table)
 	return client, citusQuery, nil
 }
+
+// GetColumnType returns the postgres type of the provided field within the
+// table of the query. Types are cached per database, table and column. An
+// empty string is returned if the table contains no rows.
+func (t *Tile) GetColumnType(client *pgx.ConnPool, query *Query, field string) (string, error) {
+	if len(query.Tables) == 0 {
+		return "", fmt.Errorf("query has no table")
+	}
+	table := query.Tables[0]
+	column, err := query.Column(field)
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s/%s/%s", t.Config.key(), table, column)
+	columnTypesMutex.Lock()
+	typ, ok := columnTypes[key]
+	columnTypesMutex.Unlock()
+	if ok {
+		return typ, nil
+	}
+	rows, err := client.Query(fmt.Sprintf("SELECT CAST(pg_typeof(%s) AS TEXT) FROM %s LIMIT 1;", column, table))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return "", rows.Err()
+	}
+	err = rows.Scan(&typ)
+	if err != nil {
+		return "", err
+	}
+	columnTypesMutex.Lock()
+	columnTypes[key] = typ
+	columnTypesMutex.Unlock()
+	return typ, nil
+}
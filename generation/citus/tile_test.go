@@ -0,0 +1,240 @@
+package citus_test
+
+import (
+	"strconv"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Tile", func() {
+
+	var query *citus.Query
+
+	BeforeEach(func() {
+		var err error
+		query, err = citus.NewQuery()
+		Expect(err).To(BeNil())
+		query.From(`"points"`)
+	})
+
+	Describe("Edge", func() {
+		It("should query the source points within the tile", func() {
+			edge := &citus.Edge{}
+			err := edge.Parse(JSON(
+				`{
+					"srcXField": "sx",
+					"srcYField": "sy",
+					"dstXField": "dx",
+					"dstYField": "dy",
+					"weightField": "w",
+					"left": 0,
+					"right": 256,
+					"bottom": 0,
+					"top": 256
+				}`))
+			Expect(err).To(BeNil())
+			_, err = edge.AddQuery(&binning.TileCoord{X: 1, Y: 0, Z: 1}, query)
+			Expect(err).To(BeNil())
+			Expect(query.WhereClauses).To(Equal([]string{
+				`"sx" >= $1 and "sx" < $2`,
+				`"sy" >= $3 and "sy" < $4`,
+			}))
+			Expect(query.QueryArgs).To(Equal([]interface{}{
+				int64(128), int64(256), int64(0), int64(128),
+			}))
+		})
+	})
+
+	Describe("BinnedTopHits", func() {
+		It("should rank the rows within each bin", func() {
+			b := &citus.BinnedTopHits{}
+			err := b.Parse(JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"left": 0,
+					"right": 256,
+					"bottom": 0,
+					"top": 256,
+					"resolution": 4,
+					"hitsCount": 2,
+					"sortField": "score",
+					"sortOrder": "desc",
+					"includeFields": ["name"]
+				}`))
+			Expect(err).To(BeNil())
+			coord := &binning.TileCoord{X: 0, Y: 0, Z: 0}
+			_, err = b.Bivariate.AddQuery(coord, query)
+			Expect(err).To(BeNil())
+			sql, err := b.AddAggs(coord, query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(HavePrefix("SELECT * FROM (SELECT "))
+			Expect(sql).To(ContainSubstring(" AS x_bucket, "))
+			Expect(sql).To(ContainSubstring(" AS y_bucket, "))
+			Expect(sql).To(ContainSubstring(`ROW_NUMBER() OVER (PARTITION BY `))
+			Expect(sql).To(ContainSubstring(`ORDER BY "score" DESC) AS bin_rank`))
+			Expect(sql).To(HaveSuffix(") AS ranked WHERE bin_rank <= $" +
+				strconv.Itoa(len(query.QueryArgs)) + ";"))
+			Expect(query.QueryArgs[len(query.QueryArgs)-1]).To(Equal(2))
+			// partition by the expressions, not the aliases
+			Expect(sql).NotTo(ContainSubstring("PARTITION BY x_bucket"))
+		})
+	})
+
+	Describe("Frequency", func() {
+		var frequency *citus.Frequency
+
+		BeforeEach(func() {
+			frequency = &citus.Frequency{}
+		})
+
+		It("should truncate timestamp columns to calendar intervals in the time zone", func() {
+			err := frequency.Parse(JSON(`{"frequencyField": "t", "gte": 0, "interval": "day", "timeZone": "America/Toronto"}`))
+			Expect(err).To(BeNil())
+			frequency.FieldType = "timestamp with time zone"
+			_, err = frequency.AddQuery(query)
+			Expect(err).To(BeNil())
+			_, err = frequency.AddAggs(query)
+			Expect(err).To(BeNil())
+			Expect(query.WhereClauses).To(Equal([]string{`"t" >= to_timestamp($1 / 1000.0)`}))
+			Expect(query.Fields[0]).To(Equal(`CAST(EXTRACT(EPOCH FROM (date_trunc($2, "t" AT TIME ZONE $3) AT TIME ZONE $3)) * 1000 AS BIGINT) as bucket`))
+			Expect(query.QueryArgs[1:]).To(Equal([]interface{}{"day", "America/Toronto"}))
+		})
+
+		It("should bin timestamp columns to fixed intervals from the lower bound", func() {
+			err := frequency.Parse(JSON(`{"frequencyField": "t", "gte": 1000, "interval": "90m"}`))
+			Expect(err).To(BeNil())
+			frequency.FieldType = "timestamp without time zone"
+			_, err = frequency.AddAggs(query)
+			Expect(err).To(BeNil())
+			Expect(query.Fields[0]).To(Equal(`CAST(EXTRACT(EPOCH FROM date_bin(CAST($1 AS INTERVAL), ("t" AT TIME ZONE 'UTC'), to_timestamp($2 / 1000.0))) * 1000 AS BIGINT) as bucket`))
+			Expect(query.QueryArgs).To(Equal([]interface{}{"5400000 milliseconds", int64(1000)}))
+		})
+
+		It("should bin numeric columns as milliseconds since the epoch", func() {
+			err := frequency.Parse(JSON(`{"frequencyField": "t", "gte": "2017-01-01", "interval": "1h"}`))
+			Expect(err).To(BeNil())
+			_, err = frequency.AddQuery(query)
+			Expect(err).To(BeNil())
+			_, err = frequency.AddAggs(query)
+			Expect(err).To(BeNil())
+			Expect(query.WhereClauses).To(Equal([]string{`"t" >= CAST(EXTRACT(EPOCH FROM CAST($1 AS TIMESTAMPTZ)) * 1000 AS BIGINT)`}))
+			Expect(query.Fields[0]).To(Equal(`CAST(floor(("t" - $3) / CAST($2 AS DOUBLE PRECISION)) * $2 + $3 AS BIGINT) as bucket`))
+			Expect(query.QueryArgs[1:]).To(Equal([]interface{}{int64(3600000), int64(1483228800000)}))
+		})
+
+		It("should fill empty buckets within the range", func() {
+			err := frequency.Parse(JSON(`{"frequencyField": "t", "gte": 0, "lt": 3000, "interval": "1s"}`))
+			Expect(err).To(BeNil())
+			buckets, err := frequency.CreateBuckets(map[int64]float64{1000: 4})
+			Expect(err).To(BeNil())
+			Expect(buckets).To(Equal([]*citus.FrequencyResult{
+				{Bucket: 0, Value: 0},
+				{Bucket: 1000, Value: 4},
+				{Bucket: 2000, Value: 0},
+			}))
+		})
+
+		It("should return an error for unsupported intervals", func() {
+			err := frequency.Parse(JSON(`{"frequencyField": "t", "gte": 0, "interval": "fortnight"}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("CubeTile", func() {
+		It("should accept any frequency interval", func() {
+			ctor := citus.NewCubeTile(nil)
+			t, err := ctor()
+			Expect(err).To(BeNil())
+			err = t.Parse(JSON(`{
+				"xField": "x",
+				"yField": "y",
+				"left": 0,
+				"right": 256,
+				"bottom": 0,
+				"top": 256,
+				"frequencyField": "t",
+				"gte": 0,
+				"interval": "90m",
+				"timeZone": "America/Toronto"
+			}`))
+			Expect(err).To(BeNil())
+		})
+
+		It("should return an error for unsupported intervals", func() {
+			ctor := citus.NewCubeTile(nil)
+			t, err := ctor()
+			Expect(err).To(BeNil())
+			err = t.Parse(JSON(`{
+				"xField": "x",
+				"yField": "y",
+				"left": 0,
+				"right": 256,
+				"bottom": 0,
+				"top": 256,
+				"frequencyField": "t",
+				"gte": 0,
+				"interval": "fortnight"
+			}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should select the time bucket in the time zone of the tile", func() {
+			frequency := &citus.Frequency{}
+			err := frequency.Parse(JSON(`{"frequencyField": "t", "gte": 0, "interval": "month", "timeZone": "America/Toronto"}`))
+			Expect(err).To(BeNil())
+			frequency.FieldType = "timestamp without time zone"
+			bucket, err := frequency.BucketExpression(query)
+			Expect(err).To(BeNil())
+			Expect(bucket).To(Equal(`CAST(EXTRACT(EPOCH FROM (date_trunc($1, ("t" AT TIME ZONE 'UTC') AT TIME ZONE $2) AT TIME ZONE $2)) * 1000 AS BIGINT)`))
+			Expect(query.QueryArgs).To(Equal([]interface{}{"month", "America/Toronto"}))
+		})
+	})
+
+	Describe("MatchesString", func() {
+		It("should use ILIKE for plain match strings", func() {
+			q := &citus.MatchesString{}
+			err := q.Parse(JSON(`{"match": "50%_off", "fields": ["title", "body"]}`))
+			Expect(err).To(BeNil())
+			res, err := q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(`(CAST("title" AS TEXT) ILIKE $1 OR CAST("body" AS TEXT) ILIKE $1)`))
+			Expect(query.QueryArgs).To(Equal([]interface{}{`%50\%\_off%`}))
+		})
+
+		It("should use ILIKE for plain match strings containing regular expression syntax", func() {
+			q := &citus.MatchesString{}
+			err := q.Parse(JSON(`{"match": "foo.com", "fields": ["title"]}`))
+			Expect(err).To(BeNil())
+			res, err := q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(`(CAST("title" AS TEXT) ILIKE $1)`))
+			Expect(query.QueryArgs).To(Equal([]interface{}{"%foo.com%"}))
+		})
+
+		It("should use a regular expression match when flagged", func() {
+			q := &citus.MatchesString{}
+			err := q.Parse(JSON(`{"match": "^foo.*bar$", "fields": ["title"], "regex": true}`))
+			Expect(err).To(BeNil())
+			res, err := q.Get(query)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(`(CAST("title" AS TEXT) ~* $1)`))
+			Expect(query.QueryArgs).To(Equal([]interface{}{"^foo.*bar$"}))
+		})
+
+		It("should return an error for invalid patterns or missing fields", func() {
+			q := &citus.MatchesString{}
+			q.Parse(JSON(`{"match": "(foo", "fields": ["title"], "regex": true}`))
+			_, err := q.Get(query)
+			Expect(err).NotTo(BeNil())
+			q.Parse(JSON(`{"match": "foo", "fields": []}`))
+			_, err = q.Get(query)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -14,21 +14,50 @@ type TopHits struct {
 }
 
 // AddAggs adds the tiling aggregations to the provided query object.
-func (t *TopHits) AddAggs(query *Query) *Query {
+func (t *TopHits) AddAggs(query *Query) (*Query, error) {
 	//Select the top N rows when sorted. Return only the specified fields.
-	for _, field := range t.IncludeFields {
-		query.Select(field)
+	err := t.AddIncludes(query)
+	if err != nil {
+		return nil, err
 	}
 	// sort
-	if t.SortField != "" {
-		if t.SortOrder == "desc" {
-			query.OrderBy(fmt.Sprintf("%s DESC", t.SortField))
-		} else {
-			query.OrderBy(t.SortField)
-		}
+	orderBy, err := t.GetOrderBy(query)
+	if err != nil {
+		return nil, err
+	}
+	if orderBy != "" {
+		query.OrderBy(orderBy)
 	}
 	query.Limit(uint32(t.HitsCount))
-	return query
+	return query, nil
+}
+
+// AddIncludes selects the included fields in the provided query object.
+func (t *TopHits) AddIncludes(query *Query) error {
+	for _, field := range t.IncludeFields {
+		column, err := query.Column(field)
+		if err != nil {
+			return err
+		}
+		query.Select(column)
+	}
+	return nil
+}
+
+// GetOrderBy returns the ordering of the hits, or an empty string if the hits
+// are not sorted.
+func (t *TopHits) GetOrderBy(query *Query) (string, error) {
+	if t.SortField == "" {
+		return "", nil
+	}
+	sortField, err := query.Column(t.SortField)
+	if err != nil {
+		return "", err
+	}
+	if t.SortOrder == "desc" {
+		return fmt.Sprintf("%s DESC", sortField), nil
+	}
+	return sortField, nil
 }
 
 // GetTopHits returns the individual hits from the provided rows.
@@ -39,12 +68,18 @@ func (t *TopHits) GetTopHits(rows *pgx.Rows) ([]map[string]interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
-		rowResult := make(map[string]interface{})
-		// Cycle through the fields to create the map.
-		for i, field := range t.IncludeFields {
-			rowResult[field] = columnValues[i]
-		}
-		hits = append(hits, rowResult)
+		hits = append(hits, t.GetHit(columnValues))
 	}
 	return hits, nil
 }
+
+// GetHit returns the hit of the included fields from the provided column
+// values.
+func (t *TopHits) GetHit(columnValues []interface{}) map[string]interface{} {
+	rowResult := make(map[string]interface{})
+	// Cycle through the fields to create the map.
+	for i, field := range t.IncludeFields {
+		rowResult[field] = columnValues[i]
+	}
+	return rowResult
+}
@@ -42,10 +42,16 @@ func (t *TopTermCountTile) Create(uri string, coord *binning.TileCoord, query ve
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get agg
-	citusQuery = t.TopTerms.AddAggs(citusQuery)
+	citusQuery, err = t.TopTerms.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
 	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
@@ -0,0 +1,41 @@
+package citus
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Wildcard represents a citus wildcard query using LIKE, or ILIKE if
+// case-insensitive.
+type Wildcard struct {
+	query.Wildcard
+}
+
+// NewWildcard instantiates and returns a new query struct.
+func NewWildcard() (veldt.Query, error) {
+	return &Wildcard{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Wildcard) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, token := range q.Tokens() {
+		switch token.Wildcard {
+		case '*':
+			sb.WriteString("%")
+		case '?':
+			sb.WriteString("_")
+		default:
+			sb.WriteString(likeEscaper.Replace(string(token.Literal)))
+		}
+	}
+	valueParam := query.AddParameter(sb.String())
+	return fmt.Sprintf("CAST(%s AS TEXT) %s %s", field, likeOperator(q.CaseSensitive), valueParam), nil
+}
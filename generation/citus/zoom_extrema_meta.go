@@ -0,0 +1,131 @@
+package citus
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/meta"
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// ZoomExtremaMeta represents a citus implementation of the per zoom level
+// heatmap extrema meta data.
+type ZoomExtremaMeta struct {
+	Bivariate
+	Tile
+	meta.ZoomExtrema
+}
+
+// NewZoomExtremaMeta instantiates and returns a pointer to a new generator.
+func NewZoomExtremaMeta(cfg *Config) veldt.MetaCtor {
+	return func() (veldt.Meta, error) {
+		m := &ZoomExtremaMeta{}
+		m.Config = cfg
+		return m, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (m *ZoomExtremaMeta) Parse(params map[string]interface{}) error {
+	err := m.ZoomExtrema.Parse(params)
+	if err != nil {
+		return err
+	}
+	return m.Bivariate.Parse(params)
+}
+
+// Create generates metadata from the provided URI.
+func (m *ZoomExtremaMeta) Create(uri string) ([]byte, error) {
+	return m.CreateScoped(uri, nil, nil, nil)
+}
+
+// CreateScoped generates metadata from the provided URI, restricted to the
+// rows matching the query and within the tile of the scope.
+func (m *ZoomExtremaMeta) CreateScoped(uri string, scope *tile.Bivariate, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create the filter, validated against the allow-list
+	client, filter, err := m.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+	if scope != nil {
+		b := &Bivariate{Bivariate: *scope}
+		filter, err = b.AddQuery(coord, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	levels := make(map[string]*meta.LevelExtrema)
+	for _, zoom := range m.Zooms() {
+		queryString, args, err := m.LevelQuery(filter, zoom)
+		if err != nil {
+			return nil, err
+		}
+		var min *float64
+		var max *float64
+		var percentiles []float64
+		dest := []interface{}{&min, &max}
+		if len(m.Percentiles) > 0 {
+			dest = append(dest, &percentiles)
+		}
+		err = client.QueryRow(queryString, args...).Scan(dest...)
+		if err != nil {
+			return nil, err
+		}
+		// there are no bins if no rows are within the bounds
+		if min == nil || max == nil {
+			levels[meta.LevelKey(zoom)] = nil
+			continue
+		}
+		level := &meta.LevelExtrema{
+			Min: *min,
+			Max: *max,
+		}
+		if len(percentiles) == len(m.Percentiles) && len(percentiles) > 0 {
+			level.Percentiles = make(map[string]float64, len(percentiles))
+			for i, val := range percentiles {
+				level.Percentiles[meta.PercentileKey(m.Percentiles[i])] = val
+			}
+		}
+		levels[meta.LevelKey(zoom)] = level
+	}
+	return json.Marshal(levels)
+}
+
+// LevelQuery returns the query and arguments computing the extrema and
+// percentiles of the non-empty bins of the zoom level, for the rows of the
+// filter. The rows are grouped into the bins of the entire zoom level, and the
+// percentiles are interpolated by percentile_cont as defined by
+// meta.ZoomExtrema.
+func (m *ZoomExtremaMeta) LevelQuery(filter *Query, zoom uint32) (string, []interface{}, error) {
+	root := &binning.TileCoord{}
+	level := &Bivariate{Bivariate: *m.LevelBivariate(zoom)}
+	// group the rows into bins
+	query, err := level.AddQuery(root, copyQuery(filter))
+	if err != nil {
+		return "", nil, err
+	}
+	query, err = level.AddAggs(root, query)
+	if err != nil {
+		return "", nil, err
+	}
+	query.Select("COUNT(*) AS count")
+	bins := query.GetQuery(true)
+	// aggregate the bins
+	fields := []string{
+		"CAST(MIN(count) AS FLOAT) AS min",
+		"CAST(MAX(count) AS FLOAT) AS max",
+	}
+	if len(m.Percentiles) > 0 {
+		fractions := make([]float64, len(m.Percentiles))
+		for i, p := range m.Percentiles {
+			fractions[i] = p / 100
+		}
+		fractionsArg := query.AddParameter(fractions)
+		fields = append(fields, fmt.Sprintf("percentile_cont(%s::float8[]) WITHIN GROUP (ORDER BY count) AS percentiles", fractionsArg))
+	}
+	queryString := fmt.Sprintf("SELECT %s FROM (%s) AS bins;", strings.Join(fields, ", "), bins)
+	return queryString, query.QueryArgs, nil
+}
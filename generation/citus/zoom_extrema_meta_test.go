@@ -0,0 +1,58 @@
+package citus_test
+
+import (
+	"github.com/unchartedsoftware/veldt/generation/citus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("ZoomExtremaMeta", func() {
+
+	var filter *citus.Query
+
+	BeforeEach(func() {
+		var err error
+		filter, err = citus.NewQuery()
+		Expect(err).To(BeNil())
+		filter.From(`"public"."points"`)
+	})
+
+	It("should aggregate the bins of the entire zoom level", func() {
+		m := &citus.ZoomExtremaMeta{}
+		err := m.Parse(JSON(
+			`{
+				"xField": "x",
+				"yField": "y",
+				"left": 0,
+				"right": 256,
+				"bottom": 0,
+				"top": 256,
+				"resolution": 4,
+				"maxZoom": 2,
+				"percentiles": [50, 99]
+			}`))
+		Expect(err).To(BeNil())
+		query, args, err := m.LevelQuery(filter, 2)
+		Expect(err).To(BeNil())
+		Expect(query).To(Equal(`SELECT CAST(MIN(count) AS FLOAT) AS min, CAST(MAX(count) AS FLOAT) AS max, percentile_cont($10::float8[]) WITHIN GROUP (ORDER BY count) AS percentiles FROM (` +
+			`SELECT width_bucket("x", $5, $6, $7) - 1 AS x_bucket, width_bucket("y", $8, $9, $7) - 1 AS y_bucket, COUNT(*) AS count FROM "public"."points" WHERE "x" >= $1 and "x" < $2 AND "y" >= $3 and "y" < $4 GROUP BY x_bucket, y_bucket` +
+			`) AS bins;`))
+		Expect(args).To(Equal([]interface{}{
+			int64(0), int64(256), int64(0), int64(256),
+			int64(0), int64(256), 16, int64(0), int64(256),
+			[]float64{0.5, 0.99},
+		}))
+		Expect(filter.WhereClauses).To(BeEmpty())
+	})
+
+	It("should not limit the bins of the deepest zoom level", func() {
+		m := &citus.ZoomExtremaMeta{}
+		err := m.Parse(JSON(`{"xField": "x", "yField": "y", "left": 0, "right": 256, "bottom": 0, "top": 256, "maxZoom": 16}`))
+		Expect(err).To(BeNil())
+		_, _, err = m.LevelQuery(filter, 16)
+		Expect(err).To(BeNil())
+	})
+
+})
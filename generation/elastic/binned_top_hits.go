@@ -17,11 +17,10 @@ type BinnedTopHits struct {
 }
 
 // NewBinnedTopHits instantiates and returns a new tile struct.
-func NewBinnedTopHits(host, port string) veldt.TileCtor {
+func NewBinnedTopHits(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		b := &BinnedTopHits{}
-		b.Host = host
-		b.Port = port
+		b.Config = cfg
 		return b, nil
 	}
 }
@@ -39,7 +38,7 @@ func (b *BinnedTopHits) Parse(params map[string]interface{}) error {
 // parameters.
 func (b *BinnedTopHits) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := b.CreateSearchService(uri)
+	search, err := b.CreateSearchService(uri, query)
 	if err != nil {
 		return nil, err
 	}
@@ -10,6 +10,12 @@ import (
 	"github.com/unchartedsoftware/veldt/tile"
 )
 
+const (
+	// binScript returns the position of the document in bins along the axis
+	// of the field, which a histogram of interval 1 floors to the bin.
+	binScript = `return (doc[field].value - min) / size;`
+)
+
 // Bivariate represents an elasticsearch implementation of the bivariate tile.
 type Bivariate struct {
 	tile.Bivariate
@@ -21,6 +27,16 @@ func (b *Bivariate) GetQuery(coord *binning.TileCoord) elastic.Query {
 	bounds := b.TileBounds(coord)
 	// create the range queries
 	query := elastic.NewBoolQuery()
+	if b.Projection == tile.MercatorProjection {
+		// lon / lat bounds must not be truncated
+		query.Must(elastic.NewRangeQuery(b.XField).
+			Gte(bounds.MinX()).
+			Lt(bounds.MaxX()))
+		query.Must(elastic.NewRangeQuery(b.YField).
+			Gte(bounds.MinY()).
+			Lt(bounds.MaxY()))
+		return query
+	}
 	query.Must(elastic.NewRangeQuery(b.XField).
 		Gte(int64(bounds.MinX())).
 		Lt(int64(bounds.MaxX())))
@@ -32,30 +48,14 @@ func (b *Bivariate) GetQuery(coord *binning.TileCoord) elastic.Query {
 
 // GetAggs returns the tiling aggregation.
 func (b *Bivariate) GetAggs(coord *binning.TileCoord) map[string]elastic.Aggregation {
-	bounds := b.TileBounds(coord)
-	// compute binning itnernal
-	intervalX := int64(math.Max(1, b.BinSizeX(coord)))
-	intervalY := int64(math.Max(1, b.BinSizeY(coord)))
-	// create the binning aggregations
-	x := elastic.NewHistogramAggregation().
-		Field(b.XField).
-		Offset(int64(bounds.MinX())).
-		Interval(intervalX).
-		MinDocCount(1)
-	y := elastic.NewHistogramAggregation().
-		Field(b.YField).
-		Offset(int64(bounds.MinY())).
-		Interval(intervalY).
-		MinDocCount(1)
-	x.SubAggregation("y", y)
-	return map[string]elastic.Aggregation{
-		"x": x,
-		"y": y,
-	}
+	return b.GetAggsWithNested(coord, "", nil)
 }
 
 // GetAggsWithNested returns the tiling aggregation with a nested child agg.
 func (b *Bivariate) GetAggsWithNested(coord *binning.TileCoord, id string, nested elastic.Aggregation) map[string]elastic.Aggregation {
+	if b.Projection == tile.MercatorProjection {
+		return b.getRangeAggs(coord, id, nested)
+	}
 	bounds := b.TileBounds(coord)
 	// compute binning itnernal
 	intervalX := int64(math.Max(1, b.BinSizeX(coord)))
@@ -85,6 +85,9 @@ func (b *Bivariate) GetAggsWithNested(coord *binning.TileCoord, id string, neste
 
 // GetBins parses the resulting histograms into bins.
 func (b *Bivariate) GetBins(coord *binning.TileCoord, aggs *elastic.Aggregations) ([]*elastic.AggregationBucketHistogramItem, error) {
+	if b.Projection == tile.MercatorProjection {
+		return b.getRangeBins(coord, aggs)
+	}
 	// parse aggregations
 	xAgg, ok := aggs.Histogram("x")
 	if !ok {
@@ -109,3 +112,74 @@ func (b *Bivariate) GetBins(coord *binning.TileCoord, aggs *elastic.Aggregations
 	}
 	return bins, nil
 }
+
+// getRangeAggs returns the tiling aggregation for bins which are not uniform
+// in data space across the y axis. The x bins are aggregated with a histogram
+// such that only non-empty columns are returned, while the y bins use their
+// explicit boundaries.
+func (b *Bivariate) getRangeAggs(coord *binning.TileCoord, id string, nested elastic.Aggregation) map[string]elastic.Aggregation {
+	// NOTE: the x bins are not integral in size, which histograms do not
+	// support, so the bin of each document is scripted, which requires
+	// dynamic scripting to be enabled.
+	script := elastic.NewScript(binScript).
+		Lang("groovy").
+		Param("field", b.XField).
+		Param("min", b.TileBounds(coord).MinX()).
+		Param("size", b.BinSizeX(coord))
+	x := elastic.NewHistogramAggregation().
+		Script(script).
+		Interval(1).
+		MinDocCount(1)
+	y := elastic.NewRangeAggregation().Field(b.YField)
+	yBounds := b.GetYBinBounds(coord)
+	for i := 0; i < b.Resolution; i++ {
+		y.AddRange(yBounds[i], yBounds[i+1])
+	}
+	x.SubAggregation("y", y)
+	aggs := map[string]elastic.Aggregation{
+		"x": x,
+		"y": y,
+	}
+	if nested != nil {
+		y.SubAggregation(id, nested)
+		aggs[id] = nested
+	}
+	return aggs
+}
+
+// getRangeBins parses the resulting x histogram and y range aggregations into
+// bins.
+func (b *Bivariate) getRangeBins(coord *binning.TileCoord, aggs *elastic.Aggregations) ([]*elastic.AggregationBucketHistogramItem, error) {
+	// parse aggregations
+	xAgg, ok := aggs.Histogram("x")
+	if !ok {
+		return nil, fmt.Errorf("histogram aggregation `x` was not found")
+	}
+	// allocate bins
+	bins := make([]*elastic.AggregationBucketHistogramItem, b.Resolution*b.Resolution)
+	// fill bins
+	for _, xBucket := range xAgg.Buckets {
+		// the key is the x bin
+		xBin := int(xBucket.Key)
+		if xBin < 0 || xBin >= b.Resolution {
+			continue
+		}
+		yAgg, ok := xBucket.Range("y")
+		if !ok {
+			return nil, fmt.Errorf("range aggregation `y` was not found")
+		}
+		for _, yBucket := range yAgg.Buckets {
+			if yBucket.DocCount == 0 || yBucket.From == nil || yBucket.To == nil {
+				continue
+			}
+			yBin := b.GetYBin(coord, (*yBucket.From+*yBucket.To)/2)
+			index := xBin + b.Resolution*yBin
+			bins[index] = &elastic.AggregationBucketHistogramItem{
+				Aggregations: yBucket.Aggregations,
+				Key:          int64(*yBucket.From),
+				DocCount:     yBucket.DocCount,
+			}
+		}
+	}
+	return bins, nil
+}
@@ -0,0 +1,73 @@
+package elastic_test
+
+import (
+	"encoding/json"
+
+	olivere "gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/elastic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Bivariate", func() {
+
+	var bivariate *elastic.Bivariate
+
+	BeforeEach(func() {
+		bivariate = &elastic.Bivariate{}
+		err := bivariate.Parse(JSON(
+			`{
+				"xField": "lon",
+				"yField": "lat",
+				"projection": "mercator",
+				"resolution": 4
+			}`))
+		Expect(err).To(BeNil())
+	})
+
+	It("should bin mercator x with a scripted histogram and y with ranges", func() {
+		aggs := bivariate.GetAggs(&binning.TileCoord{})
+		source, err := aggs["x"].Source()
+		Expect(err).To(BeNil())
+		histogram := source.(map[string]interface{})["histogram"].(map[string]interface{})
+		Expect(histogram["interval"]).To(Equal(int64(1)))
+		Expect(histogram["min_doc_count"]).To(Equal(int64(1)))
+		script := histogram["script"].(map[string]interface{})
+		Expect(script["params"]).To(Equal(map[string]interface{}{
+			"field": "lon",
+			"min":   -180.0,
+			"size":  90.0,
+		}))
+		source, err = aggs["y"].Source()
+		Expect(err).To(BeNil())
+		ranges := source.(map[string]interface{})["range"].(map[string]interface{})["ranges"]
+		Expect(len(ranges.([]interface{}))).To(Equal(4))
+	})
+
+	It("should parse the scripted histogram keys as x bins", func() {
+		aggs := olivere.Aggregations{}
+		err := json.Unmarshal([]byte(`{
+			"x": {
+				"buckets": [
+					{ "key": 0, "doc_count": 3, "y": { "buckets": [
+						{ "from": -85, "to": -67, "doc_count": 3 },
+						{ "from": -67, "to": 0, "doc_count": 0 }
+					] } },
+					{ "key": 3, "doc_count": 2, "y": { "buckets": [
+						{ "from": 67, "to": 85, "doc_count": 2 }
+					] } }
+				]
+			}
+		}`), &aggs)
+		Expect(err).To(BeNil())
+		bins, err := bivariate.GetBins(&binning.TileCoord{}, &aggs)
+		Expect(err).To(BeNil())
+		Expect(bins[0].DocCount).To(Equal(int64(3)))
+		Expect(bins[15].DocCount).To(Equal(int64(2)))
+		Expect(bins[4]).To(BeNil())
+	})
+})
@@ -55,6 +55,45 @@ func (e *BinaryExpression) Get() (elastic.Query, error) {
 	return res, nil
 }
 
+// NaryExpression represents a must / should boolean query over any number of
+// queries.
+type NaryExpression struct {
+	veldt.NaryExpression
+}
+
+// NewNaryExpression instantiates and returns a new n-ary expression.
+func NewNaryExpression() (veldt.Query, error) {
+	return &NaryExpression{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the n-ary expression.
+func (e *NaryExpression) Get() (elastic.Query, error) {
+	queries := make([]elastic.Query, len(e.Queries))
+	for i, q := range e.Queries {
+		query, ok := q.(Query)
+		if !ok {
+			return nil, fmt.Errorf("`Queries` element is not of type elastic.Query")
+		}
+		a, err := query.Get()
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = a
+	}
+	res := elastic.NewBoolQuery()
+	switch e.Op {
+	case veldt.And:
+		// AND
+		res.Must(queries...)
+	case veldt.Or:
+		// OR
+		res.Should(queries...)
+	default:
+		return nil, fmt.Errorf("`%v` operator is not a valid n-ary operator", e.Op)
+	}
+	return res, nil
+}
+
 // UnaryExpression represents a must_not boolean query.
 type UnaryExpression struct {
 	veldt.UnaryExpression
@@ -14,11 +14,10 @@ type Count struct {
 }
 
 // NewCountTile instantiates and returns a new tile struct.
-func NewCountTile(host, port string) veldt.TileCtor {
+func NewCountTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		t := &Count{}
-		t.Host = host
-		t.Port = port
+		t.Config = cfg
 		return t, nil
 	}
 }
@@ -28,11 +27,16 @@ func (t *Count) Parse(params map[string]interface{}) error {
 	return t.Bivariate.Parse(params)
 }
 
+// Empty returns the tile containing no data.
+func (t *Count) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return []byte(`{"count":0}`), nil
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := t.CreateSearchService(uri)
+	search, err := t.CreateSearchService(uri, query)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,104 @@
+package elastic
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// CubeTile represents an elasticsearch implementation of the time-series cube
+// tile.
+type CubeTile struct {
+	Elastic
+	Bivariate
+	Frequency
+	tile.Cube
+}
+
+// NewCubeTile instantiates and returns a new tile struct.
+func NewCubeTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &CubeTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *CubeTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = t.Frequency.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Cube.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *CubeTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create search service
+	timeRange, err := t.Frequency.GetTimeRange()
+	if err != nil {
+		return nil, err
+	}
+	search, err := t.CreateSearchService(uri, query, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	// create root query
+	q, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	q.Must(t.Bivariate.GetQuery(coord))
+	// add frequency query
+	q.Must(t.Frequency.GetQuery())
+	// set the query
+	search.Query(q)
+
+	// get aggs
+	frequencyAggs := t.Frequency.GetAggs()
+	aggs := t.Bivariate.GetAggsWithNested(coord, "frequency", frequencyAggs["frequency"])
+	// set the aggregation
+	search.Aggregation("x", aggs["x"])
+
+	// send query
+	res, err := search.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	// get bins
+	bins, err := t.Bivariate.GetBins(coord, &res.Aggregations)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the time buckets of each bin
+	buckets := make([]tile.CubeBucket, 0)
+	for i, bin := range bins {
+		if bin == nil {
+			continue
+		}
+		frequency, err := t.Frequency.GetBuckets(&bin.Aggregations)
+		if err != nil {
+			return nil, err
+		}
+		for _, bucket := range frequency {
+			buckets = append(buckets, tile.CubeBucket{
+				Bin:       i,
+				Timestamp: bucket.Key,
+				Count:     uint32(bucket.DocCount),
+			})
+		}
+	}
+
+	// encode the result
+	return t.Cube.Encode(t.Resolution, buckets)
+}
@@ -2,37 +2,64 @@ package elastic
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
 	"gopkg.in/olivere/elastic.v3"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/meta"
+	"github.com/unchartedsoftware/veldt/tile"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
 // DefaultMeta represents a meta data generator that produces default
-// metadata with property types and extrema.
+// metadata with property types and extrema, along with any requested
+// statistics.
 type DefaultMeta struct {
 	Elastic
+	meta.Statistics
+	query  veldt.Query
+	filter elastic.Query
 }
 
 // NewDefaultMeta instantiates and returns a pointer to a new generator.
-func NewDefaultMeta(host string, port string) veldt.MetaCtor {
+func NewDefaultMeta(cfg *Config) veldt.MetaCtor {
 	return func() (veldt.Meta, error) {
 		m := &DefaultMeta{}
-		m.Host = host
-		m.Port = port
+		m.Config = cfg
 		return m, nil
 	}
 }
 
 // Parse parses the provided JSON object and populates the structs attributes.
 func (m *DefaultMeta) Parse(params map[string]interface{}) error {
-	return nil
+	return m.Statistics.Parse(params)
 }
 
 // Create generates metadata from the provided URI.
 func (m *DefaultMeta) Create(uri string) ([]byte, error) {
+	return m.CreateScoped(uri, nil, nil, nil)
+}
+
+// CreateScoped generates metadata from the provided URI, restricted to the
+// documents matching the query and within the tile of the scope.
+func (m *DefaultMeta) CreateScoped(uri string, scope *tile.Bivariate, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create the filter applied to every search
+	m.query = query
+	m.filter = nil
+	if scope != nil || query != nil {
+		filter, err := m.CreateQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		if scope != nil {
+			b := &Bivariate{Bivariate: *scope}
+			filter.Must(b.GetQuery(coord))
+		}
+		m.filter = filter
+	}
 	// get the raw mappings
 	service, err := m.CreateMappingService(uri)
 	if err != nil {
@@ -43,26 +70,44 @@ func (m *DefaultMeta) Create(uri string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	// get nested 'properties' attribute of mappings payload
-	// NOTE: If running a `mapping` query on an aliased index, the mapping
-	// response will be nested under the original index name. Since we are only
-	// getting the mapping of a single index at a time, we can simply get the
-	// 'first' and only node.
-	_, index, ok := json.GetRandomChild(mapping)
-	if !ok {
+	// NOTE: the response is keyed by the concrete index names, which differ
+	// from the uri for aliases, patterns and date templates. The properties of
+	// each type are merged across the indices, in order, such that
+	// conflicting mappings resolve consistently.
+	indices := make([]string, 0, len(mapping))
+	for index := range mapping {
+		indices = append(indices, index)
+	}
+	if len(indices) == 0 {
 		return nil, fmt.Errorf("Unable to retrieve the mappings response for %s",
 			uri)
 	}
-	// get mappings node
-	mappings, ok := json.GetChildMap(index, "mappings")
-	if !ok {
-		return nil, fmt.Errorf("unable to parse `mappings` from mappings response for %s",
-			uri)
+	sort.Strings(indices)
+	types := make(map[string]map[string]interface{})
+	for _, index := range indices {
+		// get mappings node
+		mappings, ok := json.GetChildMap(mapping, index, "mappings")
+		if !ok {
+			return nil, fmt.Errorf("unable to parse `mappings` from mappings response for %s",
+				uri)
+		}
+		for key, typ := range mappings {
+			props, ok := json.GetChild(typ, "properties")
+			if !ok {
+				continue
+			}
+			merged, ok := types[key]
+			if !ok {
+				merged = make(map[string]interface{})
+				types[key] = merged
+			}
+			mergeProperties(merged, props)
+		}
 	}
 	// for each type, parse the mapping
 	meta := make(map[string]interface{})
-	for key, typ := range mappings {
-		typeMeta, err := m.parseType(uri, typ)
+	for key, props := range types {
+		typeMeta, err := m.parseProperties(uri, props)
 		if err != nil {
 			return nil, err
 		}
@@ -76,6 +121,19 @@ func (m *DefaultMeta) Create(uri string) ([]byte, error) {
 type PropertyMeta struct {
 	Type    string           `json:"type"`
 	Extrema *binning.Extrema `json:"extrema,omitempty"`
+	meta.PropertyStatistics
+}
+
+// createSearch creates the search service, filtered by the scope and query.
+func (m *DefaultMeta) createSearch(uri string) (*elastic.SearchService, error) {
+	search, err := m.CreateSearchService(uri, m.query)
+	if err != nil {
+		return nil, err
+	}
+	if m.filter != nil {
+		search.Query(m.filter)
+	}
+	return search, nil
 }
 
 func isOrdinal(typ string) bool {
@@ -88,9 +146,13 @@ func isOrdinal(typ string) bool {
 		typ == "date"
 }
 
+func isKeyword(typ string) bool {
+	return typ == "keyword"
+}
+
 func (m *DefaultMeta) getExtrema(uri string, field string) (*binning.Extrema, error) {
 	// search
-	search, err := m.CreateSearchService(uri)
+	search, err := m.createSearch(uri)
 	if err != nil {
 		return nil, err
 	}
@@ -136,9 +198,155 @@ func (m *DefaultMeta) getPropertyMeta(uri string, field string, typ string) (*Pr
 		}
 		prop.Extrema = extrema
 	}
+	err := m.getStatistics(uri, field, prop)
+	if err != nil {
+		return nil, err
+	}
 	return prop, nil
 }
 
+func (m *DefaultMeta) getStatistics(uri string, field string, prop *PropertyMeta) error {
+	ordinal := isOrdinal(prop.Type)
+	percentiles := ordinal && len(m.Percentiles) > 0
+	topValues := isKeyword(prop.Type) && m.TopValues > 0
+	if !percentiles && !topValues && !m.Cardinality && !m.Missing {
+		return nil
+	}
+	// search
+	search, err := m.createSearch(uri)
+	if err != nil {
+		return err
+	}
+	if percentiles {
+		search.Aggregation("percentiles",
+			elastic.NewPercentilesAggregation().
+				Field(field).
+				Percentiles(m.Percentiles...))
+	}
+	if m.Cardinality {
+		search.Aggregation("cardinality",
+			elastic.NewCardinalityAggregation().
+				Field(field))
+	}
+	if m.Missing {
+		search.Aggregation("missing",
+			elastic.NewMissingAggregation().
+				Field(field))
+	}
+	if topValues {
+		search.Aggregation("top",
+			elastic.NewTermsAggregation().
+				Field(field).
+				Size(m.TopValues))
+	}
+	result, err := search.Do()
+	if err != nil {
+		return err
+	}
+	// parse aggregations
+	if percentiles {
+		agg, ok := result.Aggregations.Percentiles("percentiles")
+		if !ok {
+			return fmt.Errorf("percentiles '%s' aggregation was not found in response for %s", field, uri)
+		}
+		// NOTE: percentiles are null if no documents have the attribute
+		if len(agg.Values) > 0 {
+			prop.Percentiles = make(map[string]float64, len(agg.Values))
+			for _, p := range m.Percentiles {
+				val, ok := agg.Values[meta.PercentileKey(p)]
+				if !ok {
+					val, ok = agg.Values[strconv.FormatFloat(p, 'f', 1, 64)]
+				}
+				if ok {
+					prop.Percentiles[meta.PercentileKey(p)] = val
+				}
+			}
+		}
+	}
+	if m.Cardinality {
+		agg, ok := result.Aggregations.Cardinality("cardinality")
+		if !ok {
+			return fmt.Errorf("cardinality '%s' aggregation was not found in response for %s", field, uri)
+		}
+		cardinality := uint64(0)
+		if agg.Value != nil {
+			cardinality = uint64(*agg.Value)
+		}
+		prop.Cardinality = &cardinality
+	}
+	if m.Missing {
+		agg, ok := result.Aggregations.Missing("missing")
+		if !ok {
+			return fmt.Errorf("missing '%s' aggregation was not found in response for %s", field, uri)
+		}
+		missing := uint64(agg.DocCount)
+		prop.Missing = &missing
+	}
+	if topValues {
+		agg, ok := result.Aggregations.Terms("top")
+		if !ok {
+			return fmt.Errorf("terms '%s' aggregation was not found in response for %s", field, uri)
+		}
+		prop.TopValues = make([]meta.ValueCount, len(agg.Buckets))
+		for i, bucket := range agg.Buckets {
+			prop.TopValues[i] = meta.ValueCount{
+				Value: bucket.Key,
+				Count: uint64(bucket.DocCount),
+			}
+		}
+	}
+	// the histogram buckets depend on the extrema
+	if ordinal && m.HistogramBuckets > 0 && prop.Extrema != nil {
+		histogram, err := m.getHistogram(uri, field, prop.Extrema)
+		if err != nil {
+			return err
+		}
+		prop.Histogram = histogram
+	}
+	return nil
+}
+
+func (m *DefaultMeta) getHistogram(uri string, field string, extrema *binning.Extrema) (*meta.Histogram, error) {
+	edges := m.HistogramEdges(extrema)
+	agg := elastic.NewRangeAggregation().
+		Field(field).
+		Keyed(false)
+	for i := 0; i < m.HistogramBuckets-1; i++ {
+		agg.AddRange(edges[i], edges[i+1])
+	}
+	// the last bucket includes the maximum
+	agg.AddUnboundedTo(edges[m.HistogramBuckets-1])
+	// search
+	search, err := m.createSearch(uri)
+	if err != nil {
+		return nil, err
+	}
+	result, err := search.
+		Aggregation("histogram", agg).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	// parse aggregation
+	ranges, ok := result.Aggregations.Range("histogram")
+	if !ok {
+		return nil, fmt.Errorf("range '%s' aggregation was not found in response for %s", field, uri)
+	}
+	if len(ranges.Buckets) != m.HistogramBuckets {
+		return nil, fmt.Errorf("range '%s' aggregation returned %d buckets instead of %d for %s",
+			field, len(ranges.Buckets), m.HistogramBuckets, uri)
+	}
+	counts := make([]uint64, m.HistogramBuckets)
+	for i, bucket := range ranges.Buckets {
+		counts[i] = uint64(bucket.DocCount)
+	}
+	return &meta.Histogram{
+		Min:    extrema.Min,
+		Max:    extrema.Max,
+		Counts: counts,
+	}, nil
+}
+
 func (m *DefaultMeta) parsePropertiesRecursive(meta map[string]PropertyMeta, uri string, p map[string]interface{}, path string) error {
 	children, ok := json.GetChildMap(p)
 	if !ok {
@@ -197,13 +405,31 @@ func (m *DefaultMeta) parseProperties(uri string, props map[string]interface{})
 	return meta, nil
 }
 
-func (m *DefaultMeta) parseType(uri string, typ map[string]interface{}) (map[string]PropertyMeta, error) {
-	props, ok := json.GetChild(typ, "properties")
-	if !ok {
-		return nil, fmt.Errorf("Unable to parse `properties` from mappings response for type `%s` for %s",
-			typ,
-			uri)
+// mergeProperties merges the properties of a mapping into the destination,
+// recursing into object properties. Existing properties take precedence.
+func mergeProperties(dst map[string]interface{}, src map[string]interface{}) {
+	for key, val := range src {
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = val
+			continue
+		}
+		dstProp, ok := existing.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		srcProp, ok := val.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dstProps, ok := json.GetChild(dstProp, "properties")
+		if !ok {
+			continue
+		}
+		srcProps, ok := json.GetChild(srcProp, "properties")
+		if !ok {
+			continue
+		}
+		mergeProperties(dstProps, srcProps)
 	}
-	// parse json mappings into the property map
-	return m.parseProperties(uri, props)
 }
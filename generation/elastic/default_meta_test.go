@@ -0,0 +1,147 @@
+package elastic_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/generation/elastic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("DefaultMeta", func() {
+
+	var server *httptest.Server
+	var paths []string
+
+	BeforeEach(func() {
+		paths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			var body []byte
+			if reader, err := gzip.NewReader(r.Body); err == nil {
+				body, _ = ioutil.ReadAll(reader)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case strings.Contains(r.URL.Path, "/_mapping"):
+				w.Write([]byte(`{
+					"logs-2017.01.02": {
+						"mappings": {
+							"log": {
+								"properties": {
+									"level": { "type": "keyword" },
+									"host": { "type": "keyword" },
+									"geo": { "properties": { "lat": { "type": "double" } } }
+								}
+							}
+						}
+					},
+					"logs-2017.01.01": {
+						"mappings": {
+							"log": {
+								"properties": {
+									"level": { "type": "text" },
+									"geo": { "properties": { "lon": { "type": "double" } } }
+								}
+							}
+						}
+					}
+				}`))
+			case strings.HasSuffix(r.URL.Path, "/_search") && strings.Contains(string(body), `"range"`):
+				w.Write([]byte(`{
+					"hits": { "total": 0, "hits": [] },
+					"aggregations": {
+						"histogram": {
+							"buckets": [
+								{ "from": 1, "to": 1.5, "doc_count": 3 },
+								{ "from": 1.5, "doc_count": 4 }
+							]
+						}
+					}
+				}`))
+			case strings.HasSuffix(r.URL.Path, "/_search") && strings.Contains(string(body), `"cardinality"`):
+				w.Write([]byte(`{
+					"hits": { "total": 0, "hits": [] },
+					"aggregations": {
+						"percentiles": { "values": { "50.0": 1.5 } },
+						"cardinality": { "value": 5 },
+						"missing": { "doc_count": 2 },
+						"top": { "buckets": [ { "key": "error", "doc_count": 6 } ] }
+					}
+				}`))
+			case strings.HasSuffix(r.URL.Path, "/_search"):
+				w.Write([]byte(`{
+					"hits": { "total": 0, "hits": [] },
+					"aggregations": { "min": { "value": 1 }, "max": { "value": 2 } }
+				}`))
+			default:
+				w.Write([]byte(`{}`))
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		elastic.CloseClients()
+		server.Close()
+	})
+
+	It("should merge the mappings of every resolved index", func() {
+		m, err := elastic.NewDefaultMeta(&elastic.Config{
+			Hosts:          []string{server.URL},
+			IndexTimeField: "timestamp",
+		})()
+		Expect(err).To(BeNil())
+		res, err := m.Create("logs-{yyyy.MM.dd}")
+		Expect(err).To(BeNil())
+		Expect(JSON(string(res))).To(Equal(JSON(`{
+			"log": {
+				"level": { "type": "text" },
+				"host": { "type": "keyword" },
+				"geo.lat": { "type": "double", "extrema": { "min": 1, "max": 2 } },
+				"geo.lon": { "type": "double", "extrema": { "min": 1, "max": 2 } }
+			}
+		}`)))
+		Expect(paths).To(ContainElement("/logs-*/_mapping/_all"))
+		Expect(paths).To(ContainElement("/logs-*/_search"))
+	})
+
+	It("should compute the requested statistics", func() {
+		m, err := elastic.NewDefaultMeta(&elastic.Config{
+			Hosts:          []string{server.URL},
+			IndexTimeField: "timestamp",
+		})()
+		Expect(err).To(BeNil())
+		err = m.Parse(JSON(`{
+			"histogram": 2,
+			"percentiles": [50],
+			"cardinality": true,
+			"missing": true,
+			"topValues": 1
+		}`))
+		Expect(err).To(BeNil())
+		res, err := m.Create("logs-{yyyy.MM.dd}")
+		Expect(err).To(BeNil())
+		log, ok := JSON(string(res))["log"].(map[string]interface{})
+		Expect(ok).To(Equal(true))
+		Expect(log["geo.lat"]).To(Equal(JSON(`{
+			"type": "double",
+			"extrema": { "min": 1, "max": 2 },
+			"histogram": { "min": 1, "max": 2, "counts": [3, 4] },
+			"percentiles": { "50": 1.5 },
+			"cardinality": 5,
+			"missing": 2
+		}`)))
+		Expect(log["host"]).To(Equal(JSON(`{
+			"type": "keyword",
+			"cardinality": 5,
+			"missing": 2,
+			"topValues": [ { "value": "error", "count": 6 } ]
+		}`)))
+	})
+})
@@ -21,21 +21,25 @@ func (e *Edge) GetQuery(coord *binning.TileCoord) elastic.Query {
 
 	// Require at least 1 of the points, possibly both.
 	if e.Edge.RequireSrc || !e.Edge.RequireDst {
-		query.Must(elastic.NewRangeQuery(e.Edge.SrcXField).
-			Gte(int64(bounds.MinX())).
-			Lt(int64(bounds.MaxX())))
-		query.Must(elastic.NewRangeQuery(e.Edge.SrcYField).
-			Gte(int64(bounds.MinY())).
-			Lt(int64(bounds.MaxY())))
+		query.Must(e.getRangeQuery(e.Edge.SrcXField, bounds.MinX(), bounds.MaxX()))
+		query.Must(e.getRangeQuery(e.Edge.SrcYField, bounds.MinY(), bounds.MaxY()))
 	}
 	if e.Edge.RequireDst {
-		query.Must(elastic.NewRangeQuery(e.Edge.DstXField).
-			Gte(int64(bounds.MinX())).
-			Lt(int64(bounds.MaxX())))
-		query.Must(elastic.NewRangeQuery(e.Edge.DstYField).
-			Gte(int64(bounds.MinY())).
-			Lt(int64(bounds.MaxY())))
+		query.Must(e.getRangeQuery(e.Edge.DstXField, bounds.MinX(), bounds.MaxX()))
+		query.Must(e.getRangeQuery(e.Edge.DstYField, bounds.MinY(), bounds.MaxY()))
 	}
 
 	return query
 }
+
+func (e *Edge) getRangeQuery(field string, min float64, max float64) elastic.Query {
+	if e.Projection == tile.MercatorProjection {
+		// lon / lat bounds must not be truncated
+		return elastic.NewRangeQuery(field).
+			Gte(min).
+			Lt(max)
+	}
+	return elastic.NewRangeQuery(field).
+		Gte(int64(min)).
+		Lt(int64(max))
+}
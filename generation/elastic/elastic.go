@@ -2,6 +2,7 @@ package elastic
 
 import (
 	"fmt"
+	"hash/fnv"
 	"net/http"
 	"runtime"
 	"strings"
@@ -11,10 +12,16 @@ import (
 	"gopkg.in/olivere/elastic.v3"
 
 	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/transport"
 )
 
 const (
-	timeout = time.Second * 60
+	defaultTimeout = time.Second * 60
+	defaultHost    = "localhost:9200"
+	defaultScheme  = "http"
+	// defaultRetryBackoff is the wait before the first retry, which doubles
+	// on each subsequent retry.
+	defaultRetryBackoff = time.Millisecond * 100
 )
 
 var (
@@ -22,32 +29,249 @@ var (
 	clients = make(map[string]*elastic.Client)
 )
 
+// Config defines the cluster, credentials and transport options required to
+// establish a connection.
+type Config struct {
+	// Hosts lists the nodes of the cluster, either as `host:port` or as full
+	// URLs, ie. `https://localhost:9200`. If empty, `localhost:9200` is used.
+	Hosts []string
+	// Scheme is used for hosts that do not specify one. If empty, `http` is
+	// used.
+	Scheme string
+	// Username and Password are sent using basic authentication.
+	Username string
+	Password string
+	// APIKey is the base64 encoded `id:api_key` credential, sent using API
+	// key authentication. It takes precedence over basic authentication.
+	APIKey string
+	// CACert is the path to a PEM encoded certificate bundle used to verify
+	// the cluster certificate. If empty, the system roots are used.
+	CACert string
+	// InsecureSkipVerify disables verification of the cluster certificate.
+	InsecureSkipVerify bool
+	// Timeout is the maximum duration of a request. If zero, a default of 60
+	// seconds is used.
+	Timeout time.Duration
+	// MaxRetries is the number of times a failed request is retried. If zero,
+	// requests are not retried.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, which doubles on each
+	// subsequent retry. If zero, a default of 100 milliseconds is used.
+	RetryBackoff time.Duration
+	// MaxRetryBackoff caps the wait between retries. If zero, the wait is not
+	// capped.
+	MaxRetryBackoff time.Duration
+	// DisableGzip disables gzip compression of request bodies.
+	DisableGzip bool
+	// MaxIdleConns is the maximum number of idle connections kept open per
+	// host. If zero, the net/http default is used.
+	MaxIdleConns int
+	// Sniff enables discovery of the remaining nodes of the cluster.
+	Sniff bool
+	// IndexTimeField is the time field by which date templated indices, such
+	// as `logs-{yyyy.MM.dd}`, are partitioned. Requests restricting the range
+	// of the field only search the indices overlapping the range.
+	IndexTimeField string
+}
+
+func (c *Config) key() string {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%v|%s|%s|%s|%s|%s|%v|%v|%d|%v|%v|%v|%d|%v",
+		c.Hosts,
+		c.Scheme,
+		c.Username,
+		c.Password,
+		c.APIKey,
+		c.CACert,
+		c.InsecureSkipVerify,
+		c.Timeout,
+		c.MaxRetries,
+		c.RetryBackoff,
+		c.MaxRetryBackoff,
+		c.DisableGzip,
+		c.MaxIdleConns,
+		c.Sniff)
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// NewClient returns a client for the provided config. Clients are cached and
+// shared between tiles using the same config. A nil config connects using the
+// defaults.
+func NewClient(cfg *Config) (*elastic.Client, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	key := cfg.key()
+	mutex.Lock()
+	client, ok := clients[key]
+	if !ok {
+		c, err := newClient(cfg)
+		if err != nil {
+			mutex.Unlock()
+			runtime.Gosched()
+			return nil, err
+		}
+		clients[key] = c
+		client = c
+	}
+	mutex.Unlock()
+	runtime.Gosched()
+	return client, nil
+}
+
+// RefreshClient replaces the cached client for the provided config with a new
+// client, such as after the cluster has been restarted or the credentials
+// have been rotated.
+func RefreshClient(cfg *Config) (*elastic.Client, error) {
+	CloseClient(cfg)
+	return NewClient(cfg)
+}
+
+// CloseClient stops the client for the provided config, if one has been
+// opened. Subsequent calls to NewClient will open a new client.
+func CloseClient(cfg *Config) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	key := cfg.key()
+	mutex.Lock()
+	client, ok := clients[key]
+	if ok {
+		delete(clients, key)
+	}
+	mutex.Unlock()
+	if ok {
+		client.Stop()
+	}
+}
+
+// CloseClients stops all open clients.
+func CloseClients() {
+	mutex.Lock()
+	closing := clients
+	clients = make(map[string]*elastic.Client)
+	mutex.Unlock()
+	for _, client := range closing {
+		client.Stop()
+	}
+}
+
+func newClient(cfg *Config) (*elastic.Client, error) {
+	scheme := cfg.Scheme
+	if scheme == "" {
+		scheme = defaultScheme
+	}
+	hosts := make([]string, 0, len(cfg.Hosts))
+	for _, host := range cfg.Hosts {
+		if !strings.Contains(host, "://") {
+			host = scheme + "://" + host
+		}
+		hosts = append(hosts, strings.TrimRight(host, "/"))
+	}
+	if len(hosts) == 0 {
+		hosts = append(hosts, scheme+"://"+defaultHost)
+	}
+	// create the transport
+	httpTransport, err := transport.New(&transport.Config{
+		Username:            cfg.Username,
+		Password:            cfg.Password,
+		APIKey:              cfg.APIKey,
+		CACert:              cfg.CACert,
+		InsecureSkipVerify:  cfg.InsecureSkipVerify,
+		MaxIdleConnsPerHost: cfg.MaxIdleConns,
+	})
+	if err != nil {
+		return nil, err
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	if cfg.MaxRetries < 0 {
+		return nil, fmt.Errorf("`MaxRetries` of %d is negative", cfg.MaxRetries)
+	}
+	backoff := cfg.RetryBackoff
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+	// authentication is added by the transport
+	options := []elastic.ClientOptionFunc{
+		elastic.SetHttpClient(&http.Client{
+			Transport: httpTransport,
+			Timeout:   timeout,
+		}),
+		elastic.SetURL(hosts...),
+		elastic.SetScheme(scheme),
+		elastic.SetSniff(cfg.Sniff),
+		elastic.SetGzip(!cfg.DisableGzip),
+		elastic.SetRetrier(elastic.NewBackoffRetrier(&retryBackoff{
+			maxRetries: cfg.MaxRetries,
+			initial:    backoff,
+			max:        cfg.MaxRetryBackoff,
+		})),
+	}
+	return elastic.NewClient(options...)
+}
+
+// retryBackoff represents an exponential backoff which stops after a maximum
+// number of retries.
+type retryBackoff struct {
+	maxRetries int
+	initial    time.Duration
+	max        time.Duration
+}
+
+// Next returns the wait before the provided retry, and whether to retry.
+func (b *retryBackoff) Next(retry int) (time.Duration, bool) {
+	if retry > b.maxRetries {
+		return 0, false
+	}
+	wait := b.initial
+	for i := 1; i < retry; i++ {
+		wait *= 2
+		if b.max > 0 && wait >= b.max {
+			break
+		}
+	}
+	if b.max > 0 && wait > b.max {
+		wait = b.max
+	}
+	return wait, true
+}
+
 // Elastic represents an elasticsearch type.
 type Elastic struct {
-	Host string
-	Port string
+	Config *Config
 }
 
-// CreateSearchService creates the elasticsearch search service from the provided uri.
-func (e *Elastic) CreateSearchService(uri string) (*elastic.SearchService, error) {
+// CreateSearchService creates the elasticsearch search service from the
+// provided uri. The uri may be a comma separated list of indices, wildcards
+// and date templated indices, optionally followed by a `/type`. Date templated
+// indices are routed using the time range of the query and the provided
+// ranges.
+func (e *Elastic) CreateSearchService(uri string, query veldt.Query, ranges ...*TimeRange) (*elastic.SearchService, error) {
 	// get client
-	client, err := e.createClient()
+	client, err := NewClient(e.Config)
 	if err != nil {
 		return nil, err
 	}
 	split := strings.Split(uri, "/")
-	if len(split) < 2 {
-		index := split[0]
-		return client.Search().
-			Index(index).
-			Size(0), nil
-	}
-	index := split[0]
-	typ := split[1]
-	return client.Search().
-		Index(index).
-		Type(typ).
-		Size(0), nil
+	indices, templated, err := e.ResolveIndices(split[0], query, ranges...)
+	if err != nil {
+		return nil, err
+	}
+	search := client.Search().
+		Index(indices...).
+		Size(0)
+	if templated {
+		// not every period is guaranteed to have an index
+		search.IgnoreUnavailable(true).
+			AllowNoIndices(true)
+	}
+	if len(split) > 1 {
+		search.Type(split[1])
+	}
+	return search, nil
 }
 
 // CreateQuery creates the elasticsearch query from the query struct.
@@ -71,48 +295,27 @@ func (e *Elastic) CreateQuery(query veldt.Query) (*elastic.BoolQuery, error) {
 	return root, nil
 }
 
-// CreateMappingService creates the elasticsearch mapping service from the provided uri.
+// CreateMappingService creates the elasticsearch mapping service from the
+// provided uri. Date templated indices resolve to every matching index.
 func (e *Elastic) CreateMappingService(uri string) (*elastic.IndicesGetMappingService, error) {
 	// get client
-	client, err := e.createClient()
+	client, err := NewClient(e.Config)
 	if err != nil {
 		return nil, err
 	}
 	split := strings.Split(uri, "/")
-	if len(split) < 2 {
-		index := split[0]
-		return client.GetMapping().
-			Index(index), nil
-	}
-	index := split[0]
-	typ := split[1]
-	return client.GetMapping().
-		Index(index).
-		Type(typ), nil
-}
-
-func (e *Elastic) createClient() (*elastic.Client, error) {
-	endpoint := e.Host + ":" + e.Port
-	mutex.Lock()
-	client, ok := clients[endpoint]
-	if !ok {
-		c, err := elastic.NewClient(
-			elastic.SetHttpClient(&http.Client{
-				Timeout: timeout,
-			}),
-			elastic.SetURL(endpoint),
-			elastic.SetSniff(false),
-			elastic.SetGzip(true),
-		)
-		if err != nil {
-			mutex.Unlock()
-			runtime.Gosched()
-			return nil, err
-		}
-		clients[endpoint] = c
-		client = c
+	indices, templated, err := e.ResolveIndices(split[0], nil)
+	if err != nil {
+		return nil, err
 	}
-	mutex.Unlock()
-	runtime.Gosched()
-	return client, nil
+	mapping := client.GetMapping().
+		Index(indices...)
+	if templated {
+		mapping.IgnoreUnavailable(true).
+			AllowNoIndices(true)
+	}
+	if len(split) > 1 {
+		mapping.Type(split[1])
+	}
+	return mapping, nil
 }
@@ -0,0 +1,116 @@
+package elastic_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/unchartedsoftware/veldt/generation/elastic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Elastic", func() {
+
+	var server *httptest.Server
+	var mutex sync.Mutex
+	var headers []http.Header
+
+	BeforeEach(func() {
+		headers = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			headers = append(headers, r.Header)
+			mutex.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"took":1,"hits":{"total":0,"hits":[]}}`))
+		}))
+	})
+
+	AfterEach(func() {
+		elastic.CloseClients()
+		server.Close()
+	})
+
+	search := func(cfg *elastic.Config) {
+		e := &elastic.Elastic{
+			Config: cfg,
+		}
+		service, err := e.CreateSearchService("tweets", nil)
+		Expect(err).To(BeNil())
+		_, err = service.Do()
+		Expect(err).To(BeNil())
+	}
+
+	last := func() http.Header {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return headers[len(headers)-1]
+	}
+
+	It("should apply the scheme to hosts without one", func() {
+		search(&elastic.Config{
+			Hosts:  []string{strings.TrimPrefix(server.URL, "http://")},
+			Scheme: "http",
+		})
+	})
+
+	It("should send basic authentication", func() {
+		search(&elastic.Config{
+			Hosts:    []string{server.URL},
+			Username: "elastic",
+			Password: "changeme",
+		})
+		username, password, ok := (&http.Request{Header: last()}).BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(username).To(Equal("elastic"))
+		Expect(password).To(Equal("changeme"))
+	})
+
+	It("should prefer API key authentication", func() {
+		search(&elastic.Config{
+			Hosts:    []string{server.URL},
+			Username: "elastic",
+			Password: "changeme",
+			APIKey:   "aWQ6a2V5",
+		})
+		Expect(last().Get("Authorization")).To(Equal("ApiKey aWQ6a2V5"))
+	})
+
+	It("should cache clients by config", func() {
+		cfg := &elastic.Config{
+			Hosts: []string{server.URL},
+		}
+		a, err := elastic.NewClient(cfg)
+		Expect(err).To(BeNil())
+		b, err := elastic.NewClient(&elastic.Config{
+			Hosts: []string{server.URL},
+		})
+		Expect(err).To(BeNil())
+		Expect(a).To(BeIdenticalTo(b))
+		c, err := elastic.RefreshClient(cfg)
+		Expect(err).To(BeNil())
+		Expect(c).NotTo(BeIdenticalTo(a))
+		elastic.CloseClient(cfg)
+		d, err := elastic.NewClient(cfg)
+		Expect(err).To(BeNil())
+		Expect(d).NotTo(BeIdenticalTo(c))
+	})
+
+	It("should connect using the defaults without a config", func() {
+		Expect(func() {
+			elastic.NewClient(nil)
+			elastic.CloseClient(nil)
+		}).NotTo(Panic())
+	})
+
+	It("should return an error for a negative number of retries", func() {
+		_, err := elastic.NewClient(&elastic.Config{
+			Hosts:      []string{server.URL},
+			MaxRetries: -1,
+		})
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -38,6 +38,9 @@ func (f *Frequency) GetAggs() map[string]elastic.Aggregation {
 		Field(f.FrequencyField).
 		Interval(f.Interval).
 		MinDocCount(0)
+	if f.TimeZone != "" {
+		agg.TimeZone(f.TimeZone)
+	}
 	if f.GTE != nil {
 		agg.ExtendedBoundsMin(castTime(f.GTE))
 		agg.Offset(castTimeToString(f.GTE))
@@ -91,3 +94,17 @@ func castTime(val interface{}) interface{} {
 	}
 	return val
 }
+
+// GetTimeRange returns the time range of the tile, used to route date
+// templated indices.
+func (f *Frequency) GetTimeRange() (*TimeRange, error) {
+	min, max, err := f.TimeBounds()
+	if err != nil {
+		return nil, err
+	}
+	return &TimeRange{
+		Field: f.FrequencyField,
+		Min:   min,
+		Max:   max,
+	}, nil
+}
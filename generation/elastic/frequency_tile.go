@@ -15,11 +15,10 @@ type FrequencyTile struct {
 }
 
 // NewFrequencyTile instantiates and returns a new tile struct.
-func NewFrequencyTile(host, port string) veldt.TileCtor {
+func NewFrequencyTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		t := &FrequencyTile{}
-		t.Host = host
-		t.Port = port
+		t.Config = cfg
 		return t, nil
 	}
 }
@@ -37,7 +36,11 @@ func (t *FrequencyTile) Parse(params map[string]interface{}) error {
 // parameters.
 func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := t.CreateSearchService(uri)
+	timeRange, err := t.Frequency.GetTimeRange()
+	if err != nil {
+		return nil, err
+	}
+	search, err := t.CreateSearchService(uri, query, timeRange)
 	if err != nil {
 		return nil, err
 	}
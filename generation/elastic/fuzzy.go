@@ -0,0 +1,38 @@
+package elastic
+
+import (
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+const (
+	// maxFuzzyExpansions is the maximum number of terms a fuzzy query expands
+	// to, which is the default maximum clause count of a boolean query.
+	maxFuzzyExpansions = 1024
+)
+
+// Fuzzy represents an elasticsearch fuzzy query.
+type Fuzzy struct {
+	query.Fuzzy
+}
+
+// NewFuzzy instantiates and returns a new query struct.
+func NewFuzzy() (veldt.Query, error) {
+	return &Fuzzy{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Fuzzy) Get() (elastic.Query, error) {
+	if !q.CaseSensitive {
+		return nil, fmt.Errorf("case-insensitive fuzzy queries are not supported by elasticsearch")
+	}
+	return elastic.NewFuzzyQuery(q.Field, q.Value).
+		Fuzziness(q.Distance).
+		PrefixLength(0).
+		MaxExpansions(maxFuzzyExpansions).
+		Transpositions(false), nil
+}
@@ -0,0 +1,45 @@
+package elastic
+
+import (
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// GeoBBox represents an elasticsearch geo bounding box query, or a pair of
+// range queries for data-space coordinate fields.
+type GeoBBox struct {
+	query.GeoBBox
+}
+
+// NewGeoBBox instantiates and returns a new query struct.
+func NewGeoBBox() (veldt.Query, error) {
+	return &GeoBBox{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *GeoBBox) Get() (elastic.Query, error) {
+	if q.IsLonLat() {
+		return elastic.NewGeoBoundingBoxQuery(q.Field).
+			TopLeft(q.Bounds.MaxY(), q.Bounds.MinX()).
+			BottomRight(q.Bounds.MinY(), q.Bounds.MaxX()), nil
+	}
+	return newPlanarBoundsQuery(q.XField, q.YField,
+		q.Bounds.MinX(),
+		q.Bounds.MaxX(),
+		q.Bounds.MinY(),
+		q.Bounds.MaxY()), nil
+}
+
+// newPlanarBoundsQuery returns a query checking that the data-space coordinate
+// fields are within the inclusive bounds.
+func newPlanarBoundsQuery(xField string, yField string, minX, maxX, minY, maxY float64) *elastic.BoolQuery {
+	return elastic.NewBoolQuery().
+		Must(elastic.NewRangeQuery(xField).
+			Gte(minX).
+			Lte(maxX)).
+		Must(elastic.NewRangeQuery(yField).
+			Gte(minY).
+			Lte(maxY))
+}
@@ -0,0 +1,53 @@
+package elastic
+
+import (
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+const (
+	// planarDistanceScript tests whether the data-space point is within the
+	// euclidean distance of the center.
+	planarDistanceScript = `if (doc[xField].empty || doc[yField].empty) { return false };` +
+		`def dx = doc[xField].value - x; def dy = doc[yField].value - y;` +
+		`return dx * dx + dy * dy <= distance * distance;`
+)
+
+// GeoDistance represents an elasticsearch geo distance query, or a scripted
+// distance query for data-space coordinate fields.
+type GeoDistance struct {
+	query.GeoDistance
+}
+
+// NewGeoDistance instantiates and returns a new query struct.
+func NewGeoDistance() (veldt.Query, error) {
+	return &GeoDistance{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *GeoDistance) Get() (elastic.Query, error) {
+	if q.IsLonLat() {
+		return elastic.NewGeoDistanceQuery(q.Field).
+			Point(q.Center.Y, q.Center.X).
+			Distance(fmt.Sprintf("%vm", q.Distance)), nil
+	}
+	// NOTE: data-space distances require dynamic scripting to be enabled, the
+	// bounding box of the circle is used to limit the documents scripted.
+	script := elastic.NewScript(planarDistanceScript).
+		Lang("groovy").
+		Param("xField", q.XField).
+		Param("yField", q.YField).
+		Param("x", q.Center.X).
+		Param("y", q.Center.Y).
+		Param("distance", q.Distance)
+	return newPlanarBoundsQuery(q.XField, q.YField,
+		q.Center.X-q.Distance,
+		q.Center.X+q.Distance,
+		q.Center.Y-q.Distance,
+		q.Center.Y+q.Distance).
+		Must(elastic.NewScriptQuery(script)), nil
+}
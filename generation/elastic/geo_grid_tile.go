@@ -0,0 +1,141 @@
+package elastic
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+const (
+	// maxGridSize is the maximum number of grid cells returned for a tile.
+	maxGridSize = 1 << 21
+)
+
+// GeoGridTile represents an elasticsearch implementation of the geo-grid
+// tile. The geo_point field is aggregated into geohash or geotile cells which
+// are then re-binned into a heatmap.
+type GeoGridTile struct {
+	Elastic
+	tile.GeoGrid
+}
+
+// NewGeoGridTile instantiates and returns a new tile struct.
+func NewGeoGridTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &GeoGridTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *GeoGridTile) Parse(params map[string]interface{}) error {
+	return t.GeoGrid.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *GeoGridTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create search service
+	search, err := t.CreateSearchService(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// create root query
+	q, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	bounds := t.TileBounds(coord)
+	q.Must(elastic.NewGeoBoundingBoxQuery(t.GeoField).
+		TopLeft(bounds.MaxY(), bounds.MinX()).
+		BottomRight(bounds.MinY(), bounds.MaxX()))
+	// set the query
+	search.Query(q)
+
+	// set the aggregation
+	search.Aggregation("grid", t.getAggs(coord))
+
+	// send query
+	res, err := search.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	// parse aggregation
+	grid, ok := res.Aggregations.Terms("grid")
+	if !ok {
+		return nil, fmt.Errorf("grid aggregation `grid` was not found")
+	}
+
+	// re-bin the cells
+	bins := make([]uint32, t.Resolution*t.Resolution)
+	for _, bucket := range grid.Buckets {
+		center, err := t.GetCellCenter(fmt.Sprintf("%v", bucket.Key))
+		if err != nil {
+			return nil, err
+		}
+		bin, ok := t.GetBin(coord, center)
+		if !ok {
+			continue
+		}
+		bins[bin] += uint32(bucket.DocCount)
+	}
+
+	// convert to byte array
+	bits := make([]byte, len(bins)*4)
+	for i, bin := range bins {
+		binary.LittleEndian.PutUint32(bits[i*4:i*4+4], bin)
+	}
+	return bits, nil
+}
+
+// getAggs returns the grid aggregation, sized to return every cell within the
+// tile. Grids of more than maxGridSize cells, which only occur with a fine
+// explicit precision, are truncated to the most populated cells.
+func (t *GeoGridTile) getAggs(coord *binning.TileCoord) elastic.Aggregation {
+	precision := t.GetPrecision(coord)
+	size := t.GetCellCount(coord)
+	if size > maxGridSize {
+		size = maxGridSize
+	}
+	if t.GridType == tile.GeohashGrid {
+		return elastic.NewGeoHashGridAggregation().
+			Field(t.GeoField).
+			Precision(precision).
+			Size(size).
+			ShardSize(size)
+	}
+	return &geotileGridAggregation{
+		field:     t.GeoField,
+		precision: precision,
+		size:      size,
+	}
+}
+
+// geotileGridAggregation represents a `geotile_grid` aggregation, which is not
+// provided by the client library.
+type geotileGridAggregation struct {
+	field     string
+	precision int
+	size      int
+}
+
+// Source returns the JSON-serializable data of the aggregation.
+func (a *geotileGridAggregation) Source() (interface{}, error) {
+	return map[string]interface{}{
+		"geotile_grid": map[string]interface{}{
+			"field":      a.field,
+			"precision":  a.precision,
+			"size":       a.size,
+			"shard_size": a.size,
+		},
+	}, nil
+}
@@ -0,0 +1,67 @@
+package elastic_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/elastic"
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("GeoGridTile", func() {
+
+	var server *httptest.Server
+	var bodies []string
+
+	BeforeEach(func() {
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if !strings.HasSuffix(r.URL.Path, "/_search") {
+				w.Write([]byte(`{}`))
+				return
+			}
+			var body []byte
+			if reader, err := gzip.NewReader(r.Body); err == nil {
+				body, _ = ioutil.ReadAll(reader)
+			}
+			bodies = append(bodies, string(body))
+			w.Write([]byte(`{
+				"hits": { "total": 0, "hits": [] },
+				"aggregations": { "grid": { "buckets": [] } }
+			}`))
+		}))
+	})
+
+	AfterEach(func() {
+		elastic.CloseClients()
+		server.Close()
+	})
+
+	It("should size the grid aggregation by the cells within the tile", func() {
+		t, err := elastic.NewGeoGridTile(&elastic.Config{
+			Hosts: []string{server.URL},
+		})()
+		Expect(err).To(BeNil())
+		err = t.Parse(JSON(`{"geoField": "location", "precision": 5, "resolution": 4}`))
+		Expect(err).To(BeNil())
+		coord := &binning.TileCoord{X: 0, Y: 0, Z: 3}
+		_, err = t.Create("points", coord, nil)
+		Expect(err).To(BeNil())
+		Expect(bodies).To(HaveLen(1))
+		Expect(bodies[0]).To(ContainSubstring(`"geohash_grid"`))
+		size := t.(*elastic.GeoGridTile).GetCellCount(coord)
+		Expect(size).To(BeNumerically(">", 16))
+		Expect(bodies[0]).To(ContainSubstring(`"size":` + strconv.Itoa(size)))
+		Expect(t.(*elastic.GeoGridTile).GridType).To(Equal(tile.GeohashGrid))
+	})
+})
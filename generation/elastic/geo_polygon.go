@@ -0,0 +1,66 @@
+package elastic
+
+import (
+	"math"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+const (
+	// planarPolygonScript tests whether the data-space point is within the
+	// polygon using the even-odd rule.
+	planarPolygonScript = `if (doc[xField].empty || doc[yField].empty) { return false };` +
+		`def x = doc[xField].value; def y = doc[yField].value;` +
+		`def inside = false; def j = xs.size() - 1;` +
+		`for (int i = 0; i < xs.size(); i++) {` +
+		`if (((ys[i] > y) != (ys[j] > y)) && (x < (xs[j] - xs[i]) * (y - ys[i]) / (ys[j] - ys[i]) + xs[i])) { inside = !inside };` +
+		`j = i };` +
+		`return inside;`
+)
+
+// GeoPolygon represents an elasticsearch geo polygon query, or a scripted
+// point in polygon query for data-space coordinate fields.
+type GeoPolygon struct {
+	query.GeoPolygon
+}
+
+// NewGeoPolygon instantiates and returns a new query struct.
+func NewGeoPolygon() (veldt.Query, error) {
+	return &GeoPolygon{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *GeoPolygon) Get() (elastic.Query, error) {
+	if q.IsLonLat() {
+		polygon := elastic.NewGeoPolygonQuery(q.Field)
+		for _, point := range q.Points {
+			polygon.AddPoint(point.Y, point.X)
+		}
+		return polygon, nil
+	}
+	// NOTE: data-space polygons require dynamic scripting to be enabled, the
+	// bounding box of the polygon is used to limit the documents scripted.
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	xs := make([]float64, len(q.Points))
+	ys := make([]float64, len(q.Points))
+	for i, point := range q.Points {
+		xs[i] = point.X
+		ys[i] = point.Y
+		minX = math.Min(minX, point.X)
+		maxX = math.Max(maxX, point.X)
+		minY = math.Min(minY, point.Y)
+		maxY = math.Max(maxY, point.Y)
+	}
+	script := elastic.NewScript(planarPolygonScript).
+		Lang("groovy").
+		Param("xField", q.XField).
+		Param("yField", q.YField).
+		Param("xs", xs).
+		Param("ys", ys)
+	return newPlanarBoundsQuery(q.XField, q.YField, minX, maxX, minY, maxY).
+		Must(elastic.NewScriptQuery(script)), nil
+}
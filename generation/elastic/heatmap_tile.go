@@ -14,11 +14,10 @@ type HeatmapTile struct {
 }
 
 // NewHeatmapTile instantiates and returns a new tile struct.
-func NewHeatmapTile(host, port string) veldt.TileCtor {
+func NewHeatmapTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		h := &HeatmapTile{}
-		h.Host = host
-		h.Port = port
+		h.Config = cfg
 		return h, nil
 	}
 }
@@ -28,11 +27,16 @@ func (h *HeatmapTile) Parse(params map[string]interface{}) error {
 	return h.Bivariate.Parse(params)
 }
 
+// Empty returns the tile containing no data.
+func (h *HeatmapTile) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return make([]byte, h.Resolution*h.Resolution*4), nil
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := h.CreateSearchService(uri)
+	search, err := h.CreateSearchService(uri, query)
 	if err != nil {
 		return nil, err
 	}
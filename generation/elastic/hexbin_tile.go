@@ -0,0 +1,115 @@
+package elastic
+
+import (
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// HexbinTile represents an elasticsearch implementation of the hexbin tile.
+type HexbinTile struct {
+	Elastic
+	Bivariate
+	tile.Hexbin
+}
+
+// NewHexbinTile instantiates and returns a new tile struct.
+func NewHexbinTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		h := &HexbinTile{}
+		h.Config = cfg
+		return h, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (h *HexbinTile) Parse(params map[string]interface{}) error {
+	err := h.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return h.Hexbin.Parse(params)
+}
+
+// QueryBivariate returns the bivariate tile padded to cover the hexagons on the
+// boundaries of the tile coord, whose bounds are those of the data queried.
+func (h *HexbinTile) QueryBivariate(coord *binning.TileCoord) *tile.Bivariate {
+	return h.Bivariate.PaddedBivariate(coord, h.Hexbin.Padding(h.Resolution))
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (h *HexbinTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create search service
+	search, err := h.CreateSearchService(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// pad the tile to cover the hexagons on its boundaries
+	padding := h.Hexbin.Padding(h.Resolution)
+	padded := &Bivariate{Bivariate: *h.QueryBivariate(coord)}
+
+	// create root query
+	q, err := h.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	q.Must(padded.GetQuery(coord))
+	// set the query
+	search.Query(q)
+
+	// get aggs
+	var aggs map[string]elastic.Aggregation
+	if h.Metric != tile.CountMetric {
+		sum := elastic.NewSumAggregation().Field(h.ValueField)
+		aggs = padded.GetAggsWithNested(coord, "value", sum)
+	} else {
+		aggs = padded.GetAggs(coord)
+	}
+	// set the aggregation
+	search.Aggregation("x", aggs["x"])
+
+	// send query
+	res, err := search.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	// get bins
+	bins, err := padded.GetBins(coord, &res.Aggregations)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the sub-bin values
+	counts := make([]float64, len(bins))
+	var sums []float64
+	if h.Metric != tile.CountMetric {
+		sums = make([]float64, len(bins))
+	}
+	for i, bin := range bins {
+		if bin == nil {
+			continue
+		}
+		counts[i] = float64(bin.DocCount)
+		if sums != nil {
+			sum, ok := bin.Sum("value")
+			if !ok {
+				return nil, fmt.Errorf("sum aggregation `value` was not found")
+			}
+			if sum.Value != nil {
+				sums[i] = *sum.Value
+			}
+		}
+	}
+
+	// aggregate into hexagons
+	hexes := h.Hexbin.AggregateBins(coord, h.Resolution, padding, counts, sums)
+	return h.Hexbin.Encode(hexes), nil
+}
@@ -0,0 +1,322 @@
+package elastic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+const (
+	// maxResolvedIndices is the maximum number of indices a templated index
+	// is expanded into before falling back to a wildcard.
+	maxResolvedIndices = 512
+)
+
+// TimeRange represents the bounds of a time field, used to route templated
+// index names to the indices containing the range. A nil bound is unbounded.
+type TimeRange struct {
+	Field string
+	Min   *time.Time
+	Max   *time.Time
+}
+
+// intersect returns the intersection of the two ranges.
+func (r *TimeRange) intersect(other *TimeRange) *TimeRange {
+	res := &TimeRange{
+		Field: r.Field,
+		Min:   r.Min,
+		Max:   r.Max,
+	}
+	if other.Min != nil && (res.Min == nil || other.Min.After(*res.Min)) {
+		res.Min = other.Min
+	}
+	if other.Max != nil && (res.Max == nil || other.Max.Before(*res.Max)) {
+		res.Max = other.Max
+	}
+	return res
+}
+
+// union returns the smallest range containing both ranges.
+func (r *TimeRange) union(other *TimeRange) *TimeRange {
+	res := &TimeRange{
+		Field: r.Field,
+	}
+	if r.Min != nil && other.Min != nil {
+		res.Min = r.Min
+		if other.Min.Before(*r.Min) {
+			res.Min = other.Min
+		}
+	}
+	if r.Max != nil && other.Max != nil {
+		res.Max = r.Max
+		if other.Max.After(*r.Max) {
+			res.Max = other.Max
+		}
+	}
+	return res
+}
+
+// ResolveIndices returns the indices to search for the provided uri. Comma
+// separated lists and wildcards are passed through. Index names containing a
+// date template, such as `logs-{yyyy.MM.dd}`, are expanded into the indices
+// overlapping the time range of the `IndexTimeField` of the config, as
+// restricted by the query and the provided ranges. If the time range is
+// unbounded, the template is replaced by a wildcard. The boolean return value
+// indicates whether any template was expanded.
+func (e *Elastic) ResolveIndices(uri string, query veldt.Query, ranges ...*TimeRange) ([]string, bool, error) {
+	var field string
+	if e.Config != nil {
+		field = e.Config.IndexTimeField
+	}
+	bounds := &TimeRange{
+		Field: field,
+	}
+	if field != "" {
+		bounds = bounds.intersect(getQueryTimeRange(field, query))
+		for _, r := range ranges {
+			if r != nil && r.Field == field {
+				bounds = bounds.intersect(r)
+			}
+		}
+	}
+	indices := make([]string, 0)
+	templated := false
+	for _, index := range strings.Split(uri, ",") {
+		index = strings.TrimSpace(index)
+		if index == "" {
+			continue
+		}
+		if !strings.Contains(index, "{") {
+			indices = append(indices, index)
+			continue
+		}
+		templated = true
+		expanded, err := expandIndexTemplate(index, bounds)
+		if err != nil {
+			return nil, false, err
+		}
+		indices = append(indices, expanded...)
+	}
+	if len(indices) == 0 {
+		return nil, false, fmt.Errorf("uri `%s` does not specify an index", uri)
+	}
+	return indices, templated, nil
+}
+
+// getQueryTimeRange returns the range of the field which a document must
+// fall within to match the query.
+func getQueryTimeRange(field string, query veldt.Query) *TimeRange {
+	unbounded := &TimeRange{
+		Field: field,
+	}
+	switch q := query.(type) {
+	case *Range:
+		if q.Field != field {
+			return unbounded
+		}
+		res := &TimeRange{
+			Field: field,
+		}
+		res.Min = castBound(q.GTE, q.GT)
+		res.Max = castBound(q.LTE, q.LT)
+		return res
+	case *BinaryExpression:
+		left := getQueryTimeRange(field, q.Left)
+		right := getQueryTimeRange(field, q.Right)
+		switch q.Op {
+		case veldt.And:
+			return left.intersect(right)
+		case veldt.Or:
+			return left.union(right)
+		}
+	case *NaryExpression:
+		var res *TimeRange
+		for _, operand := range q.Queries {
+			r := getQueryTimeRange(field, operand)
+			switch {
+			case res == nil:
+				res = r
+			case q.Op == veldt.And:
+				res = res.intersect(r)
+			case q.Op == veldt.Or:
+				res = res.union(r)
+			}
+		}
+		if res != nil {
+			return res
+		}
+	}
+	// negations and other queries do not restrict the range
+	return unbounded
+}
+
+// castBound returns the first of the provided bounds which can be cast to a
+// time. Bounds such as date math expressions are considered unbounded.
+func castBound(vals ...interface{}) *time.Time {
+	for _, val := range vals {
+		if val == nil {
+			continue
+		}
+		t, err := tile.CastTime(val)
+		if err != nil {
+			return nil
+		}
+		return &t
+	}
+	return nil
+}
+
+// indexTemplate represents an index name containing a date template.
+type indexTemplate struct {
+	prefix string
+	layout string
+	suffix string
+	step   func(time.Time) time.Time
+	trunc  func(time.Time) time.Time
+}
+
+// indexUnit represents a date token of an index template.
+type indexUnit struct {
+	token    string
+	layout   string
+	duration time.Duration
+	trunc    func(time.Time) time.Time
+	step     func(time.Time) time.Time
+}
+
+var indexUnits = []*indexUnit{
+	{
+		token:    "yyyy",
+		layout:   "2006",
+		duration: time.Hour * 24 * 365,
+		trunc: func(t time.Time) time.Time {
+			return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		},
+		step: func(t time.Time) time.Time {
+			return t.AddDate(1, 0, 0)
+		},
+	},
+	{
+		token:    "yy",
+		layout:   "06",
+		duration: time.Hour * 24 * 365,
+		trunc: func(t time.Time) time.Time {
+			return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		},
+		step: func(t time.Time) time.Time {
+			return t.AddDate(1, 0, 0)
+		},
+	},
+	{
+		token:    "MM",
+		layout:   "01",
+		duration: time.Hour * 24 * 28,
+		trunc: func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		},
+		step: func(t time.Time) time.Time {
+			return t.AddDate(0, 1, 0)
+		},
+	},
+	{
+		token:    "dd",
+		layout:   "02",
+		duration: time.Hour * 24,
+		trunc: func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		},
+		step: func(t time.Time) time.Time {
+			return t.AddDate(0, 0, 1)
+		},
+	},
+	{
+		token:    "HH",
+		layout:   "15",
+		duration: time.Hour,
+		trunc: func(t time.Time) time.Time {
+			return t.Truncate(time.Hour)
+		},
+		step: func(t time.Time) time.Time {
+			return t.Add(time.Hour)
+		},
+	},
+}
+
+// parseIndexTemplate parses an index name containing a single date template
+// of the form `{yyyy.MM.dd}`. Supported tokens are `yyyy`, `yy`, `MM`, `dd`
+// and `HH`.
+func parseIndexTemplate(index string) (*indexTemplate, error) {
+	start := strings.Index(index, "{")
+	end := strings.Index(index, "}")
+	if end < start || strings.Count(index, "{") != 1 || strings.Count(index, "}") != 1 {
+		return nil, fmt.Errorf("index template `%s` must contain a single `{...}` date pattern", index)
+	}
+	pattern := index[start+1 : end]
+	layout := ""
+	var unit *indexUnit
+	for i := 0; i < len(pattern); {
+		rest := pattern[i:]
+		matched := false
+		for _, u := range indexUnits {
+			if strings.HasPrefix(rest, u.token) {
+				layout += u.layout
+				i += len(u.token)
+				// index granularity is that of the finest token
+				if unit == nil || u.duration < unit.duration {
+					unit = u
+				}
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if strings.ContainsAny(rest[:1], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") {
+			return nil, fmt.Errorf("index template `%s` contains unsupported date token at `%s`", index, rest)
+		}
+		layout += rest[:1]
+		i++
+	}
+	if unit == nil {
+		return nil, fmt.Errorf("index template `%s` does not contain a date token", index)
+	}
+	return &indexTemplate{
+		prefix: index[:start],
+		layout: layout,
+		suffix: index[end+1:],
+		step:   unit.step,
+		trunc:  unit.trunc,
+	}, nil
+}
+
+// expandIndexTemplate returns the indices of the template overlapping the
+// time range. Index dates are in UTC.
+func expandIndexTemplate(index string, bounds *TimeRange) ([]string, error) {
+	template, err := parseIndexTemplate(index)
+	if err != nil {
+		return nil, err
+	}
+	wildcard := []string{template.prefix + "*" + template.suffix}
+	if bounds.Min == nil || bounds.Max == nil {
+		return wildcard, nil
+	}
+	min := bounds.Min.UTC()
+	max := bounds.Max.UTC()
+	if max.Before(min) {
+		// the range is empty, search the first index only so that the
+		// response is well formed
+		max = min
+	}
+	indices := make([]string, 0)
+	for t := template.trunc(min); !t.After(max); t = template.step(t) {
+		if len(indices) == maxResolvedIndices {
+			return wildcard, nil
+		}
+		indices = append(indices, template.prefix+t.Format(template.layout)+template.suffix)
+	}
+	return indices, nil
+}
@@ -0,0 +1,115 @@
+package elastic_test
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/generation/elastic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolveIndices", func() {
+
+	const (
+		jan1 = 1483228800000.0 // 2017-01-01T00:00:00Z
+		jan3 = 1483401600000.0 // 2017-01-03T00:00:00Z
+	)
+
+	e := &elastic.Elastic{
+		Config: &elastic.Config{
+			IndexTimeField: "timestamp",
+		},
+	}
+
+	timeRange := func(field string, gte interface{}, lt interface{}) veldt.Query {
+		q := &elastic.Range{}
+		q.Field = field
+		q.GTE = gte
+		q.LT = lt
+		return q
+	}
+
+	It("should pass through lists and wildcards", func() {
+		indices, templated, err := e.ResolveIndices("logs-*,metrics", nil)
+		Expect(err).To(BeNil())
+		Expect(templated).To(BeFalse())
+		Expect(indices).To(Equal([]string{"logs-*", "metrics"}))
+	})
+
+	It("should route templates using range queries on the time field", func() {
+		indices, templated, err := e.ResolveIndices("logs-{yyyy.MM.dd}",
+			timeRange("timestamp", jan1, jan3))
+		Expect(err).To(BeNil())
+		Expect(templated).To(BeTrue())
+		Expect(indices).To(Equal([]string{"logs-2017.01.01", "logs-2017.01.02", "logs-2017.01.03"}))
+	})
+
+	It("should route templates using the provided time ranges", func() {
+		f := &elastic.Frequency{}
+		f.FrequencyField = "timestamp"
+		f.GTE = "2016-12-31T12:00:00Z"
+		f.LT = "2017-01-01T12:00:00Z"
+		r, err := f.GetTimeRange()
+		Expect(err).To(BeNil())
+		indices, _, err := e.ResolveIndices("logs-{yyyy.MM.dd}", nil, r)
+		Expect(err).To(BeNil())
+		Expect(indices).To(Equal([]string{"logs-2016.12.31", "logs-2017.01.01"}))
+	})
+
+	It("should intersect conjunctions and union disjunctions", func() {
+		and := &elastic.BinaryExpression{}
+		and.Left = timeRange("timestamp", jan1, nil)
+		and.Right = timeRange("timestamp", nil, jan3)
+		and.Op = veldt.And
+		indices, _, err := e.ResolveIndices("logs-{yyyy.MM}", and)
+		Expect(err).To(BeNil())
+		Expect(indices).To(Equal([]string{"logs-2017.01"}))
+
+		or := &elastic.BinaryExpression{}
+		or.Left = timeRange("timestamp", "2016-11-15T00:00:00Z", "2016-11-16T00:00:00Z")
+		or.Right = timeRange("timestamp", jan1, jan3)
+		or.Op = veldt.Or
+		indices, _, err = e.ResolveIndices("logs-{yyyy.MM}", or)
+		Expect(err).To(BeNil())
+		Expect(indices).To(Equal([]string{"logs-2016.11", "logs-2016.12", "logs-2017.01"}))
+	})
+
+	It("should use the finest token of the template", func() {
+		indices, _, err := e.ResolveIndices("logs-{dd.MM.yyyy}",
+			timeRange("timestamp", jan1, jan3))
+		Expect(err).To(BeNil())
+		Expect(indices).To(Equal([]string{"logs-01.01.2017", "logs-02.01.2017", "logs-03.01.2017"}))
+	})
+
+	It("should use a wildcard if the time range is unbounded", func() {
+		not := &elastic.UnaryExpression{}
+		not.Query = timeRange("timestamp", jan1, jan3)
+		not.Op = veldt.Not
+		for _, query := range []veldt.Query{
+			nil,
+			timeRange("other", jan1, jan3),
+			timeRange("timestamp", jan1, nil),
+			timeRange("timestamp", "now-1d", jan3),
+			not,
+		} {
+			indices, templated, err := e.ResolveIndices("logs-{yyyy.MM.dd}-v1,metrics", query)
+			Expect(err).To(BeNil())
+			Expect(templated).To(BeTrue())
+			Expect(indices).To(Equal([]string{"logs-*-v1", "metrics"}))
+		}
+	})
+
+	It("should use a wildcard if the range spans too many indices", func() {
+		indices, _, err := e.ResolveIndices("logs-{yyyy.MM.dd.HH}",
+			timeRange("timestamp", 0.0, jan1))
+		Expect(err).To(BeNil())
+		Expect(indices).To(Equal([]string{"logs-*"}))
+	})
+
+	It("should return an error for invalid templates", func() {
+		for _, uri := range []string{"logs-{yyyy", "logs-{ww}", "logs-{}", "logs-{yyyy}-{MM}"} {
+			_, _, err := e.ResolveIndices(uri, nil)
+			Expect(err).NotTo(BeNil(), uri)
+		}
+	})
+})
@@ -17,11 +17,10 @@ type MacroEdgeTile struct {
 }
 
 // NewMacroEdgeTile instantiates and returns a new tile struct.
-func NewMacroEdgeTile(host, port string) veldt.TileCtor {
+func NewMacroEdgeTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		e := &MacroEdgeTile{}
-		e.Host = host
-		e.Port = port
+		e.Config = cfg
 		return e, nil
 	}
 }
@@ -51,7 +50,7 @@ func (e *MacroEdgeTile) Parse(params map[string]interface{}) error {
 // parameters.
 func (e *MacroEdgeTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := e.CreateSearchService(uri)
+	search, err := e.CreateSearchService(uri, query)
 	if err != nil {
 		return nil, err
 	}
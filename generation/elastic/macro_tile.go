@@ -16,11 +16,10 @@ type MacroTile struct {
 }
 
 // NewMacroTile instantiates and returns a new tile struct.
-func NewMacroTile(host, port string) veldt.TileCtor {
+func NewMacroTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		m := &MacroTile{}
-		m.Host = host
-		m.Port = port
+		m.Config = cfg
 		return m, nil
 	}
 }
@@ -38,7 +37,7 @@ func (m *MacroTile) Parse(params map[string]interface{}) error {
 // parameters.
 func (m *MacroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := m.CreateSearchService(uri)
+	search, err := m.CreateSearchService(uri, query)
 	if err != nil {
 		return nil, err
 	}
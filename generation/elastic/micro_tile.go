@@ -17,11 +17,10 @@ type MicroTile struct {
 }
 
 // NewMicroTile instantiates and returns a new tile struct.
-func NewMicroTile(host, port string) veldt.TileCtor {
+func NewMicroTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		m := &MicroTile{}
-		m.Host = host
-		m.Port = port
+		m.Config = cfg
 		return m, nil
 	}
 }
@@ -52,7 +51,7 @@ func (m *MicroTile) Parse(params map[string]interface{}) error {
 // parameters.
 func (m *MicroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := m.CreateSearchService(uri)
+	search, err := m.CreateSearchService(uri, query)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,28 @@
+package elastic
+
+import (
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Prefix represents an elasticsearch prefix query, or a regexp query if
+// case-insensitive.
+type Prefix struct {
+	query.Prefix
+}
+
+// NewPrefix instantiates and returns a new query struct.
+func NewPrefix() (veldt.Query, error) {
+	return &Prefix{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Prefix) Get() (elastic.Query, error) {
+	if q.CaseSensitive {
+		return elastic.NewPrefixQuery(q.Field, q.Value), nil
+	}
+	pattern := caseInsensitiveRegexp(query.QuoteRegexp(q.Value)) + ".*"
+	return newRegexpQuery(q.Field, pattern), nil
+}
@@ -0,0 +1,103 @@
+package elastic
+
+import (
+	"strings"
+	"unicode"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Regexp represents an elasticsearch regexp query. The optional lucene
+// operators are disabled such that the pattern has the semantics defined by
+// query.Regexp.
+type Regexp struct {
+	query.Regexp
+}
+
+// NewRegexp instantiates and returns a new query struct.
+func NewRegexp() (veldt.Query, error) {
+	return &Regexp{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Regexp) Get() (elastic.Query, error) {
+	pattern := q.Value
+	if !q.CaseSensitive {
+		pattern = caseInsensitiveRegexp(pattern)
+	}
+	return newRegexpQuery(q.Field, pattern), nil
+}
+
+func newRegexpQuery(field string, pattern string) *elastic.RegexpQuery {
+	return elastic.NewRegexpQuery(field, pattern).Flags("NONE")
+}
+
+// caseInsensitiveRegexp returns the pattern with each letter replaced by a
+// character class of both its cases, as regexp queries do not support case
+// folding.
+func caseInsensitiveRegexp(pattern string) string {
+	runes := []rune(pattern)
+	var sb strings.Builder
+	inClass := false
+	// the additional ranges and characters of the current class
+	var folded strings.Builder
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\\' && i+1 < len(runes) {
+			sb.WriteRune(r)
+			sb.WriteRune(runes[i+1])
+			i++
+			continue
+		}
+		if !inClass {
+			switch {
+			case r == '[':
+				inClass = true
+				folded.Reset()
+				sb.WriteRune(r)
+			case unicode.ToLower(r) != unicode.ToUpper(r):
+				sb.WriteRune('[')
+				sb.WriteRune(unicode.ToLower(r))
+				sb.WriteRune(unicode.ToUpper(r))
+				sb.WriteRune(']')
+			default:
+				sb.WriteRune(r)
+			}
+			continue
+		}
+		switch {
+		case r == ']':
+			sb.WriteString(folded.String())
+			sb.WriteRune(r)
+			inClass = false
+		case i+2 < len(runes) && runes[i+1] == '-' && runes[i+2] != ']':
+			// range, add the swapped case range if both bounds are letters
+			// of the same case
+			lo, hi := r, runes[i+2]
+			sb.WriteRune(lo)
+			sb.WriteRune('-')
+			sb.WriteRune(hi)
+			if unicode.IsLower(lo) && unicode.IsLower(hi) {
+				folded.WriteRune(unicode.ToUpper(lo))
+				folded.WriteRune('-')
+				folded.WriteRune(unicode.ToUpper(hi))
+			} else if unicode.IsUpper(lo) && unicode.IsUpper(hi) {
+				folded.WriteRune(unicode.ToLower(lo))
+				folded.WriteRune('-')
+				folded.WriteRune(unicode.ToLower(hi))
+			}
+			i += 2
+		default:
+			sb.WriteRune(r)
+			if unicode.IsLower(r) {
+				folded.WriteRune(unicode.ToUpper(r))
+			} else if unicode.IsUpper(r) {
+				folded.WriteRune(unicode.ToLower(r))
+			}
+		}
+	}
+	return sb.String()
+}
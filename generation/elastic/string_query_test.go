@@ -0,0 +1,106 @@
+package elastic_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/generation/elastic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+// luceneWildcard returns an anchored go regular expression equivalent to the
+// elasticsearch wildcard pattern.
+func luceneWildcard(pattern string) *regexp.Regexp {
+	expr := ""
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			expr += regexp.QuoteMeta(string(r))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			expr += ".*"
+		case r == '?':
+			expr += "."
+		default:
+			expr += regexp.QuoteMeta(string(r))
+		}
+	}
+	return regexp.MustCompile("^(?s:" + expr + ")$")
+}
+
+// evaluate returns whether the elasticsearch query source matches the value
+// of the `name` field.
+func evaluate(source map[string]interface{}, value string) bool {
+	for typ, body := range source {
+		params := body.(map[string]interface{})["name"]
+		switch typ {
+		case "prefix":
+			return strings.HasPrefix(value, params.(string))
+		case "wildcard":
+			pattern := params.(map[string]interface{})["wildcard"].(string)
+			return luceneWildcard(pattern).MatchString(value)
+		case "regexp":
+			p := params.(map[string]interface{})
+			// optional lucene operators must be disabled
+			Expect(p["flags"]).To(Equal("NONE"))
+			return regexp.MustCompile("^(?s:" + p["value"].(string) + ")$").MatchString(value)
+		case "fuzzy":
+			p := params.(map[string]interface{})
+			Expect(p["transpositions"]).To(Equal(false))
+			Expect(p["prefix_length"]).To(Equal(0.0))
+			return Levenshtein(p["value"].(string), value) <= int(p["fuzziness"].(float64))
+		}
+		Fail("unexpected query type `" + typ + "`")
+	}
+	return false
+}
+
+var _ = Describe("String queries", func() {
+
+	ctors := map[string]veldt.QueryCtor{
+		"prefix":   elastic.NewPrefix,
+		"wildcard": elastic.NewWildcard,
+		"regexp":   elastic.NewRegexp,
+		"fuzzy":    elastic.NewFuzzy,
+	}
+
+	for _, c := range StringQueryCases {
+		c := c
+		It("should conform: "+c.Name, func() {
+			q, err := ctors[c.Query]()
+			Expect(err).To(BeNil())
+			err = q.Parse(JSON(c.Params))
+			Expect(err).To(BeNil())
+			query, err := q.(elastic.Query).Get()
+			for _, backend := range c.Unsupported {
+				if backend == "elastic" {
+					Expect(err).NotTo(BeNil())
+					return
+				}
+			}
+			Expect(err).To(BeNil())
+			src, err := query.Source()
+			Expect(err).To(BeNil())
+			bytes, err := json.Marshal(src)
+			Expect(err).To(BeNil())
+			source := make(map[string]interface{})
+			err = json.Unmarshal(bytes, &source)
+			Expect(err).To(BeNil())
+			for _, value := range c.Matches {
+				Expect(evaluate(source, value)).To(BeTrue(), value)
+			}
+			for _, value := range c.Misses {
+				Expect(evaluate(source, value)).To(BeFalse(), value)
+			}
+		})
+	}
+
+})
@@ -15,11 +15,10 @@ type TargetTermCountTile struct {
 }
 
 // NewTargetTermCountTile instantiates and returns a new tile struct.
-func NewTargetTermCountTile(host, port string) veldt.TileCtor {
+func NewTargetTermCountTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		t := &TargetTermCountTile{}
-		t.Host = host
-		t.Port = port
+		t.Config = cfg
 		return t, nil
 	}
 }
@@ -37,7 +36,7 @@ func (t *TargetTermCountTile) Parse(params map[string]interface{}) error {
 // parameters.
 func (t *TargetTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := t.CreateSearchService(uri)
+	search, err := t.CreateSearchService(uri, query)
 	if err != nil {
 		return nil, err
 	}
@@ -16,11 +16,10 @@ type TargetTermFrequencyTile struct {
 }
 
 // NewTargetTermFrequencyTile instantiates and returns a new tile struct.
-func NewTargetTermFrequencyTile(host, port string) veldt.TileCtor {
+func NewTargetTermFrequencyTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		t := &TargetTermFrequencyTile{}
-		t.Host = host
-		t.Port = port
+		t.Config = cfg
 		return t, nil
 	}
 }
@@ -38,7 +37,11 @@ func (t *TargetTermFrequencyTile) Parse(params map[string]interface{}) error {
 // parameters.
 func (t *TargetTermFrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := t.CreateSearchService(uri)
+	timeRange, err := t.Frequency.GetTimeRange()
+	if err != nil {
+		return nil, err
+	}
+	search, err := t.CreateSearchService(uri, query, timeRange)
 	if err != nil {
 		return nil, err
 	}
@@ -16,11 +16,10 @@ type TopTermFrequencyTile struct {
 }
 
 // NewTopTermFrequencyTile instantiates and returns a new tile struct.
-func NewTopTermFrequencyTile(host, port string) veldt.TileCtor {
+func NewTopTermFrequencyTile(cfg *Config) veldt.TileCtor {
 	return func() (veldt.Tile, error) {
 		t := &TopTermFrequencyTile{}
-		t.Host = host
-		t.Port = port
+		t.Config = cfg
 		return t, nil
 	}
 }
@@ -38,7 +37,11 @@ func (t *TopTermFrequencyTile) Parse(params map[string]interface{}) error {
 // parameters.
 func (t *TopTermFrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
-	search, err := t.CreateSearchService(uri)
+	timeRange, err := t.Frequency.GetTimeRange()
+	if err != nil {
+		return nil, err
+	}
+	search, err := t.CreateSearchService(uri, query, timeRange)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,41 @@
+package elastic
+
+import (
+	"strings"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Wildcard represents an elasticsearch wildcard query, or a regexp query if
+// case-insensitive.
+type Wildcard struct {
+	query.Wildcard
+}
+
+// NewWildcard instantiates and returns a new query struct.
+func NewWildcard() (veldt.Query, error) {
+	return &Wildcard{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Wildcard) Get() (elastic.Query, error) {
+	if q.CaseSensitive {
+		// the wildcard syntax is that of elasticsearch
+		return elastic.NewWildcardQuery(q.Field, q.Value), nil
+	}
+	var sb strings.Builder
+	for _, token := range q.Tokens() {
+		switch token.Wildcard {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(caseInsensitiveRegexp(query.QuoteRegexp(string(token.Literal))))
+		}
+	}
+	return newRegexpQuery(q.Field, sb.String()), nil
+}
@@ -0,0 +1,206 @@
+package elastic
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/meta"
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// MaxZoomExtremaResolution is the maximum number of bins across each axis
+	// of the deepest zoom level, limiting the number of histogram buckets to
+	// its square.
+	MaxZoomExtremaResolution = 4096
+	// levelBinScript returns the index of the bin of the document within the
+	// root tile, which a histogram of interval 1 buckets by bin.
+	levelBinScript = `return Math.floor((doc[xField].value - minX) / sizeX) + Math.floor((doc[yField].value - minY) / sizeY) * columns;`
+)
+
+// ZoomExtremaMeta represents an elasticsearch implementation of the per zoom
+// level heatmap extrema meta data.
+type ZoomExtremaMeta struct {
+	Elastic
+	Bivariate
+	meta.ZoomExtrema
+}
+
+// NewZoomExtremaMeta instantiates and returns a pointer to a new generator.
+func NewZoomExtremaMeta(cfg *Config) veldt.MetaCtor {
+	return func() (veldt.Meta, error) {
+		m := &ZoomExtremaMeta{}
+		m.Config = cfg
+		return m, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (m *ZoomExtremaMeta) Parse(params map[string]interface{}) error {
+	err := m.ZoomExtrema.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = m.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	// every bin of the deepest zoom level is bucketed at once
+	if m.Resolution > MaxZoomExtremaResolution>>m.MaxZoom {
+		return json.NewFieldError("maxZoom", json.InvalidCode,
+			fmt.Sprintf("`maxZoom` of %d at a `resolution` of %d exceeds the maximum of %d bins across each axis",
+				m.MaxZoom, m.Resolution, MaxZoomExtremaResolution))
+	}
+	// the latitude bins are not uniform, and cannot be histogrammed
+	if m.Projection == tile.MercatorProjection {
+		return json.NewFieldError("projection", json.InvalidCode,
+			"`mercator` projection is not supported by elastic zoom extrema")
+	}
+	return nil
+}
+
+// Create generates metadata from the provided URI.
+func (m *ZoomExtremaMeta) Create(uri string) ([]byte, error) {
+	return m.CreateScoped(uri, nil, nil, nil)
+}
+
+// CreateScoped generates metadata from the provided URI, restricted to the
+// documents matching the query and within the tile of the scope. The extrema
+// and percentiles of the bins are computed by pipeline aggregations, such that
+// the bins themselves are not returned. The percentiles_bucket aggregation
+// does not interpolate, such that percentiles approximate those defined by
+// meta.ZoomExtrema with the value of the closest rank.
+func (m *ZoomExtremaMeta) CreateScoped(uri string, scope *tile.Bivariate, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	root := &binning.TileCoord{}
+	levels := make(map[string]*meta.LevelExtrema)
+	for _, zoom := range m.Zooms() {
+		level := &Bivariate{Bivariate: *m.LevelBivariate(zoom)}
+		// create search service
+		search, err := m.CreateSearchService(uri, query)
+		if err != nil {
+			return nil, err
+		}
+		// create root query
+		q, err := m.CreateQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		if scope != nil {
+			b := &Bivariate{Bivariate: *scope}
+			q.Must(b.GetQuery(coord))
+		}
+		// add tiling query
+		q.Must(level.GetQuery(root))
+		search.Query(q)
+		// add aggs
+		search.Aggregation("bins", m.getLevelAggs(level, root))
+		search.Aggregation("max", elastic.NewMaxBucketAggregation().
+			BucketsPath("bins>_count"))
+		search.Aggregation("min", elastic.NewMinBucketAggregation().
+			BucketsPath("bins>_count"))
+		filterPaths := []string{"aggregations.max", "aggregations.min"}
+		if len(m.Percentiles) > 0 {
+			search.Aggregation("percentiles", &percentilesBucketAggregation{
+				bucketsPath: "bins>_count",
+				percents:    m.Percentiles,
+			})
+			filterPaths = append(filterPaths, "aggregations.percentiles")
+		}
+		search.FilterPath(filterPaths...)
+		// send query
+		res, err := search.Do()
+		if err != nil {
+			return nil, err
+		}
+		extrema, err := m.getLevelExtrema(&res.Aggregations)
+		if err != nil {
+			return nil, err
+		}
+		levels[meta.LevelKey(zoom)] = extrema
+	}
+	return json.Marshal(levels)
+}
+
+// getLevelAggs returns the histogram aggregation of the bins of the root
+// tile, keyed by the index of each bin. The bins are the same as those of the
+// histograms of a heatmap tile.
+func (m *ZoomExtremaMeta) getLevelAggs(level *Bivariate, root *binning.TileCoord) elastic.Aggregation {
+	bounds := level.TileBounds(root)
+	intervalX := int64(math.Max(1, level.BinSizeX(root)))
+	intervalY := int64(math.Max(1, level.BinSizeY(root)))
+	// NOTE: a single histogram of both axes allows the pipeline aggregations
+	// to span every bin, which requires dynamic scripting to be enabled.
+	script := elastic.NewScript(levelBinScript).
+		Lang("groovy").
+		Param("xField", level.XField).
+		Param("yField", level.YField).
+		Param("minX", int64(bounds.MinX())).
+		Param("minY", int64(bounds.MinY())).
+		Param("sizeX", intervalX).
+		Param("sizeY", intervalY).
+		Param("columns", int64(math.Ceil(bounds.RangeX()/float64(intervalX)))+1)
+	return elastic.NewHistogramAggregation().
+		Script(script).
+		Interval(1).
+		MinDocCount(1)
+}
+
+func (m *ZoomExtremaMeta) getLevelExtrema(aggs *elastic.Aggregations) (*meta.LevelExtrema, error) {
+	max, ok := aggs.MaxBucket("max")
+	if !ok {
+		return nil, fmt.Errorf("max bucket aggregation `max` was not found")
+	}
+	min, ok := aggs.MinBucket("min")
+	if !ok {
+		return nil, fmt.Errorf("min bucket aggregation `min` was not found")
+	}
+	// there are no bins if no documents are within the bounds
+	if max.Value == nil || min.Value == nil {
+		return nil, nil
+	}
+	level := &meta.LevelExtrema{
+		Min: *min.Value,
+		Max: *max.Value,
+	}
+	if len(m.Percentiles) > 0 {
+		agg, ok := aggs.Percentiles("percentiles")
+		if !ok {
+			return nil, fmt.Errorf("percentiles bucket aggregation `percentiles` was not found")
+		}
+		level.Percentiles = make(map[string]float64, len(m.Percentiles))
+		for _, p := range m.Percentiles {
+			val, ok := agg.Values[meta.PercentileKey(p)]
+			if !ok {
+				val, ok = agg.Values[strconv.FormatFloat(p, 'f', 1, 64)]
+			}
+			if ok {
+				level.Percentiles[meta.PercentileKey(p)] = val
+			}
+		}
+	}
+	return level, nil
+}
+
+// percentilesBucketAggregation represents a sibling pipeline aggregation which
+// computes the percentiles of a metric of the buckets of another aggregation,
+// as the value of the closest rank.
+type percentilesBucketAggregation struct {
+	bucketsPath string
+	percents    []float64
+}
+
+// Source returns the JSON source of the aggregation.
+func (a *percentilesBucketAggregation) Source() (interface{}, error) {
+	return map[string]interface{}{
+		"percentiles_bucket": map[string]interface{}{
+			"buckets_path": a.bucketsPath,
+			"percents":     a.percents,
+		},
+	}, nil
+}
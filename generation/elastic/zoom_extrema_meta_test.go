@@ -0,0 +1,122 @@
+package elastic_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/generation/elastic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("ZoomExtremaMeta", func() {
+
+	var server *httptest.Server
+	var bodies []string
+	var filterPaths []string
+
+	params := `
+		"xField": "x",
+		"yField": "y",
+		"left": 0,
+		"right": 256,
+		"bottom": 0,
+		"top": 256,
+		"resolution": 2,
+		"minZoom": 1,
+		"maxZoom": 2`
+
+	BeforeEach(func() {
+		bodies = nil
+		filterPaths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if !strings.HasSuffix(r.URL.Path, "/_search") {
+				w.Write([]byte(`{}`))
+				return
+			}
+			var body []byte
+			if reader, err := gzip.NewReader(r.Body); err == nil {
+				body, _ = ioutil.ReadAll(reader)
+			}
+			bodies = append(bodies, string(body))
+			filterPaths = append(filterPaths, r.URL.Query().Get("filter_path"))
+			w.Write([]byte(`{
+				"hits": { "total": 4, "hits": [] },
+				"aggregations": {
+					"max": { "value": 4, "keys": ["2"] },
+					"min": { "value": 1, "keys": ["0"] },
+					"percentiles": { "values": { "50.0": 2 } }
+				}
+			}`))
+		}))
+	})
+
+	AfterEach(func() {
+		elastic.CloseClients()
+		server.Close()
+	})
+
+	create := func(params string) map[string]interface{} {
+		m, err := elastic.NewZoomExtremaMeta(&elastic.Config{
+			Hosts: []string{server.URL},
+		})()
+		Expect(err).To(BeNil())
+		err = m.Parse(JSON(params))
+		Expect(err).To(BeNil())
+		res, err := m.Create("points")
+		Expect(err).To(BeNil())
+		return JSON(string(res))
+	}
+
+	It("should compute the bin extrema with pipeline aggregations", func() {
+		res := create(`{` + params + `}`)
+		Expect(res).To(Equal(JSON(`{
+			"1": { "min": 1, "max": 4 },
+			"2": { "min": 1, "max": 4 }
+		}`)))
+		Expect(bodies).To(HaveLen(2))
+		Expect(bodies[0]).To(ContainSubstring(`"max_bucket":{"buckets_path":"bins\u003e_count"}`))
+		Expect(bodies[0]).To(ContainSubstring(`"min_bucket":{"buckets_path":"bins\u003e_count"}`))
+		Expect(bodies[0]).To(ContainSubstring(`"sizeX":64`))
+		Expect(bodies[0]).To(ContainSubstring(`"columns":5`))
+		Expect(bodies[1]).To(ContainSubstring(`"sizeX":32`))
+		Expect(bodies[1]).To(ContainSubstring(`"columns":9`))
+		Expect(bodies[0]).NotTo(ContainSubstring(`percentiles_bucket`))
+		Expect(filterPaths).To(Equal([]string{
+			"aggregations.max,aggregations.min",
+			"aggregations.max,aggregations.min",
+		}))
+	})
+
+	It("should compute the percentiles with a pipeline aggregation", func() {
+		res := create(`{` + params + `, "maxZoom": 1, "percentiles": [50]}`)
+		Expect(res).To(Equal(JSON(`{
+			"1": { "min": 1, "max": 4, "percentiles": { "50": 2 } }
+		}`)))
+		Expect(bodies[0]).To(ContainSubstring(`"percentiles_bucket":{"buckets_path":"bins\u003e_count","percents":[50]}`))
+		Expect(filterPaths).To(Equal([]string{
+			"aggregations.max,aggregations.min,aggregations.percentiles",
+		}))
+	})
+
+	It("should return an error if the deepest zoom level has too many bins", func() {
+		m, err := elastic.NewZoomExtremaMeta(&elastic.Config{})()
+		Expect(err).To(BeNil())
+		err = m.Parse(JSON(`{` + params + `, "maxZoom": 12}`))
+		Expect(err).To(MatchError(ContainSubstring("exceeds the maximum")))
+	})
+
+	It("should not support the mercator projection", func() {
+		m, err := elastic.NewZoomExtremaMeta(&elastic.Config{})()
+		Expect(err).To(BeNil())
+		err = m.Parse(JSON(`{"xField": "x", "yField": "y", "projection": "mercator", "maxZoom": 1}`))
+		Expect(err).To(MatchError(ContainSubstring("`mercator` projection is not supported")))
+	})
+
+})
@@ -0,0 +1,204 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+const (
+	// binScript returns the index of the bin of the document along the axis
+	// of the field, which a histogram of interval 1 buckets by bin. Date
+	// fields are binned by their milliseconds since the epoch.
+	binScript = `def v = doc[params.field].value;
+double d = v instanceof Number ? v.doubleValue() : v.toInstant().toEpochMilli();
+return Math.floor((d - params.min) / params.size);`
+)
+
+// Bivariate represents an elasticsearch implementation of the bivariate tile.
+type Bivariate struct {
+	tile.Bivariate
+}
+
+// GetQuery returns the tiling query.
+func (b *Bivariate) GetQuery(coord *binning.TileCoord) map[string]interface{} {
+	// get tile bounds
+	bounds := b.TileBounds(coord)
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": []interface{}{
+				map[string]interface{}{
+					"range": map[string]interface{}{
+						b.XField: getBounds(bounds.MinX(), nil, nil, bounds.MaxX()),
+					},
+				},
+				map[string]interface{}{
+					"range": map[string]interface{}{
+						b.YField: getBounds(bounds.MinY(), nil, nil, bounds.MaxY()),
+					},
+				},
+			},
+		},
+	}
+}
+
+// GetAggs returns the tiling aggregation under the name `bins`, with the
+// optional nested aggregations added to each bin. Bins are aggregated using
+// a composite aggregation such that every bin may be paged through,
+// regardless of the resolution.
+func (b *Bivariate) GetAggs(coord *binning.TileCoord, nested map[string]interface{}) map[string]interface{} {
+	if b.Projection == tile.MercatorProjection {
+		return b.getRangeAggs(coord, nested)
+	}
+	// NOTE: composite histograms can not be offset, so the bin of each
+	// document is scripted relative to the tile bounds.
+	bounds := b.TileBounds(coord)
+	agg := map[string]interface{}{
+		"composite": map[string]interface{}{
+			"size": compositeSize,
+			"sources": []interface{}{
+				map[string]interface{}{
+					"x": binSource(b.XField, bounds.MinX(), b.BinSizeX(coord)),
+				},
+				map[string]interface{}{
+					"y": binSource(b.YField, bounds.MinY(), b.BinSizeY(coord)),
+				},
+			},
+		},
+	}
+	if nested != nil {
+		agg["aggs"] = nested
+	}
+	return map[string]interface{}{
+		"bins": agg,
+	}
+}
+
+// GetBins parses the bins of the provided response into the provided array.
+// It may be called for each page of the composite aggregation.
+func (b *Bivariate) GetBins(coord *binning.TileCoord, aggs Aggregations, bins []*Bucket) error {
+	if b.Projection == tile.MercatorProjection {
+		return b.getRangeBins(coord, aggs, bins)
+	}
+	agg, ok := aggs.Buckets("bins")
+	if !ok {
+		return fmt.Errorf("composite aggregation `bins` was not found")
+	}
+	bounds := b.TileBounds(coord)
+	binSizeX := b.BinSizeX(coord)
+	binSizeY := b.BinSizeY(coord)
+	for _, bucket := range agg.Buckets {
+		key, ok := bucket.Key.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("composite aggregation key was not an object")
+		}
+		x, okX := key["x"].(float64)
+		y, okY := key["y"].(float64)
+		if !okX || !okY {
+			return fmt.Errorf("composite aggregation key `%v` is not numeric", key)
+		}
+		// the keys are the bin indices from the tile minimum, use the bin
+		// center to respect the orientation of the bounds
+		xBin := b.GetXBin(coord, bounds.MinX()+(x+0.5)*binSizeX)
+		yBin := b.GetYBin(coord, bounds.MinY()+(y+0.5)*binSizeY)
+		mergeBin(bins, xBin+b.Resolution*yBin, bucket)
+	}
+	return nil
+}
+
+// binSource returns the composite histogram source which buckets the field by
+// the index of its bin from the provided minimum.
+func binSource(field string, min float64, size float64) map[string]interface{} {
+	return map[string]interface{}{
+		"histogram": map[string]interface{}{
+			"script": map[string]interface{}{
+				"source": binScript,
+				"lang":   "painless",
+				"params": map[string]interface{}{
+					"field": field,
+					"min":   min,
+					"size":  size,
+				},
+			},
+			"interval": 1,
+		},
+	}
+}
+
+// mergeBin sets the bucket of the bin, merging it into any bucket already
+// within the bin, such as those clamped into the edge bins.
+func mergeBin(bins []*Bucket, index int, bucket *Bucket) {
+	if bins[index] == nil {
+		bins[index] = bucket
+		return
+	}
+	bins[index].Merge(bucket)
+}
+
+// getRangeAggs returns the tiling aggregation for bins which are not uniform
+// in data space across the y axis. The x bins are aggregated with a histogram
+// such that only non-empty columns are returned, while the y bins use their
+// explicit boundaries.
+func (b *Bivariate) getRangeAggs(coord *binning.TileCoord, nested map[string]interface{}) map[string]interface{} {
+	yBounds := b.GetYBinBounds(coord)
+	yRanges := make([]interface{}, b.Resolution)
+	for i := 0; i < b.Resolution; i++ {
+		yRanges[i] = map[string]interface{}{
+			"from": yBounds[i],
+			"to":   yBounds[i+1],
+		}
+	}
+	y := map[string]interface{}{
+		"range": map[string]interface{}{
+			"field":  b.YField,
+			"ranges": yRanges,
+		},
+	}
+	if nested != nil {
+		y["aggs"] = nested
+	}
+	return map[string]interface{}{
+		"bins": map[string]interface{}{
+			"histogram": map[string]interface{}{
+				"field":         b.XField,
+				"interval":      b.BinSizeX(coord),
+				"offset":        b.TileBounds(coord).MinX(),
+				"min_doc_count": 1,
+			},
+			"aggs": map[string]interface{}{
+				"y": y,
+			},
+		},
+	}
+}
+
+// getRangeBins parses the resulting x histogram and y range aggregations into
+// bins.
+func (b *Bivariate) getRangeBins(coord *binning.TileCoord, aggs Aggregations, bins []*Bucket) error {
+	xAgg, ok := aggs.Buckets("bins")
+	if !ok {
+		return fmt.Errorf("histogram aggregation `bins` was not found")
+	}
+	halfX := b.BinSizeX(coord) / 2
+	for _, xBucket := range xAgg.Buckets {
+		x, ok := xBucket.Key.(float64)
+		if !ok {
+			return fmt.Errorf("histogram aggregation key `%v` is not numeric", xBucket.Key)
+		}
+		// use the bin center to avoid floating point error at the edges
+		xBin := b.GetXBin(coord, x+halfX)
+		yAgg, ok := xBucket.Aggregations.Buckets("y")
+		if !ok {
+			return fmt.Errorf("range aggregation `y` was not found")
+		}
+		for _, yBucket := range yAgg.Buckets {
+			if yBucket.DocCount == 0 || yBucket.From == nil || yBucket.To == nil {
+				continue
+			}
+			yBin := b.GetYBin(coord, (*yBucket.From+*yBucket.To)/2)
+			mergeBin(bins, xBin+b.Resolution*yBin, yBucket)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+// BinaryExpression represents an filter / should boolean query.
+type BinaryExpression struct {
+	veldt.BinaryExpression
+}
+
+// NewBinaryExpression instantiates and returns a new binary expression.
+func NewBinaryExpression() (veldt.Query, error) {
+	return &BinaryExpression{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the binary expression.
+func (e *BinaryExpression) Get() (map[string]interface{}, error) {
+
+	left, ok := e.Left.(Query)
+	if !ok {
+		return nil, fmt.Errorf("`Left` is not of type elasticsearch.Query")
+	}
+	right, ok := e.Right.(Query)
+	if !ok {
+		return nil, fmt.Errorf("`Right` is not of type elasticsearch.Query")
+	}
+
+	a, err := left.Get()
+	if err != nil {
+		return nil, err
+	}
+	b, err := right.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Op {
+	case veldt.And:
+		// AND
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{a, b},
+			},
+		}, nil
+	case veldt.Or:
+		// OR
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               []interface{}{a, b},
+				"minimum_should_match": 1,
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("`%v` operator is not a valid binary operator", e.Op)
+}
+
+// NaryExpression represents a filter / should boolean query over any number
+// of queries.
+type NaryExpression struct {
+	veldt.NaryExpression
+}
+
+// NewNaryExpression instantiates and returns a new n-ary expression.
+func NewNaryExpression() (veldt.Query, error) {
+	return &NaryExpression{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the n-ary expression.
+func (e *NaryExpression) Get() (map[string]interface{}, error) {
+	queries := make([]interface{}, len(e.Queries))
+	for i, q := range e.Queries {
+		query, ok := q.(Query)
+		if !ok {
+			return nil, fmt.Errorf("`Queries` element is not of type elasticsearch.Query")
+		}
+		a, err := query.Get()
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = a
+	}
+	switch e.Op {
+	case veldt.And:
+		// AND
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": queries,
+			},
+		}, nil
+	case veldt.Or:
+		// OR
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               queries,
+				"minimum_should_match": 1,
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("`%v` operator is not a valid n-ary operator", e.Op)
+}
+
+// UnaryExpression represents a must_not boolean query.
+type UnaryExpression struct {
+	veldt.UnaryExpression
+}
+
+// NewUnaryExpression instantiates and returns a new unary expression.
+func NewUnaryExpression() (veldt.Query, error) {
+	return &UnaryExpression{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the unary expression.
+func (e *UnaryExpression) Get() (map[string]interface{}, error) {
+
+	q, ok := e.Query.(Query)
+	if !ok {
+		return nil, fmt.Errorf("`Query` is not of type elasticsearch.Query")
+	}
+
+	a, err := q.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Op {
+	case veldt.Not:
+		// NOT
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must_not": []interface{}{a},
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("`%v` operator is not a valid unary operator", e.Op)
+}
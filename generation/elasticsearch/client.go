@@ -0,0 +1,322 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/util/transport"
+)
+
+const (
+	defaultTimeout = time.Second * 60
+	defaultHost    = "http://localhost:9200"
+)
+
+var (
+	mutex   = sync.Mutex{}
+	clients = make(map[string]*Client)
+)
+
+// Config defines the cluster and credentials required to establish a
+// connection. It is compatible with Elasticsearch 7 and 8, and OpenSearch.
+type Config struct {
+	// Hosts lists the base URLs of the nodes of the cluster, ie.
+	// `https://localhost:9200`. Requests are distributed across the hosts and
+	// retried on the next host if a host is unreachable. If empty,
+	// `http://localhost:9200` is used.
+	Hosts []string
+	// Username and Password are sent using basic authentication.
+	Username string
+	Password string
+	// APIKey is the base64 encoded `id:api_key` credential, sent using API
+	// key authentication. It takes precedence over basic authentication.
+	APIKey string
+	// CACert is the path to a PEM encoded certificate bundle used to verify
+	// the cluster certificate. If empty, the system roots are used.
+	CACert string
+	// InsecureSkipVerify disables verification of the cluster certificate.
+	InsecureSkipVerify bool
+	// Timeout is the maximum duration of a request. If zero, a default of 60
+	// seconds is used.
+	Timeout time.Duration
+	// Transport overrides the HTTP transport, such as to replay recorded
+	// responses in tests. If set, the TLS options are ignored.
+	Transport http.RoundTripper
+}
+
+func (c *Config) key() string {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%v|%s|%s|%s|%s|%v|%v|%p",
+		c.Hosts,
+		c.Username,
+		c.Password,
+		c.APIKey,
+		c.CACert,
+		c.InsecureSkipVerify,
+		c.Timeout,
+		c.Transport)
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// Error represents an error response returned by the cluster.
+type Error struct {
+	Status int
+	Type   string
+	Reason string
+}
+
+// Error returns the string representation of the error.
+func (e *Error) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("elasticsearch: %d %s", e.Status, http.StatusText(e.Status))
+	}
+	return fmt.Sprintf("elasticsearch: %d %s: %s", e.Status, e.Type, e.Reason)
+}
+
+// Client represents a version-agnostic client which communicates with the
+// cluster using the JSON REST API.
+type Client struct {
+	hosts []string
+	next  uint32
+	http  *http.Client
+}
+
+// NewClient returns a client for the provided config. Clients are cached and
+// shared between tiles using the same config. A nil config connects using the
+// defaults.
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	key := cfg.key()
+	mutex.Lock()
+	client, ok := clients[key]
+	if !ok {
+		c, err := newClient(cfg)
+		if err != nil {
+			mutex.Unlock()
+			runtime.Gosched()
+			return nil, err
+		}
+		clients[key] = c
+		client = c
+	}
+	mutex.Unlock()
+	runtime.Gosched()
+	return client, nil
+}
+
+// CloseClient closes the client for the provided config, if one has been
+// opened. Subsequent calls to NewClient will open a new client.
+func CloseClient(cfg *Config) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	key := cfg.key()
+	mutex.Lock()
+	client, ok := clients[key]
+	if ok {
+		delete(clients, key)
+	}
+	mutex.Unlock()
+	if ok {
+		client.Close()
+	}
+}
+
+// CloseClients closes all open clients.
+func CloseClients() {
+	mutex.Lock()
+	closing := clients
+	clients = make(map[string]*Client)
+	mutex.Unlock()
+	for _, client := range closing {
+		client.Close()
+	}
+}
+
+func newClient(cfg *Config) (*Client, error) {
+	hosts := make([]string, 0, len(cfg.Hosts))
+	for _, host := range cfg.Hosts {
+		u, err := url.Parse(host)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("host `%s` is not a valid URL", host)
+		}
+		hosts = append(hosts, strings.TrimRight(host, "/"))
+	}
+	if len(hosts) == 0 {
+		hosts = append(hosts, defaultHost)
+	}
+	auth := &transport.Config{
+		Username:           cfg.Username,
+		Password:           cfg.Password,
+		APIKey:             cfg.APIKey,
+		CACert:             cfg.CACert,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	var httpTransport http.RoundTripper
+	if cfg.Transport != nil {
+		httpTransport = transport.Authenticate(auth, cfg.Transport)
+	} else {
+		var err error
+		httpTransport, err = transport.New(auth)
+		if err != nil {
+			return nil, err
+		}
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &Client{
+		hosts: hosts,
+		http: &http.Client{
+			Transport: httpTransport,
+			Timeout:   timeout,
+		},
+	}, nil
+}
+
+// Search executes the provided search request body against the index and
+// returns the response.
+func (c *Client) Search(index string, body map[string]interface{}) (*SearchResponse, error) {
+	bytes, err := c.Perform("POST", "/"+escapeIndex(index)+"/_search", body)
+	if err != nil {
+		return nil, err
+	}
+	res := &SearchResponse{}
+	err = json.Unmarshal(bytes, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetMapping returns the mappings of the index, keyed by the concrete index
+// name.
+func (c *Client) GetMapping(index string) (map[string]interface{}, error) {
+	bytes, err := c.Perform("GET", "/"+escapeIndex(index)+"/_mapping", nil)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]interface{})
+	err = json.Unmarshal(bytes, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Perform sends a request to the cluster and returns the response body. The
+// request is attempted on each host in turn until one is reachable.
+func (c *Client) Perform(method string, path string, body interface{}) ([]byte, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	start := int(atomic.AddUint32(&c.next, 1))
+	var lastErr error
+	for i := 0; i < len(c.hosts); i++ {
+		host := c.hosts[(start+i)%len(c.hosts)]
+		res, err := c.perform(method, host+path, payload)
+		if err != nil {
+			// try the next host
+			Warnf("Request to `%s` failed: %v", host, err)
+			lastErr = err
+			continue
+		}
+		return res, nil
+	}
+	return nil, lastErr
+}
+
+func (c *Client) perform(method string, endpoint string, payload []byte) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	bytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, parseError(res.StatusCode, bytes)
+	}
+	return bytes, nil
+}
+
+// Close closes any idle connections held by the client.
+func (c *Client) Close() {
+	type idleCloser interface {
+		CloseIdleConnections()
+	}
+	if t, ok := c.http.Transport.(idleCloser); ok {
+		t.CloseIdleConnections()
+	}
+}
+
+func parseError(status int, body []byte) error {
+	res := struct {
+		Error json.RawMessage `json:"error"`
+	}{}
+	err := json.Unmarshal(body, &res)
+	if err != nil || res.Error == nil {
+		return &Error{
+			Status: status,
+		}
+	}
+	cause := struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	}{}
+	err = json.Unmarshal(res.Error, &cause)
+	if err != nil {
+		// some errors are a plain string
+		var reason string
+		json.Unmarshal(res.Error, &reason)
+		return &Error{
+			Status: status,
+			Reason: reason,
+		}
+	}
+	return &Error{
+		Status: status,
+		Type:   cause.Type,
+		Reason: cause.Reason,
+	}
+}
+
+func escapeIndex(index string) string {
+	// index names may not contain `/`, preserve wildcards and lists
+	escaped := url.PathEscape(index)
+	escaped = strings.Replace(escaped, "%2C", ",", -1)
+	return strings.Replace(escaped, "%2A", "*", -1)
+}
@@ -0,0 +1,98 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+
+	"github.com/unchartedsoftware/veldt/generation/elasticsearch"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+
+	var server *standIn
+
+	AfterEach(func() {
+		elasticsearch.CloseClients()
+		server.Close()
+	})
+
+	It("should connect using the defaults without a config", func() {
+		server = newStandIn("count.json")
+		Expect(func() {
+			_, err := elasticsearch.NewClient(nil)
+			Expect(err).To(BeNil())
+			elasticsearch.CloseClient(nil)
+		}).NotTo(Panic())
+	})
+
+	It("should send basic authentication", func() {
+		server = newStandIn("count.json")
+		client, err := elasticsearch.NewClient(&elasticsearch.Config{
+			Hosts:    []string{server.URL()},
+			Username: "elastic",
+			Password: "changeme",
+		})
+		Expect(err).To(BeNil())
+		_, err = client.Search("tweets", map[string]interface{}{})
+		Expect(err).To(BeNil())
+		username, password, ok := (&http.Request{Header: server.requests[0].Header}).BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(username).To(Equal("elastic"))
+		Expect(password).To(Equal("changeme"))
+	})
+
+	It("should prefer API key authentication", func() {
+		server = newStandIn("count.json")
+		client, err := elasticsearch.NewClient(&elasticsearch.Config{
+			Hosts:    []string{server.URL()},
+			Username: "elastic",
+			Password: "changeme",
+			APIKey:   "aWQ6a2V5",
+		})
+		Expect(err).To(BeNil())
+		_, err = client.Search("tweets", map[string]interface{}{})
+		Expect(err).To(BeNil())
+		Expect(server.requests[0].Header.Get("Authorization")).To(Equal("ApiKey aWQ6a2V5"))
+	})
+
+	It("should retry on the next host if a host is unreachable", func() {
+		unreachable := newStandIn()
+		unreachable.Close()
+		server = newStandIn("count.json")
+		client, err := elasticsearch.NewClient(&elasticsearch.Config{
+			Hosts: []string{unreachable.URL(), server.URL()},
+		})
+		Expect(err).To(BeNil())
+		res, err := client.Search("tweets", map[string]interface{}{})
+		Expect(err).To(BeNil())
+		Expect(res.Hits.Total.Value).To(Equal(int64(42)))
+	})
+
+	It("should return the error of the cluster", func() {
+		server = newStandIn("index_not_found.json")
+		server.status = http.StatusNotFound
+		client, err := elasticsearch.NewClient(&elasticsearch.Config{
+			Hosts: []string{server.URL()},
+		})
+		Expect(err).To(BeNil())
+		_, err = client.Search("missing", map[string]interface{}{})
+		Expect(err).NotTo(BeNil())
+		esErr, ok := err.(*elasticsearch.Error)
+		Expect(ok).To(BeTrue())
+		Expect(esErr.Status).To(Equal(http.StatusNotFound))
+		Expect(esErr.Type).To(Equal("index_not_found_exception"))
+	})
+
+	It("should support multiple indices and patterns", func() {
+		server = newStandIn("count.json")
+		client, err := elasticsearch.NewClient(&elasticsearch.Config{
+			Hosts: []string{server.URL()},
+		})
+		Expect(err).To(BeNil())
+		_, err = client.Search("logs-*,metrics", map[string]interface{}{})
+		Expect(err).To(BeNil())
+		Expect(server.requests[0].Path).To(Equal("/logs-*,metrics/_search"))
+	})
+})
@@ -0,0 +1,54 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+// CountTile represents an elasticsearch implementation of the count tile.
+type CountTile struct {
+	Elasticsearch
+	Bivariate
+}
+
+// NewCountTile instantiates and returns a new tile struct.
+func NewCountTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &CountTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *CountTile) Parse(params map[string]interface{}) error {
+	return t.Bivariate.Parse(params)
+}
+
+// Empty returns the tile containing no data.
+func (t *CountTile) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return []byte(`{"count":0}`), nil
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *CountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	filters, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	filters = append(filters, t.Bivariate.GetQuery(coord))
+
+	// send query
+	res, err := t.Search(uri, filters, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// encode
+	return []byte(fmt.Sprintf(`{"count":%d}`, res.Hits.Total.Value)), nil
+}
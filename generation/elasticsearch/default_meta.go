@@ -0,0 +1,201 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// PropertyMeta represents the meta data for a single property.
+type PropertyMeta struct {
+	Type    string           `json:"type"`
+	Extrema *binning.Extrema `json:"extrema,omitempty"`
+}
+
+// DefaultMeta represents a meta data generator that produces default
+// metadata with property types and extrema.
+type DefaultMeta struct {
+	Elasticsearch
+}
+
+// NewDefaultMeta instantiates and returns a pointer to a new generator.
+func NewDefaultMeta(cfg *Config) veldt.MetaCtor {
+	return func() (veldt.Meta, error) {
+		m := &DefaultMeta{}
+		m.Config = cfg
+		return m, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (m *DefaultMeta) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+// Create generates metadata from the provided URI. The properties of every
+// index matching the URI are merged.
+func (m *DefaultMeta) Create(uri string) ([]byte, error) {
+	return m.CreateScoped(uri, nil, nil, nil)
+}
+
+// CreateScoped generates metadata from the provided URI, restricting the
+// extrema to the documents matching the query and within the tile of the
+// scope.
+func (m *DefaultMeta) CreateScoped(uri string, scope *tile.Bivariate, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	filters, err := m.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if scope != nil {
+		b := &Bivariate{Bivariate: *scope}
+		filters = append(filters, b.GetQuery(coord))
+	}
+	client, err := NewClient(m.Config)
+	if err != nil {
+		return nil, err
+	}
+	// get the raw mappings
+	mapping, err := client.GetMapping(m.Index(uri))
+	if err != nil {
+		return nil, err
+	}
+	// NOTE: the response is keyed by the concrete index names, which differ
+	// from the uri for aliases and patterns. Indices are parsed in order so
+	// that conflicting types resolve consistently.
+	indices := make([]string, 0, len(mapping))
+	for index := range mapping {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	meta := make(map[string]*PropertyMeta)
+	for _, index := range indices {
+		props, ok := json.GetChild(mapping, index, "mappings", "properties")
+		if !ok {
+			// indices without any documents have no properties
+			continue
+		}
+		parseProperties(meta, props, "")
+	}
+	// get the extrema of all ordinal properties in a single request
+	err = m.addExtrema(uri, filters, meta)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(meta)
+}
+
+func isOrdinal(typ string) bool {
+	return typ == "long" ||
+		typ == "integer" ||
+		typ == "short" ||
+		typ == "byte" ||
+		typ == "double" ||
+		typ == "float" ||
+		typ == "half_float" ||
+		typ == "scaled_float" ||
+		typ == "unsigned_long" ||
+		typ == "date" ||
+		typ == "date_nanos"
+}
+
+func parseProperties(meta map[string]*PropertyMeta, props map[string]interface{}, path string) {
+	for key, val := range props {
+		prop, ok := val.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		subpath := key
+		if path != "" {
+			subpath = path + "." + key
+		}
+		typ, _ := json.GetString(prop, "type")
+		// we don't support nested types
+		if typ == "nested" {
+			continue
+		}
+		subprops, ok := json.GetChild(prop, "properties")
+		if ok {
+			// recurse further
+			parseProperties(meta, subprops, subpath)
+			continue
+		}
+		if typ == "" {
+			continue
+		}
+		if _, exists := meta[subpath]; !exists {
+			meta[subpath] = &PropertyMeta{
+				Type: typ,
+			}
+		}
+		// parse out multi-field mapping
+		fields, ok := json.GetChild(prop, "fields")
+		if ok {
+			for name := range fields {
+				fieldTyp, ok := json.GetString(fields, name, "type")
+				if !ok {
+					continue
+				}
+				multiFieldPath := subpath + "." + name
+				if _, exists := meta[multiFieldPath]; !exists {
+					meta[multiFieldPath] = &PropertyMeta{
+						Type: fieldTyp,
+					}
+				}
+			}
+		}
+	}
+}
+
+func (m *DefaultMeta) addExtrema(uri string, filters []interface{}, meta map[string]*PropertyMeta) error {
+	fields := make([]string, 0)
+	for field, prop := range meta {
+		if isOrdinal(prop.Type) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	aggs := make(map[string]interface{})
+	for i, field := range fields {
+		aggs[fmt.Sprintf("min-%d", i)] = map[string]interface{}{
+			"min": map[string]interface{}{
+				"field": field,
+			},
+		}
+		aggs[fmt.Sprintf("max-%d", i)] = map[string]interface{}{
+			"max": map[string]interface{}{
+				"field": field,
+			},
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	res, err := m.Search(uri, filters, aggs)
+	if err != nil {
+		return err
+	}
+	for i, field := range fields {
+		min, ok := res.Aggregations.Value(fmt.Sprintf("min-%d", i))
+		if !ok {
+			return fmt.Errorf("min '%s' aggregation was not found in response for %s", field, uri)
+		}
+		max, ok := res.Aggregations.Value(fmt.Sprintf("max-%d", i))
+		if !ok {
+			return fmt.Errorf("max '%s' aggregation was not found in response for %s", field, uri)
+		}
+		// if the mapping exists, but no documents have the attribute, the min /
+		// max are null
+		if min == nil || max == nil {
+			continue
+		}
+		meta[field].Extrema = &binning.Extrema{
+			Min: *min,
+			Max: *max,
+		}
+	}
+	return nil
+}
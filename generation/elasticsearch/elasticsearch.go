@@ -0,0 +1,107 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+const (
+	// compositeSize is the number of buckets requested per page of a
+	// composite aggregation.
+	compositeSize = 10000
+)
+
+// Elasticsearch represents an elasticsearch tile type.
+type Elasticsearch struct {
+	Config *Config
+}
+
+// Index returns the index from the provided uri. Mapping types have been
+// removed from Elasticsearch, so any type of a legacy `index/type` uri is
+// ignored.
+func (e *Elasticsearch) Index(uri string) string {
+	return strings.Split(uri, "/")[0]
+}
+
+// CreateQuery creates the filters of the search request from the query
+// struct.
+func (e *Elasticsearch) CreateQuery(query veldt.Query) ([]interface{}, error) {
+	filters := make([]interface{}, 0)
+	if query != nil {
+		// type assert
+		esquery, ok := query.(Query)
+		if !ok {
+			return nil, fmt.Errorf("query is not elasticsearch.Query")
+		}
+		// get underlying query
+		q, err := esquery.Get()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, q)
+	}
+	return filters, nil
+}
+
+// Search executes a search of the uri with the provided filters and
+// aggregations, returning no documents.
+func (e *Elasticsearch) Search(uri string, filters []interface{}, aggs map[string]interface{}) (*SearchResponse, error) {
+	client, err := NewClient(e.Config)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{
+		"size":             0,
+		"track_total_hits": true,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filters,
+			},
+		},
+	}
+	if len(aggs) > 0 {
+		body["aggs"] = aggs
+	}
+	return client.Search(e.Index(uri), body)
+}
+
+// SearchComposite executes a search of the uri, paging through every bucket
+// of the named composite aggregation. The provided function is called with
+// the response of each page. If the aggregation is not a composite
+// aggregation, a single search is executed.
+func (e *Elasticsearch) SearchComposite(uri string, filters []interface{}, aggs map[string]interface{}, name string, fn func(*SearchResponse) error) error {
+	for {
+		res, err := e.Search(uri, filters, aggs)
+		if err != nil {
+			return err
+		}
+		err = fn(res)
+		if err != nil {
+			return err
+		}
+		composite, ok := getComposite(aggs, name)
+		if !ok {
+			return nil
+		}
+		agg, ok := res.Aggregations.Buckets(name)
+		if !ok {
+			return fmt.Errorf("composite aggregation `%s` was not found", name)
+		}
+		if agg.AfterKey == nil || len(agg.Buckets) == 0 {
+			return nil
+		}
+		// request the next page
+		composite["after"] = agg.AfterKey
+	}
+}
+
+func getComposite(aggs map[string]interface{}, name string) (map[string]interface{}, bool) {
+	agg, ok := aggs[name].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	composite, ok := agg["composite"].(map[string]interface{})
+	return composite, ok
+}
@@ -0,0 +1,25 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Equals represents an elasticsearch term query.
+type Equals struct {
+	query.Equals
+}
+
+// NewEquals instantiates and returns a new query struct.
+func NewEquals() (veldt.Query, error) {
+	return &Equals{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Equals) Get() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"term": map[string]interface{}{
+			q.Field: q.Value,
+		},
+	}, nil
+}
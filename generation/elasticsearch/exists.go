@@ -0,0 +1,25 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Exists represents an elasticsearch exists query.
+type Exists struct {
+	query.Exists
+}
+
+// NewExists instantiates and returns a new query struct.
+func NewExists() (veldt.Query, error) {
+	return &Exists{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Exists) Get() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"exists": map[string]interface{}{
+			"field": q.Field,
+		},
+	}, nil
+}
@@ -0,0 +1,132 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+var (
+	calendarIntervals = map[string]string{
+		"day":     "1d",
+		"week":    "1w",
+		"month":   "1M",
+		"quarter": "1q",
+		"year":    "1y",
+	}
+)
+
+// Frequency represents an elasticsearch implementation of the frequency
+// tile.
+type Frequency struct {
+	tile.Frequency
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (f *Frequency) Parse(params map[string]interface{}) error {
+	err := f.Frequency.Parse(params)
+	if err != nil {
+		return err
+	}
+	_, err = tile.ParseInterval(f.Interval)
+	if err != nil {
+		return err
+	}
+	// validate the bounds
+	_, _, err = f.TimeBounds()
+	return err
+}
+
+// GetQuery returns the appropriate elasticsearch query for the tile.
+func (f *Frequency) GetQuery() map[string]interface{} {
+	bounds := getBounds(f.GTE, f.GT, f.LTE, f.LT)
+	bounds["format"] = "strict_date_optional_time||epoch_millis"
+	return map[string]interface{}{
+		"range": map[string]interface{}{
+			f.FrequencyField: bounds,
+		},
+	}
+}
+
+// GetAggs returns the appropriate elasticsearch aggregation for the tile.
+// Calendar intervals are aligned to the calendar of the time zone, and fixed
+// intervals to the lower bound of the range.
+func (f *Frequency) GetAggs() (map[string]interface{}, error) {
+	interval, err := tile.ParseInterval(f.Interval)
+	if err != nil {
+		return nil, err
+	}
+	min, max, err := f.TimeBounds()
+	if err != nil {
+		return nil, err
+	}
+	histogram := map[string]interface{}{
+		"field":         f.FrequencyField,
+		"min_doc_count": 0,
+	}
+	if interval.IsCalendar() {
+		histogram["calendar_interval"] = calendarIntervals[interval.Unit]
+	} else {
+		size := int64(interval.Duration / time.Millisecond)
+		histogram["fixed_interval"] = fmt.Sprintf("%dms", size)
+		if min != nil {
+			offset := tile.ToMillis(*min) % size
+			if offset < 0 {
+				offset += size
+			}
+			if offset != 0 {
+				histogram["offset"] = fmt.Sprintf("%dms", offset)
+			}
+		}
+	}
+	if f.TimeZone != "" {
+		histogram["time_zone"] = f.TimeZone
+	}
+	if min != nil || max != nil {
+		extended := make(map[string]interface{})
+		if min != nil {
+			extended["min"] = tile.ToMillis(*min)
+		}
+		if max != nil {
+			end := tile.ToMillis(*max)
+			if f.LT != nil {
+				// exclusive upper bound
+				end--
+			}
+			extended["max"] = end
+		}
+		histogram["extended_bounds"] = extended
+	}
+	return map[string]interface{}{
+		"frequency": map[string]interface{}{
+			"date_histogram": histogram,
+		},
+	}, nil
+}
+
+// GetBuckets returns the individual frequency buckets from an elasticsearch
+// aggregation.
+func (f *Frequency) GetBuckets(aggs Aggregations) ([]*Bucket, error) {
+	frequency, ok := aggs.Buckets("frequency")
+	if !ok {
+		return nil, fmt.Errorf("date histogram aggregation `frequency` was not found")
+	}
+	return frequency.Buckets, nil
+}
+
+// EncodeBuckets encodes the buckets as timestamp and count pairs.
+func (f *Frequency) EncodeBuckets(buckets []*Bucket) []map[string]interface{} {
+	res := make([]map[string]interface{}, len(buckets))
+	for i, bucket := range buckets {
+		var timestamp int64
+		if key, ok := bucket.Key.(float64); ok {
+			timestamp = int64(key)
+		}
+		res[i] = map[string]interface{}{
+			"timestamp": timestamp,
+			"count":     bucket.DocCount,
+		}
+	}
+	return res
+}
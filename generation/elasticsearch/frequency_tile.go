@@ -0,0 +1,68 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// FrequencyTile represents an elasticsearch implementation of the frequency
+// tile.
+type FrequencyTile struct {
+	Elasticsearch
+	Bivariate
+	Frequency
+}
+
+// NewFrequencyTile instantiates and returns a new tile struct.
+func NewFrequencyTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &FrequencyTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *FrequencyTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	filters, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	filters = append(filters, t.Bivariate.GetQuery(coord))
+	// add frequency query
+	filters = append(filters, t.Frequency.GetQuery())
+
+	// get aggs
+	aggs, err := t.Frequency.GetAggs()
+	if err != nil {
+		return nil, err
+	}
+
+	// send query
+	res, err := t.Search(uri, filters, aggs)
+	if err != nil {
+		return nil, err
+	}
+
+	// get buckets
+	frequency, err := t.Frequency.GetBuckets(res.Aggregations)
+	if err != nil {
+		return nil, err
+	}
+
+	// marshal results
+	return json.Marshal(t.Frequency.EncodeBuckets(frequency))
+}
@@ -0,0 +1,25 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Has represents an elasticsearch terms query.
+type Has struct {
+	query.Has
+}
+
+// NewHas instantiates and returns a new query struct.
+func NewHas() (veldt.Query, error) {
+	return &Has{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Has) Get() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"terms": map[string]interface{}{
+			q.Field: q.Values,
+		},
+	}, nil
+}
@@ -0,0 +1,68 @@
+package elasticsearch
+
+import (
+	"encoding/binary"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+// HeatmapTile represents an elasticsearch implementation of the heatmap tile.
+type HeatmapTile struct {
+	Elasticsearch
+	Bivariate
+}
+
+// NewHeatmapTile instantiates and returns a new tile struct.
+func NewHeatmapTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		h := &HeatmapTile{}
+		h.Config = cfg
+		return h, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (h *HeatmapTile) Parse(params map[string]interface{}) error {
+	return h.Bivariate.Parse(params)
+}
+
+// Empty returns the tile containing no data.
+func (h *HeatmapTile) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return make([]byte, h.Resolution*h.Resolution*4), nil
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	filters, err := h.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	filters = append(filters, h.Bivariate.GetQuery(coord))
+
+	// get aggs
+	aggs := h.Bivariate.GetAggs(coord, nil)
+
+	// send query, paging through all bins
+	bins := make([]*Bucket, h.Resolution*h.Resolution)
+	err = h.SearchComposite(uri, filters, aggs, "bins", func(res *SearchResponse) error {
+		return h.Bivariate.GetBins(coord, res.Aggregations, bins)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to byte array
+	bits := make([]byte, len(bins)*4)
+	for i, bin := range bins {
+		if bin != nil {
+			binary.LittleEndian.PutUint32(
+				bits[i*4:i*4+4],
+				uint32(bin.DocCount))
+		}
+	}
+	return bits, nil
+}
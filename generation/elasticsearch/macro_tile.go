@@ -0,0 +1,78 @@
+package elasticsearch
+
+import (
+	"math"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// MacroTile represents an elasticsearch implementation of the macro tile.
+type MacroTile struct {
+	Elasticsearch
+	Bivariate
+	tile.Macro
+}
+
+// NewMacroTile instantiates and returns a new tile struct.
+func NewMacroTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		m := &MacroTile{}
+		m.Config = cfg
+		return m, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (m *MacroTile) Parse(params map[string]interface{}) error {
+	err := m.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return m.Macro.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (m *MacroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	filters, err := m.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	filters = append(filters, m.Bivariate.GetQuery(coord))
+
+	// get aggs
+	aggs := m.Bivariate.GetAggs(coord, nil)
+
+	// send query, paging through all bins
+	bins := make([]*Bucket, m.Resolution*m.Resolution)
+	err = m.SearchComposite(uri, filters, aggs, "bins", func(res *SearchResponse) error {
+		return m.Bivariate.GetBins(coord, res.Aggregations, bins)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// bin width
+	binSize := binning.MaxTileResolution / float64(m.Resolution)
+	halfSize := float64(binSize / 2)
+
+	// convert to point array
+	points := make([]float32, len(bins)*2)
+	numPoints := 0
+	for i, bin := range bins {
+		if bin != nil {
+			x := float32(float64(i%m.Resolution)*binSize + halfSize)
+			y := float32(math.Floor(float64(i/m.Resolution))*binSize + halfSize)
+			points[numPoints*2] = x
+			points[numPoints*2+1] = y
+			numPoints++
+		}
+	}
+
+	// encode the result
+	return m.Macro.Encode(points[0 : numPoints*2])
+}
@@ -0,0 +1,29 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// MatchesString represents an elasticsearch query-string query.
+type MatchesString struct {
+	query.MatchesString
+}
+
+// NewMatchesString instantiates and returns a new struct.
+func NewMatchesString() (veldt.Query, error) {
+	return &MatchesString{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *MatchesString) Get() (map[string]interface{}, error) {
+	queryString := map[string]interface{}{
+		"query": q.Match,
+	}
+	if len(q.Fields) > 0 {
+		queryString["fields"] = q.Fields
+	}
+	return map[string]interface{}{
+		"query_string": queryString,
+	}, nil
+}
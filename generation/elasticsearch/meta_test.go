@@ -0,0 +1,41 @@
+package elasticsearch_test
+
+import (
+	"github.com/unchartedsoftware/veldt/generation/elasticsearch"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("DefaultMeta", func() {
+
+	var server *standIn
+
+	AfterEach(func() {
+		elasticsearch.CloseClients()
+		server.Close()
+	})
+
+	It("should merge the properties of every matching index", func() {
+		server = newStandIn("mapping.json", "extrema.json")
+		cfg := &elasticsearch.Config{
+			Hosts: []string{server.URL()},
+		}
+		m, err := elasticsearch.NewDefaultMeta(cfg)()
+		Expect(err).To(BeNil())
+		res, err := m.Create("tweets-*")
+		Expect(err).To(BeNil())
+		Expect(JSON(string(res))).To(Equal(JSON(`{
+			"timestamp": { "type": "date" },
+			"text": { "type": "text" },
+			"text.keyword": { "type": "keyword" },
+			"location.x": { "type": "double", "extrema": { "min": 1, "max": 2 } },
+			"location.y": { "type": "double", "extrema": { "min": 3, "max": 4 } }
+		}`)))
+		Expect(len(server.requests)).To(Equal(2))
+		Expect(server.requests[0].Method).To(Equal("GET"))
+		Expect(server.requests[0].Path).To(Equal("/tweets-*/_mapping"))
+		Expect(server.requests[1].Path).To(Equal("/tweets-*/_search"))
+	})
+})
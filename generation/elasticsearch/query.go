@@ -0,0 +1,7 @@
+package elasticsearch
+
+// Query represents an elasticsearch implementation of the veldt.Query
+// interface. Queries are returned in the JSON query DSL.
+type Query interface {
+	Get() (map[string]interface{}, error)
+}
@@ -0,0 +1,42 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Range represents an elasticsearch range query.
+type Range struct {
+	query.Range
+}
+
+// NewRange instantiates and returns a new query struct.
+func NewRange() (veldt.Query, error) {
+	return &Range{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Range) Get() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"range": map[string]interface{}{
+			q.Field: getBounds(q.GTE, q.GT, q.LTE, q.LT),
+		},
+	}, nil
+}
+
+func getBounds(gte, gt, lte, lt interface{}) map[string]interface{} {
+	bounds := make(map[string]interface{})
+	if gte != nil {
+		bounds["gte"] = gte
+	}
+	if gt != nil {
+		bounds["gt"] = gt
+	}
+	if lte != nil {
+		bounds["lte"] = lte
+	}
+	if lt != nil {
+		bounds["lt"] = lt
+	}
+	return bounds
+}
@@ -0,0 +1,304 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// SearchResponse represents the response of a search request.
+type SearchResponse struct {
+	Took         int64        `json:"took"`
+	TimedOut     bool         `json:"timed_out"`
+	Hits         SearchHits   `json:"hits"`
+	Aggregations Aggregations `json:"aggregations"`
+}
+
+// SearchHits represents the hits of a search response.
+type SearchHits struct {
+	Total TotalHits    `json:"total"`
+	Hits  []*SearchHit `json:"hits"`
+}
+
+// TotalHits represents the total number of hits of a search response.
+type TotalHits struct {
+	Value    int64  `json:"value"`
+	Relation string `json:"relation"`
+}
+
+// UnmarshalJSON parses the total hits, which are returned as an object by
+// Elasticsearch 7 and later, and as a number by earlier versions.
+func (t *TotalHits) UnmarshalJSON(data []byte) error {
+	var value int64
+	if json.Unmarshal(data, &value) == nil {
+		t.Value = value
+		t.Relation = "eq"
+		return nil
+	}
+	total := struct {
+		Value    int64  `json:"value"`
+		Relation string `json:"relation"`
+	}{}
+	err := json.Unmarshal(data, &total)
+	if err != nil {
+		return err
+	}
+	t.Value = total.Value
+	t.Relation = total.Relation
+	return nil
+}
+
+// SearchHit represents a single hit of a search response.
+type SearchHit struct {
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Score  *float64        `json:"_score"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// Aggregations represents the aggregations of a search response, or the
+// sub-aggregations of a bucket.
+type Aggregations map[string]json.RawMessage
+
+// Buckets returns the multi-bucket aggregation under the provided name.
+func (a Aggregations) Buckets(name string) (*BucketAggregation, bool) {
+	raw, ok := a[name]
+	if !ok {
+		return nil, false
+	}
+	agg := &BucketAggregation{}
+	err := json.Unmarshal(raw, agg)
+	if err != nil {
+		return nil, false
+	}
+	return agg, true
+}
+
+// Bucket returns the single-bucket aggregation under the provided name.
+func (a Aggregations) Bucket(name string) (*Bucket, bool) {
+	raw, ok := a[name]
+	if !ok {
+		return nil, false
+	}
+	bucket := &Bucket{}
+	err := json.Unmarshal(raw, bucket)
+	if err != nil {
+		return nil, false
+	}
+	return bucket, true
+}
+
+// TopHits returns the top hits aggregation under the provided name.
+func (a Aggregations) TopHits(name string) (*SearchHits, bool) {
+	raw, ok := a[name]
+	if !ok {
+		return nil, false
+	}
+	agg := struct {
+		Hits SearchHits `json:"hits"`
+	}{}
+	err := json.Unmarshal(raw, &agg)
+	if err != nil {
+		return nil, false
+	}
+	return &agg.Hits, true
+}
+
+// Value returns the value of the metric aggregation under the provided name.
+// The value is nil if the metric has no value.
+func (a Aggregations) Value(name string) (*float64, bool) {
+	raw, ok := a[name]
+	if !ok {
+		return nil, false
+	}
+	agg := struct {
+		Value *float64 `json:"value"`
+	}{}
+	err := json.Unmarshal(raw, &agg)
+	if err != nil {
+		return nil, false
+	}
+	return agg.Value, true
+}
+
+// BucketAggregation represents a multi-bucket aggregation.
+type BucketAggregation struct {
+	// AfterKey holds the key to request the next page of a composite
+	// aggregation.
+	AfterKey map[string]interface{}
+	Buckets  []*Bucket
+}
+
+// UnmarshalJSON parses the aggregation. Keyed buckets, such as those of a
+// `filters` aggregation, are returned in order of key with the key set.
+func (b *BucketAggregation) UnmarshalJSON(data []byte) error {
+	agg := struct {
+		AfterKey map[string]interface{} `json:"after_key"`
+		Buckets  json.RawMessage        `json:"buckets"`
+	}{}
+	err := json.Unmarshal(data, &agg)
+	if err != nil {
+		return err
+	}
+	b.AfterKey = agg.AfterKey
+	b.Buckets = nil
+	if len(agg.Buckets) == 0 {
+		return nil
+	}
+	if agg.Buckets[0] == '{' {
+		keyed := make(map[string]*Bucket)
+		err := json.Unmarshal(agg.Buckets, &keyed)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(keyed))
+		for key := range keyed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			bucket := keyed[key]
+			bucket.Key = key
+			b.Buckets = append(b.Buckets, bucket)
+		}
+		return nil
+	}
+	return json.Unmarshal(agg.Buckets, &b.Buckets)
+}
+
+// Bucket represents a single bucket of an aggregation.
+type Bucket struct {
+	Key          interface{}
+	KeyAsString  string
+	DocCount     int64
+	From         *float64
+	To           *float64
+	Aggregations Aggregations
+}
+
+// UnmarshalJSON parses the bucket, where any unknown attributes are parsed
+// as sub-aggregations.
+func (b *Bucket) UnmarshalJSON(data []byte) error {
+	attrs := make(map[string]json.RawMessage)
+	err := json.Unmarshal(data, &attrs)
+	if err != nil {
+		return err
+	}
+	b.Aggregations = make(Aggregations)
+	for key, raw := range attrs {
+		switch key {
+		case "key":
+			err = json.Unmarshal(raw, &b.Key)
+		case "key_as_string":
+			err = json.Unmarshal(raw, &b.KeyAsString)
+		case "doc_count":
+			err = json.Unmarshal(raw, &b.DocCount)
+		case "from":
+			err = json.Unmarshal(raw, &b.From)
+		case "to":
+			err = json.Unmarshal(raw, &b.To)
+		case "from_as_string", "to_as_string", "doc_count_error_upper_bound", "meta":
+			// ignored
+		default:
+			b.Aggregations[key] = raw
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Merge merges the provided bucket into the bucket, such as when two buckets
+// fall within the same bin. The document counts are summed, along with the
+// values of single value metrics, which is exact for `sum` and `value_count`
+// metrics. Buckets of nested multi-bucket aggregations are merged by key.
+func (b *Bucket) Merge(other *Bucket) {
+	b.DocCount += other.DocCount
+	if b.Aggregations == nil {
+		b.Aggregations = make(Aggregations)
+	}
+	for name, raw := range other.Aggregations {
+		existing, ok := b.Aggregations[name]
+		if !ok {
+			b.Aggregations[name] = raw
+			continue
+		}
+		b.Aggregations[name] = mergeAggregation(existing, raw)
+	}
+}
+
+// mergeAggregation merges the raw aggregation b into a. Aggregations which
+// can not be merged are left as a.
+func mergeAggregation(a json.RawMessage, b json.RawMessage) json.RawMessage {
+	attrsA := make(map[string]json.RawMessage)
+	attrsB := make(map[string]json.RawMessage)
+	if json.Unmarshal(a, &attrsA) != nil || json.Unmarshal(b, &attrsB) != nil {
+		return a
+	}
+	if bucketsA, ok := attrsA["buckets"]; ok {
+		var listA, listB []map[string]json.RawMessage
+		if json.Unmarshal(bucketsA, &listA) != nil || json.Unmarshal(attrsB["buckets"], &listB) != nil {
+			// keyed buckets are not merged
+			return a
+		}
+		keys := make(map[string]map[string]json.RawMessage, len(listA))
+		for _, bucket := range listA {
+			keys[string(bucket["key"])] = bucket
+		}
+		for _, bucket := range listB {
+			match, ok := keys[string(bucket["key"])]
+			if !ok {
+				listA = append(listA, bucket)
+				continue
+			}
+			mergeBucketAttrs(match, bucket)
+		}
+		return marshalMerged(a, attrsA, "buckets", listA)
+	}
+	var valueA, valueB *float64
+	if json.Unmarshal(attrsA["value"], &valueA) != nil || json.Unmarshal(attrsB["value"], &valueB) != nil {
+		return a
+	}
+	if valueA == nil {
+		return b
+	}
+	if valueB == nil {
+		return a
+	}
+	return marshalMerged(a, attrsA, "value", *valueA+*valueB)
+}
+
+// mergeBucketAttrs merges the raw attributes of bucket b into a.
+func mergeBucketAttrs(a map[string]json.RawMessage, b map[string]json.RawMessage) {
+	for key, raw := range b {
+		existing, ok := a[key]
+		if !ok {
+			a[key] = raw
+			continue
+		}
+		switch key {
+		case "doc_count":
+			var countA, countB int64
+			if json.Unmarshal(existing, &countA) == nil && json.Unmarshal(raw, &countB) == nil {
+				a[key], _ = json.Marshal(countA + countB)
+			}
+		case "key", "key_as_string", "from", "to", "from_as_string", "to_as_string", "doc_count_error_upper_bound", "meta":
+			// identifying attributes
+		default:
+			a[key] = mergeAggregation(existing, raw)
+		}
+	}
+}
+
+func marshalMerged(original json.RawMessage, attrs map[string]json.RawMessage, key string, val interface{}) json.RawMessage {
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return original
+	}
+	attrs[key] = raw
+	merged, err := json.Marshal(attrs)
+	if err != nil {
+		return original
+	}
+	return merged
+}
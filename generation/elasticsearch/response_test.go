@@ -0,0 +1,52 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+
+	"github.com/unchartedsoftware/veldt/generation/elasticsearch"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bucket", func() {
+
+	bucket := func(str string) *elasticsearch.Bucket {
+		b := &elasticsearch.Bucket{}
+		err := json.Unmarshal([]byte(str), b)
+		Expect(err).To(BeNil())
+		return b
+	}
+
+	Describe("Merge", func() {
+		It("should sum the document counts and metric values", func() {
+			a := bucket(`{"key": 1, "doc_count": 3, "sum": {"value": 4.5}}`)
+			a.Merge(bucket(`{"key": 2, "doc_count": 2, "sum": {"value": 1.5}, "other": {"value": 1}}`))
+			Expect(a.DocCount).To(Equal(int64(5)))
+			sum, ok := a.Aggregations.Value("sum")
+			Expect(ok).To(Equal(true))
+			Expect(*sum).To(Equal(6.0))
+			other, ok := a.Aggregations.Value("other")
+			Expect(ok).To(Equal(true))
+			Expect(*other).To(Equal(1.0))
+		})
+
+		It("should merge nested buckets by key", func() {
+			a := bucket(`{"doc_count": 3, "terms": {"buckets": [
+				{"key": "a", "doc_count": 2},
+				{"key": "b", "doc_count": 1}
+			]}}`)
+			a.Merge(bucket(`{"doc_count": 2, "terms": {"buckets": [
+				{"key": "b", "doc_count": 1},
+				{"key": "c", "doc_count": 1}
+			]}}`))
+			terms, ok := a.Aggregations.Buckets("terms")
+			Expect(ok).To(Equal(true))
+			counts := make(map[interface{}]int64)
+			for _, b := range terms.Buckets {
+				counts[b.Key] = b.DocCount
+			}
+			Expect(counts).To(Equal(map[interface{}]int64{"a": 2, "b": 2, "c": 1}))
+		})
+	})
+})
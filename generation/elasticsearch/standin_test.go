@@ -0,0 +1,82 @@
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+)
+
+// recordedRequest represents a request received by the stand-in.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// standIn represents an HTTP stand-in for a cluster, which replays recorded
+// responses from the testdata directory in order.
+type standIn struct {
+	server    *httptest.Server
+	mutex     sync.Mutex
+	responses []string
+	status    int
+	requests  []*recordedRequest
+}
+
+func newStandIn(responses ...string) *standIn {
+	s := &standIn{
+		responses: responses,
+		status:    http.StatusOK,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *standIn) serve(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	req := &recordedRequest{
+		Method: r.Method,
+		Path:   r.URL.EscapedPath(),
+		Header: r.Header,
+	}
+	bytes, _ := ioutil.ReadAll(r.Body)
+	if len(bytes) > 0 {
+		json.Unmarshal(bytes, &req.Body)
+	}
+	s.requests = append(s.requests, req)
+	if len(s.responses) == 0 {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	response, err := ioutil.ReadFile(filepath.Join("testdata", s.responses[0]))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	s.responses = s.responses[1:]
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(s.status)
+	w.Write(response)
+}
+
+func (s *standIn) URL() string {
+	return s.server.URL
+}
+
+func (s *standIn) Close() {
+	s.server.Close()
+}
+
+// toJSON round trips the provided value through JSON such that it may be
+// compared against a recorded request body.
+func toJSON(v interface{}) interface{} {
+	bytes, _ := json.Marshal(v)
+	var res interface{}
+	json.Unmarshal(bytes, &res)
+	return res
+}
@@ -0,0 +1,64 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TargetTermCountTile represents an elasticsearch implementation of the
+// target term count tile.
+type TargetTermCountTile struct {
+	Elasticsearch
+	Bivariate
+	TargetTerms
+}
+
+// NewTargetTermCountTile instantiates and returns a new tile struct.
+func NewTargetTermCountTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &TargetTermCountTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TargetTermCountTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.TargetTerms.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TargetTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	filters, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	filters = append(filters, t.Bivariate.GetQuery(coord))
+
+	// send query
+	res, err := t.Search(uri, filters, t.TargetTerms.GetAggs())
+	if err != nil {
+		return nil, err
+	}
+
+	// get terms
+	terms, err := t.TargetTerms.GetTerms(res.Aggregations)
+	if err != nil {
+		return nil, err
+	}
+
+	// encode
+	counts := make(map[string]uint32)
+	for term, bucket := range terms {
+		counts[term] = uint32(bucket.DocCount)
+	}
+	return json.Marshal(counts)
+}
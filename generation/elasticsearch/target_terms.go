@@ -0,0 +1,59 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TargetTerms represents an elasticsearch implementation of the target terms
+// tile.
+type TargetTerms struct {
+	tile.TargetTerms
+}
+
+// GetQuery returns the appropriate elasticsearch query for the tile.
+func (t *TargetTerms) GetQuery() map[string]interface{} {
+	return map[string]interface{}{
+		"terms": map[string]interface{}{
+			t.TermsField: t.Terms,
+		},
+	}
+}
+
+// GetAggs returns the appropriate elasticsearch aggregation for the tile.
+func (t *TargetTerms) GetAggs() map[string]interface{} {
+	filters := make(map[string]interface{}, len(t.Terms))
+	for _, term := range t.Terms {
+		filters[term] = map[string]interface{}{
+			"term": map[string]interface{}{
+				t.TermsField: term,
+			},
+		}
+	}
+	return map[string]interface{}{
+		"target-terms": map[string]interface{}{
+			"filters": map[string]interface{}{
+				"filters": filters,
+			},
+		},
+	}
+}
+
+// GetTerms returns the individual term buckets from the provided aggregation.
+func (t *TargetTerms) GetTerms(aggs Aggregations) (map[string]*Bucket, error) {
+	agg, ok := aggs.Buckets("target-terms")
+	if !ok {
+		return nil, fmt.Errorf("filters aggregation `target-terms` was not found")
+	}
+	res := make(map[string]*Bucket, len(agg.Buckets))
+	for _, bucket := range agg.Buckets {
+		res[termKey(bucket)] = bucket
+	}
+	for _, term := range t.Terms {
+		if _, ok := res[term]; !ok {
+			return nil, fmt.Errorf("filter '%s' was not found", term)
+		}
+	}
+	return res, nil
+}
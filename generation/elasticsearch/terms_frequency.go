@@ -0,0 +1,55 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TermsFrequency represents an elasticsearch implementation of the terms
+// frequency tile. Every term is counted using a composite aggregation, such
+// that large sets of terms may be paged through.
+type TermsFrequency struct {
+	tile.TermsFrequency
+}
+
+// GetAggs returns the appropriate elasticsearch aggregation for the tile.
+func (t *TermsFrequency) GetAggs() map[string]interface{} {
+	return map[string]interface{}{
+		"terms": map[string]interface{}{
+			"composite": map[string]interface{}{
+				"size": compositeSize,
+				"sources": []interface{}{
+					map[string]interface{}{
+						"term": map[string]interface{}{
+							"terms": map[string]interface{}{
+								"field": t.TermsField,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// AddTerms adds the term counts of a page of the composite aggregation to
+// the provided map.
+func (t *TermsFrequency) AddTerms(aggs Aggregations, counts map[string]uint32) error {
+	agg, ok := aggs.Buckets("terms")
+	if !ok {
+		return fmt.Errorf("composite aggregation `terms` was not found")
+	}
+	for _, bucket := range agg.Buckets {
+		key, ok := bucket.Key.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("composite aggregation key was not an object")
+		}
+		term, ok := key["term"].(string)
+		if !ok {
+			term = fmt.Sprintf("%v", key["term"])
+		}
+		counts[term] = uint32(bucket.DocCount)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TermsFrequencyCountTile represents an elasticsearch implementation of the
+// terms frequency count tile, which counts every term within the tile.
+type TermsFrequencyCountTile struct {
+	Elasticsearch
+	Bivariate
+	TermsFrequency
+}
+
+// NewTermsFrequencyCountTile instantiates and returns a new tile struct.
+func NewTermsFrequencyCountTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &TermsFrequencyCountTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TermsFrequencyCountTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.TermsFrequency.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TermsFrequencyCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	filters, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	filters = append(filters, t.Bivariate.GetQuery(coord))
+
+	// send query, paging through all terms
+	counts := make(map[string]uint32)
+	err = t.SearchComposite(uri, filters, t.TermsFrequency.GetAggs(), "terms", func(res *SearchResponse) error {
+		return t.TermsFrequency.AddTerms(res.Aggregations, counts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// marshal results
+	return json.Marshal(counts)
+}
@@ -0,0 +1,246 @@
+package elasticsearch_test
+
+import (
+	"encoding/binary"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/elasticsearch"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Tile", func() {
+
+	const (
+		bivariate = `
+			"xField": "x",
+			"yField": "y",
+			"left": 0,
+			"right": 256,
+			"bottom": 0,
+			"top": 256`
+	)
+
+	var server *standIn
+	var cfg *elasticsearch.Config
+
+	AfterEach(func() {
+		elasticsearch.CloseClients()
+		server.Close()
+	})
+
+	serve := func(responses ...string) {
+		server = newStandIn(responses...)
+		cfg = &elasticsearch.Config{
+			Hosts: []string{server.URL()},
+		}
+	}
+
+	create := func(ctor veldt.TileCtor, params string, uri string, coord *binning.TileCoord, query veldt.Query) []byte {
+		t, err := ctor()
+		Expect(err).To(BeNil())
+		err = t.Parse(JSON(params))
+		Expect(err).To(BeNil())
+		res, err := t.Create(uri, coord, query)
+		Expect(err).To(BeNil())
+		return res
+	}
+
+	Describe("HeatmapTile", func() {
+		It("should page through every bin of the composite aggregation", func() {
+			serve("heatmap_page1.json", "heatmap_page2.json", "heatmap_page3.json")
+			res := create(elasticsearch.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 4}`,
+				"tweets/tweet", &binning.TileCoord{}, nil)
+			bins := make([]uint32, len(res)/4)
+			for i := range bins {
+				bins[i] = binary.LittleEndian.Uint32(res[i*4 : i*4+4])
+			}
+			Expect(bins).To(Equal([]uint32{3, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2}))
+			Expect(len(server.requests)).To(Equal(3))
+			// mapping types are ignored
+			Expect(server.requests[0].Path).To(Equal("/tweets/_search"))
+			composite, ok := server.requests[0].Body["aggs"].(map[string]interface{})["bins"].(map[string]interface{})["composite"].(map[string]interface{})
+			Expect(ok).To(BeTrue())
+			Expect(composite["after"]).To(BeNil())
+			sources := composite["sources"].([]interface{})
+			Expect(sources).To(HaveLen(2))
+			x := sources[0].(map[string]interface{})["x"].(map[string]interface{})["histogram"].(map[string]interface{})
+			Expect(x["interval"]).To(Equal(1.0))
+			Expect(x["script"].(map[string]interface{})["params"]).To(Equal(toJSON(map[string]interface{}{
+				"field": "x",
+				"min":   0,
+				"size":  64,
+			})))
+			after := server.requests[1].Body["aggs"].(map[string]interface{})["bins"].(map[string]interface{})["composite"].(map[string]interface{})["after"]
+			Expect(after).To(Equal(toJSON(map[string]interface{}{"x": 1, "y": 0})))
+		})
+
+		It("should bin relative to bounds which are not aligned to the bin size", func() {
+			serve("heatmap_offset.json")
+			res := create(elasticsearch.NewHeatmapTile(cfg), `{
+				"xField": "x",
+				"yField": "y",
+				"left": 10,
+				"right": 266,
+				"bottom": -20,
+				"top": 236,
+				"resolution": 4
+			}`, "tweets", &binning.TileCoord{}, nil)
+			bins := make([]uint32, len(res)/4)
+			for i := range bins {
+				bins[i] = binary.LittleEndian.Uint32(res[i*4 : i*4+4])
+			}
+			// buckets clamped into the same bin are merged
+			Expect(bins).To(Equal([]uint32{3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4}))
+			composite := server.requests[0].Body["aggs"].(map[string]interface{})["bins"].(map[string]interface{})["composite"].(map[string]interface{})
+			sources := composite["sources"].([]interface{})
+			y := sources[1].(map[string]interface{})["y"].(map[string]interface{})["histogram"].(map[string]interface{})
+			Expect(y["script"].(map[string]interface{})["params"]).To(Equal(toJSON(map[string]interface{}{
+				"field": "y",
+				"min":   -20,
+				"size":  64,
+			})))
+		})
+
+		It("should bin mercator tiles with an x histogram and y ranges", func() {
+			serve("heatmap_mercator.json")
+			res := create(elasticsearch.NewHeatmapTile(cfg), `{
+				"xField": "lon",
+				"yField": "lat",
+				"projection": "mercator",
+				"resolution": 4
+			}`, "tweets", &binning.TileCoord{}, nil)
+			bins := make([]uint32, len(res)/4)
+			for i := range bins {
+				bins[i] = binary.LittleEndian.Uint32(res[i*4 : i*4+4])
+			}
+			Expect(bins).To(Equal([]uint32{3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2}))
+			agg := server.requests[0].Body["aggs"].(map[string]interface{})["bins"].(map[string]interface{})
+			Expect(agg["histogram"]).To(Equal(toJSON(map[string]interface{}{
+				"field":         "lon",
+				"interval":      90,
+				"offset":        -180,
+				"min_doc_count": 1,
+			})))
+			ranges := agg["aggs"].(map[string]interface{})["y"].(map[string]interface{})["range"].(map[string]interface{})["ranges"]
+			Expect(len(ranges.([]interface{}))).To(Equal(4))
+		})
+	})
+
+	Describe("CountTile", func() {
+		It("should return the total hits", func() {
+			serve("count.json")
+			res := create(elasticsearch.NewCountTile(cfg), `{`+bivariate+`}`,
+				"tweets", &binning.TileCoord{}, nil)
+			Expect(string(res)).To(Equal(`{"count":42}`))
+			Expect(server.requests[0].Body["track_total_hits"]).To(Equal(true))
+		})
+
+		It("should support numeric total hits", func() {
+			serve("count_legacy.json")
+			res := create(elasticsearch.NewCountTile(cfg), `{`+bivariate+`}`,
+				"tweets", &binning.TileCoord{}, nil)
+			Expect(string(res)).To(Equal(`{"count":17}`))
+		})
+
+		It("should add the filter query", func() {
+			serve("count.json")
+			q := &elasticsearch.Equals{}
+			q.Field = "lang"
+			q.Value = "en"
+			create(elasticsearch.NewCountTile(cfg), `{`+bivariate+`}`,
+				"tweets", &binning.TileCoord{}, q)
+			filters := server.requests[0].Body["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"].([]interface{})
+			Expect(filters[0]).To(Equal(toJSON(map[string]interface{}{
+				"term": map[string]interface{}{"lang": "en"},
+			})))
+		})
+	})
+
+	Describe("FrequencyTile", func() {
+		It("should use calendar intervals in the time zone", func() {
+			serve("frequency.json")
+			res := create(elasticsearch.NewFrequencyTile(cfg), `{`+bivariate+`,
+				"frequencyField": "timestamp",
+				"gte": 1483228800000,
+				"lt": 1483488000000,
+				"interval": "day",
+				"timeZone": "America/Toronto"
+			}`, "tweets", &binning.TileCoord{}, nil)
+			Expect(JSON(`{"buckets":` + string(res) + `}`)).To(Equal(JSON(`{
+				"buckets": [
+					{ "timestamp": 1483228800000, "count": 2 },
+					{ "timestamp": 1483315200000, "count": 0 },
+					{ "timestamp": 1483401600000, "count": 1 }
+				]
+			}`)))
+			histogram := server.requests[0].Body["aggs"].(map[string]interface{})["frequency"].(map[string]interface{})["date_histogram"]
+			Expect(histogram).To(Equal(toJSON(map[string]interface{}{
+				"field":             "timestamp",
+				"calendar_interval": "1d",
+				"min_doc_count":     0,
+				"time_zone":         "America/Toronto",
+				"extended_bounds": map[string]interface{}{
+					"min": 1483228800000,
+					"max": 1483487999999,
+				},
+			})))
+		})
+
+		It("should offset fixed intervals to the lower bound", func() {
+			serve("frequency.json")
+			create(elasticsearch.NewFrequencyTile(cfg), `{`+bivariate+`,
+				"frequencyField": "timestamp",
+				"gte": 1000,
+				"interval": "1h"
+			}`, "tweets", &binning.TileCoord{}, nil)
+			histogram := server.requests[0].Body["aggs"].(map[string]interface{})["frequency"].(map[string]interface{})["date_histogram"].(map[string]interface{})
+			Expect(histogram["fixed_interval"]).To(Equal("3600000ms"))
+			Expect(histogram["offset"]).To(Equal("1000ms"))
+		})
+	})
+
+	Describe("MicroTile", func() {
+		It("should return the top hits within the tile", func() {
+			serve("micro.json")
+			res := create(elasticsearch.NewMicroTile(cfg), `{`+bivariate+`, "hitsCount": 2, "sortField": "x", "sortOrder": "desc", "includeFields": ["name"]}`,
+				"tweets", &binning.TileCoord{}, nil)
+			Expect(JSON(string(res))).To(Equal(JSON(`{
+				"points": [ 32, 64, 128, 16 ],
+				"hits": [ { "name": "a" }, { "name": "b" } ]
+			}`)))
+			topHits := server.requests[0].Body["aggs"].(map[string]interface{})["top-hits"].(map[string]interface{})["top_hits"]
+			Expect(topHits).To(Equal(toJSON(map[string]interface{}{
+				"size": 2,
+				"sort": []interface{}{
+					map[string]interface{}{"x": map[string]interface{}{"order": "desc"}},
+				},
+				"_source": map[string]interface{}{
+					"includes": []string{"name", "x", "y"},
+				},
+			})))
+		})
+	})
+
+	Describe("TargetTermCountTile", func() {
+		It("should count each of the target terms", func() {
+			serve("target_terms.json")
+			res := create(elasticsearch.NewTargetTermCountTile(cfg), `{`+bivariate+`, "termsField": "animal", "terms": ["cat", "dog"]}`,
+				"tweets", &binning.TileCoord{}, nil)
+			Expect(JSON(string(res))).To(Equal(JSON(`{"cat": 3, "dog": 2}`)))
+		})
+	})
+
+	Describe("TermsFrequencyCountTile", func() {
+		It("should page through every term", func() {
+			serve("terms_page1.json", "terms_page2.json")
+			res := create(elasticsearch.NewTermsFrequencyCountTile(cfg), `{`+bivariate+`, "termsField": "animal"}`,
+				"tweets", &binning.TileCoord{}, nil)
+			Expect(JSON(string(res))).To(Equal(JSON(`{"cat": 4, "dog": 3, "eel": 2}`)))
+			Expect(len(server.requests)).To(Equal(2))
+		})
+	})
+})
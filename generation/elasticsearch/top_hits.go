@@ -0,0 +1,62 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TopHits represents an elasticsearch implementation of the top hits tile.
+type TopHits struct {
+	tile.TopHits
+}
+
+// GetAggs returns the appropriate elasticsearch aggregation for the tile.
+func (t *TopHits) GetAggs() map[string]interface{} {
+	agg := map[string]interface{}{
+		"size": t.HitsCount,
+	}
+	// sort
+	if t.SortField != "" {
+		order := "asc"
+		if t.SortOrder == "desc" {
+			order = "desc"
+		}
+		agg["sort"] = []interface{}{
+			map[string]interface{}{
+				t.SortField: map[string]interface{}{
+					"order": order,
+				},
+			},
+		}
+	}
+	// add includes
+	if t.IncludeFields != nil {
+		agg["_source"] = map[string]interface{}{
+			"includes": t.IncludeFields,
+		}
+	}
+	return map[string]interface{}{
+		"top-hits": map[string]interface{}{
+			"top_hits": agg,
+		},
+	}
+}
+
+// GetTopHits returns the individual hits from the provided aggregation.
+func (t *TopHits) GetTopHits(aggs Aggregations) ([]map[string]interface{}, error) {
+	topHits, ok := aggs.TopHits("top-hits")
+	if !ok {
+		return nil, fmt.Errorf("top-hits aggregation `top-hits` was not found")
+	}
+	hits := make([]map[string]interface{}, len(topHits.Hits))
+	for index, hit := range topHits.Hits {
+		src, err := json.Unmarshal(hit.Source)
+		if err != nil {
+			return nil, err
+		}
+		hits[index] = src
+	}
+	return hits, nil
+}
@@ -0,0 +1,64 @@
+package elasticsearch
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TopTermCountTile represents an elasticsearch implementation of the top term
+// count tile.
+type TopTermCountTile struct {
+	Elasticsearch
+	Bivariate
+	TopTerms
+}
+
+// NewTopTermCountTile instantiates and returns a new tile struct.
+func NewTopTermCountTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &TopTermCountTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TopTermCountTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.TopTerms.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TopTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	filters, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// add tiling query
+	filters = append(filters, t.Bivariate.GetQuery(coord))
+
+	// send query
+	res, err := t.Search(uri, filters, t.TopTerms.GetAggs())
+	if err != nil {
+		return nil, err
+	}
+
+	// get terms
+	terms, err := t.TopTerms.GetTerms(res.Aggregations)
+	if err != nil {
+		return nil, err
+	}
+
+	// encode
+	counts := make(map[string]uint32)
+	for term, bucket := range terms {
+		counts[term] = uint32(bucket.DocCount)
+	}
+	return json.Marshal(counts)
+}
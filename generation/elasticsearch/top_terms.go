@@ -0,0 +1,48 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TopTerms represents an elasticsearch implementation of the top terms tile.
+type TopTerms struct {
+	tile.TopTerms
+}
+
+// GetAggs returns the appropriate elasticsearch aggregation for the tile.
+func (t *TopTerms) GetAggs() map[string]interface{} {
+	return map[string]interface{}{
+		"top-terms": map[string]interface{}{
+			"terms": map[string]interface{}{
+				"field": t.TermsField,
+				"size":  t.TermsCount,
+			},
+		},
+	}
+}
+
+// GetTerms returns the individual term buckets from the provided aggregation.
+func (t *TopTerms) GetTerms(aggs Aggregations) (map[string]*Bucket, error) {
+	terms, ok := aggs.Buckets("top-terms")
+	if !ok {
+		return nil, fmt.Errorf("terms aggregation `top-terms` was not found")
+	}
+	counts := make(map[string]*Bucket)
+	for _, bucket := range terms.Buckets {
+		counts[termKey(bucket)] = bucket
+	}
+	return counts, nil
+}
+
+// termKey returns the key of a terms bucket as a string.
+func termKey(bucket *Bucket) string {
+	if bucket.KeyAsString != "" {
+		return bucket.KeyAsString
+	}
+	if str, ok := bucket.Key.(string); ok {
+		return str
+	}
+	return fmt.Sprintf("%v", bucket.Key)
+}
@@ -0,0 +1,53 @@
+package filescan
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Config defines the data directory of the file scan backend.
+type Config struct {
+	// Root is the directory containing the data files. Tile URIs are
+	// resolved relative to it and may not refer to any file outside of it.
+	Root string
+}
+
+// Resolve returns the path of the provided uri within the root directory,
+// with any symbolic links evaluated. An error is returned if the path does
+// not exist or escapes the root directory.
+func (c *Config) Resolve(uri string) (string, error) {
+	if c == nil || c.Root == "" {
+		return "", fmt.Errorf("no data root directory has been configured")
+	}
+	root, err := filepath.Abs(c.Root)
+	if err != nil {
+		return "", err
+	}
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	// absolute uris are treated as relative to the root
+	path := filepath.Join(root, uri)
+	if !isWithin(root, path) {
+		return "", fmt.Errorf("`%s` is outside of the data root directory", uri)
+	}
+	// symbolic links may also point outside of the root
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	if !isWithin(root, path) {
+		return "", fmt.Errorf("`%s` is outside of the data root directory", uri)
+	}
+	return path, nil
+}
+
+func isWithin(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
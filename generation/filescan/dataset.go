@@ -0,0 +1,203 @@
+package filescan
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/unchartedsoftware/veldt/generation/memory"
+)
+
+const (
+	csvExtension     = ".csv"
+	parquetExtension = ".parquet"
+)
+
+var (
+	mutex    = sync.Mutex{}
+	datasets = make(map[string]*Dataset)
+)
+
+// Dataset represents the table of records read from the files of a uri, along
+// with the spatial indexes built over it.
+type Dataset struct {
+	Table   *memory.Table
+	uri     string
+	files   []string
+	modTime time.Time
+	mutex   sync.Mutex
+	indexes map[string]*Index
+}
+
+// GetDataset returns the dataset for the provided uri. The uri must be the
+// resolved path to a single CSV or Parquet file, or to a directory containing
+// them, see Config.Resolve. Datasets are cached and only re-read when the
+// underlying files are modified.
+func GetDataset(uri string) (*Dataset, error) {
+	files, modTime, err := getFiles(uri)
+	if err != nil {
+		return nil, err
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	dataset, ok := datasets[uri]
+	if ok && dataset.modTime.Equal(modTime) && len(dataset.files) == len(files) {
+		return dataset, nil
+	}
+	// read the records of each file
+	records := make([]map[string]interface{}, 0)
+	for _, file := range files {
+		recs, err := readFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read `%s`: %v", file, err)
+		}
+		records = append(records, recs...)
+	}
+	Infof("Loaded %d records from `%s`", len(records), uri)
+	dataset = &Dataset{
+		Table:   memory.NewTable(records),
+		uri:     uri,
+		files:   files,
+		modTime: modTime,
+		indexes: make(map[string]*Index),
+	}
+	datasets[uri] = dataset
+	return dataset, nil
+}
+
+// GetIndex returns the spatial index of the dataset for the provided
+// bivariate parameters. The index is read from disk if it has been persisted
+// and is up to date, otherwise it is built and persisted next to the data.
+func (d *Dataset) GetIndex(bivariate *memory.Bivariate) (*Index, error) {
+	key, err := indexKey(bivariate)
+	if err != nil {
+		return nil, err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	index, ok := d.indexes[key]
+	if ok {
+		return index, nil
+	}
+	path := d.indexPath(key)
+	// attempt to read the persisted index
+	index, err = readIndex(path, d.modTime, d.Table.NumRows)
+	if err != nil {
+		index = NewIndex(d.Table, bivariate)
+		err = index.Write(path)
+		if err != nil {
+			// the index is still usable in memory
+			Warnf("Unable to persist index to `%s`: %v", path, err)
+		}
+	}
+	d.indexes[key] = index
+	return index, nil
+}
+
+func (d *Dataset) indexPath(key string) string {
+	info, err := os.Stat(d.uri)
+	if err == nil && info.IsDir() {
+		return filepath.Join(d.uri, "."+key+indexExtension)
+	}
+	return d.uri + "." + key + indexExtension
+}
+
+func isDataFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == csvExtension || ext == parquetExtension
+}
+
+// getFiles returns the data files of the uri in lexical order, along with the
+// most recent modification time among them.
+func getFiles(uri string) ([]string, time.Time, error) {
+	info, err := os.Stat(uri)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if !info.IsDir() {
+		if !isDataFile(uri) {
+			return nil, time.Time{}, fmt.Errorf("`%s` is not a CSV or Parquet file", uri)
+		}
+		return []string{uri}, info.ModTime(), nil
+	}
+	infos, err := ioutil.ReadDir(uri)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	files := make([]string, 0)
+	modTime := time.Time{}
+	for _, info := range infos {
+		if info.IsDir() || !isDataFile(info.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(uri, info.Name()))
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	if len(files) == 0 {
+		return nil, time.Time{}, fmt.Errorf("`%s` does not contain any CSV or Parquet files", uri)
+	}
+	sort.Strings(files)
+	return files, modTime, nil
+}
+
+func readFile(path string) ([]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(path)) == csvExtension {
+		return memory.ReadCSV(f)
+	}
+	return readParquet(f)
+}
+
+func readParquet(f *os.File) ([]map[string]interface{}, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	file, err := parquet.OpenFile(f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+	records := make([]map[string]interface{}, 0, file.NumRows())
+	for {
+		record := make(map[string]interface{})
+		err := reader.Read(&record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, convertParquetRecord(record))
+	}
+	return records, nil
+}
+
+// convertParquetRecord converts byte array values, which parquet uses for
+// strings, into strings.
+func convertParquetRecord(record map[string]interface{}) map[string]interface{} {
+	for key, val := range record {
+		switch v := val.(type) {
+		case []byte:
+			record[key] = string(v)
+		case map[string]interface{}:
+			record[key] = convertParquetRecord(v)
+		}
+	}
+	return record
+}
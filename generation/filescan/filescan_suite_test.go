@@ -0,0 +1,13 @@
+package filescan_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestFileScan(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "FileScan Suite")
+}
@@ -0,0 +1,59 @@
+package filescan
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// FrequencyTile represents an file scan implementation of the frequency tile.
+type FrequencyTile struct {
+	Tile
+	memory.Bivariate
+	memory.Frequency
+}
+
+// NewFrequencyTile instantiates and returns a new tile struct.
+func NewFrequencyTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		f := &FrequencyTile{}
+		f.Config = cfg
+		return f, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *FrequencyTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// get the rows matching the query
+	table, rows, err := t.InitializeTile(uri, coord, &t.Bivariate, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// get buckets
+	frequency, err := t.Frequency.GetBuckets(table, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]map[string]interface{}, len(frequency))
+	for i, bucket := range frequency {
+		buckets[i] = map[string]interface{}{
+			"timestamp": bucket.Timestamp,
+			"count":     len(bucket.Rows),
+		}
+	}
+	// marshal results
+	return json.Marshal(buckets)
+}
@@ -0,0 +1,56 @@
+package filescan
+
+import (
+	"encoding/binary"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+)
+
+// HeatmapTile represents an file scan implementation of the heatmap tile.
+type HeatmapTile struct {
+	Tile
+	memory.Bivariate
+}
+
+// NewHeatmapTile instantiates and returns a new tile struct.
+func NewHeatmapTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		h := &HeatmapTile{}
+		h.Config = cfg
+		return h, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (h *HeatmapTile) Parse(params map[string]interface{}) error {
+	return h.Bivariate.Parse(params)
+}
+
+// Empty returns the tile containing no data.
+func (h *HeatmapTile) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return make([]byte, h.Resolution*h.Resolution*4), nil
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// get the rows matching the query
+	table, rows, err := h.InitializeTile(uri, coord, &h.Bivariate, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// get bins
+	bins := h.Bivariate.GetBins(coord, table, rows)
+
+	// convert to byte array
+	bits := make([]byte, len(bins)*4)
+	for i, bin := range bins {
+		binary.LittleEndian.PutUint32(
+			bits[i*4:i*4+4],
+			uint32(len(bin)))
+	}
+	return bits, nil
+}
@@ -0,0 +1,246 @@
+package filescan
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+const (
+	// indexLevel is the zoom level of the index cells, matching the 8 bits
+	// per axis supported by tile.Morton.
+	indexLevel     = 8
+	indexDim       = 1 << indexLevel
+	numCells       = indexDim * indexDim
+	indexExtension = ".idx"
+	indexMagic     = "VIDX"
+	indexVersion   = 1
+)
+
+// Index represents a spatial index of the rows of a table. Rows are sorted by
+// the morton code of the level 8 tile containing them, such that the rows of
+// any tile at or above level 8 occupy a contiguous range. Rows without a
+// position, or positioned outside of the global bounds, are not indexed.
+type Index struct {
+	// Order holds the indexed rows sorted by morton code.
+	Order []uint32
+	// Offsets holds the start of each morton code within the order, with a
+	// final entry holding the total number of indexed rows.
+	Offsets []uint32
+	// NumRows holds the number of rows in the indexed table.
+	NumRows int
+}
+
+// NewIndex builds and returns a new spatial index over the x and y fields of
+// the provided table.
+func NewIndex(table *memory.Table, bivariate *memory.Bivariate) *Index {
+	codes := make([]int, table.NumRows)
+	counts := make([]uint32, numCells+1)
+	for row := 0; row < table.NumRows; row++ {
+		x, okX := table.Float(bivariate.XField, row)
+		y, okY := table.Float(bivariate.YField, row)
+		if !okX || !okY {
+			codes[row] = -1
+			continue
+		}
+		code, ok := getCode(bivariate, x, y)
+		if !ok {
+			codes[row] = -1
+			continue
+		}
+		codes[row] = code
+		counts[code+1]++
+	}
+	// prefix sum the counts into offsets
+	offsets := counts
+	for i := 1; i < len(offsets); i++ {
+		offsets[i] += offsets[i-1]
+	}
+	// place each row, preserving row order within a code
+	order := make([]uint32, offsets[numCells])
+	next := make([]uint32, numCells)
+	copy(next, offsets[:numCells])
+	for row, code := range codes {
+		if code < 0 {
+			continue
+		}
+		order[next[code]] = uint32(row)
+		next[code]++
+	}
+	return &Index{
+		Order:   order,
+		Offsets: offsets,
+		NumRows: table.NumRows,
+	}
+}
+
+// Rows returns the indexed rows which may fall within the provided tile, in
+// ascending row order. The result is a superset of the rows within the tile
+// and must be filtered against the tile bounds. No rows are returned for
+// coords outside of the tile pyramid.
+func (i *Index) Rows(coord *binning.TileCoord) []int {
+	dim := uint64(1) << coord.Z
+	if uint64(coord.X) >= dim || uint64(coord.Y) >= dim {
+		return nil
+	}
+	var start, end int
+	if coord.Z <= indexLevel {
+		// the tile spans a contiguous range of codes
+		shift := 2 * (indexLevel - coord.Z)
+		first := tile.Morton(float32(coord.X), float32(coord.Y)) << shift
+		start = first
+		end = first + (1 << shift)
+	} else {
+		// the tile lies within a single code
+		shift := coord.Z - indexLevel
+		code := tile.Morton(float32(coord.X>>shift), float32(coord.Y>>shift))
+		start = code
+		end = code + 1
+	}
+	order := i.Order[i.Offsets[start]:i.Offsets[end]]
+	rows := make([]int, len(order))
+	for j, row := range order {
+		rows[j] = int(row)
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+// Write persists the index to the provided path.
+func (i *Index) Write(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	header := []uint32{indexVersion, uint32(i.NumRows), uint32(len(i.Order))}
+	_, err = w.WriteString(indexMagic)
+	if err == nil {
+		err = binary.Write(w, binary.LittleEndian, header)
+	}
+	if err == nil {
+		err = binary.Write(w, binary.LittleEndian, i.Offsets)
+	}
+	if err == nil {
+		err = binary.Write(w, binary.LittleEndian, i.Order)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// readIndex reads a persisted index from the provided path. An error is
+// returned if the index does not exist, is older than the data, or does not
+// match the number of rows in the table.
+func readIndex(path string, modTime time.Time, numRows int) (*Index, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.ModTime().Before(modTime) {
+		return nil, fmt.Errorf("index `%s` is out of date", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	magic := make([]byte, len(indexMagic))
+	_, err = io.ReadFull(r, magic)
+	if err != nil {
+		return nil, err
+	}
+	if string(magic) != indexMagic {
+		return nil, fmt.Errorf("`%s` is not an index file", path)
+	}
+	header := make([]uint32, 3)
+	err = binary.Read(r, binary.LittleEndian, header)
+	if err != nil {
+		return nil, err
+	}
+	if header[0] != indexVersion {
+		return nil, fmt.Errorf("index `%s` version %d is not supported", path, header[0])
+	}
+	if int(header[1]) != numRows {
+		return nil, fmt.Errorf("index `%s` does not match the number of rows", path)
+	}
+	index := &Index{
+		Offsets: make([]uint32, numCells+1),
+		Order:   make([]uint32, header[2]),
+		NumRows: numRows,
+	}
+	err = binary.Read(r, binary.LittleEndian, index.Offsets)
+	if err != nil {
+		return nil, err
+	}
+	err = binary.Read(r, binary.LittleEndian, index.Order)
+	if err != nil {
+		return nil, err
+	}
+	if index.Offsets[numCells] != header[2] {
+		return nil, fmt.Errorf("index `%s` is corrupt", path)
+	}
+	return index, nil
+}
+
+// getCode returns the morton code of the level 8 tile containing the
+// position, or false if the position is outside of the global bounds.
+func getCode(bivariate *memory.Bivariate, x float64, y float64) (int, bool) {
+	var fractional *binning.FractionalTileCoord
+	if bivariate.Projection == tile.MercatorProjection {
+		fractional = binning.LonLatToFractionalTile(binning.NewLonLat(x, y), indexLevel)
+	} else {
+		fractional = binning.CoordToFractionalTile(
+			&geometry.Coord{X: x, Y: y},
+			indexLevel,
+			bivariate.GlobalBounds())
+	}
+	fx := math.Floor(fractional.X)
+	fy := math.Floor(fractional.Y)
+	if fx < 0 || fx >= indexDim || fy < 0 || fy >= indexDim ||
+		math.IsNaN(fx) || math.IsNaN(fy) {
+		return 0, false
+	}
+	return tile.Morton(float32(fx), float32(fy)), true
+}
+
+// indexKey returns a key identifying the index for the provided bivariate
+// parameters.
+func indexKey(bivariate *memory.Bivariate) (string, error) {
+	desc := fmt.Sprintf("%s:%s:%s",
+		bivariate.XField,
+		bivariate.YField,
+		bivariate.Projection)
+	if bivariate.Projection != tile.MercatorProjection {
+		bounds := bivariate.GlobalBounds()
+		if bounds == nil {
+			return "", fmt.Errorf("bivariate tile has not been parsed")
+		}
+		desc += fmt.Sprintf(":%v:%v:%v:%v",
+			bounds.Left,
+			bounds.Right,
+			bounds.Bottom,
+			bounds.Top)
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(desc))
+	return fmt.Sprintf("%016x", hash.Sum64()), nil
+}
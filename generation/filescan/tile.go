@@ -0,0 +1,44 @@
+package filescan
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+)
+
+// Tile represents a file scan tile type.
+type Tile struct {
+	memory.Tile
+	Config *Config
+}
+
+// InitializeTile reads the dataset for the provided uri and returns the rows
+// of it which fall within the tile and match the query. The uri is resolved
+// within the configured root directory. Candidate rows are retrieved from the
+// spatial index rather than scanning the entire table.
+func (t *Tile) InitializeTile(uri string, coord *binning.TileCoord, bivariate *memory.Bivariate, query veldt.Query) (*memory.Table, []int, error) {
+	// resolve the data path
+	path, err := t.Config.Resolve(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	// get dataset
+	dataset, err := GetDataset(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	// get spatial index
+	index, err := dataset.GetIndex(bivariate)
+	if err != nil {
+		return nil, nil, err
+	}
+	table := dataset.Table
+	// create predicate
+	predicate, err := t.CreateQuery(table, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	// scan the index range of the tile
+	rows := bivariate.GetRows(coord, table, index.Rows(coord))
+	return table, table.Filter(rows, predicate), nil
+}
@@ -0,0 +1,219 @@
+package filescan_test
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/filescan"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+type record struct {
+	X         float64 `parquet:"x"`
+	Y         float64 `parquet:"y"`
+	Term      string  `parquet:"term"`
+	Timestamp int64   `parquet:"timestamp"`
+}
+
+var _ = Describe("Tile", func() {
+
+	const (
+		data = "x,y,term,timestamp\n" +
+			"10,10,a,0\n" +
+			"20,20,a,1000\n" +
+			"30,200,b,2000\n" +
+			"300,30,c,3000\n"
+	)
+
+	var dir string
+	var uri string
+	var cfg *filescan.Config
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "filescan")
+		Expect(err).To(BeNil())
+		cfg = &filescan.Config{
+			Root: dir,
+		}
+		uri = "data.csv"
+		err = ioutil.WriteFile(filepath.Join(dir, uri), []byte(data), 0644)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	create := func(ctor veldt.TileCtor, params string, coord *binning.TileCoord) []byte {
+		t, err := ctor()
+		Expect(err).To(BeNil())
+		err = t.Parse(JSON(params))
+		Expect(err).To(BeNil())
+		res, err := t.Create(uri, coord, nil)
+		Expect(err).To(BeNil())
+		return res
+	}
+
+	getBins := func(res []byte) []uint32 {
+		bins := make([]uint32, len(res)/4)
+		for i := range bins {
+			bins[i] = binary.LittleEndian.Uint32(res[i*4 : i*4+4])
+		}
+		return bins
+	}
+
+	bivariate := `
+		"xField": "x",
+		"yField": "y",
+		"left": 0,
+		"right": 512,
+		"bottom": 0,
+		"top": 512`
+
+	Describe("HeatmapTile", func() {
+		It("should bin the rows within the tile", func() {
+			res := create(filescan.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 2}`,
+				&binning.TileCoord{X: 0, Y: 0, Z: 1})
+			Expect(getBins(res)).To(Equal([]uint32{2, 0, 1, 0}))
+		})
+
+		It("should bin the rows within tiles below the index level", func() {
+			// level 10 tile containing x: [10, 10.5), y: [10, 10.5)
+			res := create(filescan.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 1}`,
+				&binning.TileCoord{X: 20, Y: 20, Z: 10})
+			Expect(getBins(res)).To(Equal([]uint32{1}))
+			res = create(filescan.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 1}`,
+				&binning.TileCoord{X: 21, Y: 20, Z: 10})
+			Expect(getBins(res)).To(Equal([]uint32{0}))
+		})
+
+		It("should return no rows for coords outside of the tile pyramid", func() {
+			res := create(filescan.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 2}`,
+				&binning.TileCoord{X: 1, Y: 0, Z: 0})
+			Expect(getBins(res)).To(Equal([]uint32{0, 0, 0, 0}))
+			res = create(filescan.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 2}`,
+				&binning.TileCoord{X: 0, Y: 1024, Z: 10})
+			Expect(getBins(res)).To(Equal([]uint32{0, 0, 0, 0}))
+		})
+
+		It("should persist the index next to the data", func() {
+			create(filescan.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 2}`,
+				&binning.TileCoord{X: 0, Y: 0, Z: 1})
+			matches, err := filepath.Glob(filepath.Join(dir, uri) + ".*.idx")
+			Expect(err).To(BeNil())
+			Expect(len(matches)).To(Equal(1))
+		})
+
+		It("should read the files of a directory", func() {
+			err := ioutil.WriteFile(filepath.Join(dir, "more.csv"), []byte("x,y\n40,40\n"), 0644)
+			Expect(err).To(BeNil())
+			uri = "."
+			res := create(filescan.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 2}`,
+				&binning.TileCoord{X: 0, Y: 0, Z: 1})
+			Expect(getBins(res)).To(Equal([]uint32{3, 0, 1, 0}))
+		})
+
+		It("should read parquet files", func() {
+			uri = "data.parquet"
+			f, err := os.Create(filepath.Join(dir, uri))
+			Expect(err).To(BeNil())
+			err = parquet.Write(f, []record{
+				{X: 10, Y: 10, Term: "a"},
+				{X: 300, Y: 30, Term: "c"},
+			})
+			Expect(err).To(BeNil())
+			f.Close()
+			res := create(filescan.NewHeatmapTile(cfg), `{`+bivariate+`, "resolution": 2}`,
+				&binning.TileCoord{X: 0, Y: 0, Z: 1})
+			Expect(getBins(res)).To(Equal([]uint32{1, 0, 0, 0}))
+		})
+
+		It("should return an error for unsupported files", func() {
+			uri = "data.txt"
+			ioutil.WriteFile(filepath.Join(dir, uri), []byte(data), 0644)
+			t, _ := filescan.NewHeatmapTile(cfg)()
+			t.Parse(JSON(`{` + bivariate + `}`))
+			_, err := t.Create(uri, &binning.TileCoord{}, nil)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Config", func() {
+		It("should resolve uris within the data root", func() {
+			path, err := cfg.Resolve("/data.csv")
+			Expect(err).To(BeNil())
+			expected, err := filepath.EvalSymlinks(filepath.Join(dir, "data.csv"))
+			Expect(err).To(BeNil())
+			Expect(path).To(Equal(expected))
+		})
+
+		It("should reject uris outside of the data root", func() {
+			root := filepath.Join(dir, "root")
+			err := os.Mkdir(root, 0755)
+			Expect(err).To(BeNil())
+			cfg.Root = root
+			_, err = cfg.Resolve("../data.csv")
+			Expect(err).To(MatchError(ContainSubstring("outside of the data root")))
+			t, _ := filescan.NewHeatmapTile(cfg)()
+			t.Parse(JSON(`{` + bivariate + `}`))
+			_, err = t.Create("../data.csv", &binning.TileCoord{}, nil)
+			Expect(err).NotTo(BeNil())
+			// no index is persisted next to the data
+			matches, err := filepath.Glob(filepath.Join(dir, "*.idx"))
+			Expect(err).To(BeNil())
+			Expect(matches).To(BeEmpty())
+		})
+
+		It("should reject symbolic links outside of the data root", func() {
+			root := filepath.Join(dir, "root")
+			err := os.Mkdir(root, 0755)
+			Expect(err).To(BeNil())
+			err = os.Symlink(filepath.Join(dir, "data.csv"), filepath.Join(root, "link.csv"))
+			Expect(err).To(BeNil())
+			cfg.Root = root
+			_, err = cfg.Resolve("link.csv")
+			Expect(err).To(MatchError(ContainSubstring("outside of the data root")))
+		})
+
+		It("should return an error if no data root is configured", func() {
+			_, err := (&filescan.Config{}).Resolve("data.csv")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("FrequencyTile", func() {
+		It("should return the counts of each time bucket within the tile", func() {
+			res := create(filescan.NewFrequencyTile(cfg), `{`+bivariate+`, "frequencyField": "timestamp", "gte": 0, "lt": 4000, "interval": "2s"}`,
+				&binning.TileCoord{X: 0, Y: 0, Z: 1})
+			Expect(JSON(`{"buckets":` + string(res) + `}`)).To(Equal(JSON(
+				`{
+					"buckets": [
+						{ "timestamp": 0, "count": 2 },
+						{ "timestamp": 2000, "count": 1 }
+					]
+				}`)))
+		})
+	})
+
+	Describe("MicroTile", func() {
+		It("should return the sorted top hits within the tile", func() {
+			res := create(filescan.NewMicroTile(cfg), `{`+bivariate+`, "hitsCount": 2, "sortField": "x", "sortOrder": "desc", "includeFields": ["term"]}`,
+				&binning.TileCoord{X: 0, Y: 0, Z: 1})
+			Expect(JSON(string(res))).To(Equal(JSON(
+				`{
+					"points": [ 30, 200, 20, 20 ],
+					"hits": [ { "term": "b" }, { "term": "a" } ]
+				}`)))
+		})
+	})
+})
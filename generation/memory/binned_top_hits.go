@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"math"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// BinnedTopHits represents an in-memory implementation of the binned top hits
+// tile.
+type BinnedTopHits struct {
+	Tile
+	Bivariate
+	TopHits
+}
+
+// NewBinnedTopHits instantiates and returns a new tile struct.
+func NewBinnedTopHits() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &BinnedTopHits{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (b *BinnedTopHits) Parse(params map[string]interface{}) error {
+	err := b.TopHits.Parse(params)
+	if err != nil {
+		return err
+	}
+	return b.Bivariate.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (b *BinnedTopHits) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// get the rows matching the query
+	table, rows, err := b.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// get bins
+	buckets := b.Bivariate.GetBins(coord, table, rows)
+
+	// convert hit bins
+	bins := make([][]map[string]interface{}, len(buckets))
+	for i, bucket := range buckets {
+		if bucket != nil {
+			bins[i] = b.TopHits.GetTopHits(table, bucket)
+		}
+	}
+
+	// bin width
+	binSize := binning.MaxTileResolution / float64(b.Resolution)
+	halfSize := float64(binSize / 2)
+
+	// convert to point array
+	points := make([]float32, len(bins)*2)
+	numPoints := 0
+	for i, bin := range bins {
+		if bin != nil {
+			x := float32(float64(i%b.Resolution)*binSize + halfSize)
+			y := float32(math.Floor(float64(i/b.Resolution))*binSize + halfSize)
+			points[numPoints*2] = x
+			points[numPoints*2+1] = y
+			numPoints++
+		}
+	}
+
+	//encode
+	return json.Marshal(map[string]interface{}{
+		"points": points[0 : numPoints*2],
+		"hits":   bins,
+	})
+}
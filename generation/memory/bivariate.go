@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Bivariate represents an in-memory implementation of the bivariate tile.
+type Bivariate struct {
+	tile.Bivariate
+}
+
+// GetRows returns the provided rows which fall within the tile.
+func (b *Bivariate) GetRows(coord *binning.TileCoord, table *Table, rows []int) []int {
+	bounds := b.TileBounds(coord)
+	minX := bounds.MinX()
+	maxX := bounds.MaxX()
+	minY := bounds.MinY()
+	maxY := bounds.MaxY()
+	return table.Filter(rows, func(row int) bool {
+		x, ok := table.Float(b.XField, row)
+		if !ok || x < minX || x >= maxX {
+			return false
+		}
+		y, ok := table.Float(b.YField, row)
+		if !ok || y < minY || y >= maxY {
+			return false
+		}
+		return true
+	})
+}
+
+// GetBins bins the provided rows, returning the rows within each bin. Empty
+// bins are nil.
+func (b *Bivariate) GetBins(coord *binning.TileCoord, table *Table, rows []int) [][]int {
+	bins := make([][]int, b.Resolution*b.Resolution)
+	for _, row := range b.GetRows(coord, table, rows) {
+		x, _ := table.Float(b.XField, row)
+		y, _ := table.Float(b.YField, row)
+		index := b.GetXBin(coord, x) + b.Resolution*b.GetYBin(coord, y)
+		bins[index] = append(bins[index], row)
+	}
+	return bins
+}
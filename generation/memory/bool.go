@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+// BinaryExpression represents an and/or boolean query.
+type BinaryExpression struct {
+	veldt.BinaryExpression
+}
+
+// NewBinaryExpression instantiates and returns a new binary expression.
+func NewBinaryExpression() (veldt.Query, error) {
+	return &BinaryExpression{}, nil
+}
+
+// Get returns the predicate for the query.
+func (e *BinaryExpression) Get(table *Table) (Predicate, error) {
+	left, ok := e.Left.(Query)
+	if !ok {
+		return nil, fmt.Errorf("`Left` is not of type memory.Query")
+	}
+	right, ok := e.Right.(Query)
+	if !ok {
+		return nil, fmt.Errorf("`Right` is not of type memory.Query")
+	}
+	a, err := left.Get(table)
+	if err != nil {
+		return nil, err
+	}
+	b, err := right.Get(table)
+	if err != nil {
+		return nil, err
+	}
+	switch e.Op {
+	case veldt.And:
+		// AND
+		return func(row int) bool {
+			return a(row) && b(row)
+		}, nil
+	case veldt.Or:
+		// OR
+		return func(row int) bool {
+			return a(row) || b(row)
+		}, nil
+	}
+	return nil, fmt.Errorf("`%v` operator is not a valid binary operator", e.Op)
+}
+
+// NaryExpression represents an and/or boolean query over any number of
+// queries.
+type NaryExpression struct {
+	veldt.NaryExpression
+}
+
+// NewNaryExpression instantiates and returns a new n-ary expression.
+func NewNaryExpression() (veldt.Query, error) {
+	return &NaryExpression{}, nil
+}
+
+// Get returns the predicate for the query.
+func (e *NaryExpression) Get(table *Table) (Predicate, error) {
+	predicates := make([]Predicate, len(e.Queries))
+	for i, q := range e.Queries {
+		query, ok := q.(Query)
+		if !ok {
+			return nil, fmt.Errorf("`Queries` element is not of type memory.Query")
+		}
+		a, err := query.Get(table)
+		if err != nil {
+			return nil, err
+		}
+		predicates[i] = a
+	}
+	switch e.Op {
+	case veldt.And:
+		// AND
+		return func(row int) bool {
+			for _, predicate := range predicates {
+				if !predicate(row) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	case veldt.Or:
+		// OR
+		return func(row int) bool {
+			for _, predicate := range predicates {
+				if predicate(row) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	return nil, fmt.Errorf("`%v` operator is not a valid n-ary operator", e.Op)
+}
+
+// UnaryExpression represents a must_not boolean query.
+type UnaryExpression struct {
+	veldt.UnaryExpression
+}
+
+// NewUnaryExpression instantiates and returns a new unary expression.
+func NewUnaryExpression() (veldt.Query, error) {
+	return &UnaryExpression{}, nil
+}
+
+// Get returns the predicate for the query.
+func (e *UnaryExpression) Get(table *Table) (Predicate, error) {
+	query, ok := e.Query.(Query)
+	if !ok {
+		return nil, fmt.Errorf("`Query` is not of type memory.Query")
+	}
+	a, err := query.Get(table)
+	if err != nil {
+		return nil, err
+	}
+	switch e.Op {
+	case veldt.Not:
+		// NOT
+		return func(row int) bool {
+			return !a(row)
+		}, nil
+	}
+	return nil, fmt.Errorf("`%v` operator is not a valid unary operator", e.Op)
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+// CountTile represents an in-memory implementation of the count tile.
+type CountTile struct {
+	Tile
+	Bivariate
+}
+
+// NewCountTile instantiates and returns a new tile struct.
+func NewCountTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &CountTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *CountTile) Parse(params map[string]interface{}) error {
+	return t.Bivariate.Parse(params)
+}
+
+// Empty returns the tile containing no data.
+func (t *CountTile) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return []byte(`{"count":0}`), nil
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *CountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// get the rows matching the query
+	table, rows, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the rows within the tile
+	rows = t.Bivariate.GetRows(coord, table, rows)
+
+	return []byte(fmt.Sprintf(`{"count":%d}`, len(rows))), nil
+}
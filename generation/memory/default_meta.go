@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"math"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// PropertyMeta represents the meta data for a single property.
+type PropertyMeta struct {
+	Type    string           `json:"type"`
+	Extrema *binning.Extrema `json:"extrema,omitempty"`
+}
+
+// DefaultMeta represents a meta data generator that produces default
+// metadata with property types and extrema.
+type DefaultMeta struct {
+	Tile
+}
+
+// NewDefaultMeta instantiates and returns a pointer to a new generator.
+func NewDefaultMeta() veldt.MetaCtor {
+	return func() (veldt.Meta, error) {
+		return &DefaultMeta{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (m *DefaultMeta) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+// Create generates metadata from the provided URI.
+func (m *DefaultMeta) Create(uri string) ([]byte, error) {
+	return m.CreateScoped(uri, nil, nil, nil)
+}
+
+// CreateScoped generates metadata from the provided URI, restricting the
+// extrema to the rows matching the query and within the tile of the scope.
+func (m *DefaultMeta) CreateScoped(uri string, scope *tile.Bivariate, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// get the rows matching the query
+	table, rows, err := m.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+	// get the rows within the tile
+	if scope != nil {
+		b := &Bivariate{Bivariate: *scope}
+		rows = b.GetRows(coord, table, rows)
+	}
+	meta := make(map[string]PropertyMeta)
+	for field, column := range table.Columns {
+		meta[field] = PropertyMeta{
+			Type:    column.Type,
+			Extrema: getExtrema(column, rows),
+		}
+	}
+	return json.Marshal(meta)
+}
+
+// getExtrema returns the extrema of a numeric or date column over the provided
+// rows. Dates are represented as milliseconds since the epoch.
+func getExtrema(column *Column, rows []int) *binning.Extrema {
+	if column.Type != NumberType && column.Type != DateType {
+		return nil
+	}
+	min := math.MaxFloat64
+	max := -math.MaxFloat64
+	found := false
+	for _, row := range rows {
+		val := column.Values[row]
+		if val == nil {
+			continue
+		}
+		var num float64
+		if column.Type == DateType {
+			t, err := castTime(val)
+			if err != nil {
+				continue
+			}
+			num = toMillis(t)
+		} else {
+			num = val.(float64)
+		}
+		min = math.Min(min, num)
+		max = math.Max(max, num)
+		found = true
+	}
+	// no rows have the attribute
+	if !found {
+		return nil
+	}
+	return &binning.Extrema{
+		Min: min,
+		Max: max,
+	}
+}
@@ -0,0 +1,69 @@
+package memory_test
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("DefaultMeta", func() {
+
+	const (
+		uri = "memory_meta_test"
+	)
+
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		memory.Register(uri, memory.NewTable([]map[string]interface{}{
+			{"x": 10, "y": 10, "value": 1},
+			{"x": 20, "y": 20, "value": 2},
+			{"x": 30, "y": 200, "value": 3},
+			{"x": 300, "y": 30, "value": 4},
+		}))
+		pipeline = veldt.NewPipeline()
+		pipeline.Query("range", memory.NewRange)
+		pipeline.Meta("default", memory.NewDefaultMeta())
+	})
+
+	AfterEach(func() {
+		memory.Unregister(uri)
+	})
+
+	create := func(args string) interface{} {
+		req, err := pipeline.NewMetaRequest(JSON(args))
+		Expect(err).To(BeNil())
+		res, err := req.Create()
+		Expect(err).To(BeNil())
+		return JSON(string(res))["value"]
+	}
+
+	It("should describe the entire table by default", func() {
+		Expect(create(`{"uri": "memory_meta_test", "meta": {"default": {}}}`)).To(Equal(JSON(
+			`{"type": "number", "extrema": {"min": 1, "max": 4}}`)))
+	})
+
+	It("should restrict the extrema to the rows matching the query", func() {
+		Expect(create(`{
+			"uri": "memory_meta_test",
+			"meta": {"default": {}},
+			"query": {"range": {"field": "x", "gte": 20}}
+		}`)).To(Equal(JSON(
+			`{"type": "number", "extrema": {"min": 2, "max": 4}}`)))
+	})
+
+	It("should restrict the extrema to the rows within the tile of the scope", func() {
+		Expect(create(`{
+			"uri": "memory_meta_test",
+			"meta": {"default": {}},
+			"scope": {"xField": "x", "yField": "y", "left": 0, "right": 512, "bottom": 0, "top": 512},
+			"coord": {"x": 0, "y": 0, "z": 1},
+			"query": {"range": {"field": "x", "gte": 20}}
+		}`)).To(Equal(JSON(
+			`{"type": "number", "extrema": {"min": 2, "max": 3}}`)))
+	})
+
+})
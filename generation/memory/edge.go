@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Edge represents an in-memory implementation of the edge tile.
+type Edge struct {
+	tile.Edge
+}
+
+// GetRows returns the provided rows which have their required points within
+// the tile.
+func (e *Edge) GetRows(coord *binning.TileCoord, table *Table, rows []int) []int {
+	bounds := e.TileBounds(coord)
+	inside := func(row int, xField string, yField string) bool {
+		x, ok := table.Float(xField, row)
+		if !ok || x < bounds.MinX() || x >= bounds.MaxX() {
+			return false
+		}
+		y, ok := table.Float(yField, row)
+		if !ok || y < bounds.MinY() || y >= bounds.MaxY() {
+			return false
+		}
+		return true
+	}
+	return table.Filter(rows, func(row int) bool {
+		// Require at least 1 of the points, possibly both.
+		if (e.RequireSrc || !e.RequireDst) && !inside(row, e.SrcXField, e.SrcYField) {
+			return false
+		}
+		if e.RequireDst && !inside(row, e.DstXField, e.DstYField) {
+			return false
+		}
+		return true
+	})
+}
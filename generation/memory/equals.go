@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Equals represents an in-memory equality query. Array values match if any
+// element equals the value.
+type Equals struct {
+	query.Equals
+}
+
+// NewEquals instantiates and returns a new query struct.
+func NewEquals() (veldt.Query, error) {
+	return &Equals{}, nil
+}
+
+// Get returns the predicate for the query.
+func (q *Equals) Get(table *Table) (Predicate, error) {
+	return func(row int) bool {
+		val, ok := table.Value(q.Field, row)
+		if !ok {
+			return false
+		}
+		return anyValue(val, func(v interface{}) bool {
+			return equals(v, q.Value)
+		})
+	}, nil
+}
@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Exists checks for the existence of the field.
+type Exists struct {
+	query.Exists
+}
+
+// NewExists instantiates and returns a new query struct.
+func NewExists() (veldt.Query, error) {
+	return &Exists{}, nil
+}
+
+// Get returns the predicate for the query.
+func (q *Exists) Get(table *Table) (Predicate, error) {
+	return func(row int) bool {
+		_, ok := table.Value(q.Field, row)
+		return ok
+	}, nil
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Frequency represents an in-memory implementation of the frequency tile.
+type Frequency struct {
+	tile.Frequency
+}
+
+// FrequencyBucket represents the rows within a single time bucket.
+type FrequencyBucket struct {
+	Timestamp int64
+	Rows      []int
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (f *Frequency) Parse(params map[string]interface{}) error {
+	err := f.Frequency.Parse(params)
+	if err != nil {
+		return err
+	}
+	_, err = tile.ParseInterval(f.Interval)
+	if err != nil {
+		return err
+	}
+	// validate the bounds
+	for _, bound := range []interface{}{f.GTE, f.GT, f.LTE, f.LT} {
+		if bound != nil {
+			_, err := castTime(bound)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetBuckets returns the time buckets of the provided rows. Empty buckets
+// within the range are included.
+func (f *Frequency) GetBuckets(table *Table, rows []int) ([]*FrequencyBucket, error) {
+	interval, err := tile.ParseInterval(f.Interval)
+	if err != nil {
+		return nil, err
+	}
+	anchor, err := f.Anchor()
+	if err != nil {
+		return nil, err
+	}
+	loc := f.Location()
+	// bucket the rows
+	buckets := make(map[int64]*FrequencyBucket)
+	starts := make([]int64, 0)
+	for _, row := range rows {
+		val, ok := table.Value(f.FrequencyField, row)
+		if !ok || !f.inRange(val) {
+			continue
+		}
+		t, err := castTime(val)
+		if err != nil {
+			continue
+		}
+		key := tile.ToMillis(interval.Truncate(t, anchor, loc))
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &FrequencyBucket{
+				Timestamp: key,
+			}
+			buckets[key] = bucket
+			starts = append(starts, key)
+		}
+		bucket.Rows = append(bucket.Rows, row)
+	}
+	// fill the buckets, including empty ones
+	timestamps, err := f.GetBucketTimestamps(interval, starts)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*FrequencyBucket, len(timestamps))
+	for i, timestamp := range timestamps {
+		bucket, ok := buckets[timestamp]
+		if !ok {
+			bucket = &FrequencyBucket{
+				Timestamp: timestamp,
+			}
+		}
+		res[i] = bucket
+	}
+	return res, nil
+}
+
+func (f *Frequency) inRange(val interface{}) bool {
+	r := &Range{}
+	r.GTE = f.GTE
+	r.GT = f.GT
+	r.LTE = f.LTE
+	r.LT = f.LT
+	return r.inRange(val)
+}
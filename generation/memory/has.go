@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Has represents an in-memory query checking if the field, or any element of
+// an array field, has one or more of the provided values.
+type Has struct {
+	query.Has
+}
+
+// NewHas instantiates and returns a new query struct.
+func NewHas() (veldt.Query, error) {
+	return &Has{}, nil
+}
+
+// Get returns the predicate for the query.
+func (q *Has) Get(table *Table) (Predicate, error) {
+	return func(row int) bool {
+		val, ok := table.Value(q.Field, row)
+		if !ok {
+			return false
+		}
+		return anyValue(val, func(v interface{}) bool {
+			for _, value := range q.Values {
+				if equals(v, value) {
+					return true
+				}
+			}
+			return false
+		})
+	}, nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// MatchesString represents an in-memory string query. The match string is
+// tested as a case-insensitive substring, unless the query is flagged as a
+// regular expression, in which case it is tested as a case-insensitive
+// regular expression. The query matches if any of the fields match.
+type MatchesString struct {
+	query.MatchesString
+}
+
+// NewMatchesString instantiates and returns a new query struct.
+func NewMatchesString() (veldt.Query, error) {
+	return &MatchesString{}, nil
+}
+
+// Get returns the predicate for the query.
+func (q *MatchesString) Get(table *Table) (Predicate, error) {
+	pattern := regexp.QuoteMeta(q.Match)
+	if q.Regex {
+		_, err := regexp.Compile(q.Match)
+		if err != nil {
+			return nil, fmt.Errorf("`match` is not a valid regular expression: %v", err)
+		}
+		pattern = q.Match
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, err
+	}
+	test := func(v interface{}) bool {
+		str, ok := v.(string)
+		return ok && re.MatchString(str)
+	}
+	return func(row int) bool {
+		for _, field := range q.Fields {
+			val, ok := table.Value(field, row)
+			if ok && anyValue(val, test) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Predicate represents a function that returns true if the provided row of a
+// table matches.
+type Predicate func(row int) bool
+
+// Query represents an in-memory implementation of the veldt.Query interface.
+type Query interface {
+	Get(*Table) (Predicate, error)
+}
+
+// anyValue returns true if the provided value, or any element of the value if
+// it is an array, satisfies the provided test.
+func anyValue(val interface{}, test func(interface{}) bool) bool {
+	arr, ok := val.([]interface{})
+	if !ok {
+		return test(val)
+	}
+	for _, v := range arr {
+		if test(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func equals(a interface{}, b interface{}) bool {
+	cmp, ok := compare(a, b)
+	if ok {
+		return cmp == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare returns the ordering of two values. Numbers are compared
+// numerically, strings lexicographically, and a number is compared to a date
+// string as milliseconds since the epoch.
+func compare(a interface{}, b interface{}) (int, bool) {
+	a = normalizeValue(a)
+	b = normalizeValue(b)
+	aNum, aIsNum := a.(float64)
+	bNum, bIsNum := b.(float64)
+	aStr, aIsStr := a.(string)
+	bStr, bIsStr := b.(string)
+	if aIsStr && bIsNum {
+		t, err := tile.ParseTime(aStr)
+		if err != nil {
+			return 0, false
+		}
+		aNum, aIsNum = toMillis(t), true
+	}
+	if aIsNum && bIsStr {
+		t, err := tile.ParseTime(bStr)
+		if err != nil {
+			return 0, false
+		}
+		bNum, bIsNum = toMillis(t), true
+	}
+	if aIsNum && bIsNum {
+		if aNum < bNum {
+			return -1, true
+		}
+		if aNum > bNum {
+			return 1, true
+		}
+		return 0, true
+	}
+	if aIsStr && bIsStr {
+		return strings.Compare(aStr, bStr), true
+	}
+	return 0, false
+}
+
+// castTime converts a value into a time. Numeric values are interpreted as
+// milliseconds since the epoch.
+func castTime(val interface{}) (time.Time, error) {
+	return tile.CastTime(normalizeValue(val))
+}
+
+func toMillis(t time.Time) float64 {
+	return float64(tile.ToMillis(t))
+}
@@ -0,0 +1,94 @@
+package memory_test
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Query", func() {
+
+	var table *memory.Table
+
+	BeforeEach(func() {
+		table = memory.NewTable([]map[string]interface{}{
+			{"name": "john", "age": 32, "tags": []string{"a", "b"}, "joined": "2017-01-01"},
+			{"name": "jane", "age": 18, "tags": []string{"c"}},
+			{"name": "jim", "tags": []string{"b"}, "joined": "2017-06-01"},
+		})
+	})
+
+	matches := func(query veldt.Query, params string) []int {
+		err := query.Parse(JSON(params))
+		Expect(err).To(BeNil())
+		predicate, err := query.(memory.Query).Get(table)
+		Expect(err).To(BeNil())
+		return table.Filter(table.Rows(), predicate)
+	}
+
+	It("should match rows with `equals`", func() {
+		q, _ := memory.NewEquals()
+		Expect(matches(q, `{"field": "name", "value": "jane"}`)).To(Equal([]int{1}))
+		q, _ = memory.NewEquals()
+		Expect(matches(q, `{"field": "age", "value": 32}`)).To(Equal([]int{0}))
+	})
+
+	It("should match array elements with `has`", func() {
+		q, _ := memory.NewHas()
+		Expect(matches(q, `{"field": "tags", "values": ["b", "z"]}`)).To(Equal([]int{0, 2}))
+	})
+
+	It("should match rows with `range`", func() {
+		q, _ := memory.NewRange()
+		Expect(matches(q, `{"field": "age", "gte": 18, "lt": 32}`)).To(Equal([]int{1}))
+	})
+
+	It("should compare dates to milliseconds with `range`", func() {
+		q, _ := memory.NewRange()
+		Expect(matches(q, `{"field": "joined", "gt": 1483228800000}`)).To(Equal([]int{2}))
+	})
+
+	It("should match rows with `exists`", func() {
+		q, _ := memory.NewExists()
+		Expect(matches(q, `{"field": "age"}`)).To(Equal([]int{0, 1}))
+	})
+
+	It("should match rows with `matches_string`", func() {
+		q, _ := memory.NewMatchesString()
+		Expect(matches(q, `{"match": "^j.m$", "fields": ["name"], "regex": true}`)).To(Equal([]int{2}))
+	})
+
+	It("should match `matches_string` regular expressions case-insensitively", func() {
+		q, _ := memory.NewMatchesString()
+		Expect(matches(q, `{"match": "^J.N", "fields": ["name"], "regex": true}`)).To(Equal([]int{1}))
+	})
+
+	It("should match plain `matches_string` queries as case-insensitive substrings", func() {
+		q, _ := memory.NewMatchesString()
+		Expect(matches(q, `{"match": "J.M", "fields": ["name"]}`)).To(Equal([]int{}))
+		q, _ = memory.NewMatchesString()
+		Expect(matches(q, `{"match": "AN", "fields": ["name"]}`)).To(Equal([]int{1}))
+		q, _ = memory.NewMatchesString()
+		Expect(matches(q, `{"match": "^j", "fields": ["name"], "regex": false}`)).To(Equal([]int{}))
+	})
+
+	It("should combine predicates with boolean expressions", func() {
+		left, _ := memory.NewExists()
+		left.Parse(JSON(`{"field": "age"}`))
+		right, _ := memory.NewEquals()
+		right.Parse(JSON(`{"field": "name", "value": "john"}`))
+		and := &memory.BinaryExpression{}
+		and.Left = left
+		and.Right = right
+		and.Op = veldt.And
+		not := &memory.UnaryExpression{}
+		not.Query = and
+		not.Op = veldt.Not
+		predicate, err := not.Get(table)
+		Expect(err).To(BeNil())
+		Expect(table.Filter(table.Rows(), predicate)).To(Equal([]int{1, 2}))
+	})
+})
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Range represents an in-memory range query.
+type Range struct {
+	query.Range
+}
+
+// NewRange instantiates and returns a new query struct.
+func NewRange() (veldt.Query, error) {
+	return &Range{}, nil
+}
+
+// Get returns the predicate for the query.
+func (q *Range) Get(table *Table) (Predicate, error) {
+	return func(row int) bool {
+		val, ok := table.Value(q.Field, row)
+		if !ok {
+			return false
+		}
+		return anyValue(val, q.inRange)
+	}, nil
+}
+
+func (q *Range) inRange(val interface{}) bool {
+	if q.GTE != nil {
+		cmp, ok := compare(val, q.GTE)
+		if !ok || cmp < 0 {
+			return false
+		}
+	}
+	if q.GT != nil {
+		cmp, ok := compare(val, q.GT)
+		if !ok || cmp <= 0 {
+			return false
+		}
+	}
+	if q.LTE != nil {
+		cmp, ok := compare(val, q.LTE)
+		if !ok || cmp > 0 {
+			return false
+		}
+	}
+	if q.LT != nil {
+		cmp, ok := compare(val, q.LT)
+		if !ok || cmp >= 0 {
+			return false
+		}
+	}
+	return true
+}
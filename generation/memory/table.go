@@ -0,0 +1,371 @@
+package memory
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// NumberType represents a column of numeric values.
+	NumberType = "number"
+	// StringType represents a column of string values.
+	StringType = "string"
+	// DateType represents a column of string values that are all parsable
+	// as dates.
+	DateType = "date"
+	// BooleanType represents a column of boolean values.
+	BooleanType = "boolean"
+	// ArrayType represents a column of array values.
+	ArrayType = "array"
+	// MixedType represents a column of values of differing types.
+	MixedType = "mixed"
+
+	maxLineSize = 1024 * 1024 * 16
+)
+
+var (
+	mutex  = sync.RWMutex{}
+	tables = make(map[string]*Table)
+)
+
+// Column represents a single column of a table. Missing values are stored as
+// nil.
+type Column struct {
+	Type   string
+	Values []interface{}
+}
+
+// Table represents an in-memory columnar table. Nested record attributes are
+// flattened into columns using period delimited paths.
+type Table struct {
+	Columns map[string]*Column
+	NumRows int
+}
+
+// NewTable instantiates and returns a new table from the provided records.
+func NewTable(records []map[string]interface{}) *Table {
+	table := &Table{
+		Columns: make(map[string]*Column),
+		NumRows: len(records),
+	}
+	for i, record := range records {
+		table.addRecord(i, record, "")
+	}
+	for _, column := range table.Columns {
+		column.Type = getColumnType(column.Values)
+	}
+	return table
+}
+
+// NewTableFromCSV instantiates and returns a new table from the provided CSV
+// data.
+func NewTableFromCSV(reader io.Reader) (*Table, error) {
+	records, err := ReadCSV(reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewTable(records), nil
+}
+
+// NewTableFromJSON instantiates and returns a new table from the provided
+// JSON lines data.
+func NewTableFromJSON(reader io.Reader) (*Table, error) {
+	records, err := ReadJSON(reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewTable(records), nil
+}
+
+// ReadCSV reads the records of the provided CSV data. The first row is
+// expected to contain the column names. Numeric and boolean values are
+// parsed, empty values are treated as missing.
+func ReadCSV(reader io.Reader) ([]map[string]interface{}, error) {
+	rows, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("csv data does not contain a header row")
+	}
+	header := rows[0]
+	records := make([]map[string]interface{}, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := make(map[string]interface{}, len(header))
+		for j, name := range header {
+			if j < len(row) {
+				record[name] = parseCSVValue(row[j])
+			}
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// ReadJSON reads the records of the provided JSON lines data, where each line
+// is a single JSON object.
+func ReadJSON(reader io.Reader) ([]map[string]interface{}, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	records := make([]map[string]interface{}, 0)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		record, err := json.Unmarshal([]byte(text))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse json on line %d: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Register registers the table under the provided uri.
+func Register(uri string, table *Table) {
+	mutex.Lock()
+	tables[uri] = table
+	mutex.Unlock()
+}
+
+// Unregister removes the table registered under the provided uri.
+func Unregister(uri string) {
+	mutex.Lock()
+	delete(tables, uri)
+	mutex.Unlock()
+}
+
+// GetTable returns the table registered under the provided uri.
+func GetTable(uri string) (*Table, error) {
+	mutex.RLock()
+	table, ok := tables[uri]
+	mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no table registered for uri `%s`", uri)
+	}
+	return table, nil
+}
+
+// Value returns the value of the field for the provided row.
+func (t *Table) Value(field string, row int) (interface{}, bool) {
+	column, ok := t.Columns[field]
+	if !ok {
+		return nil, false
+	}
+	val := column.Values[row]
+	return val, val != nil
+}
+
+// Float returns the numeric value of the field for the provided row.
+func (t *Table) Float(field string, row int) (float64, bool) {
+	val, ok := t.Value(field, row)
+	if !ok {
+		return 0, false
+	}
+	num, ok := val.(float64)
+	return num, ok
+}
+
+// Rows returns the indices of all rows in the table.
+func (t *Table) Rows() []int {
+	rows := make([]int, t.NumRows)
+	for i := range rows {
+		rows[i] = i
+	}
+	return rows
+}
+
+// Filter returns the indices of the provided rows that match the predicate.
+func (t *Table) Filter(rows []int, predicate Predicate) []int {
+	filtered := make([]int, 0, len(rows))
+	for _, row := range rows {
+		if predicate(row) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+// Record returns the provided row as a nested record containing only the
+// included fields. If no fields are included, all fields are returned.
+func (t *Table) Record(row int, includes []string) map[string]interface{} {
+	fields := includes
+	if fields == nil {
+		fields = t.fields()
+	}
+	record := make(map[string]interface{})
+	for _, field := range fields {
+		val, ok := t.Value(field, row)
+		if !ok {
+			continue
+		}
+		path := strings.Split(field, ".")
+		node := record
+		for _, key := range path[:len(path)-1] {
+			child, ok := node[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[key] = child
+			}
+			node = child
+		}
+		node[path[len(path)-1]] = val
+	}
+	return record
+}
+
+func (t *Table) fields() []string {
+	fields := make([]string, 0, len(t.Columns))
+	for field := range t.Columns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func (t *Table) addRecord(row int, record map[string]interface{}, path string) {
+	for key, val := range record {
+		field := key
+		if path != "" {
+			field = path + "." + key
+		}
+		child, ok := val.(map[string]interface{})
+		if ok {
+			// flatten nested attributes
+			t.addRecord(row, child, field)
+			continue
+		}
+		column, ok := t.Columns[field]
+		if !ok {
+			column = &Column{
+				Values: make([]interface{}, t.NumRows),
+			}
+			t.Columns[field] = column
+		}
+		column.Values[row] = normalizeValue(val)
+	}
+}
+
+func normalizeValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case int:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint8:
+		return float64(v)
+	case uint16:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case []string:
+		arr := make([]interface{}, len(v))
+		for i, s := range v {
+			arr[i] = s
+		}
+		return arr
+	case []float64:
+		arr := make([]interface{}, len(v))
+		for i, f := range v {
+			arr[i] = f
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, e := range v {
+			arr[i] = normalizeValue(e)
+		}
+		return arr
+	}
+	return val
+}
+
+func parseCSVValue(str string) interface{} {
+	if str == "" {
+		return nil
+	}
+	num, err := strconv.ParseFloat(str, 64)
+	if err == nil {
+		return num
+	}
+	b, err := strconv.ParseBool(str)
+	if err == nil {
+		return b
+	}
+	return str
+}
+
+func getValueType(val interface{}) string {
+	switch v := val.(type) {
+	case float64:
+		return NumberType
+	case bool:
+		return BooleanType
+	case []interface{}:
+		return ArrayType
+	case string:
+		if _, err := tile.ParseTime(v); err == nil {
+			return DateType
+		}
+		return StringType
+	}
+	return MixedType
+}
+
+func getColumnType(values []interface{}) string {
+	typ := ""
+	for _, val := range values {
+		if val == nil {
+			continue
+		}
+		valType := getValueType(val)
+		if typ == "" {
+			typ = valType
+			continue
+		}
+		if typ == valType {
+			continue
+		}
+		if (typ == DateType && valType == StringType) ||
+			(typ == StringType && valType == DateType) {
+			// not every string is a date
+			typ = StringType
+			continue
+		}
+		return MixedType
+	}
+	if typ == "" {
+		return MixedType
+	}
+	return typ
+}
@@ -0,0 +1,106 @@
+package memory_test
+
+import (
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/generation/memory"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+
+	Describe("NewTable", func() {
+		It("should flatten nested records into columns", func() {
+			table := memory.NewTable([]map[string]interface{}{
+				{
+					"name": "a",
+					"pixel": map[string]interface{}{
+						"x": 1,
+						"y": 2,
+					},
+				},
+				{
+					"name": "b",
+				},
+			})
+			Expect(table.NumRows).To(Equal(2))
+			Expect(table.Columns["name"].Type).To(Equal(memory.StringType))
+			Expect(table.Columns["pixel.x"].Type).To(Equal(memory.NumberType))
+			x, ok := table.Float("pixel.x", 0)
+			Expect(ok).To(Equal(true))
+			Expect(x).To(Equal(1.0))
+			_, ok = table.Float("pixel.x", 1)
+			Expect(ok).To(Equal(false))
+		})
+
+		It("should detect date columns", func() {
+			table := memory.NewTable([]map[string]interface{}{
+				{"timestamp": "2017-01-01T00:00:00Z"},
+				{"timestamp": "2017-01-02"},
+			})
+			Expect(table.Columns["timestamp"].Type).To(Equal(memory.DateType))
+		})
+	})
+
+	Describe("NewTableFromCSV", func() {
+		It("should parse numeric, boolean and missing values", func() {
+			table, err := memory.NewTableFromCSV(strings.NewReader(
+				"name,age,active\n" +
+					"john,32,true\n" +
+					"jane,,false\n"))
+			Expect(err).To(BeNil())
+			Expect(table.NumRows).To(Equal(2))
+			Expect(table.Columns["age"].Type).To(Equal(memory.NumberType))
+			Expect(table.Columns["active"].Type).To(Equal(memory.BooleanType))
+			_, ok := table.Value("age", 1)
+			Expect(ok).To(Equal(false))
+		})
+	})
+
+	Describe("NewTableFromJSON", func() {
+		It("should parse one record per line", func() {
+			table, err := memory.NewTableFromJSON(strings.NewReader(
+				`{"name": "john", "tags": ["a", "b"]}` + "\n" +
+					"\n" +
+					`{"name": "jane", "tags": ["b"]}` + "\n"))
+			Expect(err).To(BeNil())
+			Expect(table.NumRows).To(Equal(2))
+			Expect(table.Columns["tags"].Type).To(Equal(memory.ArrayType))
+		})
+
+		It("should return an error for invalid lines", func() {
+			_, err := memory.NewTableFromJSON(strings.NewReader(`{"name": `))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Record", func() {
+		It("should return the included fields as a nested record", func() {
+			table := memory.NewTable([]map[string]interface{}{
+				{
+					"name": "a",
+					"pixel": map[string]interface{}{
+						"x": 1,
+						"y": 2,
+					},
+				},
+			})
+			record := table.Record(0, []string{"pixel.x", "name"})
+			Expect(record).To(Equal(map[string]interface{}{
+				"name": "a",
+				"pixel": map[string]interface{}{
+					"x": 1.0,
+				},
+			}))
+		})
+	})
+
+	Describe("GetTable", func() {
+		It("should return an error if no table is registered", func() {
+			_, err := memory.GetTable("missing")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+// Tile represents an in-memory tile type.
+type Tile struct {
+}
+
+// CreateQuery creates the predicate from the query struct.
+func (t *Tile) CreateQuery(table *Table, query veldt.Query) (Predicate, error) {
+	if query == nil {
+		return func(row int) bool {
+			return true
+		}, nil
+	}
+	// type assert
+	memoryQuery, ok := query.(Query)
+	if !ok {
+		return nil, fmt.Errorf("query is not memory.Query")
+	}
+	return memoryQuery.Get(table)
+}
+
+// InitializeTile retrieves the table for the provided uri and returns the
+// rows of it which match the query.
+func (t *Tile) InitializeTile(uri string, query veldt.Query) (*Table, []int, error) {
+	// get table
+	table, err := GetTable(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	// create predicate
+	predicate, err := t.CreateQuery(table, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return table, table.Filter(table.Rows(), predicate), nil
+}
@@ -0,0 +1,127 @@
+package memory_test
+
+import (
+	"encoding/binary"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Tile", func() {
+
+	const (
+		uri = "memory_test"
+	)
+
+	var coord *binning.TileCoord
+
+	BeforeEach(func() {
+		memory.Register(uri, memory.NewTable([]map[string]interface{}{
+			{"x": 10, "y": 10, "term": "a", "timestamp": 0},
+			{"x": 20, "y": 20, "term": "a", "timestamp": 1000},
+			{"x": 30, "y": 200, "term": "b", "timestamp": 2000},
+			{"x": 300, "y": 30, "term": "c", "timestamp": 3000},
+		}))
+		coord = &binning.TileCoord{X: 0, Y: 0, Z: 1}
+	})
+
+	AfterEach(func() {
+		memory.Unregister(uri)
+	})
+
+	create := func(ctor veldt.TileCtor, params string, query veldt.Query) []byte {
+		t, err := ctor()
+		Expect(err).To(BeNil())
+		err = t.Parse(JSON(params))
+		Expect(err).To(BeNil())
+		res, err := t.Create(uri, coord, query)
+		Expect(err).To(BeNil())
+		return res
+	}
+
+	bivariate := `
+		"xField": "x",
+		"yField": "y",
+		"left": 0,
+		"right": 512,
+		"bottom": 0,
+		"top": 512`
+
+	Describe("HeatmapTile", func() {
+		It("should bin the rows within the tile", func() {
+			res := create(memory.NewHeatmapTile(), `{`+bivariate+`, "resolution": 2}`, nil)
+			bins := make([]uint32, 4)
+			for i := range bins {
+				bins[i] = binary.LittleEndian.Uint32(res[i*4 : i*4+4])
+			}
+			Expect(bins).To(Equal([]uint32{2, 0, 1, 0}))
+		})
+	})
+
+	Describe("CountTile", func() {
+		It("should count the rows matching the query within the tile", func() {
+			query, _ := memory.NewEquals()
+			query.Parse(JSON(`{"field": "term", "value": "a"}`))
+			res := create(memory.NewCountTile(), `{`+bivariate+`}`, query)
+			Expect(string(res)).To(Equal(`{"count":2}`))
+		})
+	})
+
+	Describe("TopTermCountTile", func() {
+		It("should return the counts of the top terms within the tile", func() {
+			res := create(memory.NewTopTermCountTile(), `{`+bivariate+`, "termsField": "term", "termsCount": 1}`, nil)
+			Expect(string(res)).To(Equal(`{"a":2}`))
+		})
+	})
+
+	Describe("FrequencyTile", func() {
+		It("should return the counts of each time bucket including empty buckets", func() {
+			res := create(memory.NewFrequencyTile(), `{`+bivariate+`, "frequencyField": "timestamp", "gte": 0, "lt": 4000, "interval": "2s"}`, nil)
+			Expect(JSON(`{"buckets":` + string(res) + `}`)).To(Equal(JSON(
+				`{
+					"buckets": [
+						{ "timestamp": 0, "count": 2 },
+						{ "timestamp": 2000, "count": 1 }
+					]
+				}`)))
+		})
+
+		It("should return an error for unsupported intervals", func() {
+			t, _ := memory.NewFrequencyTile()()
+			err := t.Parse(JSON(`{` + bivariate + `, "frequencyField": "timestamp", "gte": 0, "interval": "fortnight"}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("MicroTile", func() {
+		It("should return the sorted top hits within the tile", func() {
+			res := create(memory.NewMicroTile(), `{`+bivariate+`, "hitsCount": 2, "sortField": "x", "sortOrder": "desc", "includeFields": ["term"]}`, nil)
+			Expect(JSON(string(res))).To(Equal(JSON(
+				`{
+					"points": [ 30, 200, 20, 20 ],
+					"hits": [ { "term": "b" }, { "term": "a" } ]
+				}`)))
+		})
+	})
+
+	Describe("DefaultMeta", func() {
+		It("should return the type and extrema of each column", func() {
+			m, err := memory.NewDefaultMeta()()
+			Expect(err).To(BeNil())
+			res, err := m.Create(uri)
+			Expect(err).To(BeNil())
+			Expect(JSON(string(res))).To(Equal(JSON(
+				`{
+					"x": { "type": "number", "extrema": { "min": 10, "max": 300 } },
+					"y": { "type": "number", "extrema": { "min": 10, "max": 200 } },
+					"term": { "type": "string" },
+					"timestamp": { "type": "number", "extrema": { "min": 0, "max": 3000 } }
+				}`)))
+		})
+	})
+})
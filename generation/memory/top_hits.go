@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TopHits represents an in-memory implementation of the top hits tile.
+type TopHits struct {
+	tile.TopHits
+}
+
+// GetTopHits returns the top hits of the provided rows.
+func (t *TopHits) GetTopHits(table *Table, rows []int) []map[string]interface{} {
+	sorted := make([]int, len(rows))
+	copy(sorted, rows)
+	// sort
+	if t.SortField != "" {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, aOk := table.Value(t.SortField, sorted[i])
+			b, bOk := table.Value(t.SortField, sorted[j])
+			if !aOk || !bOk {
+				// missing values are sorted last
+				return aOk && !bOk
+			}
+			cmp, _ := compare(a, b)
+			if t.SortOrder == "desc" {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+	}
+	// limit
+	if len(sorted) > t.HitsCount {
+		sorted = sorted[:t.HitsCount]
+	}
+	hits := make([]map[string]interface{}, len(sorted))
+	for i, row := range sorted {
+		hits[i] = table.Record(row, t.IncludeFields)
+	}
+	return hits
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TopTermCountTile represents an in-memory implementation of the top term
+// count tile.
+type TopTermCountTile struct {
+	Tile
+	Bivariate
+	TopTerms
+}
+
+// NewTopTermCountTile instantiates and returns a new tile struct.
+func NewTopTermCountTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &TopTermCountTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TopTermCountTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.TopTerms.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TopTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// get the rows matching the query
+	table, rows, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+	// get terms
+	terms := t.TopTerms.GetTerms(table, t.Bivariate.GetRows(coord, table, rows))
+	// encode
+	counts := make(map[string]uint32)
+	for term, termRows := range terms {
+		counts[term] = uint32(len(termRows))
+	}
+	// marshal results
+	return json.Marshal(counts)
+}
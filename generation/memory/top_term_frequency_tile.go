@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TopTermFrequencyTile represents an in-memory implementation of the top term
+// frequency tile.
+type TopTermFrequencyTile struct {
+	Tile
+	Bivariate
+	TopTerms
+	Frequency
+}
+
+// NewTopTermFrequencyTile instantiates and returns a new tile struct.
+func NewTopTermFrequencyTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &TopTermFrequencyTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TopTermFrequencyTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = t.TopTerms.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TopTermFrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// get the rows matching the query
+	table, rows, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+	// get terms
+	terms := t.TopTerms.GetTerms(table, t.Bivariate.GetRows(coord, table, rows))
+	// encode
+	result := make(map[string][]map[string]interface{})
+	for term, termRows := range terms {
+		// get buckets
+		buckets, err := t.Frequency.GetBuckets(table, termRows)
+		if err != nil {
+			return nil, err
+		}
+		// add frequency
+		frequency := make([]map[string]interface{}, len(buckets))
+		for i, bucket := range buckets {
+			frequency[i] = map[string]interface{}{
+				"timestamp": bucket.Timestamp,
+				"count":     len(bucket.Rows),
+			}
+		}
+		result[term] = frequency
+	}
+	// marshal results
+	return json.Marshal(result)
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TopTerms represents an in-memory implementation of the top terms tile.
+type TopTerms struct {
+	tile.TopTerms
+}
+
+// GetTerms returns the rows containing each of the top terms of the provided
+// rows. Each element of an array field is counted as a separate term.
+func (t *TopTerms) GetTerms(table *Table, rows []int) map[string][]int {
+	counts := make(map[string][]int)
+	for _, row := range rows {
+		val, ok := table.Value(t.TermsField, row)
+		if !ok {
+			continue
+		}
+		seen := make(map[string]bool)
+		anyValue(val, func(v interface{}) bool {
+			term, ok := getTerm(v)
+			if ok && !seen[term] {
+				seen[term] = true
+				counts[term] = append(counts[term], row)
+			}
+			return false
+		})
+	}
+	// sort by count, then by term
+	terms := make([]string, 0, len(counts))
+	for term := range counts {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		a := len(counts[terms[i]])
+		b := len(counts[terms[j]])
+		if a == b {
+			return terms[i] < terms[j]
+		}
+		return a > b
+	})
+	if len(terms) > t.TermsCount {
+		for _, term := range terms[t.TermsCount:] {
+			delete(counts, term)
+		}
+	}
+	return counts
+}
+
+func getTerm(val interface{}) (string, bool) {
+	switch v := val.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
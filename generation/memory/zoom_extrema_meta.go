@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/meta"
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// ZoomExtremaMeta represents an in-memory implementation of the per zoom
+// level heatmap extrema meta data.
+type ZoomExtremaMeta struct {
+	Tile
+	Bivariate
+	meta.ZoomExtrema
+}
+
+// NewZoomExtremaMeta instantiates and returns a pointer to a new generator.
+func NewZoomExtremaMeta() veldt.MetaCtor {
+	return func() (veldt.Meta, error) {
+		return &ZoomExtremaMeta{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (m *ZoomExtremaMeta) Parse(params map[string]interface{}) error {
+	err := m.ZoomExtrema.Parse(params)
+	if err != nil {
+		return err
+	}
+	return m.Bivariate.Parse(params)
+}
+
+// Create generates metadata from the provided URI.
+func (m *ZoomExtremaMeta) Create(uri string) ([]byte, error) {
+	return m.CreateScoped(uri, nil, nil, nil)
+}
+
+// CreateScoped generates metadata from the provided URI, restricted to the
+// rows matching the query and within the tile of the scope.
+func (m *ZoomExtremaMeta) CreateScoped(uri string, scope *tile.Bivariate, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// get the rows matching the query
+	table, rows, err := m.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+	// get the rows within the tile
+	if scope != nil {
+		b := &Bivariate{Bivariate: *scope}
+		rows = b.GetRows(coord, table, rows)
+	}
+	root := &binning.TileCoord{}
+	levels := make(map[string]*meta.LevelExtrema)
+	for _, zoom := range m.Zooms() {
+		level := &Bivariate{Bivariate: *m.LevelBivariate(zoom)}
+		// count the rows of each non-empty bin, such that the bins buffered
+		// are bounded by the rows rather than the resolution of the level
+		counts := make(map[[2]int]float64)
+		for _, row := range level.GetRows(root, table, rows) {
+			x, _ := table.Float(level.XField, row)
+			y, _ := table.Float(level.YField, row)
+			counts[[2]int{level.GetXBin(root, x), level.GetYBin(root, y)}]++
+		}
+		values := make([]float64, 0, len(counts))
+		for _, count := range counts {
+			values = append(values, count)
+		}
+		levels[meta.LevelKey(zoom)] = m.Compute(values)
+	}
+	return json.Marshal(levels)
+}
@@ -0,0 +1,108 @@
+package memory_test
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("ZoomExtremaMeta", func() {
+
+	const (
+		uri = "memory_zoom_test"
+	)
+
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		memory.Register(uri, memory.NewTable([]map[string]interface{}{
+			{"x": 10, "y": 10},
+			{"x": 20, "y": 20},
+			{"x": 30, "y": 200},
+			{"x": 300, "y": 30},
+		}))
+		pipeline = veldt.NewPipeline()
+		pipeline.Query("range", memory.NewRange)
+		pipeline.Meta("zoom", memory.NewZoomExtremaMeta())
+	})
+
+	AfterEach(func() {
+		memory.Unregister(uri)
+	})
+
+	create := func(args string) map[string]interface{} {
+		req, err := pipeline.NewMetaRequest(JSON(args))
+		Expect(err).To(BeNil())
+		res, err := req.Create()
+		Expect(err).To(BeNil())
+		return JSON(string(res))
+	}
+
+	It("should compute the bin extrema at each zoom level", func() {
+		Expect(create(`{
+			"uri": "memory_zoom_test",
+			"meta": {
+				"zoom": {
+					"xField": "x",
+					"yField": "y",
+					"left": 0,
+					"right": 512,
+					"bottom": 0,
+					"top": 512,
+					"resolution": 2,
+					"maxZoom": 1,
+					"percentiles": [50]
+				}
+			}
+		}`)).To(Equal(JSON(`{
+			"0": {"min": 1, "max": 3, "percentiles": {"50": 2}},
+			"1": {"min": 1, "max": 2, "percentiles": {"50": 1}}
+		}`)))
+	})
+
+	It("should only buffer the non-empty bins of the deepest zoom level", func() {
+		Expect(create(`{
+			"uri": "memory_zoom_test",
+			"meta": {
+				"zoom": {
+					"xField": "x",
+					"yField": "y",
+					"left": 0,
+					"right": 512,
+					"bottom": 0,
+					"top": 512,
+					"minZoom": 16,
+					"maxZoom": 16
+				}
+			}
+		}`)).To(Equal(JSON(`{
+			"16": {"min": 1, "max": 1}
+		}`)))
+	})
+
+	It("should restrict the bins to the rows matching the query", func() {
+		Expect(create(`{
+			"uri": "memory_zoom_test",
+			"meta": {
+				"zoom": {
+					"xField": "x",
+					"yField": "y",
+					"left": 0,
+					"right": 512,
+					"bottom": 0,
+					"top": 512,
+					"resolution": 2,
+					"minZoom": 1,
+					"maxZoom": 1
+				}
+			},
+			"query": {"range": {"field": "x", "gte": 100}}
+		}`)).To(Equal(JSON(`{
+			"1": {"min": 1, "max": 1}
+		}`)))
+	})
+
+})
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Geometry represents the parameters required for any tile over a PostGIS
+// geometry column. Tiles are in web mercator.
+type Geometry struct {
+	GeometryField string
+	SRID          int
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (g *Geometry) Parse(params map[string]interface{}) error {
+	geometryField, ok := json.GetString(params, "geometryField")
+	if !ok {
+		return fmt.Errorf("`geometryField` parameter missing from tile")
+	}
+	srid := json.GetIntDefault(params, defaultSRID, "srid")
+	if srid <= 0 {
+		return fmt.Errorf("`srid` must be positive")
+	}
+	g.GeometryField = geometryField
+	g.SRID = srid
+	return nil
+}
+
+// Envelope adds the web mercator envelope of the tile to the query and
+// returns the expression.
+func (g *Geometry) Envelope(coord *binning.TileCoord, query *citus.Query) string {
+	bounds := binning.GetTileMercatorBounds(coord)
+	return fmt.Sprintf("ST_MakeEnvelope(%s, %s, %s, %s, %d)",
+		query.AddParameter(bounds.MinX()),
+		query.AddParameter(bounds.MinY()),
+		query.AddParameter(bounds.MaxX()),
+		query.AddParameter(bounds.MaxY()),
+		mercatorSRID)
+}
+
+// Projected returns the expression of the geometry column in web mercator.
+func (g *Geometry) Projected(query *citus.Query) (string, error) {
+	field, err := query.Column(g.GeometryField)
+	if err != nil {
+		return "", err
+	}
+	if g.SRID == mercatorSRID {
+		return field, nil
+	}
+	return fmt.Sprintf("ST_Transform(%s, %d)", field, mercatorSRID), nil
+}
+
+// AddQuery adds the tiling query to the provided query object. The tile
+// envelope is transformed into the geometry column's reference system so that
+// the spatial index of the column may be used.
+func (g *Geometry) AddQuery(coord *binning.TileCoord, query *citus.Query) (*citus.Query, error) {
+	field, err := query.Column(g.GeometryField)
+	if err != nil {
+		return nil, err
+	}
+	envelope := g.Envelope(coord, query)
+	if g.SRID != mercatorSRID {
+		envelope = fmt.Sprintf("ST_Transform(%s, %d)", envelope, g.SRID)
+	}
+	query.Where(fmt.Sprintf("%s && %s", field, envelope))
+	return query, nil
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// HeatmapTile represents a PostGIS implementation of the heatmap tile. The
+// centroid of each geometry is snapped to the center of the bin containing
+// it.
+type HeatmapTile struct {
+	citus.Tile
+	Geometry
+	Resolution int
+}
+
+// NewHeatmapTile instantiates and returns a new tile struct.
+func NewHeatmapTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		h := &HeatmapTile{}
+		h.Config = cfg
+		return h, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (h *HeatmapTile) Parse(params map[string]interface{}) error {
+	err := h.Geometry.Parse(params)
+	if err != nil {
+		return err
+	}
+	h.Resolution = json.GetIntDefault(params, 256, "resolution")
+	if h.Resolution <= 0 {
+		return fmt.Errorf("`resolution` must be positive")
+	}
+	return nil
+}
+
+// AddAggs adds the binning aggregation to the provided query object and
+// returns the query selecting the x, y and count of each bin.
+func (h *HeatmapTile) AddAggs(coord *binning.TileCoord, query *citus.Query) (string, error) {
+	projected, err := h.Projected(query)
+	if err != nil {
+		return "", err
+	}
+	bounds := binning.GetTileMercatorBounds(coord)
+	size := bounds.RangeX() / float64(h.Resolution)
+	// offset the grid origin so that points snap to bin centers
+	originXArg := query.AddParameter(bounds.MinX() + size/2)
+	originYArg := query.AddParameter(bounds.MinY() + size/2)
+	sizeArg := query.AddParameter(size)
+	query.Select(fmt.Sprintf("ST_SnapToGrid(ST_Centroid(%s), %s, %s, %s, %s) AS cell",
+		projected, originXArg, originYArg, sizeArg, sizeArg))
+	query.Select("CAST(COUNT(*) AS FLOAT) AS value")
+	query.GroupBy("cell")
+	return fmt.Sprintf("SELECT ST_X(cell), ST_Y(cell), value FROM (%s) AS cells;",
+		query.GetQuery(true)), nil
+}
+
+// GetBins parses the snapped bin centers into bins.
+func (h *HeatmapTile) GetBins(coord *binning.TileCoord, rows *pgx.Rows) ([]float64, error) {
+	bounds := binning.GetTileMercatorBounds(coord)
+	size := bounds.RangeX() / float64(h.Resolution)
+	bins := make([]float64, h.Resolution*h.Resolution)
+	for rows.Next() {
+		var x, y, value float64
+		err := rows.Scan(&x, &y, &value)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing snapped grid: %v", err)
+		}
+		xBin := int(math.Round((x - bounds.MinX() - size/2) / size))
+		yBin := int(math.Round((y - bounds.MinY() - size/2) / size))
+		if xBin < 0 || xBin >= h.Resolution || yBin < 0 || yBin >= h.Resolution {
+			// centroid of a geometry overlapping the tile
+			continue
+		}
+		bins[xBin+h.Resolution*yBin] += value
+	}
+	return bins, rows.Err()
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, citusQuery, err := h.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	citusQuery, err = h.Geometry.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// add aggs
+	queryString, err := h.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// send query
+	res, err := client.Query(queryString, citusQuery.QueryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// get bins
+	bins, err := h.GetBins(coord, res)
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to byte array
+	bits := make([]byte, len(bins)*4)
+	for i, bin := range bins {
+		binary.LittleEndian.PutUint32(
+			bits[i*4:i*4+4],
+			uint32(bin))
+	}
+	return bits, nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// MVTTile represents a PostGIS implementation of a mapbox vector tile,
+// generated with ST_AsMVT.
+type MVTTile struct {
+	citus.Tile
+	Geometry
+	Layer      string
+	Extent     int
+	Buffer     int
+	Properties []string
+}
+
+// NewMVTTile instantiates and returns a new tile struct.
+func NewMVTTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		m := &MVTTile{}
+		m.Config = cfg
+		return m, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (m *MVTTile) Parse(params map[string]interface{}) error {
+	err := m.Geometry.Parse(params)
+	if err != nil {
+		return err
+	}
+	m.Layer = json.GetStringDefault(params, "default", "layer")
+	m.Extent = json.GetIntDefault(params, 4096, "extent")
+	if m.Extent <= 0 {
+		return fmt.Errorf("`extent` must be positive")
+	}
+	m.Buffer = json.GetIntDefault(params, 256, "buffer")
+	if m.Buffer < 0 {
+		return fmt.Errorf("`buffer` must not be negative")
+	}
+	properties, ok := json.GetStringArray(params, "properties")
+	if !ok {
+		properties = []string{}
+	}
+	m.Properties = properties
+	return nil
+}
+
+// AddAggs adds the vector tile encoding to the provided query object and
+// returns the query selecting the encoded tile.
+func (m *MVTTile) AddAggs(coord *binning.TileCoord, query *citus.Query) (string, error) {
+	projected, err := m.Projected(query)
+	if err != nil {
+		return "", err
+	}
+	extentArg := query.AddParameter(m.Extent)
+	bufferArg := query.AddParameter(m.Buffer)
+	query.Select(fmt.Sprintf("ST_AsMVTGeom(%s, %s, CAST(%s AS INTEGER), CAST(%s AS INTEGER), true) AS mvt_geom",
+		projected, m.Envelope(coord, query), extentArg, bufferArg))
+	for _, property := range m.Properties {
+		column, err := query.Column(property)
+		if err != nil {
+			return "", err
+		}
+		query.Select(column)
+	}
+	layerArg := query.AddParameter(m.Layer)
+	return fmt.Sprintf("SELECT ST_AsMVT(mvt, CAST(%s AS TEXT), CAST(%s AS INTEGER), 'mvt_geom') FROM (%s) AS mvt;",
+		layerArg, extentArg, query.GetQuery(true)), nil
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (m *MVTTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, citusQuery, err := m.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	citusQuery, err = m.Geometry.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// add encoding
+	queryString, err := m.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// send query
+	var tile []byte
+	err = client.QueryRow(queryString, citusQuery.QueryArgs...).Scan(&tile)
+	if err != nil {
+		return nil, err
+	}
+	if tile == nil {
+		// no geometries within the tile
+		return []byte{}, nil
+	}
+	return tile, nil
+}
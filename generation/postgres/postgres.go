@@ -0,0 +1,36 @@
+// Package postgres provides PostGIS geometry tiles for plain PostgreSQL
+// databases. Queries are built with the citus SQL builder, and the
+// non-spatial citus tiles and queries may be used against PostgreSQL as is.
+package postgres
+
+import (
+	"github.com/jackc/pgx"
+
+	"github.com/unchartedsoftware/veldt/generation/citus"
+)
+
+const (
+	// mercatorSRID is the spatial reference id of web mercator.
+	mercatorSRID = 3857
+	// defaultSRID is the spatial reference id of WGS 84 lon / lat.
+	defaultSRID = 4326
+)
+
+// Config defines the database and connection pool details required to
+// establish a connection.
+type Config = citus.Config
+
+// NewClient returns a client from the connection pool of the provided config.
+func NewClient(cfg *Config) (*pgx.ConnPool, error) {
+	return citus.NewClient(cfg)
+}
+
+// CloseClient closes the connection pool for the provided config.
+func CloseClient(cfg *Config) {
+	citus.CloseClient(cfg)
+}
+
+// CloseClients closes all open connection pools.
+func CloseClients() {
+	citus.CloseClients()
+}
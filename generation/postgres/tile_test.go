@@ -0,0 +1,167 @@
+package postgres_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+	"github.com/unchartedsoftware/veldt/generation/postgres"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Tile", func() {
+
+	var query *citus.Query
+	var coord *binning.TileCoord
+
+	BeforeEach(func() {
+		query, _ = citus.NewQuery()
+		query.From(`"points"`)
+		coord = &binning.TileCoord{X: 1, Y: 1, Z: 1}
+	})
+
+	Describe("Geometry", func() {
+		It("should filter by the tile envelope in the column's reference system", func() {
+			g := &postgres.Geometry{}
+			err := g.Parse(JSON(`{"geometryField": "geom"}`))
+			Expect(err).To(BeNil())
+			g.AddQuery(coord, query)
+			Expect(query.WhereClauses).To(Equal([]string{
+				`"geom" && ST_Transform(ST_MakeEnvelope($1, $2, $3, $4, 3857), 4326)`,
+			}))
+			Expect(query.QueryArgs).To(Equal([]interface{}{
+				0.0, 0.0, binning.MercatorExtent, binning.MercatorExtent,
+			}))
+		})
+
+		It("should not transform web mercator columns", func() {
+			g := &postgres.Geometry{}
+			g.Parse(JSON(`{"geometryField": "geom", "srid": 3857}`))
+			g.AddQuery(coord, query)
+			Expect(query.WhereClauses).To(Equal([]string{
+				`"geom" && ST_MakeEnvelope($1, $2, $3, $4, 3857)`,
+			}))
+			projected, err := g.Projected(query)
+			Expect(err).To(BeNil())
+			Expect(projected).To(Equal(`"geom"`))
+		})
+
+		It("should return an error if `geometryField` is missing", func() {
+			g := &postgres.Geometry{}
+			err := g.Parse(JSON(`{}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("HeatmapTile", func() {
+		It("should snap geometries to the bin centers", func() {
+			t, _ := postgres.NewHeatmapTile(nil)()
+			h := t.(*postgres.HeatmapTile)
+			err := h.Parse(JSON(`{"geometryField": "geom", "resolution": 2}`))
+			Expect(err).To(BeNil())
+			sql, err := h.AddAggs(coord, query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal("SELECT ST_X(cell), ST_Y(cell), value FROM (" +
+				`SELECT ST_SnapToGrid(ST_Centroid(ST_Transform("geom", 3857)), $1, $2, $3, $3) AS cell, ` +
+				`CAST(COUNT(*) AS FLOAT) AS value FROM "points" GROUP BY cell) AS cells;`))
+			half := binning.MercatorExtent / 2
+			Expect(query.QueryArgs).To(Equal([]interface{}{half / 2, half / 2, half}))
+		})
+	})
+
+	Describe("MVTTile", func() {
+		It("should encode the geometries with ST_AsMVT", func() {
+			t, _ := postgres.NewMVTTile(nil)()
+			m := t.(*postgres.MVTTile)
+			err := m.Parse(JSON(`{"geometryField": "geom", "srid": 3857, "layer": "points", "properties": ["name"]}`))
+			Expect(err).To(BeNil())
+			sql, err := m.AddAggs(coord, query)
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal("SELECT ST_AsMVT(mvt, CAST($7 AS TEXT), CAST($1 AS INTEGER), 'mvt_geom') FROM (" +
+				`SELECT ST_AsMVTGeom("geom", ST_MakeEnvelope($3, $4, $5, $6, 3857), CAST($1 AS INTEGER), CAST($2 AS INTEGER), true) AS mvt_geom, ` +
+				`"name" FROM "points") AS mvt;`))
+			Expect(query.QueryArgs[0]).To(Equal(4096))
+			Expect(query.QueryArgs[1]).To(Equal(256))
+			Expect(query.QueryArgs[6]).To(Equal("points"))
+		})
+	})
+
+	// The following require a PostGIS enabled database, for example an
+	// ephemeral `postgis/postgis` container, configured through the
+	// POSTGRES_TEST_HOST, POSTGRES_TEST_PORT, POSTGRES_TEST_DB,
+	// POSTGRES_TEST_USER and POSTGRES_TEST_PASSWORD environment variables.
+	Describe("PostGIS", func() {
+
+		var cfg *postgres.Config
+
+		BeforeEach(func() {
+			host := os.Getenv("POSTGRES_TEST_HOST")
+			if host == "" {
+				Skip("POSTGRES_TEST_HOST is not set")
+			}
+			port, _ := strconv.Atoi(os.Getenv("POSTGRES_TEST_PORT"))
+			if port == 0 {
+				port = 5432
+			}
+			cfg = &postgres.Config{
+				Host:           host,
+				Port:           uint16(port),
+				Database:       os.Getenv("POSTGRES_TEST_DB"),
+				User:           os.Getenv("POSTGRES_TEST_USER"),
+				Password:       os.Getenv("POSTGRES_TEST_PASSWORD"),
+				MaxConnections: 2,
+			}
+			client, err := postgres.NewClient(cfg)
+			Expect(err).To(BeNil())
+			for _, stmt := range []string{
+				"CREATE EXTENSION IF NOT EXISTS postgis",
+				"DROP TABLE IF EXISTS veldt_points",
+				"CREATE TABLE veldt_points (name TEXT, geom GEOMETRY(Point, 4326))",
+				"INSERT INTO veldt_points VALUES " +
+					"('a', ST_SetSRID(ST_MakePoint(45, 45), 4326)), " +
+					"('b', ST_SetSRID(ST_MakePoint(46, 46), 4326)), " +
+					"('c', ST_SetSRID(ST_MakePoint(135, 10), 4326)), " +
+					"('d', ST_SetSRID(ST_MakePoint(-45, 10), 4326))",
+			} {
+				_, err := client.Exec(stmt)
+				Expect(err).To(BeNil(), fmt.Sprintf("executing `%s`", stmt))
+			}
+		})
+
+		AfterEach(func() {
+			if cfg != nil {
+				client, _ := postgres.NewClient(cfg)
+				client.Exec("DROP TABLE IF EXISTS veldt_points")
+				postgres.CloseClients()
+			}
+		})
+
+		It("should bin the geometries within the tile", func() {
+			t, _ := postgres.NewHeatmapTile(cfg)()
+			err := t.Parse(JSON(`{"geometryField": "geom", "resolution": 2}`))
+			Expect(err).To(BeNil())
+			res, err := t.Create("veldt_points", coord, nil)
+			Expect(err).To(BeNil())
+			bins := make([]uint32, 4)
+			for i := range bins {
+				bins[i] = binary.LittleEndian.Uint32(res[i*4 : i*4+4])
+			}
+			Expect(bins).To(Equal([]uint32{2, 1, 0, 0}))
+		})
+
+		It("should encode the geometries within the tile", func() {
+			t, _ := postgres.NewMVTTile(cfg)()
+			err := t.Parse(JSON(`{"geometryField": "geom", "properties": ["name"]}`))
+			Expect(err).To(BeNil())
+			res, err := t.Create("veldt_points", coord, nil)
+			Expect(err).To(BeNil())
+			Expect(len(res)).To(BeNumerically(">", 0))
+		})
+	})
+})
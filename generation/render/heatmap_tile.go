@@ -0,0 +1,153 @@
+package render
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/coocood/freecache"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+const (
+	defaultMetaCacheSize   = 1024 * 1024 * 16
+	defaultMetaCacheExpiry = 60
+)
+
+var (
+	mutex      = sync.Mutex{}
+	metaCache  = freecache.NewCache(defaultMetaCacheSize)
+	metaExpiry = defaultMetaCacheExpiry
+)
+
+// SetMetaCache replaces the cache of the meta data used to resolve extrema
+// with one of the provided size in bytes. Cached meta data expires after the
+// provided number of seconds, such that changes to the underlying data are
+// eventually reflected.
+func SetMetaCache(byteSize int, expirySeconds int) {
+	mutex.Lock()
+	metaCache = freecache.NewCache(byteSize)
+	metaExpiry = expirySeconds
+	mutex.Unlock()
+}
+
+func getMetaCache() (*freecache.Cache, int) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return metaCache, metaExpiry
+}
+
+// HeatmapTile represents a tile that renders the numeric bins of an underlying
+// heatmap tile into a colored image.
+type HeatmapTile struct {
+	tile.Render
+	heatmap veldt.Tile
+	meta    veldt.MetaCtor
+}
+
+// NewHeatmapTile instantiates and returns a new tile struct wrapping the
+// provided heatmap tile constructor. The optional meta constructor is used to
+// resolve the extrema when the tile request specifies `extremaMeta`.
+func NewHeatmapTile(heatmap veldt.TileCtor, meta veldt.MetaCtor) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t, err := heatmap()
+		if err != nil {
+			return nil, err
+		}
+		return &HeatmapTile{
+			heatmap: t,
+			meta:    meta,
+		}, nil
+	}
+}
+
+// Unwrap returns the underlying heatmap tile, such that the tile is described
+// by the parameters of both.
+func (h *HeatmapTile) Unwrap() interface{} {
+	return h.heatmap
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (h *HeatmapTile) Parse(params map[string]interface{}) error {
+	err := h.Render.Parse(params)
+	if err != nil {
+		return err
+	}
+	if h.MetaPath != nil && h.meta == nil {
+		return fmt.Errorf("`extremaMeta` provided but no meta type has been registered for the tile")
+	}
+	return h.heatmap.Parse(params)
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// generate the underlying heatmap
+	data, err := h.heatmap.Create(uri, coord, query)
+	if err != nil {
+		return nil, err
+	}
+	// decode the bins
+	bins, err := h.DecodeBins(data)
+	if err != nil {
+		return nil, err
+	}
+	// determine the extrema
+	extrema, err := h.getExtrema(uri, bins)
+	if err != nil {
+		return nil, err
+	}
+	// render the bins
+	return h.Render.Encode(bins, extrema)
+}
+
+func (h *HeatmapTile) getExtrema(uri string, bins []float64) (*binning.Extrema, error) {
+	// explicit extrema take precedence
+	if h.Extrema != nil {
+		return h.Extrema, nil
+	}
+	// then extrema from meta data
+	if h.MetaPath != nil {
+		meta, err := h.getMeta(uri)
+		if err != nil {
+			return nil, err
+		}
+		return h.GetMetaExtrema(meta)
+	}
+	// otherwise use the local extrema of the tile
+	return h.GetExtrema(bins), nil
+}
+
+func (h *HeatmapTile) getMeta(uri string) ([]byte, error) {
+	m, err := h.meta()
+	if err != nil {
+		return nil, err
+	}
+	err = m.Parse(h.MetaParams)
+	if err != nil {
+		return nil, err
+	}
+	// key the meta data the same way as the meta request would be
+	req := &veldt.MetaRequest{
+		URI:  uri,
+		Meta: m,
+	}
+	key := []byte(req.GetHash())
+	cache, expiry := getMetaCache()
+	meta, err := cache.Get(key)
+	if err == nil {
+		return meta, nil
+	}
+	// generate the meta data
+	meta, err = req.Create()
+	if err != nil {
+		return nil, err
+	}
+	err = cache.Set(key, meta, expiry)
+	if err != nil {
+		Warnf("Unable to cache meta data for %s: %v", uri, err)
+	}
+	return meta, nil
+}
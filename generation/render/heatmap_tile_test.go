@@ -0,0 +1,194 @@
+package render_test
+
+import (
+	"encoding/binary"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/render"
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+type stubHeatmap struct {
+	params map[string]interface{}
+	bins   []uint32
+}
+
+func (s *stubHeatmap) Parse(params map[string]interface{}) error {
+	s.params = params
+	return nil
+}
+
+func (s *stubHeatmap) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	bits := make([]byte, len(s.bins)*4)
+	for i, bin := range s.bins {
+		binary.LittleEndian.PutUint32(bits[i*4:i*4+4], bin)
+	}
+	return bits, nil
+}
+
+var metaCreates int
+
+type stubMeta struct {
+	params map[string]interface{}
+}
+
+func (s *stubMeta) Parse(params map[string]interface{}) error {
+	s.params = params
+	return nil
+}
+
+func (s *stubMeta) Create(uri string) ([]byte, error) {
+	metaCreates++
+	return []byte(`{"count":{"extrema":{"min":0,"max":8}}}`), nil
+}
+
+var _ = Describe("HeatmapTile", func() {
+
+	var heatmap *stubHeatmap
+
+	heatmapCtor := func() (veldt.Tile, error) {
+		return heatmap, nil
+	}
+
+	metaCtor := func() (veldt.Meta, error) {
+		return &stubMeta{}, nil
+	}
+
+	pixel := func(rgba []byte, x int, y int) []byte {
+		offset := (x + y*2) * 4
+		return rgba[offset : offset+4]
+	}
+
+	color := func(ramp string, value float64) []byte {
+		r, err := tile.GetColorRamp(ramp)
+		Expect(err).To(BeNil())
+		c := r.Interpolate(value)
+		return []byte{c.R, c.G, c.B, c.A}
+	}
+
+	create := func(ctor veldt.TileCtor, uri string, params map[string]interface{}) ([]byte, error) {
+		t, err := ctor()
+		Expect(err).To(BeNil())
+		err = t.Parse(params)
+		if err != nil {
+			return nil, err
+		}
+		return t.Create(uri, &binning.TileCoord{}, nil)
+	}
+
+	BeforeEach(func() {
+		// bins are ordered bottom-left first
+		heatmap = &stubHeatmap{
+			bins: []uint32{0, 2, 4, 8},
+		}
+		metaCreates = 0
+		render.SetMetaCache(1024*1024, 60)
+	})
+
+	Describe("Parse", func() {
+		It("should pass the params through to the wrapped tile", func() {
+			params := JSON(`{"colorRamp": "hot", "xField": "x"}`)
+			_, err := create(render.NewHeatmapTile(heatmapCtor, nil), "uri", params)
+			Expect(err).To(BeNil())
+			Expect(heatmap.params).To(Equal(params))
+		})
+
+		It("should unwrap to the wrapped tile such that its params are described", func() {
+			t, err := render.NewHeatmapTile(heatmapCtor, nil)()
+			Expect(err).To(BeNil())
+			wrapper, ok := t.(veldt.Wrapper)
+			Expect(ok).To(Equal(true))
+			Expect(wrapper.Unwrap()).To(BeIdenticalTo(heatmap))
+		})
+
+		It("should return an error if `extremaMeta` is provided without a meta type", func() {
+			params := JSON(`{"extremaMeta": {"path": ["count"]}}`)
+			_, err := create(render.NewHeatmapTile(heatmapCtor, nil), "uri", params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Create", func() {
+		It("should render the bins with the color ramp using the local extrema", func() {
+			params := JSON(`{"colorRamp": "hot", "format": "rgba"}`)
+			rgba, err := create(render.NewHeatmapTile(heatmapCtor, nil), "uri", params)
+			Expect(err).To(BeNil())
+			Expect(len(rgba)).To(Equal(16))
+			// empty bins are transparent
+			Expect(pixel(rgba, 0, 1)).To(Equal([]byte{0, 0, 0, 0}))
+			Expect(pixel(rgba, 1, 1)).To(Equal(color("hot", 0)))
+			Expect(pixel(rgba, 0, 0)).To(Equal(color("hot", 1.0/3.0)))
+			Expect(pixel(rgba, 1, 0)).To(Equal(color("hot", 1)))
+		})
+
+		It("should render the bins using explicit extrema", func() {
+			params := JSON(
+				`{
+					"colorRamp": "greyscale",
+					"format": "rgba",
+					"extrema": {
+						"min": 0,
+						"max": 16
+					}
+				}`)
+			rgba, err := create(render.NewHeatmapTile(heatmapCtor, nil), "uri", params)
+			Expect(err).To(BeNil())
+			Expect(pixel(rgba, 1, 0)).To(Equal(color("greyscale", 0.5)))
+		})
+
+		It("should encode the image as a png by default", func() {
+			params := JSON(`{}`)
+			img, err := create(render.NewHeatmapTile(heatmapCtor, nil), "uri", params)
+			Expect(err).To(BeNil())
+			Expect(img[0:8]).To(Equal([]byte("\x89PNG\r\n\x1a\n")))
+		})
+
+		It("should render the bins using the extrema from the meta data", func() {
+			params := JSON(
+				`{
+					"colorRamp": "greyscale",
+					"format": "rgba",
+					"extremaMeta": {
+						"path": ["count"]
+					}
+				}`)
+			rgba, err := create(render.NewHeatmapTile(heatmapCtor, metaCtor), "uri", params)
+			Expect(err).To(BeNil())
+			Expect(pixel(rgba, 1, 1)).To(Equal(color("greyscale", 0.25)))
+			Expect(pixel(rgba, 1, 0)).To(Equal(color("greyscale", 1)))
+		})
+
+		It("should cache the meta data per uri and meta params", func() {
+			ctor := render.NewHeatmapTile(heatmapCtor, metaCtor)
+			params := JSON(`{"extremaMeta": {"path": ["count"], "params": {"a": 1}}}`)
+			_, err := create(ctor, "uri", params)
+			Expect(err).To(BeNil())
+			_, err = create(ctor, "uri", params)
+			Expect(err).To(BeNil())
+			Expect(metaCreates).To(Equal(1))
+			_, err = create(ctor, "other", params)
+			Expect(err).To(BeNil())
+			Expect(metaCreates).To(Equal(2))
+			params = JSON(`{"extremaMeta": {"path": ["count"], "params": {"a": 2}}}`)
+			_, err = create(ctor, "uri", params)
+			Expect(err).To(BeNil())
+			Expect(metaCreates).To(Equal(3))
+		})
+
+		It("should regenerate the meta data once the cache is replaced", func() {
+			ctor := render.NewHeatmapTile(heatmapCtor, metaCtor)
+			params := JSON(`{"extremaMeta": {"path": ["count"]}}`)
+			_, err := create(ctor, "uri", params)
+			Expect(err).To(BeNil())
+			render.SetMetaCache(1024*1024, 60)
+			_, err = create(ctor, "uri", params)
+			Expect(err).To(BeNil())
+			Expect(metaCreates).To(Equal(2))
+		})
+	})
+})
@@ -91,7 +91,7 @@ func (t *Tile) Create(uri string, coord *binning.TileCoord, query veldt.Query) (
 		if len(str) > maxErrLength {
 			str = str[0:maxErrLength] + "..."
 		}
-		return nil, fmt.Errorf(str)
+		return nil, fmt.Errorf("%s", str)
 	}
 	return tile.Decode(t.ext, res.Body)
 }
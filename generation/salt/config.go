@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/liyinhgqw/typesafe-config/parse"
 )
@@ -28,12 +29,19 @@ type QueueConfig struct {
 	noWait    bool
 }
 
+const (
+	defaultTimeoutMS = 60000
+)
+
 // Config describes how the salt connection is to be created
 type Config struct {
 	host         string
 	port         int64
 	queueConfigs map[string]*QueueConfig
 	serverQueue  string
+	// timeout is the maximum duration to wait for a response from the salt
+	// server before the request fails.
+	timeout time.Duration
 }
 
 func tOrF(b bool) string {
@@ -49,6 +57,7 @@ func (c *Config) Key() string {
 	i := 0
 	for k := range c.queueConfigs {
 		qcs[i] = k
+		i++
 	}
 	sort.Strings(qcs)
 	qcKey := ""
@@ -60,9 +69,10 @@ func (c *Config) Key() string {
 		} else {
 			qcKey = qcKey + fmt.Sprintf(":%s.%s.%s%s%s%s", k, qc.queue, tOrF(qc.durable), tOrF(qc.deletable), tOrF(qc.exclusive), tOrF(qc.noWait))
 		}
+		first = false
 	}
 
-	return fmt.Sprintf("%s:%d|%s|%s", c.host, c.port, c.serverQueue, qcKey)
+	return fmt.Sprintf("%s:%d|%s|%s|%v", c.host, c.port, c.serverQueue, qcKey, c.timeout)
 }
 
 func getConfigString(key string, config *parse.Config) Option {
@@ -172,6 +182,11 @@ func ReadConfig(filename string) (*Config, error) {
 	port := getConfigInt("rabbitmq.port", config).OrElse(int64(5672)).(int64)
 	// Get the server queue from the config
 	serverQueue := stripTerminalQuotes(getConfigString("communications.queue", config).OrElse("salt").(string))
+	// Get the request timeout, in milliseconds, from the config
+	timeout := getConfigInt("communications.timeout", config).OrElse(int64(defaultTimeoutMS)).(int64)
+	if timeout <= 0 {
+		return nil, fmt.Errorf("communications.timeout must be positive, got %d", timeout)
+	}
 	// Get any queues that need to be pre-initialized
 	queues := make(map[string]*QueueConfig)
 	for _, queueKey := range queueKeys {
@@ -189,5 +204,11 @@ func ReadConfig(filename string) (*Config, error) {
 		queues[queueKey] = &QueueConfig{queue, durable, autoDelete, exclusive, noWait}
 	}
 
-	return &Config{host, port, queues, serverQueue}, nil
+	return &Config{
+		host:         host,
+		port:         port,
+		queueConfigs: queues,
+		serverQueue:  serverQueue,
+		timeout:      time.Duration(timeout) * time.Millisecond,
+	}, nil
 }
@@ -1,6 +1,8 @@
 package salt
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -13,6 +15,7 @@ var _ = Describe("Salt configuration", func() {
 		Expect(config.host).To(Equal("rabbitmq.uncharted.software"), "Host parameter")
 		Expect(config.port).To(Equal(int64(1234)), "Port")
 		Expect(config.serverQueue).To(Equal("salt-test-queue"), "Queue")
+		Expect(config.timeout).To(Equal(2500*time.Millisecond), "Timeout")
 		Expect(len(config.queueConfigs)).To(Equal(3), "Queues")
 		Expect(config.queueConfigs["bunny"].queue).To(Equal("bunny-queue"), "Queue bunny name")
 		Expect(config.queueConfigs["bunny"].durable).To(Equal(false), "Queue bunny durability")
@@ -38,6 +41,7 @@ var _ = Describe("Salt configuration", func() {
 		Expect(config.host).To(Equal("localhost"), "Host")
 		Expect(config.port).To(Equal(int64(5672)), "Port")
 		Expect(config.serverQueue).To(Equal("salt"), "Queue")
+		Expect(config.timeout).To(Equal(60*time.Second), "Timeout")
 		Expect(len(config.queueConfigs)).To(Equal(0), "Queues")
 	})
 })
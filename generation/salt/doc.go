@@ -40,6 +40,10 @@ system:
 All use NewConnection in salt.go to make the actual connection.  NewConnection caches
 its connection for reuse, so in typical use, should return the same connection every
 time.
+If the connection to RabbitMQ is lost, pending requests fail and the connection
+is re-established by the next request.  Requests which receive no response within
+the configured `communications.timeout` (in milliseconds, defaulting to 60000)
+fail, along with the tiles awaiting them.
 
 The Salt tile server is currently in a private repository - it will be made
 public and open-source soon.  In the mean time, apologies are extended to any
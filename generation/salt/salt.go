@@ -1,9 +1,11 @@
 package salt
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/streadway/amqp"
 )
@@ -20,88 +22,242 @@ import (
 //
 // Unexported functions herin (especially sendServerMessage) should be
 // considered private to this class, not just to the package.
+//
+// If the connection to the RabbitMQ server is lost, every pending request
+// fails, and the connection is re-established by the next request.
+
+// amqpConnection is the subset of an AMQP connection used to communicate with
+// the salt server, such that the broker may be replaced in tests.
+type amqpConnection interface {
+	Channel() (amqpChannel, error)
+	Close() error
+}
+
+// amqpChannel is the subset of an AMQP channel used to communicate with the
+// salt server.
+type amqpChannel interface {
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Close() error
+}
+
+// brokerConnection adapts an AMQP connection to the amqpConnection interface.
+type brokerConnection struct {
+	*amqp.Connection
+}
+
+// Channel opens a unique, concurrent server channel.
+func (c *brokerConnection) Channel() (amqpChannel, error) {
+	return c.Connection.Channel()
+}
+
+// dial opens a connection to the RabbitMQ server at the provided url.
+var dial = func(url string) (amqpConnection, error) {
+	connection, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &brokerConnection{connection}, nil
+}
+
+// errConnectionClosed is returned when a request is made on a closed
+// connection.
+var errConnectionClosed = errors.New("salt: connection has been closed")
+
+// pendingRequest is a request awaiting a response from the salt server.
+type pendingRequest struct {
+	connection amqpConnection
+	responses  chan pendingResponse
+}
+
+// pendingResponse is the response to a pending request, or the reason there
+// will be no response.
+type pendingResponse struct {
+	delivery amqp.Delivery
+	err      error
+}
 
 // RabbitMQConnection describes a connection to a RabbitMQ server
 type RabbitMQConnection struct {
-	connection  *amqp.Connection
-	channel     *amqp.Channel
-	queues      map[string]amqp.Queue
-	serverQueue string
+	config *Config
+	// guards the connection, channel and queues
+	mutex      sync.Mutex
+	connection amqpConnection
+	channel    amqpChannel
+	queues     map[string]amqp.Queue
+	closed     bool
+	// guards the pending requests
+	pendingMutex sync.Mutex
+	pending      map[string]*pendingRequest
+	serverQueue  string
 }
 
 var (
-	mutex            = sync.Mutex{}
-	connections      = make(map[string]*RabbitMQConnection)
-	responseChannels = make(map[string]chan<- amqp.Delivery)
-	nextMessage      = 0
-	emptyResponse    = make([]byte, 0)
+	mutex         = sync.Mutex{}
+	connections   = make(map[string]*RabbitMQConnection)
+	nextMessage   = 0
+	emptyResponse = make([]byte, 0)
 )
 
 // NewConnection returns a connection to the Salt tile server via RabbitMQ
 func NewConnection(config *Config) (*RabbitMQConnection, error) {
+	key := config.Key()
 	mutex.Lock()
-	rmq, contained := connections[config.Key()]
+	rmq, contained := connections[key]
 	if !contained {
 		Infof("New connection request")
 
-		url := fmt.Sprintf("amqp://%s:%d", config.host, config.port)
-		var connection *amqp.Connection
-		var channel *amqp.Channel
-
-		connection, err := amqp.Dial(url)
-		if err != nil {
-			return nil, err
+		rmq = &RabbitMQConnection{
+			config:      config,
+			queues:      make(map[string]amqp.Queue),
+			pending:     make(map[string]*pendingRequest),
+			serverQueue: config.serverQueue,
 		}
-
-		channel, err = connection.Channel()
+		rmq.mutex.Lock()
+		err := rmq.connect()
+		rmq.mutex.Unlock()
 		if err != nil {
+			mutex.Unlock()
+			runtime.Gosched()
 			return nil, err
 		}
 
-		rmq = &RabbitMQConnection{connection, channel, make(map[string]amqp.Queue), config.serverQueue}
+		// Store this connection for later reuse
+		connections[key] = rmq
+	}
+	mutex.Unlock()
+	runtime.Gosched()
 
-		// Register our standard queues
-		for k, v := range config.queueConfigs {
-			rmq.Declare(k, v)
-		}
+	Infof("connection request fulfilled: %v", rmq)
+	return rmq, nil
+}
 
-		// Start up a consumer on our response channel
-		responseQ, err := rmq.GetQueue("response")
+// connect establishes the connection to the RabbitMQ server, declares the
+// configured queues and starts consuming responses. The caller must hold the
+// connection mutex.
+func (rmq *RabbitMQConnection) connect() error {
+	url := fmt.Sprintf("amqp://%s:%d", rmq.config.host, rmq.config.port)
+	connection, err := dial(url)
+	if err != nil {
+		return err
+	}
+	channel, err := connection.Channel()
+	if err != nil {
+		connection.Close()
+		return err
+	}
+	rmq.connection = connection
+	rmq.channel = channel
+	rmq.queues = make(map[string]amqp.Queue)
+
+	// Register our standard queues
+	for k, v := range rmq.config.queueConfigs {
+		err = rmq.declare(k, v)
 		if err != nil {
-			return nil, err
+			rmq.disconnect()
+			return err
 		}
-		responses, err := channel.Consume(responseQ.Name, "", true, false, false, false, nil)
-		if err != nil {
-			return nil, err
+	}
+
+	// Start up a consumer on our response channel
+	responseQ, err := rmq.getQueue("response")
+	if err != nil {
+		rmq.disconnect()
+		return err
+	}
+	responses, err := channel.Consume(responseQ.Name, "", true, false, false, false, nil)
+	if err != nil {
+		rmq.disconnect()
+		return err
+	}
+	go rmq.consume(connection, responses)
+	return nil
+}
+
+// disconnect closes the current connection, if any. The caller must hold the
+// connection mutex.
+func (rmq *RabbitMQConnection) disconnect() {
+	if rmq.connection == nil {
+		return
+	}
+	rmq.channel.Close()
+	rmq.connection.Close()
+	rmq.connection = nil
+	rmq.channel = nil
+}
+
+// consume delivers responses to the pending requests until the connection is
+// lost, at which point the requests pending on the connection fail.
+func (rmq *RabbitMQConnection) consume(connection amqpConnection, responses <-chan amqp.Delivery) {
+	for response := range responses {
+		rmq.pendingMutex.Lock()
+		request, ok := rmq.pending[response.MessageId]
+		delete(rmq.pending, response.MessageId)
+		rmq.pendingMutex.Unlock()
+		if !ok {
+			// the request has already timed out
+			Warnf("Discarding response to unknown message %s", response.MessageId)
+			continue
 		}
-		go func() {
-			for response := range responses {
-				msgID := response.MessageId
-				responseChannel := responseChannels[msgID]
-				delete(responseChannels, msgID)
-				responseChannel <- response
-			}
-		}()
+		request.responses <- pendingResponse{delivery: response}
+	}
 
-		// Store this connection for later reuse
-		connections[config.Key()] = rmq
+	// the delivery channel is closed when the connection is lost
+	rmq.mutex.Lock()
+	if rmq.connection == connection {
+		Warnf("Connection to RabbitMQ lost, reconnecting on next request")
+		rmq.disconnect()
 	}
-	mutex.Unlock()
-	runtime.Gosched()
+	rmq.mutex.Unlock()
+	rmq.failPending(connection, fmt.Errorf("salt: connection to RabbitMQ lost before a response was received"))
+}
 
-	Infof("connection request fulfilled: %v", rmq)
-	return rmq, nil
+// failPending fails every request pending on the provided connection.
+func (rmq *RabbitMQConnection) failPending(connection amqpConnection, err error) {
+	rmq.pendingMutex.Lock()
+	defer rmq.pendingMutex.Unlock()
+	for msgID, request := range rmq.pending {
+		if request.connection == connection {
+			delete(rmq.pending, msgID)
+			request.responses <- pendingResponse{err: err}
+		}
+	}
 }
 
-// Close closes this RabbitMQ connection
+// Close closes this RabbitMQ connection. Pending and subsequent requests on
+// this connection fail, however a new connection may be created with
+// NewConnection.
 func (rmq *RabbitMQConnection) Close() {
 	Infof("Closing connection")
-	rmq.channel.Close()
-	rmq.connection.Close()
+	key := rmq.config.Key()
+	mutex.Lock()
+	if connections[key] == rmq {
+		delete(connections, key)
+	}
+	mutex.Unlock()
+	rmq.mutex.Lock()
+	connection := rmq.connection
+	rmq.closed = true
+	rmq.disconnect()
+	rmq.mutex.Unlock()
+	if connection != nil {
+		rmq.failPending(connection, errConnectionClosed)
+	}
 }
 
 // Declare declares a queue using this RabbitMQ connection
 func (rmq *RabbitMQConnection) Declare(qName string, qc *QueueConfig) error {
+	rmq.mutex.Lock()
+	defer rmq.mutex.Unlock()
+	err := rmq.ensureConnected()
+	if err != nil {
+		return err
+	}
+	return rmq.declare(qName, qc)
+}
+
+func (rmq *RabbitMQConnection) declare(qName string, qc *QueueConfig) error {
 	q, err := rmq.channel.QueueDeclare(qc.queue, qc.durable, qc.deletable, qc.exclusive, qc.noWait, nil)
 	if err != nil {
 		return err
@@ -115,6 +271,16 @@ func (rmq *RabbitMQConnection) Declare(qName string, qc *QueueConfig) error {
 // canonical name.  If there is no current channel with the given canonical
 // name, a temporary channel is created.
 func (rmq *RabbitMQConnection) GetQueue(cannonicalName string) (amqp.Queue, error) {
+	rmq.mutex.Lock()
+	defer rmq.mutex.Unlock()
+	err := rmq.ensureConnected()
+	if err != nil {
+		return amqp.Queue{}, err
+	}
+	return rmq.getQueue(cannonicalName)
+}
+
+func (rmq *RabbitMQConnection) getQueue(cannonicalName string) (amqp.Queue, error) {
 	var err error
 	q, contained := rmq.queues[cannonicalName]
 	if !contained {
@@ -126,6 +292,19 @@ func (rmq *RabbitMQConnection) GetQueue(cannonicalName string) (amqp.Queue, erro
 	return q, err
 }
 
+// ensureConnected re-establishes the connection if it has been lost. The
+// caller must hold the connection mutex.
+func (rmq *RabbitMQConnection) ensureConnected() error {
+	if rmq.closed {
+		return errConnectionClosed
+	}
+	if rmq.connection != nil {
+		return nil
+	}
+	Infof("Reconnecting to RabbitMQ")
+	return rmq.connect()
+}
+
 func nextMessageID() string {
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -151,37 +330,93 @@ func (rmq *RabbitMQConnection) QueryMetadata(message []byte) ([]byte, error) {
 	return rmq.sendServerMessage("metadata", message)
 }
 
-// sendServerMessage is a low-level generic function to do exactly what it says.  It is used by
-// Query and Dataset
-func (rmq *RabbitMQConnection) sendServerMessage(messageType string, message []byte) ([]byte, error) {
-	queryQ, err := rmq.GetQueue(rmq.serverQueue)
+// publish publishes the message to the server queue, registering the pending
+// request before the message is sent.
+func (rmq *RabbitMQConnection) publish(messageType string, msgID string, message []byte, request *pendingRequest) error {
+	rmq.mutex.Lock()
+	defer rmq.mutex.Unlock()
+
+	err := rmq.ensureConnected()
 	if err != nil {
-		return emptyResponse, err
+		return err
 	}
-	responseQ, err := rmq.GetQueue("response")
+	queryQ, err := rmq.getQueue(rmq.serverQueue)
 	if err != nil {
-		return emptyResponse, err
+		return err
+	}
+	responseQ, err := rmq.getQueue("response")
+	if err != nil {
+		return err
 	}
-
-	msgID := nextMessageID()
-	responseChannel := make(chan amqp.Delivery)
-	responseChannels[msgID] = responseChannel
 
 	Debugf("Publishing message \"%s\"\n\t(query queue: %s(=%s))\n\t(response queue: %s(=%s))\n\t(type: %s)",
 		string(message), rmq.serverQueue, queryQ.Name, "response", responseQ.Name, messageType)
 
-	rmq.channel.Publish("", queryQ.Name, false, false,
+	request.connection = rmq.connection
+	rmq.pendingMutex.Lock()
+	rmq.pending[msgID] = request
+	rmq.pendingMutex.Unlock()
+
+	err = rmq.channel.Publish("", queryQ.Name, false, false,
 		amqp.Publishing{
 			Type:      messageType,
 			Body:      message,
 			ReplyTo:   responseQ.Name,
 			MessageId: msgID})
+	if err != nil {
+		rmq.removePending(msgID)
+		return err
+	}
+	return nil
+}
+
+// removePending removes the pending request, returning false if it has
+// already been removed.
+func (rmq *RabbitMQConnection) removePending(msgID string) bool {
+	rmq.pendingMutex.Lock()
+	defer rmq.pendingMutex.Unlock()
+	_, ok := rmq.pending[msgID]
+	delete(rmq.pending, msgID)
+	return ok
+}
 
-	response := <-responseChannel
-	Debugf("Response received: \"%s\"", string(response.Body))
-	if "error" == response.Type {
-		return nil, fmt.Errorf(string(response.Body))
+// sendServerMessage is a low-level generic function to do exactly what it says.  It is used by
+// Query and Dataset
+func (rmq *RabbitMQConnection) sendServerMessage(messageType string, message []byte) ([]byte, error) {
+	msgID := nextMessageID()
+	request := &pendingRequest{
+		// buffered such that a response never blocks the consumer
+		responses: make(chan pendingResponse, 1),
+	}
+	err := rmq.publish(messageType, msgID, message, request)
+	if err != nil {
+		return emptyResponse, err
+	}
+
+	timeout := rmq.config.timeout
+	if timeout == 0 {
+		timeout = defaultTimeoutMS * time.Millisecond
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var response pendingResponse
+	select {
+	case response = <-request.responses:
+	case <-timer.C:
+		if rmq.removePending(msgID) {
+			return nil, fmt.Errorf("salt: %s request %s timed out after %v", messageType, msgID, timeout)
+		}
+		// the response arrived as the request timed out
+		response = <-request.responses
+	}
+	if response.err != nil {
+		return nil, response.err
+	}
+	Debugf("Response received: \"%s\"", string(response.delivery.Body))
+	if "error" == response.delivery.Type {
+		return nil, fmt.Errorf("%s", response.delivery.Body)
 	}
 
-	return response.Body, nil
+	return response.delivery.Body, nil
 }
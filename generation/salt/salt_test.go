@@ -0,0 +1,176 @@
+package salt
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/unchartedsoftware/veldt/binning"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RabbitMQConnection", func() {
+
+	var broker *standIn
+	var restore func()
+	var config *Config
+	var port int64
+
+	BeforeEach(func() {
+		broker = newStandIn(echo)
+		restore = broker.install()
+		// use a distinct config per test to avoid the connection cache
+		port++
+		config = &Config{
+			host:        "localhost",
+			port:        port,
+			serverQueue: "salt",
+			timeout:     time.Second,
+		}
+	})
+
+	AfterEach(func() {
+		connection, ok := connections[config.Key()]
+		if ok {
+			connection.Close()
+		}
+		restore()
+	})
+
+	It("should return the response to each request", func() {
+		connection, err := NewConnection(config)
+		Expect(err).To(BeNil())
+		var wg sync.WaitGroup
+		for i := 0; i < 32; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				message := []byte(fmt.Sprintf("request %d", i))
+				res, err := connection.QueryMetadata(message)
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal(message))
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	It("should return error responses as errors", func() {
+		broker.setHandler(func(msg amqp.Publishing) *amqp.Publishing {
+			return &amqp.Publishing{
+				Type: "error",
+				Body: []byte("unknown dataset"),
+			}
+		})
+		connection, err := NewConnection(config)
+		Expect(err).To(BeNil())
+		_, err = connection.QueryMetadata([]byte("tweets"))
+		Expect(err).To(MatchError("unknown dataset"))
+	})
+
+	It("should fail requests which receive no response before the timeout", func() {
+		config.timeout = time.Millisecond * 50
+		responses := make(chan struct{})
+		broker.setHandler(func(msg amqp.Publishing) *amqp.Publishing {
+			// respond after the request has timed out
+			<-responses
+			return echo(msg)
+		})
+		connection, err := NewConnection(config)
+		Expect(err).To(BeNil())
+		_, err = connection.QueryMetadata([]byte("tweets"))
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("timed out"))
+		close(responses)
+		// late responses are discarded
+		broker.setHandler(echo)
+		res, err := connection.QueryMetadata([]byte("tweets"))
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal([]byte("tweets")))
+	})
+
+	It("should fail pending requests and reconnect when the connection is lost", func() {
+		received := make(chan struct{}, 1)
+		broker.setHandler(func(msg amqp.Publishing) *amqp.Publishing {
+			received <- struct{}{}
+			return nil
+		})
+		connection, err := NewConnection(config)
+		Expect(err).To(BeNil())
+		errs := make(chan error, 1)
+		go func() {
+			_, err := connection.QueryMetadata([]byte("tweets"))
+			errs <- err
+		}()
+		<-received
+		broker.restart()
+		Eventually(errs).Should(Receive(MatchError(ContainSubstring("connection to RabbitMQ lost"))))
+		// the next request reconnects
+		broker.setHandler(echo)
+		res, err := connection.QueryMetadata([]byte("tweets"))
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal([]byte("tweets")))
+		Expect(broker.dialCount()).To(Equal(2))
+	})
+
+	It("should fail requests while the broker is down, and recover once it is up", func() {
+		connection, err := NewConnection(config)
+		Expect(err).To(BeNil())
+		broker.setDown(true)
+		broker.restart()
+		Eventually(func() error {
+			_, err := connection.QueryMetadata([]byte("tweets"))
+			return err
+		}).Should(MatchError(ContainSubstring("connection refused")))
+		broker.setDown(false)
+		res, err := connection.QueryMetadata([]byte("tweets"))
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal([]byte("tweets")))
+	})
+
+	It("should release the lock when the initial connection fails", func() {
+		broker.setDown(true)
+		_, err := NewConnection(config)
+		Expect(err).NotTo(BeNil())
+		broker.setDown(false)
+		_, err = NewConnection(config)
+		Expect(err).To(BeNil())
+	})
+
+	It("should fail requests after the connection is closed", func() {
+		connection, err := NewConnection(config)
+		Expect(err).To(BeNil())
+		connection.Close()
+		_, err = connection.QueryMetadata([]byte("tweets"))
+		Expect(err).To(Equal(errConnectionClosed))
+		// a new connection may be created
+		other, err := NewConnection(config)
+		Expect(err).To(BeNil())
+		Expect(other).NotTo(BeIdenticalTo(connection))
+	})
+
+	It("should fail the pending tile when the request times out", func() {
+		config.timeout = time.Millisecond * 50
+		broker.setHandler(func(msg amqp.Publishing) *amqp.Publishing {
+			return nil
+		})
+		t, err := NewCountTile(config)()
+		Expect(err).To(BeNil())
+		err = t.Parse(map[string]interface{}{
+			"xField": "x",
+			"yField": "y",
+			"left":   0.0,
+			"right":  256.0,
+			"bottom": 0.0,
+			"top":    256.0,
+		})
+		Expect(err).To(BeNil())
+		_, err = t.Create("tweets", &binning.TileCoord{}, nil)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("timed out"))
+	})
+})
@@ -0,0 +1,189 @@
+package salt
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/streadway/amqp"
+)
+
+// standIn represents an in-memory RabbitMQ broker and salt server. Each
+// published message is passed to the handler, and the returned publishing,
+// if any, is delivered to the reply queue.
+type standIn struct {
+	mutex       sync.Mutex
+	handler     func(amqp.Publishing) *amqp.Publishing
+	down        bool
+	dials       int
+	queues      int
+	connections []*standInConnection
+}
+
+func newStandIn(handler func(amqp.Publishing) *amqp.Publishing) *standIn {
+	return &standIn{
+		handler: handler,
+	}
+}
+
+// install replaces the dialer of the package with the stand-in, returning a
+// function which restores it.
+func (s *standIn) install() func() {
+	original := dial
+	dial = s.dial
+	return func() {
+		dial = original
+	}
+}
+
+func (s *standIn) dial(url string) (amqpConnection, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.dials++
+	if s.down {
+		return nil, fmt.Errorf("dial tcp %s: connection refused", url)
+	}
+	c := &standInConnection{
+		broker: s,
+	}
+	s.connections = append(s.connections, c)
+	return c, nil
+}
+
+// restart closes every open connection, as if the broker was restarted.
+func (s *standIn) restart() {
+	s.mutex.Lock()
+	connections := s.connections
+	s.connections = nil
+	s.mutex.Unlock()
+	for _, c := range connections {
+		c.Close()
+	}
+}
+
+// setHandler replaces the handler of published messages.
+func (s *standIn) setHandler(handler func(amqp.Publishing) *amqp.Publishing) {
+	s.mutex.Lock()
+	s.handler = handler
+	s.mutex.Unlock()
+}
+
+// setDown sets whether the broker refuses connections.
+func (s *standIn) setDown(down bool) {
+	s.mutex.Lock()
+	s.down = down
+	s.mutex.Unlock()
+}
+
+// dialCount returns the number of connection attempts.
+func (s *standIn) dialCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.dials
+}
+
+type standInConnection struct {
+	broker    *standIn
+	mutex     sync.Mutex
+	closed    bool
+	consumers map[string]chan amqp.Delivery
+}
+
+func (c *standInConnection) Channel() (amqpChannel, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return nil, amqp.ErrClosed
+	}
+	return &standInChannel{c}, nil
+}
+
+func (c *standInConnection) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return amqp.ErrClosed
+	}
+	c.closed = true
+	for _, consumer := range c.consumers {
+		close(consumer)
+	}
+	return nil
+}
+
+func (c *standInConnection) deliver(queue string, delivery amqp.Delivery) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		// the reply queue is gone along with the connection
+		return
+	}
+	consumer, ok := c.consumers[queue]
+	if ok {
+		consumer <- delivery
+	}
+}
+
+type standInChannel struct {
+	connection *standInConnection
+}
+
+func (ch *standInChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	if name == "" {
+		ch.connection.broker.mutex.Lock()
+		ch.connection.broker.queues++
+		name = fmt.Sprintf("amq.gen-%d", ch.connection.broker.queues)
+		ch.connection.broker.mutex.Unlock()
+	}
+	return amqp.Queue{Name: name}, nil
+}
+
+func (ch *standInChannel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	c := ch.connection
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return nil, amqp.ErrClosed
+	}
+	if c.consumers == nil {
+		c.consumers = make(map[string]chan amqp.Delivery)
+	}
+	deliveries := make(chan amqp.Delivery, 64)
+	c.consumers[queue] = deliveries
+	return deliveries, nil
+}
+
+func (ch *standInChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	c := ch.connection
+	c.mutex.Lock()
+	closed := c.closed
+	c.mutex.Unlock()
+	if closed {
+		return amqp.ErrClosed
+	}
+	c.broker.mutex.Lock()
+	handler := c.broker.handler
+	c.broker.mutex.Unlock()
+	go func() {
+		res := handler(msg)
+		if res == nil {
+			return
+		}
+		c.deliver(msg.ReplyTo, amqp.Delivery{
+			MessageId: msg.MessageId,
+			Type:      res.Type,
+			Body:      res.Body,
+		})
+	}()
+	return nil
+}
+
+func (ch *standInChannel) Close() error {
+	return nil
+}
+
+// echo responds to every message with its own body.
+func echo(msg amqp.Publishing) *amqp.Publishing {
+	return &amqp.Publishing{
+		Body: msg.Body,
+	}
+}
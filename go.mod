@@ -1,28 +1,38 @@
 module github.com/unchartedsoftware/veldt
 
+go 1.27.1
+
 require (
 	github.com/aws/aws-sdk-go v1.8.3
 	github.com/coocood/freecache v0.0.0-20170401024559-c7b48416d80a
 	github.com/davecgh/go-spew v1.1.0
 	github.com/garyburd/redigo v0.0.0-20170426212818-ac91d6ff49bd
-	github.com/go-ini/ini v1.27.0 // indirect
-	github.com/golang/lint v0.0.0-20181026193005-c67002cb31c3 // indirect
-	github.com/hpcloud/tail v1.0.0 // indirect
 	github.com/jackc/pgx v0.0.0-20170417134424-c16671e77e8a
-	github.com/jmespath/go-jmespath v0.0.0-20160803190731-bd40a432e4c7 // indirect
 	github.com/liyinhgqw/typesafe-config v0.0.0-20150617052320-c8ba452ab033
 	github.com/mattn/go-isatty v0.0.2
 	github.com/onsi/ginkgo v1.6.0
 	github.com/onsi/gomega v0.0.0-20170408032339-9b8c753e8dfb
-	github.com/spaolacci/murmur3 v0.0.0-20150829172844-0d12bf811670 // indirect
+	github.com/parquet-go/parquet-go v0.25.1
 	github.com/streadway/amqp v0.0.0-20170313174848-afe8eee29a74
+	gopkg.in/olivere/elastic.v3 v3.0.68
+)
+
+require (
+	github.com/andybalholm/brotli v1.1.0 // indirect
+	github.com/go-ini/ini v1.27.0 // indirect
+	github.com/golang/lint v0.0.0-20181026193005-c67002cb31c3 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/hpcloud/tail v1.0.0 // indirect
+	github.com/jmespath/go-jmespath v0.0.0-20160803190731-bd40a432e4c7 // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.21 // indirect
+	github.com/spaolacci/murmur3 v0.0.0-20150829172844-0d12bf811670 // indirect
 	github.com/wadey/gocovmerge v0.0.0-20160331181800-b5bfa59ec0ad // indirect
 	golang.org/x/lint v0.0.0-20181026193005-c67002cb31c3 // indirect
 	golang.org/x/net v0.0.0-20170424220842-da118f7b8e59 // indirect
-	golang.org/x/sys v0.0.0-20170427041856-9ccfe848b9db // indirect
+	golang.org/x/sys v0.21.0 // indirect
 	golang.org/x/tools v0.0.0-20181030151751-bb28844c46df // indirect
 	gopkg.in/fsnotify.v1 v1.4.7 // indirect
-	gopkg.in/olivere/elastic.v3 v3.0.68
 	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
 	gopkg.in/yaml.v2 v2.0.0-20170407172122-cd8b52f8269e // indirect
 )
@@ -1,11 +1,30 @@
 package veldt
 
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
 // Meta represents an interface for generating meta data.
 type Meta interface {
 	Create(string) ([]byte, error)
 	Parse(map[string]interface{}) error
 }
 
+// ScopedMeta represents meta data which can be restricted to the data
+// matching a query and within the bounds of a tile.
+type ScopedMeta interface {
+	// CreateScoped creates the meta data.
+	// parameter 1 (string): A dataset ID (typically called uri)
+	// parameter 2 (*tile.Bivariate): the tiling fields and bounds of the
+	//             scope, nil if the meta data is not spatially scoped
+	// parameter 3 (*binning.TileCoord): the coordinates of the tile within
+	//             the scope
+	// parameter 4 (Query): A query to specify which data should be included in
+	//             the meta data - essentially a filter
+	CreateScoped(string, *tile.Bivariate, *binning.TileCoord, Query) ([]byte, error)
+}
+
 // MetaCtor represents a function that instantiates and returns a new meta
 // data type.
 type MetaCtor func() (Meta, error)
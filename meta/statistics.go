@@ -0,0 +1,120 @@
+package meta
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Statistics represents the optional per property statistics of a meta data
+// generator. Each statistic is only computed if requested, such that the
+// default meta data remains cheap to generate.
+type Statistics struct {
+	// HistogramBuckets is the number of buckets of ordinal property
+	// histograms, no histograms are computed if zero.
+	HistogramBuckets int
+	// Percentiles are the percentiles of ordinal properties, in the range
+	// [0 : 100].
+	Percentiles []float64
+	// Cardinality indicates whether to estimate the number of distinct values.
+	Cardinality bool
+	// Missing indicates whether to count the documents missing a value.
+	Missing bool
+	// TopValues is the number of most frequent values of string properties,
+	// no values are computed if zero.
+	TopValues int
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (s *Statistics) Parse(params map[string]interface{}) error {
+	histogram := json.GetIntDefault(params, 0, "histogram")
+	if histogram < 0 {
+		return fmt.Errorf("`histogram` must not be negative")
+	}
+	percentiles, err := parsePercentiles(params)
+	if err != nil {
+		return err
+	}
+	topValues := json.GetIntDefault(params, 0, "topValues")
+	if topValues < 0 {
+		return fmt.Errorf("`topValues` must not be negative")
+	}
+	s.HistogramBuckets = histogram
+	s.Percentiles = percentiles
+	s.Cardinality = json.GetBoolDefault(params, false, "cardinality")
+	s.Missing = json.GetBoolDefault(params, false, "missing")
+	s.TopValues = topValues
+	return nil
+}
+
+// parsePercentiles parses the optional percentiles, each in the range
+// [0 : 100].
+func parsePercentiles(params map[string]interface{}) ([]float64, error) {
+	if !json.Exists(params, "percentiles") {
+		return nil, nil
+	}
+	percentiles, ok := json.GetFloatArray(params, "percentiles")
+	if !ok {
+		return nil, fmt.Errorf("`percentiles` parameter is not an array of numbers")
+	}
+	for _, p := range percentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("percentile %v is outside of the range [0 : 100]", p)
+		}
+	}
+	return percentiles, nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (s *Statistics) Params() []json.Param {
+	return []json.Param{
+		{Name: "histogram", Type: json.IntegerType, Default: 0, Description: "number of buckets of ordinal property histograms"},
+		{Name: "percentiles", Type: json.ArrayType, Items: json.NumberType, Description: "percentiles of ordinal properties"},
+		{Name: "cardinality", Type: json.BooleanType, Default: false, Description: "estimate the number of distinct values"},
+		{Name: "missing", Type: json.BooleanType, Default: false, Description: "count the documents missing a value"},
+		{Name: "topValues", Type: json.IntegerType, Default: 0, Description: "number of most frequent values of string properties"},
+	}
+}
+
+// HistogramEdges returns the edges of the evenly sized histogram buckets
+// spanning the extrema, including the lower and upper bound.
+func (s *Statistics) HistogramEdges(extrema *binning.Extrema) []float64 {
+	edges := make([]float64, s.HistogramBuckets+1)
+	width := (extrema.Max - extrema.Min) / float64(s.HistogramBuckets)
+	for i := range edges {
+		edges[i] = extrema.Min + width*float64(i)
+	}
+	edges[s.HistogramBuckets] = extrema.Max
+	return edges
+}
+
+// PercentileKey returns the key of the percentile in the meta data.
+func PercentileKey(percentile float64) string {
+	return strconv.FormatFloat(percentile, 'f', -1, 64)
+}
+
+// Histogram represents the counts of evenly sized buckets spanning the
+// extrema of a property. The last bucket includes the maximum.
+type Histogram struct {
+	Min    float64  `json:"min"`
+	Max    float64  `json:"max"`
+	Counts []uint64 `json:"counts"`
+}
+
+// ValueCount represents the number of documents containing a value.
+type ValueCount struct {
+	Value interface{} `json:"value"`
+	Count uint64      `json:"count"`
+}
+
+// PropertyStatistics represents the optional statistics of a single
+// property.
+type PropertyStatistics struct {
+	Histogram   *Histogram         `json:"histogram,omitempty"`
+	Percentiles map[string]float64 `json:"percentiles,omitempty"`
+	Cardinality *uint64            `json:"cardinality,omitempty"`
+	Missing     *uint64            `json:"missing,omitempty"`
+	TopValues   []ValueCount       `json:"topValues,omitempty"`
+}
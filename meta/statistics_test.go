@@ -0,0 +1,64 @@
+package meta_test
+
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/meta"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Statistics", func() {
+
+	Describe("Parse", func() {
+		It("should not compute any statistics by default", func() {
+			s := &meta.Statistics{}
+			err := s.Parse(JSON(`{}`))
+			Expect(err).To(BeNil())
+			Expect(*s).To(Equal(meta.Statistics{}))
+		})
+		It("should parse the requested statistics", func() {
+			s := &meta.Statistics{}
+			err := s.Parse(JSON(
+				`{
+					"histogram": 8,
+					"percentiles": [1, 50, 99.5],
+					"cardinality": true,
+					"missing": true,
+					"topValues": 10
+				}`))
+			Expect(err).To(BeNil())
+			Expect(*s).To(Equal(meta.Statistics{
+				HistogramBuckets: 8,
+				Percentiles:      []float64{1, 50, 99.5},
+				Cardinality:      true,
+				Missing:          true,
+				TopValues:        10,
+			}))
+		})
+		It("should return an error for invalid parameters", func() {
+			s := &meta.Statistics{}
+			Expect(s.Parse(JSON(`{"histogram": -1}`))).NotTo(BeNil())
+			Expect(s.Parse(JSON(`{"percentiles": [101]}`))).NotTo(BeNil())
+			Expect(s.Parse(JSON(`{"percentiles": ["a"]}`))).NotTo(BeNil())
+			Expect(s.Parse(JSON(`{"topValues": -1}`))).NotTo(BeNil())
+		})
+	})
+
+	Describe("HistogramEdges", func() {
+		It("should span the extrema with evenly sized buckets", func() {
+			s := &meta.Statistics{HistogramBuckets: 4}
+			Expect(s.HistogramEdges(&binning.Extrema{Min: 0, Max: 10})).
+				To(Equal([]float64{0, 2.5, 5, 7.5, 10}))
+		})
+	})
+
+	Describe("PercentileKey", func() {
+		It("should format the percentile without trailing zeros", func() {
+			Expect(meta.PercentileKey(50)).To(Equal("50"))
+			Expect(meta.PercentileKey(99.9)).To(Equal("99.9"))
+		})
+	})
+
+})
@@ -0,0 +1,109 @@
+package meta
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// ZoomExtrema represents the parameters of a meta data type which computes
+// the extrema of the heatmap bin values at each zoom level within a range.
+type ZoomExtrema struct {
+	MinZoom uint32
+	MaxZoom uint32
+	// Percentiles are the percentiles of the non-empty bin values, in the
+	// range [0 : 100]. Each percentile p of n sorted values is interpolated
+	// linearly between the closest ranks of p / 100 * (n - 1), as by the SQL
+	// percentile_cont function. Backends unable to interpolate may return the
+	// value of the closest rank instead.
+	Percentiles []float64
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (z *ZoomExtrema) Parse(params map[string]interface{}) error {
+	minZoom := json.GetIntDefault(params, 0, "minZoom")
+	maxZoom, ok := json.GetInt(params, "maxZoom")
+	if !ok {
+		return json.NewFieldError("maxZoom", json.MissingCode,
+			"`maxZoom` parameter missing from meta")
+	}
+	if minZoom < 0 || maxZoom < minZoom || maxZoom > int(binning.MaxLevelSupported) {
+		name := "maxZoom"
+		if minZoom < 0 {
+			name = "minZoom"
+		}
+		return json.NewFieldError(name, json.InvalidCode,
+			fmt.Sprintf("zoom range [%d : %d] is outside of the range [0 : %d]",
+				minZoom, maxZoom, int(binning.MaxLevelSupported)))
+	}
+	percentiles, err := parsePercentiles(params)
+	if err != nil {
+		return err
+	}
+	z.MinZoom = uint32(minZoom)
+	z.MaxZoom = uint32(maxZoom)
+	z.Percentiles = percentiles
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (z *ZoomExtrema) Params() []json.Param {
+	return []json.Param{
+		{Name: "minZoom", Type: json.IntegerType, Default: 0},
+		{Name: "maxZoom", Type: json.IntegerType, Required: true},
+		{Name: "percentiles", Type: json.ArrayType, Items: json.NumberType, Description: "percentiles of the non-empty bin values"},
+	}
+}
+
+// Zooms returns the zoom levels of the range, in ascending order.
+func (z *ZoomExtrema) Zooms() []uint32 {
+	zooms := make([]uint32, 0, z.MaxZoom-z.MinZoom+1)
+	for zoom := z.MinZoom; zoom <= z.MaxZoom; zoom++ {
+		zooms = append(zooms, zoom)
+	}
+	return zooms
+}
+
+// Compute returns the extrema and percentiles of the provided non-empty bin
+// values. Percentiles are interpolated linearly between the closest ranks, as
+// defined by the Percentiles field. Nil is returned if there are no values.
+func (z *ZoomExtrema) Compute(values []float64) *LevelExtrema {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	level := &LevelExtrema{
+		Min: sorted[0],
+		Max: sorted[len(sorted)-1],
+	}
+	if len(z.Percentiles) > 0 {
+		level.Percentiles = make(map[string]float64, len(z.Percentiles))
+		for _, p := range z.Percentiles {
+			rank := p / 100 * float64(len(sorted)-1)
+			lower := int(math.Floor(rank))
+			upper := int(math.Ceil(rank))
+			val := sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+			level.Percentiles[PercentileKey(p)] = val
+		}
+	}
+	return level
+}
+
+// LevelKey returns the key of the zoom level in the meta data.
+func LevelKey(zoom uint32) string {
+	return strconv.FormatUint(uint64(zoom), 10)
+}
+
+// LevelExtrema represents the extrema and percentiles of the non-empty bin
+// values of a single zoom level.
+type LevelExtrema struct {
+	Min         float64            `json:"min"`
+	Max         float64            `json:"max"`
+	Percentiles map[string]float64 `json:"percentiles,omitempty"`
+}
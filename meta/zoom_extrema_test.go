@@ -0,0 +1,64 @@
+package meta_test
+
+import (
+	"github.com/unchartedsoftware/veldt/meta"
+	"github.com/unchartedsoftware/veldt/util/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("ZoomExtrema", func() {
+
+	Describe("Parse", func() {
+		It("should parse the zoom range and percentiles", func() {
+			z := &meta.ZoomExtrema{}
+			err := z.Parse(JSON(`{"minZoom": 2, "maxZoom": 4, "percentiles": [99]}`))
+			Expect(err).To(BeNil())
+			Expect(z.Zooms()).To(Equal([]uint32{2, 3, 4}))
+			Expect(z.Percentiles).To(Equal([]float64{99}))
+		})
+		It("should return an error for an invalid zoom range", func() {
+			z := &meta.ZoomExtrema{}
+			Expect(z.Parse(JSON(`{"minZoom": 2}`))).NotTo(BeNil())
+			Expect(z.Parse(JSON(`{"minZoom": 4, "maxZoom": 2}`))).NotTo(BeNil())
+			Expect(z.Parse(JSON(`{"maxZoom": 25}`))).NotTo(BeNil())
+		})
+		It("should locate errors of the zoom range", func() {
+			z := &meta.ZoomExtrema{}
+			err := z.Parse(JSON(`{"minZoom": 4, "maxZoom": 2}`))
+			Expect(err).To(Equal(json.NewFieldError("maxZoom", json.InvalidCode,
+				"zoom range [4 : 2] is outside of the range [0 : 24]")))
+			err = z.Parse(JSON(`{"minZoom": -1, "maxZoom": 2}`))
+			Expect(err).To(BeAssignableToTypeOf(&json.FieldError{}))
+			Expect(err.(*json.FieldError).Pointer).To(Equal("/minZoom"))
+		})
+		It("should not limit the bins of the deepest zoom level", func() {
+			z := &meta.ZoomExtrema{}
+			Expect(z.Parse(JSON(`{"maxZoom": 24, "resolution": 256}`))).To(BeNil())
+			Expect(z.Zooms()).To(HaveLen(25))
+		})
+	})
+
+	Describe("Compute", func() {
+		It("should interpolate the percentiles between the closest ranks", func() {
+			z := &meta.ZoomExtrema{Percentiles: []float64{0, 50, 75, 100}}
+			Expect(z.Compute([]float64{4, 1, 3, 2})).To(Equal(&meta.LevelExtrema{
+				Min: 1,
+				Max: 4,
+				Percentiles: map[string]float64{
+					"0":   1,
+					"50":  2.5,
+					"75":  3.25,
+					"100": 4,
+				},
+			}))
+		})
+		It("should return nil if there are no bins", func() {
+			z := &meta.ZoomExtrema{}
+			Expect(z.Compute(nil)).To(BeNil())
+		})
+	})
+
+})
@@ -0,0 +1,371 @@
+package veldt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+type binaryExpression interface {
+	getBinary() *BinaryExpression
+}
+
+type naryExpression interface {
+	getNary() *NaryExpression
+}
+
+type unaryExpression interface {
+	getUnary() *UnaryExpression
+}
+
+type rangeQuery interface {
+	RangeQuery() *query.Range
+}
+
+type hasQuery interface {
+	HasQuery() *query.Has
+}
+
+// node represents a query in the normalized AST tree. Boolean operators are
+// n-ary, negations only wrap queries, and the key uniquely identifies the
+// node.
+type node struct {
+	op       string
+	operands []*node
+	query    Query
+	key      string
+}
+
+// NormalizeQuery returns the canonical form of the runtime AST tree, such
+// that equivalent queries produce identical trees. The normalization:
+//
+//     - pushes negations down to the queries, by De Morgan's laws
+//     - flattens nested AND / OR operators into n-ary operators
+//     - merges ranges on the same field within an AND
+//     - merges `has` values on the same field within an OR
+//     - removes duplicate operands
+//     - orders operands canonically
+//
+// Merging ranges assumes the field holds a single value per document. Leaf
+// queries may be modified in place.
+func (p *Pipeline) NormalizeQuery(q Query) (Query, error) {
+	if q == nil {
+		return nil, nil
+	}
+	n, err := toNode(q, false)
+	if err != nil {
+		return nil, err
+	}
+	return p.fromNode(simplify(n))
+}
+
+// toNode converts the runtime AST tree into the normalized tree, pushing
+// down negations.
+func toNode(q Query, negated bool) (*node, error) {
+	switch e := q.(type) {
+	case binaryExpression:
+		b := e.getBinary()
+		return toOperatorNode(b.Op, []Query{b.Left, b.Right}, negated)
+	case naryExpression:
+		n := e.getNary()
+		return toOperatorNode(n.Op, n.Queries, negated)
+	case unaryExpression:
+		u := e.getUnary()
+		if u.Op != Not {
+			return nil, fmt.Errorf("`%v` operator is not a valid unary operator", u.Op)
+		}
+		return toNode(u.Query, !negated)
+	}
+	leaf := &node{
+		query: q,
+	}
+	if negated {
+		return &node{
+			op:       Not,
+			operands: []*node{leaf},
+		}, nil
+	}
+	return leaf, nil
+}
+
+func toOperatorNode(op string, queries []Query, negated bool) (*node, error) {
+	if op != And && op != Or {
+		return nil, fmt.Errorf("`%v` operator is not a valid binary operator", op)
+	}
+	if negated {
+		// De Morgan's laws
+		if op == And {
+			op = Or
+		} else {
+			op = And
+		}
+	}
+	n := &node{
+		op:       op,
+		operands: make([]*node, 0, len(queries)),
+	}
+	for _, q := range queries {
+		operand, err := toNode(q, negated)
+		if err != nil {
+			return nil, err
+		}
+		n.operands = append(n.operands, operand)
+	}
+	return n, nil
+}
+
+// simplify flattens, merges, deduplicates and orders the operands of the
+// tree, computing the key of each node.
+func simplify(n *node) *node {
+	switch n.op {
+	case "":
+		if has, ok := n.query.(hasQuery); ok {
+			sortValues(has.HasQuery())
+		}
+		n.key = queryKey(n.query)
+		return n
+	case Not:
+		n.operands[0] = simplify(n.operands[0])
+		n.key = fmt.Sprintf("%s(%s)", Not, n.operands[0].key)
+		return n
+	}
+	// flatten
+	operands := make([]*node, 0, len(n.operands))
+	for _, operand := range n.operands {
+		operand = simplify(operand)
+		if operand.op == n.op {
+			operands = append(operands, operand.operands...)
+			continue
+		}
+		operands = append(operands, operand)
+	}
+	// merge
+	if n.op == And {
+		operands = mergeRanges(operands)
+	} else {
+		operands = mergeHas(operands)
+	}
+	// deduplicate and order
+	sort.SliceStable(operands, func(i, j int) bool {
+		return operands[i].key < operands[j].key
+	})
+	unique := operands[:0]
+	for i, operand := range operands {
+		if i > 0 && operand.key == operands[i-1].key {
+			continue
+		}
+		unique = append(unique, operand)
+	}
+	if len(unique) == 1 {
+		return unique[0]
+	}
+	n.operands = unique
+	keys := make([]string, len(unique))
+	for i, operand := range unique {
+		keys[i] = operand.key
+	}
+	n.key = fmt.Sprintf("%s(%s)", n.op, strings.Join(keys, ","))
+	return n
+}
+
+// mergeRanges merges the ranges on the same field into their intersection.
+func mergeRanges(operands []*node) []*node {
+	merged := make([]*node, 0, len(operands))
+	byField := make(map[string]*node)
+	for _, operand := range operands {
+		r, ok := operand.query.(rangeQuery)
+		if !ok || operand.op != "" {
+			merged = append(merged, operand)
+			continue
+		}
+		field := r.RangeQuery().Field
+		existing, ok := byField[field]
+		if ok && intersectRange(existing.query.(rangeQuery).RangeQuery(), r.RangeQuery()) {
+			existing.key = queryKey(existing.query)
+			continue
+		}
+		byField[field] = operand
+		merged = append(merged, operand)
+	}
+	return merged
+}
+
+// intersectRange restricts the range to the intersection with the other
+// range. Bounds are only compared if numeric, if the ranges cannot be merged
+// false is returned and the range is unmodified.
+func intersectRange(r *query.Range, other *query.Range) bool {
+	gt, gte, ok := tighterBound(r.GT, r.GTE, other.GT, other.GTE, 1)
+	if !ok {
+		return false
+	}
+	lt, lte, ok := tighterBound(r.LT, r.LTE, other.LT, other.LTE, -1)
+	if !ok {
+		return false
+	}
+	r.GT, r.GTE, r.LT, r.LTE = gt, gte, lt, lte
+	return true
+}
+
+// tighterBound returns the tighter of two exclusive / inclusive bound pairs,
+// where the sign is positive for lower bounds and negative for upper bounds.
+func tighterBound(aEx, aIn, bEx, bIn interface{}, sign float64) (interface{}, interface{}, bool) {
+	aVal, aExclusive := aIn, false
+	if aEx != nil {
+		aVal, aExclusive = aEx, true
+	}
+	bVal, bExclusive := bIn, false
+	if bEx != nil {
+		bVal, bExclusive = bEx, true
+	}
+	if bVal == nil {
+		return aEx, aIn, true
+	}
+	if aVal == nil {
+		return bEx, bIn, true
+	}
+	a, aOk := toFloat(aVal)
+	b, bOk := toFloat(bVal)
+	if !aOk || !bOk {
+		return nil, nil, false
+	}
+	if a*sign > b*sign || (a == b && aExclusive) {
+		return aEx, aIn, true
+	}
+	if a == b && !bExclusive {
+		return aEx, aIn, true
+	}
+	return bEx, bIn, true
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// mergeHas merges the `has` queries on the same field into the union of
+// their values.
+func mergeHas(operands []*node) []*node {
+	merged := make([]*node, 0, len(operands))
+	byField := make(map[string]*node)
+	for _, operand := range operands {
+		h, ok := operand.query.(hasQuery)
+		if !ok || operand.op != "" {
+			merged = append(merged, operand)
+			continue
+		}
+		field := h.HasQuery().Field
+		existing, ok := byField[field]
+		if ok {
+			has := existing.query.(hasQuery).HasQuery()
+			has.Values = append(has.Values, h.HasQuery().Values...)
+			sortValues(has)
+			existing.key = queryKey(existing.query)
+			continue
+		}
+		byField[field] = operand
+		merged = append(merged, operand)
+	}
+	return merged
+}
+
+// sortValues orders and deduplicates the values of the query.
+func sortValues(has *query.Has) {
+	keys := make(map[string]interface{}, len(has.Values))
+	for _, val := range has.Values {
+		keys[valueKey(val)] = val
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	values := make([]interface{}, len(sorted))
+	for i, key := range sorted {
+		values[i] = keys[key]
+	}
+	has.Values = values
+}
+
+func queryKey(q Query) string {
+	return strings.Join(strings.Fields(spewer.Sdump(q)), "")
+}
+
+func valueKey(val interface{}) string {
+	return spewer.Sdump(val)
+}
+
+// fromNode instantiates the runtime AST tree of the normalized tree.
+func (p *Pipeline) fromNode(n *node) (Query, error) {
+	switch n.op {
+	case "":
+		return n.query, nil
+	case Not:
+		q, err := p.fromNode(n.operands[0])
+		if err != nil {
+			return nil, err
+		}
+		unary, err := p.GetUnary()
+		if err != nil {
+			return nil, err
+		}
+		err = unary.Parse(map[string]interface{}{
+			"op":    Not,
+			"query": q,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return unary, nil
+	}
+	queries := make([]Query, len(n.operands))
+	for i, operand := range n.operands {
+		q, err := p.fromNode(operand)
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = q
+	}
+	if p.nary != nil {
+		nary, err := p.GetNary()
+		if err != nil {
+			return nil, err
+		}
+		err = nary.Parse(map[string]interface{}{
+			"op":      n.op,
+			"queries": queries,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return nary, nil
+	}
+	// compose binary operators
+	lhs := queries[0]
+	for _, rhs := range queries[1:] {
+		binary, err := p.GetBinary()
+		if err != nil {
+			return nil, err
+		}
+		err = binary.Parse(map[string]interface{}{
+			"left":  lhs,
+			"op":    n.op,
+			"right": rhs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		lhs = binary
+	}
+	return lhs, nil
+}
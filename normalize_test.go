@@ -0,0 +1,147 @@
+package veldt_test
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NormalizeQuery", func() {
+
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		pipeline = veldt.NewPipeline()
+		pipeline.Binary(func() (veldt.Query, error) {
+			return &veldt.BinaryExpression{}, nil
+		})
+		pipeline.Nary(func() (veldt.Query, error) {
+			return &veldt.NaryExpression{}, nil
+		})
+		pipeline.Unary(func() (veldt.Query, error) {
+			return &veldt.UnaryExpression{}, nil
+		})
+		pipeline.Query("equals", func() (veldt.Query, error) {
+			return &query.Equals{}, nil
+		})
+		pipeline.Query("range", func() (veldt.Query, error) {
+			return &query.Range{}, nil
+		})
+		pipeline.Query("has", func() (veldt.Query, error) {
+			return &query.Has{}, nil
+		})
+		pipeline.Tile("stub", func() (veldt.Tile, error) {
+			return &stubTile{}, nil
+		})
+	})
+
+	normalize := func(str string) veldt.Query {
+		q, err := pipeline.ParseQuery(str)
+		Expect(err).To(BeNil())
+		q, err = pipeline.NormalizeQuery(q)
+		Expect(err).To(BeNil())
+		return q
+	}
+
+	It("should flatten nested operators into n-ary operators", func() {
+		Expect(normalize(`a = 1 AND (b = 2 AND (c = 3 AND d = 4))`)).To(Equal(&veldt.NaryExpression{
+			Op: veldt.And,
+			Queries: []veldt.Query{
+				&query.Equals{Field: "a", Value: 1.0},
+				&query.Equals{Field: "b", Value: 2.0},
+				&query.Equals{Field: "c", Value: 3.0},
+				&query.Equals{Field: "d", Value: 4.0},
+			},
+		}))
+	})
+
+	It("should push negations down to the queries", func() {
+		Expect(normalize(`NOT (a = 1 OR NOT b = 2)`)).To(Equal(&veldt.NaryExpression{
+			Op: veldt.And,
+			Queries: []veldt.Query{
+				&query.Equals{Field: "b", Value: 2.0},
+				&veldt.UnaryExpression{
+					Op:    veldt.Not,
+					Query: &query.Equals{Field: "a", Value: 1.0},
+				},
+			},
+		}))
+		Expect(normalize(`NOT NOT a = 1`)).To(Equal(&query.Equals{Field: "a", Value: 1.0}))
+	})
+
+	It("should merge ranges on the same field within an AND", func() {
+		Expect(normalize(`x >= 1 AND x < 10 AND (x > 1 AND x <= 5)`)).To(Equal(&query.Range{
+			Field: "x",
+			GT:    1.0,
+			LTE:   5.0,
+		}))
+		Expect(normalize(`x >= 1 OR x < 10`)).To(Equal(&veldt.NaryExpression{
+			Op: veldt.Or,
+			Queries: []veldt.Query{
+				&query.Range{Field: "x", GTE: 1.0},
+				&query.Range{Field: "x", LT: 10.0},
+			},
+		}))
+	})
+
+	It("should not merge ranges with bounds which cannot be compared", func() {
+		Expect(normalize(`t >= "2017-01-01" AND t >= "now-1d"`)).To(Equal(&veldt.NaryExpression{
+			Op: veldt.And,
+			Queries: []veldt.Query{
+				&query.Range{Field: "t", GTE: "2017-01-01"},
+				&query.Range{Field: "t", GTE: "now-1d"},
+			},
+		}))
+	})
+
+	It("should merge `has` values on the same field within an OR", func() {
+		Expect(normalize(`a IN [3, 1] OR b IN [1] OR a IN [2, 1]`)).To(Equal(&veldt.NaryExpression{
+			Op: veldt.Or,
+			Queries: []veldt.Query{
+				&query.Has{Field: "a", Values: []interface{}{1.0, 2.0, 3.0}},
+				&query.Has{Field: "b", Values: []interface{}{1.0}},
+			},
+		}))
+	})
+
+	It("should remove duplicate operands and order them canonically", func() {
+		a := normalize(`b = 2 AND a = 1 AND b = 2`)
+		b := normalize(`a = 1 AND b = 2`)
+		Expect(a).To(Equal(b))
+		Expect(normalize(`(a = 1 OR b = 2) AND c = 3`)).To(Equal(normalize(`c = 3 AND (b = 2 OR a = 1)`)))
+	})
+
+	It("should compose binary operators if no n-ary operator is registered", func() {
+		pipeline.Nary(nil)
+		Expect(normalize(`c = 3 AND b = 2 AND a = 1`)).To(Equal(&veldt.BinaryExpression{
+			Left: &veldt.BinaryExpression{
+				Left:  &query.Equals{Field: "a", Value: 1.0},
+				Op:    veldt.And,
+				Right: &query.Equals{Field: "b", Value: 2.0},
+			},
+			Op:    veldt.And,
+			Right: &query.Equals{Field: "c", Value: 3.0},
+		}))
+	})
+
+	It("should produce identical hashes for equivalent tile requests", func() {
+		request := func(q string) *veldt.TileRequest {
+			req, err := pipeline.NewTileRequest(map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"x": 0.0, "y": 0.0, "z": 0.0},
+				"tile":  map[string]interface{}{"stub": map[string]interface{}{}},
+				"query": q,
+			})
+			Expect(err).To(BeNil())
+			return req
+		}
+		a := request(`x >= 1 AND NOT (NOT x <= 5 OR NOT type IN ["b", "a"])`)
+		b := request(`type IN ["a", "b"] AND x <= 5 AND x >= 1`)
+		c := request(`type IN ["a", "b"] AND x <= 5 AND x >= 2`)
+		Expect(a.GetHash()).To(Equal(b.GetHash()))
+		Expect(a.GetHash()).NotTo(Equal(c.GetHash()))
+	})
+
+})
@@ -18,6 +18,7 @@ type Pipeline struct {
 	queue       *queue.Queue
 	queries     map[string]QueryCtor
 	binary      QueryCtor
+	nary        QueryCtor
 	unary       QueryCtor
 	tiles       map[string]TileCtor
 	metas       map[string]MetaCtor
@@ -58,6 +59,12 @@ func (p *Pipeline) Binary(ctor QueryCtor) {
 	p.binary = ctor
 }
 
+// Nary registers an n-ary operator type, used for normalized queries. If none
+// is registered, normalized queries are composed of binary operators.
+func (p *Pipeline) Nary(ctor QueryCtor) {
+	p.nary = ctor
+}
+
 // Unary registers a unary operator type under the provided ID string.
 func (p *Pipeline) Unary(ctor QueryCtor) {
 	p.unary = ctor
@@ -82,16 +89,20 @@ func (p *Pipeline) Store(ctor StoreCtor) {
 func (p *Pipeline) GetQuery(id string, args interface{}) (Query, error) {
 	params, ok := args.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("`%s` is not of correct type", id)
+		return nil, typeError("", json.ObjectType, fmt.Sprintf("`%s` is not of correct type", id))
 	}
 	ctor, ok := p.queries[id]
 	if !ok {
-		return nil, fmt.Errorf("unrecognized query type `%v`", id)
+		return nil, json.NewFieldError("", json.UnknownCode, fmt.Sprintf("unrecognized query type `%v`", id))
 	}
 	query, err := ctor()
 	if err != nil {
 		return nil, err
 	}
+	err = validateParams(query, params)
+	if err != nil {
+		return nil, err
+	}
 	err = query.Parse(params)
 	if err != nil {
 		return nil, err
@@ -108,6 +119,14 @@ func (p *Pipeline) GetBinary() (Query, error) {
 	return p.binary()
 }
 
+// GetNary returns the instantiated n-ary operator struct.
+func (p *Pipeline) GetNary() (Query, error) {
+	if p.nary == nil {
+		return nil, fmt.Errorf("no n-ary query type has been provided")
+	}
+	return p.nary()
+}
+
 // GetUnary returns the instantiated unary operator struct from the provided
 // ID and JSON.
 func (p *Pipeline) GetUnary() (Query, error) {
@@ -122,16 +141,20 @@ func (p *Pipeline) GetUnary() (Query, error) {
 func (p *Pipeline) GetTile(id string, args interface{}) (Tile, error) {
 	params, ok := args.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("`%s` is not of correct type", id)
+		return nil, typeError("", json.ObjectType, fmt.Sprintf("`%s` is not of correct type", id))
 	}
 	ctor, ok := p.tiles[id]
 	if !ok {
-		return nil, fmt.Errorf("unrecognized tile type `%v`", id)
+		return nil, json.NewFieldError("", json.UnknownCode, fmt.Sprintf("unrecognized tile type `%v`", id))
 	}
 	tile, err := ctor()
 	if err != nil {
 		return nil, err
 	}
+	err = validateParams(tile, params)
+	if err != nil {
+		return nil, err
+	}
 	err = tile.Parse(params)
 	if err != nil {
 		return nil, err
@@ -144,16 +167,20 @@ func (p *Pipeline) GetTile(id string, args interface{}) (Tile, error) {
 func (p *Pipeline) GetMeta(id string, args interface{}) (Meta, error) {
 	params, ok := args.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("`%s` is not of correct type", id)
+		return nil, typeError("", json.ObjectType, fmt.Sprintf("`%s` is not of correct type", id))
 	}
 	ctor, ok := p.metas[id]
 	if !ok {
-		return nil, fmt.Errorf("unrecognized meta type `%v`", id)
+		return nil, json.NewFieldError("", json.UnknownCode, fmt.Sprintf("unrecognized meta type `%v`", id))
 	}
 	meta, err := ctor()
 	if err != nil {
 		return nil, err
 	}
+	err = validateParams(meta, params)
+	if err != nil {
+		return nil, err
+	}
 	err = meta.Parse(params)
 	if err != nil {
 		return nil, err
@@ -185,7 +212,7 @@ func (p *Pipeline) NewTileRequest(args map[string]interface{}) (*TileRequest, er
 	// validate request
 	req, err := newValidator(p).validateTileRequest(copy)
 	if err != nil {
-		return nil, fmt.Errorf("invalid tile request:\n%s", err)
+		return nil, fmt.Errorf("invalid tile request:\n%w", err)
 	}
 	return req, nil
 }
@@ -201,7 +228,7 @@ func (p *Pipeline) NewMetaRequest(args map[string]interface{}) (*MetaRequest, er
 	// validate request
 	req, err := newValidator(p).validateMetaRequest(copy)
 	if err != nil {
-		return nil, fmt.Errorf("invalid meta request:\n%s", err)
+		return nil, fmt.Errorf("invalid meta request:\n%w", err)
 	}
 	return req, nil
 }
@@ -0,0 +1,201 @@
+package veldt
+
+import (
+	"math"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+type bivariateTile interface {
+	BivariateTile() *tile.Bivariate
+}
+
+// queryBivariateTile represents a bivariate tile which reads data beyond the
+// bounds of the tile coord, such as a tile padded by neighbouring bins.
+type queryBivariateTile interface {
+	QueryBivariate(coord *binning.TileCoord) *tile.Bivariate
+}
+
+// pruneResult represents the evaluation of a query within the bounds of a
+// tile.
+type pruneResult int
+
+const (
+	// pruneUnknown indicates the query must be evaluated by the backend.
+	pruneUnknown pruneResult = iota
+	// pruneNone indicates the query matches no data within the tile.
+	pruneNone
+	// pruneAll indicates the query matches all data within the tile.
+	pruneAll
+)
+
+// interval represents a closed interval of a tiling field.
+type interval struct {
+	field string
+	min   float64
+	max   float64
+}
+
+// PruneQuery evaluates any ranges on the tiling fields of a bivariate tile
+// against the bounds the tile reads for the coord. Ranges that contain the
+// entire tile are removed from the query, and true is returned if the query provably
+// matches no data within the tile. If the tile is not bivariate the query is
+// returned unmodified.
+func (p *Pipeline) PruneQuery(q Query, t Tile, coord *binning.TileCoord) (Query, bool, error) {
+	if q == nil {
+		return nil, false, nil
+	}
+	bivariate, ok := t.(bivariateTile)
+	if !ok {
+		return q, false, nil
+	}
+	b := bivariate.BivariateTile()
+	if padded, ok := t.(queryBivariateTile); ok {
+		b = padded.QueryBivariate(coord)
+	}
+	intervals := tileIntervals(b, coord)
+	n, err := toNode(q, false)
+	if err != nil {
+		return nil, false, err
+	}
+	pruned, res, changed := prune(n, intervals)
+	switch {
+	case res == pruneNone:
+		return q, true, nil
+	case res == pruneAll:
+		return nil, false, nil
+	case !changed:
+		return q, false, nil
+	}
+	q, err = p.fromNode(pruned)
+	if err != nil {
+		return nil, false, err
+	}
+	return q, false, nil
+}
+
+// tileIntervals returns the intervals of the tiling fields covered by the
+// tile. Outside of a mercator projection, the bounds are widened to integers
+// as the backends truncate them.
+func tileIntervals(b *tile.Bivariate, coord *binning.TileCoord) []interval {
+	bounds := b.TileBounds(coord)
+	intervals := []interval{
+		{field: b.XField, min: bounds.MinX(), max: bounds.MaxX()},
+		{field: b.YField, min: bounds.MinY(), max: bounds.MaxY()},
+	}
+	if b.Projection != tile.MercatorProjection {
+		for i := range intervals {
+			intervals[i].min = math.Floor(intervals[i].min)
+			intervals[i].max = math.Ceil(intervals[i].max)
+		}
+	}
+	return intervals
+}
+
+// prune evaluates the tree against the intervals, returning the tree with any
+// operands which match all or no data removed, and whether it was changed.
+func prune(n *node, intervals []interval) (*node, pruneResult, bool) {
+	switch n.op {
+	case "":
+		return n, evalRange(n.query, intervals), false
+	case Not:
+		operand, res, changed := prune(n.operands[0], intervals)
+		switch res {
+		case pruneNone:
+			return n, pruneAll, true
+		case pruneAll:
+			return n, pruneNone, true
+		}
+		if changed {
+			n.operands[0] = operand
+		}
+		return n, pruneUnknown, changed
+	}
+	// an AND is decided by any operand matching nothing, an OR by any
+	// operand matching everything
+	decided, neutral := pruneNone, pruneAll
+	if n.op == Or {
+		decided, neutral = pruneAll, pruneNone
+	}
+	operands := make([]*node, 0, len(n.operands))
+	changed := false
+	for _, operand := range n.operands {
+		operand, res, c := prune(operand, intervals)
+		if res == decided {
+			return n, decided, true
+		}
+		if res == neutral {
+			changed = true
+			continue
+		}
+		changed = changed || c
+		operands = append(operands, operand)
+	}
+	switch len(operands) {
+	case 0:
+		return n, neutral, true
+	case 1:
+		return operands[0], pruneUnknown, true
+	}
+	n.operands = operands
+	return n, pruneUnknown, changed
+}
+
+// evalRange evaluates a range query on a tiling field against its interval.
+// Only numeric bounds are evaluated.
+func evalRange(q Query, intervals []interval) pruneResult {
+	r, ok := q.(rangeQuery)
+	if !ok {
+		return pruneUnknown
+	}
+	rng := r.RangeQuery()
+	for _, in := range intervals {
+		if in.field != rng.Field {
+			continue
+		}
+		lower, lowerExclusive, lowerOk := rangeBound(rng.GT, rng.GTE)
+		upper, upperExclusive, upperOk := rangeBound(rng.LT, rng.LTE)
+		if !lowerOk || !upperOk {
+			return pruneUnknown
+		}
+		// check if the range is empty
+		if lower != nil && upper != nil &&
+			(*lower > *upper || (*lower == *upper && (lowerExclusive || upperExclusive))) {
+			return pruneNone
+		}
+		// check if the range is disjoint from the interval
+		if lower != nil && (*lower > in.max || (*lower == in.max && lowerExclusive)) {
+			return pruneNone
+		}
+		if upper != nil && (*upper < in.min || (*upper == in.min && upperExclusive)) {
+			return pruneNone
+		}
+		// check if the range contains the interval
+		containsMin := lower == nil || *lower < in.min || (*lower == in.min && !lowerExclusive)
+		containsMax := upper == nil || *upper > in.max || (*upper == in.max && !upperExclusive)
+		if containsMin && containsMax {
+			return pruneAll
+		}
+		return pruneUnknown
+	}
+	return pruneUnknown
+}
+
+// rangeBound returns the numeric value of an exclusive / inclusive bound
+// pair, which is nil if the bound is unset. If the bound is not numeric false
+// is returned.
+func rangeBound(exclusive interface{}, inclusive interface{}) (*float64, bool, bool) {
+	val, isExclusive := inclusive, false
+	if exclusive != nil {
+		val, isExclusive = exclusive, true
+	}
+	if val == nil {
+		return nil, false, true
+	}
+	f, ok := toFloat(val)
+	if !ok {
+		return nil, false, false
+	}
+	return &f, isExclusive, true
+}
@@ -0,0 +1,203 @@
+package veldt_test
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/query"
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type bivariateStubTile struct {
+	tile.Bivariate
+}
+
+func (t *bivariateStubTile) Parse(params map[string]interface{}) error {
+	return t.Bivariate.Parse(params)
+}
+
+func (t *bivariateStubTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte("backend"), nil
+}
+
+func (t *bivariateStubTile) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return []byte("empty"), nil
+}
+
+type hexbinStubTile struct {
+	bivariateStubTile
+	tile.Hexbin
+}
+
+func (t *hexbinStubTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Hexbin.Parse(params)
+}
+
+func (t *hexbinStubTile) QueryBivariate(coord *binning.TileCoord) *tile.Bivariate {
+	return t.Bivariate.PaddedBivariate(coord, t.Hexbin.Padding(t.Resolution))
+}
+
+var _ = Describe("PruneQuery", func() {
+
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		pipeline = veldt.NewPipeline()
+		pipeline.Binary(func() (veldt.Query, error) {
+			return &veldt.BinaryExpression{}, nil
+		})
+		pipeline.Nary(func() (veldt.Query, error) {
+			return &veldt.NaryExpression{}, nil
+		})
+		pipeline.Unary(func() (veldt.Query, error) {
+			return &veldt.UnaryExpression{}, nil
+		})
+		pipeline.Query("equals", func() (veldt.Query, error) {
+			return &query.Equals{}, nil
+		})
+		pipeline.Query("range", func() (veldt.Query, error) {
+			return &query.Range{}, nil
+		})
+		pipeline.Tile("bivariate", func() (veldt.Tile, error) {
+			return &bivariateStubTile{}, nil
+		})
+		pipeline.Tile("hexbin", func() (veldt.Tile, error) {
+			return &hexbinStubTile{}, nil
+		})
+		pipeline.Tile("stub", func() (veldt.Tile, error) {
+			return &stubTile{}, nil
+		})
+	})
+
+	// the tile spans [0, 128] along both the x and y fields
+	linear := map[string]interface{}{
+		"bivariate": map[string]interface{}{
+			"xField": "x",
+			"yField": "y",
+			"left":   0.0,
+			"right":  256.0,
+			"bottom": 0.0,
+			"top":    256.0,
+		},
+	}
+
+	// the tile spans [0, 127.5] along both the x and y fields
+	fractional := map[string]interface{}{
+		"bivariate": map[string]interface{}{
+			"xField": "x",
+			"yField": "y",
+			"left":   0.0,
+			"right":  255.0,
+			"bottom": 0.0,
+			"top":    255.0,
+		},
+	}
+
+	// the tile spans [-180, 0] longitude
+	mercator := map[string]interface{}{
+		"bivariate": map[string]interface{}{
+			"xField":     "lon",
+			"yField":     "lat",
+			"projection": "mercator",
+		},
+	}
+
+	request := func(t map[string]interface{}, q string) *veldt.TileRequest {
+		req, err := pipeline.NewTileRequest(map[string]interface{}{
+			"uri":   "test",
+			"coord": map[string]interface{}{"x": 0.0, "y": 0.0, "z": 1.0},
+			"tile":  t,
+			"query": q,
+		})
+		Expect(err).To(BeNil())
+		return req
+	}
+
+	create := func(req *veldt.TileRequest) string {
+		res, err := req.Create()
+		Expect(err).To(BeNil())
+		return string(res)
+	}
+
+	It("should short-circuit tiles disjoint from a tiling range", func() {
+		Expect(create(request(linear, `x > 200`))).To(Equal("empty"))
+		Expect(create(request(linear, `y < 0`))).To(Equal("empty"))
+		Expect(create(request(linear, `x > 128`))).To(Equal("empty"))
+		Expect(create(request(linear, `x > 100 AND x < 50`))).To(Equal("empty"))
+		Expect(create(request(linear, `x > 200 AND type = "a"`))).To(Equal("empty"))
+		Expect(create(request(linear, `NOT x < 500`))).To(Equal("empty"))
+	})
+
+	It("should dispatch tiles intersecting a tiling range", func() {
+		req := request(linear, `x >= 128`)
+		Expect(create(req)).To(Equal("backend"))
+		Expect(req.Query).To(Equal(&query.Range{Field: "x", GTE: 128.0}))
+		Expect(create(request(linear, `y <= 0`))).To(Equal("backend"))
+		Expect(create(request(linear, `x > 200 OR type = "a"`))).To(Equal("backend"))
+	})
+
+	It("should widen linear tile bounds to integers", func() {
+		Expect(create(request(fractional, `x > 127.5`))).To(Equal("backend"))
+		Expect(create(request(fractional, `x >= 128.5`))).To(Equal("empty"))
+		Expect(request(fractional, `x <= 127.5 AND type = "a"`).Query).NotTo(Equal(
+			&query.Equals{Field: "type", Value: "a"},
+		))
+	})
+
+	It("should not widen mercator tile bounds", func() {
+		Expect(create(request(mercator, `lon > 0.5`))).To(Equal("empty"))
+		Expect(create(request(mercator, `lon >= 0`))).To(Equal("backend"))
+		Expect(request(mercator, `lon >= -180.5`).Query).To(BeNil())
+	})
+
+	It("should drop tiling ranges containing the tile", func() {
+		Expect(request(linear, `x >= 0 AND x <= 128 AND type = "a"`).Query).To(Equal(
+			&query.Equals{Field: "type", Value: "a"},
+		))
+		Expect(request(linear, `y > -10 AND (type = "a" OR x > 200)`).Query).To(Equal(
+			&query.Equals{Field: "type", Value: "a"},
+		))
+		Expect(request(linear, `x < 500 OR type = "a"`).Query).To(BeNil())
+		Expect(request(linear, `NOT x > 200`).Query).To(BeNil())
+	})
+
+	It("should not prune ranges on other fields", func() {
+		req := request(linear, `z > 200`)
+		Expect(create(req)).To(Equal("backend"))
+		Expect(req.Query).To(Equal(&query.Range{Field: "z", GT: 200.0}))
+	})
+
+	It("should prune against the padded bounds read by hexbin tiles", func() {
+		// the tile spans [64, 128] along the x field, padded to [60, 132]
+		hexbin := func(q string) *veldt.TileRequest {
+			req, err := pipeline.NewTileRequest(map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"x": 1.0, "y": 1.0, "z": 2.0},
+				"tile":  map[string]interface{}{"hexbin": linear["bivariate"]},
+				"query": q,
+			})
+			Expect(err).To(BeNil())
+			return req
+		}
+		req := hexbin(`x >= 64`)
+		Expect(create(req)).To(Equal("backend"))
+		Expect(req.Query).To(Equal(&query.Range{Field: "x", GTE: 64.0}))
+		Expect(create(hexbin(`x > 132`))).To(Equal("empty"))
+		Expect(create(hexbin(`x > 130`))).To(Equal("backend"))
+		Expect(hexbin(`x >= 60 AND x <= 132 AND type = "a"`).Query).To(Equal(
+			&query.Equals{Field: "type", Value: "a"},
+		))
+	})
+
+	It("should not prune queries of non-bivariate tiles", func() {
+		req := request(map[string]interface{}{"stub": map[string]interface{}{}}, `x > 200`)
+		Expect(req.Query).To(Equal(&query.Range{Field: "x", GT: 200.0}))
+	})
+
+})
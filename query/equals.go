@@ -27,3 +27,17 @@ func (q *Equals) Parse(params map[string]interface{}) error {
 	q.Value = value
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *Equals) Params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+		{Name: "value", Required: true},
+	}
+}
+
+// EqualsQuery returns the query, such that it is accessible through the types
+// embedding it.
+func (q *Equals) EqualsQuery() *Equals {
+	return q
+}
@@ -20,3 +20,10 @@ func (q *Exists) Parse(params map[string]interface{}) error {
 	q.Field = field
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *Exists) Params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+	}
+}
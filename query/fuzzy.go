@@ -0,0 +1,56 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// MaxFuzzyDistance is the maximum supported edit distance of a fuzzy
+	// query.
+	MaxFuzzyDistance = 2
+)
+
+// Fuzzy represents a query checking if the string value of the field is
+// within a Levenshtein distance of the provided value. Each insertion,
+// deletion or substitution of a character is a single edit, a transposition
+// of two characters is two edits. The distance defaults to, and may not
+// exceed, 2. Matching is case-sensitive unless `caseSensitive` is false.
+type Fuzzy struct {
+	Field         string
+	Value         string
+	Distance      int
+	CaseSensitive bool
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Fuzzy) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	value, ok := json.GetString(params, "value")
+	if !ok {
+		return fmt.Errorf("`value` parameter missing from query")
+	}
+	distance := json.GetIntDefault(params, MaxFuzzyDistance, "distance")
+	if distance < 0 || distance > MaxFuzzyDistance {
+		return fmt.Errorf("`distance` parameter must be in the range [0 : %d]", MaxFuzzyDistance)
+	}
+	q.Field = field
+	q.Value = value
+	q.Distance = distance
+	q.CaseSensitive = json.GetBoolDefault(params, true, "caseSensitive")
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *Fuzzy) Params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+		{Name: "value", Type: json.StringType, Required: true},
+		{Name: "distance", Type: json.IntegerType, Default: MaxFuzzyDistance},
+		{Name: "caseSensitive", Type: json.BooleanType, Default: true},
+	}
+}
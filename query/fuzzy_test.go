@@ -0,0 +1,57 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Fuzzy", func() {
+
+	var fuzzy *query.Fuzzy
+
+	BeforeEach(func() {
+		fuzzy = &query.Fuzzy{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "kitten",
+					"distance": 1
+				}`)
+			err := fuzzy.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(fuzzy.Field).To(Equal("name"))
+			Expect(fuzzy.Value).To(Equal("kitten"))
+			Expect(fuzzy.Distance).To(Equal(1))
+		})
+
+		It("should default `distance` to the maximum distance", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "kitten"
+				}`)
+			err := fuzzy.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(fuzzy.Distance).To(Equal(query.MaxFuzzyDistance))
+		})
+
+		It("should return an error if `distance` is out of range", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "kitten",
+					"distance": 3
+				}`)
+			err := fuzzy.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// GeoBBox represents a query checking if the point is within a bounding box.
+// For lon / lat point fields, left and right are longitudes, and bottom and
+// top are latitudes.
+type GeoBBox struct {
+	GeoField
+	Bounds *geometry.Bounds
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *GeoBBox) Parse(params map[string]interface{}) error {
+	err := q.GeoField.Parse(params)
+	if err != nil {
+		return err
+	}
+	b, ok := json.GetChild(params, "bounds")
+	if !ok {
+		return fmt.Errorf("`bounds` parameter missing from query")
+	}
+	bounds := &geometry.Bounds{}
+	err = bounds.Parse(b)
+	if err != nil {
+		return err
+	}
+	corners := []geometry.Coord{
+		{X: bounds.Left, Y: bounds.Bottom},
+		{X: bounds.Right, Y: bounds.Top},
+	}
+	for _, corner := range corners {
+		err = q.validateCoord(corner)
+		if err != nil {
+			return err
+		}
+	}
+	q.Bounds = bounds
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *GeoBBox) Params() []json.Param {
+	return append([]json.Param{
+		{Name: "bounds", Type: json.ObjectType, Required: true},
+	}, q.GeoField.params()...)
+}
@@ -0,0 +1,114 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("GeoBBox", func() {
+
+	var bbox *query.GeoBBox
+
+	BeforeEach(func() {
+		bbox = &query.GeoBBox{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"bounds": {
+						"left": -80.0,
+						"right": -70.0,
+						"bottom": 40.0,
+						"top": 45.0
+					}
+				}`)
+			err := bbox.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(bbox.Field).To(Equal("location"))
+			Expect(bbox.IsLonLat()).To(BeTrue())
+			Expect(bbox.Bounds.MinX()).To(Equal(-80.0))
+			Expect(bbox.Bounds.MaxY()).To(Equal(45.0))
+		})
+
+		It("should parse data-space coordinate fields", func() {
+			params := JSON(
+				`{
+					"xField": "pixel.x",
+					"yField": "pixel.y",
+					"bounds": {
+						"left": 0.0,
+						"right": 4294967296.0,
+						"bottom": 0.0,
+						"top": 4294967296.0
+					}
+				}`)
+			err := bbox.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(bbox.IsLonLat()).To(BeFalse())
+			Expect(bbox.XField).To(Equal("pixel.x"))
+			Expect(bbox.YField).To(Equal("pixel.y"))
+		})
+
+		It("should return an error if no field is specified", func() {
+			params := JSON(
+				`{
+					"bounds": {
+						"left": 0.0,
+						"right": 1.0,
+						"bottom": 0.0,
+						"top": 1.0
+					}
+				}`)
+			err := bbox.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if both `field` and `xField` are specified", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"xField": "x",
+					"yField": "y",
+					"bounds": {
+						"left": 0.0,
+						"right": 1.0,
+						"bottom": 0.0,
+						"top": 1.0
+					}
+				}`)
+			err := bbox.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `bounds` property is not specified", func() {
+			params := JSON(
+				`{
+					"field": "location"
+				}`)
+			err := bbox.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if lon / lat bounds are out of range", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"bounds": {
+						"left": 0.0,
+						"right": 1.0,
+						"bottom": 0.0,
+						"top": 91.0
+					}
+				}`)
+			err := bbox.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// GeoDistance represents a query checking if the point is within a distance
+// of a center point. For lon / lat point fields, the distance is in meters,
+// otherwise it is in data-space units.
+type GeoDistance struct {
+	GeoField
+	Center   *geometry.Coord
+	Distance float64
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *GeoDistance) Parse(params map[string]interface{}) error {
+	err := q.GeoField.Parse(params)
+	if err != nil {
+		return err
+	}
+	c, ok := json.GetChild(params, "center")
+	if !ok {
+		return fmt.Errorf("`center` parameter missing from query")
+	}
+	center, err := parseCoord(c)
+	if err != nil {
+		return err
+	}
+	err = q.validateCoord(*center)
+	if err != nil {
+		return err
+	}
+	distance, ok := json.GetFloat(params, "distance")
+	if !ok {
+		return fmt.Errorf("`distance` parameter missing from query")
+	}
+	if distance < 0 {
+		return fmt.Errorf("`distance` parameter must not be negative")
+	}
+	q.Center = center
+	q.Distance = distance
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *GeoDistance) Params() []json.Param {
+	return append([]json.Param{
+		{Name: "center", Type: json.ObjectType, Required: true},
+		{Name: "distance", Type: json.NumberType, Required: true},
+	}, q.GeoField.params()...)
+}
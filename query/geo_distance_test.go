@@ -0,0 +1,69 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("GeoDistance", func() {
+
+	var distance *query.GeoDistance
+
+	BeforeEach(func() {
+		distance = &query.GeoDistance{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"center": { "x": -75.0, "y": 45.0 },
+					"distance": 1000.0
+				}`)
+			err := distance.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(distance.Field).To(Equal("location"))
+			Expect(distance.Center.X).To(Equal(-75.0))
+			Expect(distance.Center.Y).To(Equal(45.0))
+			Expect(distance.Distance).To(Equal(1000.0))
+		})
+
+		It("should return an error if `center` property is not specified", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"distance": 1000.0
+				}`)
+			err := distance.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `distance` property is not specified", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"center": { "x": 0.0, "y": 0.0 }
+				}`)
+			err := distance.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `distance` is negative", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"center": { "x": 0.0, "y": 0.0 },
+					"distance": -1.0
+				}`)
+			err := distance.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
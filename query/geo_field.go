@@ -0,0 +1,84 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// GeoField represents the point field of a geospatial query. The point is
+// either a single lon / lat point field, or a pair of data-space coordinate
+// fields.
+type GeoField struct {
+	Field  string
+	XField string
+	YField string
+}
+
+// Parse parses the provided JSON object and populates the fields attributes.
+func (g *GeoField) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if ok {
+		if json.Exists(params, "xField") || json.Exists(params, "yField") {
+			return fmt.Errorf("both `field` and `xField` / `yField` have been provided, only one point may be provided")
+		}
+		g.Field = field
+		return nil
+	}
+	xField, ok := json.GetString(params, "xField")
+	if !ok {
+		return fmt.Errorf("`field` or `xField` parameter missing from query")
+	}
+	yField, ok := json.GetString(params, "yField")
+	if !ok {
+		return fmt.Errorf("`yField` parameter missing from query")
+	}
+	g.XField = xField
+	g.YField = yField
+	return nil
+}
+
+// params returns the descriptions of the point field parameters, where either
+// `field` or both `xField` and `yField` are required.
+func (g *GeoField) params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Description: "lon / lat point field"},
+		{Name: "xField", Type: json.StringType, Description: "data-space x coordinate field"},
+		{Name: "yField", Type: json.StringType, Description: "data-space y coordinate field"},
+	}
+}
+
+// IsLonLat returns true if the query is on a lon / lat point field rather than
+// on data-space coordinate fields.
+func (g *GeoField) IsLonLat() bool {
+	return g.Field != ""
+}
+
+// validateCoord returns an error if the coord is not a valid lon / lat point
+// for a lon / lat point field.
+func (g *GeoField) validateCoord(coord geometry.Coord) error {
+	if !g.IsLonLat() {
+		return nil
+	}
+	if coord.X < -180 || coord.X > 180 {
+		return fmt.Errorf("longitude %v is outside of the range [-180 : 180]", coord.X)
+	}
+	if coord.Y < -90 || coord.Y > 90 {
+		return fmt.Errorf("latitude %v is outside of the range [-90 : 90]", coord.Y)
+	}
+	return nil
+}
+
+// parseCoord parses a coordinate of the form `{ "x": 0, "y": 0 }`.
+func parseCoord(params map[string]interface{}) (*geometry.Coord, error) {
+	x, ok := json.GetFloat(params, "x")
+	if !ok {
+		return nil, fmt.Errorf("`x` parameter missing from coordinate")
+	}
+	y, ok := json.GetFloat(params, "y")
+	if !ok {
+		return nil, fmt.Errorf("`y` parameter missing from coordinate")
+	}
+	return geometry.NewCoord(x, y), nil
+}
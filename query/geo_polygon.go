@@ -0,0 +1,56 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// GeoPolygon represents a query checking if the point is within a polygon.
+// For lon / lat point fields, the x and y of each vertex are the longitude
+// and latitude. The polygon is implicitly closed.
+type GeoPolygon struct {
+	GeoField
+	Points []geometry.Coord
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *GeoPolygon) Parse(params map[string]interface{}) error {
+	err := q.GeoField.Parse(params)
+	if err != nil {
+		return err
+	}
+	vertices, ok := json.GetChildArray(params, "points")
+	if !ok {
+		return fmt.Errorf("`points` parameter missing from query")
+	}
+	points := make([]geometry.Coord, 0, len(vertices))
+	for _, vertex := range vertices {
+		point, err := parseCoord(vertex)
+		if err != nil {
+			return err
+		}
+		err = q.validateCoord(*point)
+		if err != nil {
+			return err
+		}
+		points = append(points, *point)
+	}
+	// drop the closing point if provided
+	if len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) < 3 {
+		return fmt.Errorf("`points` parameter must contain at least 3 distinct points")
+	}
+	q.Points = points
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *GeoPolygon) Params() []json.Param {
+	return append([]json.Param{
+		{Name: "points", Type: json.ArrayType, Items: json.ObjectType, Required: true},
+	}, q.GeoField.params()...)
+}
@@ -0,0 +1,96 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("GeoPolygon", func() {
+
+	var polygon *query.GeoPolygon
+
+	BeforeEach(func() {
+		polygon = &query.GeoPolygon{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"points": [
+						{ "x": -80.0, "y": 40.0 },
+						{ "x": -70.0, "y": 40.0 },
+						{ "x": -75.0, "y": 45.0 }
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(polygon.Field).To(Equal("location"))
+			Expect(polygon.Points).To(Equal([]geometry.Coord{
+				{X: -80, Y: 40},
+				{X: -70, Y: 40},
+				{X: -75, Y: 45},
+			}))
+		})
+
+		It("should drop the closing point", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"points": [
+						{ "x": 0.0, "y": 0.0 },
+						{ "x": 10.0, "y": 0.0 },
+						{ "x": 10.0, "y": 10.0 },
+						{ "x": 0.0, "y": 0.0 }
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(len(polygon.Points)).To(Equal(3))
+		})
+
+		It("should return an error if `points` property is not specified", func() {
+			params := JSON(
+				`{
+					"field": "location"
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if less than 3 points are specified", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"points": [
+						{ "x": 0.0, "y": 0.0 },
+						{ "x": 10.0, "y": 0.0 },
+						{ "x": 0.0, "y": 0.0 }
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if a point is missing a coordinate", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"points": [
+						{ "x": 0.0, "y": 0.0 },
+						{ "x": 10.0 },
+						{ "x": 10.0, "y": 10.0 }
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
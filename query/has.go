@@ -27,3 +27,17 @@ func (q *Has) Parse(params map[string]interface{}) error {
 	q.Values = values
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *Has) Params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+		{Name: "values", Type: json.ArrayType, Required: true},
+	}
+}
+
+// HasQuery returns the query, such that it is accessible through the types
+// embedding it.
+func (q *Has) HasQuery() *Has {
+	return q
+}
@@ -8,10 +8,14 @@ import (
 
 // MatchesString query represents a raw string query. The string could be
 // A regular expression, or Lucene query, etc. (depends on the implementation
-// support chosen.)
+// support chosen.) For consistent semantics across implementations, use the
+// Prefix, Wildcard, Regexp and Fuzzy queries instead.
 type MatchesString struct {
 	Match  string
 	Fields []string
+	// Regex indicates whether the match string is a regular expression, for
+	// implementations which otherwise treat it as a plain string.
+	Regex bool
 }
 
 // Parse parses the provided JSON object and populates the querys attributes.
@@ -26,5 +30,15 @@ func (q *MatchesString) Parse(params map[string]interface{}) error {
 	}
 	q.Fields = fields
 	q.Match = match
+	q.Regex = json.GetBoolDefault(params, false, "regex")
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *MatchesString) Params() []json.Param {
+	return []json.Param{
+		{Name: "match", Type: json.StringType, Required: true},
+		{Name: "fields", Type: json.ArrayType, Items: json.StringType, Required: true},
+		{Name: "regex", Type: json.BooleanType, Default: false, Description: "treat `match` as a regular expression rather than a plain string"},
+	}
+}
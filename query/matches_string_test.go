@@ -29,6 +29,19 @@ var _ = Describe("MatchesString", func() {
 			Expect(matchesString.Fields[0]).To(Equal("a"))
 			Expect(matchesString.Fields[1]).To(Equal("b"))
 			Expect(matchesString.Fields[2]).To(Equal("c"))
+			Expect(matchesString.Regex).To(BeFalse())
+		})
+
+		It("should parse the optional `regex` property", func() {
+			params := JSON(
+				`{
+					"match": "^a.*",
+					"fields": ["a"],
+					"regex": true
+				}`)
+			err := matchesString.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(matchesString.Regex).To(BeTrue())
 		})
 
 		It("should return an error if `match` property is not specified", func() {
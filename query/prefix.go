@@ -0,0 +1,41 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Prefix represents a query checking if the string value of the field starts
+// with the provided prefix. Characters of the prefix have no special meaning.
+// Matching is case-sensitive unless `caseSensitive` is false.
+type Prefix struct {
+	Field         string
+	Value         string
+	CaseSensitive bool
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Prefix) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	value, ok := json.GetString(params, "value")
+	if !ok {
+		return fmt.Errorf("`value` parameter missing from query")
+	}
+	q.Field = field
+	q.Value = value
+	q.CaseSensitive = json.GetBoolDefault(params, true, "caseSensitive")
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *Prefix) Params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+		{Name: "value", Type: json.StringType, Required: true},
+		{Name: "caseSensitive", Type: json.BooleanType, Default: true},
+	}
+}
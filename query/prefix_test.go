@@ -0,0 +1,61 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Prefix", func() {
+
+	var prefix *query.Prefix
+
+	BeforeEach(func() {
+		prefix = &query.Prefix{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "App",
+					"caseSensitive": false
+				}`)
+			err := prefix.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(prefix.Field).To(Equal("name"))
+			Expect(prefix.Value).To(Equal("App"))
+			Expect(prefix.CaseSensitive).To(BeFalse())
+		})
+
+		It("should be case-sensitive by default", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "App"
+				}`)
+			err := prefix.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(prefix.CaseSensitive).To(BeTrue())
+		})
+
+		It("should return an error if `field` property is not specified", func() {
+			params := JSON(`{}`)
+			err := prefix.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `value` property is not specified", func() {
+			params := JSON(
+				`{
+					"field": "name"
+				}`)
+			err := prefix.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
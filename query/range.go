@@ -42,3 +42,20 @@ func (q *Range) Parse(params map[string]interface{}) error {
 	q.LT = lt
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *Range) Params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+		{Name: "gte"},
+		{Name: "gt"},
+		{Name: "lte"},
+		{Name: "lt"},
+	}
+}
+
+// RangeQuery returns the query, such that it is accessible through the types
+// embedding it.
+func (q *Range) RangeQuery() *Range {
+	return q
+}
@@ -0,0 +1,153 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// regexpReserved are the characters which must be escaped to be matched
+	// literally outside of a character class.
+	regexpReserved = `.?+*|{}[]()"\^$`
+)
+
+var (
+	repetition = regexp.MustCompile(`^\{[0-9]+(,[0-9]*)?\}`)
+)
+
+// Regexp represents a query checking if the entire string value of the field
+// matches the provided regular expression. The pattern is implicitly anchored
+// at both ends and supports the syntax common to the supported backends:
+//
+//     .          any character
+//     ? + *      zero or one, one or more, zero or more repetitions
+//     {n,m}      between n and m repetitions, as {n}, {n,} or {n,m}
+//     |          alternation
+//     ( )        grouping
+//     [a-z] [^a] character classes, with ranges and negation
+//     \c         the non-alphanumeric character c, literally
+//
+// The characters `.?+*|{}[]()"\^$` must be escaped to be matched literally,
+// and escapes of alphanumeric characters, such as `\d`, are not supported.
+// Matching is case-sensitive unless `caseSensitive` is false.
+type Regexp struct {
+	Field         string
+	Value         string
+	CaseSensitive bool
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Regexp) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	value, ok := json.GetString(params, "value")
+	if !ok {
+		return fmt.Errorf("`value` parameter missing from query")
+	}
+	err := validateRegexp(value)
+	if err != nil {
+		return err
+	}
+	q.Field = field
+	q.Value = value
+	q.CaseSensitive = json.GetBoolDefault(params, true, "caseSensitive")
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *Regexp) Params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+		{Name: "value", Type: json.StringType, Required: true},
+		{Name: "caseSensitive", Type: json.BooleanType, Default: true},
+	}
+}
+
+// validateRegexp returns an error if the pattern uses syntax outside of the
+// common subset.
+func validateRegexp(pattern string) error {
+	runes := []rune(pattern)
+	inClass := false
+	classSize := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\\' {
+			if i+1 == len(runes) {
+				return fmt.Errorf("`value` parameter ends with an unescaped `\\`")
+			}
+			next := runes[i+1]
+			if unicode.IsLetter(next) || unicode.IsDigit(next) {
+				return fmt.Errorf("`value` parameter contains unsupported escape `\\%c`", next)
+			}
+			i++
+			classSize++
+			continue
+		}
+		if inClass {
+			switch {
+			case r == ']':
+				if classSize == 0 {
+					return fmt.Errorf("`value` parameter contains an empty character class")
+				}
+				inClass = false
+			case r == '^' && classSize == 0 && runes[i-1] == '[':
+				// negation
+			case r == '[' || r == '^':
+				return fmt.Errorf("`value` parameter contains unescaped `%c` within a character class", r)
+			case r == '-' && (classSize == 0 || i+1 == len(runes) || runes[i+1] == ']'):
+				return fmt.Errorf("`value` parameter contains unescaped `-` at the boundary of a character class")
+			default:
+				classSize++
+			}
+			continue
+		}
+		switch r {
+		case '[':
+			inClass = true
+			classSize = 0
+		case '{':
+			// braces are only valid as a repetition
+			match := repetition.FindString(string(runes[i:]))
+			if match == "" {
+				return fmt.Errorf("`value` parameter contains unescaped `{` outside of a repetition")
+			}
+			i += len(match) - 1
+		case '}':
+			return fmt.Errorf("`value` parameter contains unescaped `}` outside of a repetition")
+		case '"', '^', '$':
+			return fmt.Errorf("`value` parameter contains unescaped `%c`", r)
+		case '(':
+			if i+1 < len(runes) && runes[i+1] == '?' {
+				return fmt.Errorf("`value` parameter contains unsupported group syntax `(?`")
+			}
+		}
+	}
+	if inClass {
+		return fmt.Errorf("`value` parameter contains an unterminated character class")
+	}
+	// the structure of the common subset is valid perl syntax
+	_, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return fmt.Errorf("`value` parameter is not a valid regular expression: %v", err)
+	}
+	return nil
+}
+
+// QuoteRegexp returns a pattern matching the provided string literally.
+func QuoteRegexp(str string) string {
+	var sb strings.Builder
+	for _, r := range str {
+		if strings.ContainsRune(regexpReserved, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package query_test
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Regexp", func() {
+
+	var rx *query.Regexp
+
+	BeforeEach(func() {
+		rx = &query.Regexp{}
+	})
+
+	parse := func(pattern string) error {
+		return rx.Parse(map[string]interface{}{
+			"field": "name",
+			"value": pattern,
+		})
+	}
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			err := parse("gr[ae]y")
+			Expect(err).To(BeNil())
+			Expect(rx.Field).To(Equal("name"))
+			Expect(rx.Value).To(Equal("gr[ae]y"))
+			Expect(rx.CaseSensitive).To(BeTrue())
+		})
+
+		It("should accept the common syntax", func() {
+			for _, pattern := range []string{
+				"a.b*c+d?",
+				"(ab|cd){2}x{1,}y{1,3}",
+				"[^a-z0-9\\-]+",
+				"\\(\\$5\\.00\\)\\^\\\"",
+				"a@b#c~d<e>f&g",
+			} {
+				Expect(parse(pattern)).To(BeNil(), pattern)
+			}
+		})
+
+		It("should return an error for syntax outside of the common subset", func() {
+			for _, pattern := range []string{
+				"^abc",
+				"abc$",
+				"\\d+",
+				"a\"b",
+				"(?i)abc",
+				"a{b}",
+				"a}",
+				"[]",
+				"[a-]",
+				"[[:alpha:]]",
+				"[abc",
+				"(abc",
+				"**",
+				"abc\\",
+			} {
+				Expect(parse(pattern)).NotTo(BeNil(), fmt.Sprintf("%q", pattern))
+			}
+		})
+
+		It("should return an error if `value` property is not specified", func() {
+			err := rx.Parse(map[string]interface{}{
+				"field": "name",
+			})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("QuoteRegexp", func() {
+		It("should escape all reserved characters", func() {
+			Expect(query.QuoteRegexp(`a.b*"$`)).To(Equal(`a\.b\*\"\$`))
+			Expect(parse(query.QuoteRegexp(`.?+*|{}[]()"\^$`))).To(BeNil())
+		})
+	})
+
+})
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Wildcard represents a query checking if the entire string value of the
+// field matches the provided pattern. A `*` matches any sequence of
+// characters, including none, a `?` matches exactly one character, and a `\`
+// matches the following character literally. All other characters match
+// themselves. Matching is case-sensitive unless `caseSensitive` is false.
+type Wildcard struct {
+	Field         string
+	Value         string
+	CaseSensitive bool
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Wildcard) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	value, ok := json.GetString(params, "value")
+	if !ok {
+		return fmt.Errorf("`value` parameter missing from query")
+	}
+	trailing := len(value) - len(strings.TrimRight(value, `\`))
+	if trailing%2 == 1 {
+		return fmt.Errorf("`value` parameter ends with an unescaped `\\`")
+	}
+	q.Field = field
+	q.Value = value
+	q.CaseSensitive = json.GetBoolDefault(params, true, "caseSensitive")
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (q *Wildcard) Params() []json.Param {
+	return []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+		{Name: "value", Type: json.StringType, Required: true},
+		{Name: "caseSensitive", Type: json.BooleanType, Default: true},
+	}
+}
+
+// WildcardToken represents a single element of a wildcard pattern.
+type WildcardToken struct {
+	// Wildcard is one of `*` or `?`, or zero for a literal character.
+	Wildcard rune
+	Literal  rune
+}
+
+// Tokens returns the elements of the wildcard pattern, with escapes resolved.
+func (q *Wildcard) Tokens() []WildcardToken {
+	tokens := make([]WildcardToken, 0, len(q.Value))
+	escaped := false
+	for _, r := range q.Value {
+		switch {
+		case escaped:
+			tokens = append(tokens, WildcardToken{Literal: r})
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*' || r == '?':
+			tokens = append(tokens, WildcardToken{Wildcard: r})
+		default:
+			tokens = append(tokens, WildcardToken{Literal: r})
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,71 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Wildcard", func() {
+
+	var wildcard *query.Wildcard
+
+	BeforeEach(func() {
+		wildcard = &query.Wildcard{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "j*n?"
+				}`)
+			err := wildcard.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(wildcard.Field).To(Equal("name"))
+			Expect(wildcard.Value).To(Equal("j*n?"))
+			Expect(wildcard.CaseSensitive).To(BeTrue())
+		})
+
+		It("should return an error if `value` property is not specified", func() {
+			params := JSON(
+				`{
+					"field": "name"
+				}`)
+			err := wildcard.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `value` ends with an unescaped `\\`", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "a\\\\\\"
+				}`)
+			err := wildcard.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Tokens", func() {
+		It("should resolve wildcards and escapes", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "a*\\?\\\\"
+				}`)
+			err := wildcard.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(wildcard.Tokens()).To(Equal([]query.WildcardToken{
+				{Literal: 'a'},
+				{Wildcard: '*'},
+				{Literal: '?'},
+				{Literal: '\\'},
+			}))
+		})
+	})
+
+})
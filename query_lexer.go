@@ -0,0 +1,276 @@
+package veldt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	tokenEOF = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenIn
+	tokenTrue
+	tokenFalse
+	tokenNull
+	tokenEq
+	tokenNeq
+	tokenLt
+	tokenLte
+	tokenGt
+	tokenGte
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+	tokenColon
+)
+
+var (
+	keywords = map[string]int{
+		And:     tokenAnd,
+		Or:      tokenOr,
+		Not:     tokenNot,
+		"IN":    tokenIn,
+		"TRUE":  tokenTrue,
+		"FALSE": tokenFalse,
+		"NULL":  tokenNull,
+	}
+	symbols = map[string]int{
+		"=":  tokenEq,
+		"==": tokenEq,
+		"!=": tokenNeq,
+		"<":  tokenLt,
+		"<=": tokenLte,
+		">":  tokenGt,
+		">=": tokenGte,
+		"(":  tokenLParen,
+		")":  tokenRParen,
+		"[":  tokenLBracket,
+		"]":  tokenRBracket,
+		",":  tokenComma,
+		":":  tokenColon,
+	}
+)
+
+// token represents a single lexeme of a textual query, along with its byte
+// offset and length within the input.
+type token struct {
+	typ    int
+	text   string
+	value  interface{}
+	offset int
+	length int
+}
+
+// String returns the token as it appears in error messages.
+func (t token) String() string {
+	if t.typ == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("`%s`", t.text)
+}
+
+// lexer splits a textual query into tokens.
+type lexer struct {
+	input  string
+	offset int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{
+		input: input,
+	}
+}
+
+// tokenize returns all tokens of the input, terminated by an EOF token.
+func (l *lexer) tokenize() ([]token, error) {
+	tokens := make([]token, 0)
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.typ == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) peekRune() rune {
+	if l.offset >= len(l.input) {
+		return utf8.RuneError
+	}
+	r, _ := utf8.DecodeRuneInString(l.input[l.offset:])
+	return r
+}
+
+func (l *lexer) skipWhitespace() {
+	for l.offset < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.offset:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		l.offset += size
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipWhitespace()
+	start := l.offset
+	if l.offset >= len(l.input) {
+		return token{typ: tokenEOF, offset: start, length: 1}, nil
+	}
+	r := l.peekRune()
+	switch {
+	case r == '"' || r == '\'':
+		return l.lexString(r)
+	case r == '`':
+		return l.lexQuotedIdent()
+	case r == '-' || r == '+' || unicode.IsDigit(r):
+		return l.lexNumber()
+	case isIdentStart(r):
+		return l.lexIdent()
+	}
+	// two character symbols take precedence
+	if l.offset+2 <= len(l.input) {
+		if typ, ok := symbols[l.input[l.offset:l.offset+2]]; ok {
+			l.offset += 2
+			return l.token(typ, start), nil
+		}
+	}
+	if typ, ok := symbols[l.input[l.offset:l.offset+1]]; ok {
+		l.offset++
+		return l.token(typ, start), nil
+	}
+	_, size := utf8.DecodeRuneInString(l.input[l.offset:])
+	return token{}, newSyntaxError(l.input, start, size,
+		fmt.Sprintf("unexpected character `%c`", r))
+}
+
+func (l *lexer) token(typ int, start int) token {
+	return token{
+		typ:    typ,
+		text:   l.input[start:l.offset],
+		offset: start,
+		length: l.offset - start,
+	}
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '@' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '.' || r == '-'
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	start := l.offset
+	for l.offset < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.offset:])
+		if !isIdentPart(r) {
+			break
+		}
+		l.offset += size
+	}
+	tok := l.token(tokenIdent, start)
+	tok.value = tok.text
+	// keywords are case-insensitive
+	if typ, ok := keywords[strings.ToUpper(tok.text)]; ok {
+		tok.typ = typ
+		switch typ {
+		case tokenTrue:
+			tok.value = true
+		case tokenFalse:
+			tok.value = false
+		case tokenNull:
+			tok.value = nil
+		}
+	}
+	return tok, nil
+}
+
+func (l *lexer) lexQuotedIdent() (token, error) {
+	start := l.offset
+	end := strings.IndexRune(l.input[start+1:], '`')
+	if end == -1 {
+		return token{}, newSyntaxError(l.input, start, len(l.input)-start,
+			"unterminated identifier")
+	}
+	l.offset = start + end + 2
+	tok := l.token(tokenIdent, start)
+	tok.value = l.input[start+1 : start+end+1]
+	if tok.value == "" {
+		return token{}, newSyntaxError(l.input, start, tok.length, "empty identifier")
+	}
+	return tok, nil
+}
+
+func (l *lexer) lexString(quote rune) (token, error) {
+	start := l.offset
+	l.offset++
+	var sb strings.Builder
+	for l.offset < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.offset:])
+		l.offset += size
+		switch r {
+		case quote:
+			tok := l.token(tokenString, start)
+			tok.value = sb.String()
+			return tok, nil
+		case '\\':
+			if l.offset >= len(l.input) {
+				break
+			}
+			escaped, size := utf8.DecodeRuneInString(l.input[l.offset:])
+			l.offset += size
+			switch escaped {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case '\\', '"', '\'':
+				sb.WriteRune(escaped)
+			default:
+				return token{}, newSyntaxError(l.input, l.offset-size-1, size+1,
+					fmt.Sprintf("invalid escape sequence `\\%c`", escaped))
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return token{}, newSyntaxError(l.input, start, len(l.input)-start,
+		"unterminated string")
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.offset
+	for l.offset < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.offset:])
+		if !(unicode.IsDigit(r) || r == '.' || r == 'e' || r == 'E' ||
+			((r == '-' || r == '+') && (l.offset == start || l.input[l.offset-1] == 'e' || l.input[l.offset-1] == 'E'))) {
+			break
+		}
+		l.offset += size
+	}
+	tok := l.token(tokenNumber, start)
+	num, err := strconv.ParseFloat(tok.text, 64)
+	if err != nil {
+		return token{}, newSyntaxError(l.input, start, tok.length,
+			fmt.Sprintf("invalid number %s", tok))
+	}
+	tok.value = num
+	return tok, nil
+}
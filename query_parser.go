@@ -0,0 +1,462 @@
+package veldt
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/unchartedsoftware/veldt/util/color"
+)
+
+const (
+	// maxQueryDepth is the maximum nesting depth of parentheses, negations
+	// and arrays within a textual query.
+	maxQueryDepth = 64
+)
+
+// SyntaxError represents an error in a textual query, positioned at the
+// offending portion of the query.
+type SyntaxError struct {
+	Query   string
+	Offset  int
+	Length  int
+	Message string
+}
+
+func newSyntaxError(query string, offset int, length int, msg string) *SyntaxError {
+	return &SyntaxError{
+		Query:   query,
+		Offset:  offset,
+		Length:  length,
+		Message: msg,
+	}
+}
+
+// Line returns the 1-based line of the error.
+func (e *SyntaxError) Line() int {
+	return strings.Count(e.Query[:e.clampedOffset()], "\n") + 1
+}
+
+// Column returns the 1-based column of the error, in characters.
+func (e *SyntaxError) Column() int {
+	offset := e.clampedOffset()
+	start := strings.LastIndex(e.Query[:offset], "\n") + 1
+	return utf8.RuneCountInString(e.Query[start:offset]) + 1
+}
+
+func (e *SyntaxError) clampedOffset() int {
+	if e.Offset > len(e.Query) {
+		return len(e.Query)
+	}
+	return e.Offset
+}
+
+// Error returns the line of the query containing the error, annotated with
+// carets beneath the offending portion.
+func (e *SyntaxError) Error() string {
+	offset := e.clampedOffset()
+	start := strings.LastIndex(e.Query[:offset], "\n") + 1
+	end := strings.Index(e.Query[start:], "\n")
+	if end == -1 {
+		end = len(e.Query)
+	} else {
+		end += start
+	}
+	line := e.Query[start:end]
+	// preserve tabs such that the carets align
+	prefix := []rune(e.Query[start:offset])
+	for i, r := range prefix {
+		if r != '\t' {
+			prefix[i] = ' '
+		}
+	}
+	// underline the portion of the error within the line
+	length := e.Length
+	if offset+length > end {
+		length = end - offset
+	}
+	width := utf8.RuneCountInString(e.Query[offset : offset+length])
+	if width < 1 {
+		width = 1
+	}
+	annotation := fmt.Sprintf("%s%s Error: %s",
+		string(prefix),
+		strings.Repeat("^", width),
+		e.Message)
+	if color.ColorTerminal {
+		annotation = fmt.Sprintf("%s%s%s", color.Red, annotation, color.Reset)
+	}
+	return fmt.Sprintf("%s\n%s", line, annotation)
+}
+
+// queryParser parses a textual query into its runtime AST tree, using the
+// query types registered with the pipeline.
+//
+// The grammar, in order of increasing precedence, is:
+//
+//     expression := term ( OR term )*
+//     term       := factor ( AND factor )*
+//     factor     := NOT factor | '(' expression ')' | predicate
+//     predicate  := field ( '=' | '!=' | '<' | '<=' | '>' | '>=' ) value
+//                 | field [ NOT ] IN '[' value ( ',' value )* ']'
+//                 | id '(' [ field ] ( [ ',' ] key ':' value )* ')'
+//
+// Comparisons compile to the `equals` and `range` query types, `IN` compiles
+// to the `has` query type, and calls compile to the query type registered
+// under `id`, with the optional positional argument as its `field`.
+type queryParser struct {
+	pipeline *Pipeline
+	input    string
+	tokens   []token
+	pos      int
+	depth    int
+}
+
+// ParseQuery parses the textual query into its runtime AST tree.
+//
+// Ex:
+//     type = "tweet" AND (retweets >= 10 OR NOT exists(geo))
+//
+func (p *Pipeline) ParseQuery(str string) (Query, error) {
+	tokens, err := newLexer(str).tokenize()
+	if err != nil {
+		return nil, err
+	}
+	parser := &queryParser{
+		pipeline: p,
+		input:    str,
+		tokens:   tokens,
+	}
+	if parser.peek().typ == tokenEOF {
+		return nil, parser.errorAt(parser.peek(), "query is empty")
+	}
+	query, err := parser.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := parser.peek(); tok.typ != tokenEOF {
+		return nil, parser.errorAt(tok, fmt.Sprintf("unexpected %s", tok))
+	}
+	return query, nil
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) pop() token {
+	tok := p.tokens[p.pos]
+	if tok.typ != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) expect(typ int, desc string) (token, error) {
+	tok := p.pop()
+	if tok.typ != typ {
+		return tok, p.errorAt(tok, fmt.Sprintf("expected %s, found %s", desc, tok))
+	}
+	return tok, nil
+}
+
+func (p *queryParser) errorAt(tok token, msg string) error {
+	return newSyntaxError(p.input, tok.offset, tok.length, msg)
+}
+
+// enter descends into a nested portion of the query opened by the provided
+// token, returning an error if the query is nested too deeply.
+func (p *queryParser) enter(tok token) error {
+	p.depth++
+	if p.depth > maxQueryDepth {
+		return p.errorAt(tok, fmt.Sprintf("query is nested more than %d levels deep", maxQueryDepth))
+	}
+	return nil
+}
+
+// leave ascends from a nested portion of the query.
+func (p *queryParser) leave() {
+	p.depth--
+}
+
+// errorSpan returns an error spanning from the start token to the end token.
+func (p *queryParser) errorSpan(start token, end token, msg string) error {
+	return newSyntaxError(p.input, start.offset, end.offset+end.length-start.offset, msg)
+}
+
+func (p *queryParser) parseExpression() (Query, error) {
+	lhs, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().typ == tokenOr {
+		op := p.pop()
+		rhs, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		lhs, err = p.newBinary(op, lhs, Or, rhs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lhs, nil
+}
+
+func (p *queryParser) parseTerm() (Query, error) {
+	lhs, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().typ == tokenAnd {
+		op := p.pop()
+		rhs, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		lhs, err = p.newBinary(op, lhs, And, rhs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lhs, nil
+}
+
+func (p *queryParser) parseFactor() (Query, error) {
+	tok := p.peek()
+	switch tok.typ {
+	case tokenNot:
+		p.pop()
+		err := p.enter(tok)
+		if err != nil {
+			return nil, err
+		}
+		query, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		p.leave()
+		return p.newUnary(tok, query)
+	case tokenLParen:
+		p.pop()
+		err := p.enter(tok)
+		if err != nil {
+			return nil, err
+		}
+		query, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		p.leave()
+		_, err = p.expect(tokenRParen, "`)`")
+		if err != nil {
+			return nil, err
+		}
+		return query, nil
+	case tokenIdent:
+		return p.parsePredicate()
+	}
+	p.pop()
+	return nil, p.errorAt(tok, fmt.Sprintf("expected field or query, found %s", tok))
+}
+
+func (p *queryParser) parsePredicate() (Query, error) {
+	field := p.pop()
+	name := field.value.(string)
+	op := p.pop()
+	switch op.typ {
+	case tokenLParen:
+		return p.parseCall(field)
+	case tokenEq, tokenNeq:
+		value, end, err := p.parseScalar()
+		if err != nil {
+			return nil, err
+		}
+		query, err := p.newQuery(field, end, "equals", map[string]interface{}{
+			"field": name,
+			"value": value,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if op.typ == tokenNeq {
+			return p.newUnary(op, query)
+		}
+		return query, nil
+	case tokenLt, tokenLte, tokenGt, tokenGte:
+		value, end, err := p.parseScalar()
+		if err != nil {
+			return nil, err
+		}
+		bound := map[int]string{
+			tokenLt:  "lt",
+			tokenLte: "lte",
+			tokenGt:  "gt",
+			tokenGte: "gte",
+		}[op.typ]
+		return p.newQuery(field, end, "range", map[string]interface{}{
+			"field": name,
+			bound:   value,
+		})
+	case tokenIn:
+		return p.parseIn(field, nil)
+	case tokenNot:
+		in, err := p.expect(tokenIn, "`IN`")
+		if err != nil {
+			return nil, err
+		}
+		return p.parseIn(field, &in)
+	}
+	return nil, p.errorAt(op, fmt.Sprintf("expected comparison operator after field %s, found %s", field, op))
+}
+
+func (p *queryParser) parseIn(field token, not *token) (Query, error) {
+	values, end, err := p.parseArray()
+	if err != nil {
+		return nil, err
+	}
+	query, err := p.newQuery(field, end, "has", map[string]interface{}{
+		"field":  field.value.(string),
+		"values": values,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if not != nil {
+		return p.newUnary(*not, query)
+	}
+	return query, nil
+}
+
+func (p *queryParser) parseCall(id token) (Query, error) {
+	params := make(map[string]interface{})
+	// optional positional field
+	if p.peek().typ == tokenIdent && p.tokens[p.pos+1].typ != tokenColon {
+		params["field"] = p.pop().value
+		if p.peek().typ == tokenComma {
+			p.pop()
+		}
+	}
+	// named arguments
+	for p.peek().typ != tokenRParen {
+		key, err := p.expect(tokenIdent, "argument name")
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.expect(tokenColon, "`:`")
+		if err != nil {
+			return nil, err
+		}
+		name := key.value.(string)
+		if _, ok := params[name]; ok {
+			return nil, p.errorAt(key, fmt.Sprintf("duplicate argument %s", key))
+		}
+		value, _, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		params[name] = value
+		if p.peek().typ != tokenComma {
+			break
+		}
+		p.pop()
+	}
+	end, err := p.expect(tokenRParen, "`)`")
+	if err != nil {
+		return nil, err
+	}
+	return p.newQuery(id, end, id.value.(string), params)
+}
+
+// parseScalar parses a string, number or boolean literal.
+func (p *queryParser) parseScalar() (interface{}, token, error) {
+	tok := p.pop()
+	switch tok.typ {
+	case tokenString, tokenNumber, tokenTrue, tokenFalse:
+		return tok.value, tok, nil
+	}
+	return nil, tok, p.errorAt(tok, fmt.Sprintf("expected value, found %s", tok))
+}
+
+// parseValue parses a literal, including arrays and null.
+func (p *queryParser) parseValue() (interface{}, token, error) {
+	tok := p.peek()
+	switch tok.typ {
+	case tokenLBracket:
+		return p.parseArray()
+	case tokenNull:
+		p.pop()
+		return nil, tok, nil
+	}
+	return p.parseScalar()
+}
+
+func (p *queryParser) parseArray() ([]interface{}, token, error) {
+	start, err := p.expect(tokenLBracket, "`[`")
+	if err != nil {
+		return nil, token{}, err
+	}
+	err = p.enter(start)
+	if err != nil {
+		return nil, token{}, err
+	}
+	defer p.leave()
+	values := make([]interface{}, 0)
+	for p.peek().typ != tokenRBracket {
+		value, _, err := p.parseValue()
+		if err != nil {
+			return nil, token{}, err
+		}
+		values = append(values, value)
+		if p.peek().typ != tokenComma {
+			break
+		}
+		p.pop()
+	}
+	end, err := p.expect(tokenRBracket, "`,` or `]`")
+	if err != nil {
+		return nil, token{}, err
+	}
+	return values, end, nil
+}
+
+// newQuery instantiates the registered query type, positioning any error
+// over the span of the predicate.
+func (p *queryParser) newQuery(start token, end token, id string, params map[string]interface{}) (Query, error) {
+	query, err := p.pipeline.GetQuery(id, params)
+	if err != nil {
+		return nil, p.errorSpan(start, end, err.Error())
+	}
+	return query, nil
+}
+
+func (p *queryParser) newBinary(op token, left Query, operator string, right Query) (Query, error) {
+	binary, err := p.pipeline.GetBinary()
+	if err != nil {
+		return nil, p.errorAt(op, err.Error())
+	}
+	err = binary.Parse(map[string]interface{}{
+		"left":  left,
+		"op":    operator,
+		"right": right,
+	})
+	if err != nil {
+		return nil, p.errorAt(op, err.Error())
+	}
+	return binary, nil
+}
+
+func (p *queryParser) newUnary(op token, query Query) (Query, error) {
+	unary, err := p.pipeline.GetUnary()
+	if err != nil {
+		return nil, p.errorAt(op, err.Error())
+	}
+	err = unary.Parse(map[string]interface{}{
+		"op":    Not,
+		"query": query,
+	})
+	if err != nil {
+		return nil, p.errorAt(op, err.Error())
+	}
+	return unary, nil
+}
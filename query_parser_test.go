@@ -0,0 +1,242 @@
+package veldt_test
+
+import (
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/query"
+	"github.com/unchartedsoftware/veldt/util/color"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type stubTile struct{}
+
+func (t *stubTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *stubTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return nil, nil
+}
+
+var _ = Describe("ParseQuery", func() {
+
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		color.ColorTerminal = false
+		pipeline = veldt.NewPipeline()
+		pipeline.Binary(func() (veldt.Query, error) {
+			return &veldt.BinaryExpression{}, nil
+		})
+		pipeline.Unary(func() (veldt.Query, error) {
+			return &veldt.UnaryExpression{}, nil
+		})
+		pipeline.Query("equals", func() (veldt.Query, error) {
+			return &query.Equals{}, nil
+		})
+		pipeline.Query("range", func() (veldt.Query, error) {
+			return &query.Range{}, nil
+		})
+		pipeline.Query("has", func() (veldt.Query, error) {
+			return &query.Has{}, nil
+		})
+		pipeline.Query("exists", func() (veldt.Query, error) {
+			return &query.Exists{}, nil
+		})
+		pipeline.Query("matches", func() (veldt.Query, error) {
+			return &query.MatchesString{}, nil
+		})
+		pipeline.Tile("stub", func() (veldt.Tile, error) {
+			return &stubTile{}, nil
+		})
+	})
+
+	It("should compile comparisons into registered queries", func() {
+		q, err := pipeline.ParseQuery(`type = "tweet"`)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&query.Equals{Field: "type", Value: "tweet"}))
+
+		q, err = pipeline.ParseQuery(`retweets >= 10`)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&query.Range{Field: "retweets", GTE: 10.0}))
+
+		q, err = pipeline.ParseQuery(`lang IN ["en", 'fr']`)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&query.Has{Field: "lang", Values: []interface{}{"en", "fr"}}))
+
+		q, err = pipeline.ParseQuery("exists(`geo location`)")
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&query.Exists{Field: "geo location"}))
+
+		q, err = pipeline.ParseQuery(`matches(match: "*cat*", fields: ["text", "user.name"])`)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&query.MatchesString{Match: "*cat*", Fields: []string{"text", "user.name"}}))
+	})
+
+	It("should negate inequality and NOT IN", func() {
+		q, err := pipeline.ParseQuery(`type != "tweet"`)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&veldt.UnaryExpression{
+			Op:    veldt.Not,
+			Query: &query.Equals{Field: "type", Value: "tweet"},
+		}))
+
+		q, err = pipeline.ParseQuery(`lang not in ["en"]`)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&veldt.UnaryExpression{
+			Op:    veldt.Not,
+			Query: &query.Has{Field: "lang", Values: []interface{}{"en"}},
+		}))
+	})
+
+	It("should respect precedence and parentheses", func() {
+		q, err := pipeline.ParseQuery(`type = "tweet" AND (retweets >= 10 OR NOT exists(geo))`)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&veldt.BinaryExpression{
+			Left: &query.Equals{Field: "type", Value: "tweet"},
+			Op:   veldt.And,
+			Right: &veldt.BinaryExpression{
+				Left: &query.Range{Field: "retweets", GTE: 10.0},
+				Op:   veldt.Or,
+				Right: &veldt.UnaryExpression{
+					Op:    veldt.Not,
+					Query: &query.Exists{Field: "geo"},
+				},
+			},
+		}))
+
+		q, err = pipeline.ParseQuery(`a = 1 or b = 2 and c = true`)
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal(&veldt.BinaryExpression{
+			Left: &query.Equals{Field: "a", Value: 1.0},
+			Op:   veldt.Or,
+			Right: &veldt.BinaryExpression{
+				Left:  &query.Equals{Field: "b", Value: 2.0},
+				Op:    veldt.And,
+				Right: &query.Equals{Field: "c", Value: true},
+			},
+		}))
+	})
+
+	It("should produce the same tree as the JSON expression syntax", func() {
+		text, err := pipeline.ParseQuery(`a = 1 AND NOT b = 2 OR c = 3`)
+		Expect(err).To(BeNil())
+		req, err := pipeline.NewTileRequest(map[string]interface{}{
+			"uri":   "test",
+			"coord": map[string]interface{}{"x": 0.0, "y": 0.0, "z": 0.0},
+			"tile":  map[string]interface{}{"stub": map[string]interface{}{}},
+			"query": []interface{}{
+				map[string]interface{}{"equals": map[string]interface{}{"field": "a", "value": 1.0}},
+				"AND",
+				"NOT",
+				map[string]interface{}{"equals": map[string]interface{}{"field": "b", "value": 2.0}},
+				"OR",
+				map[string]interface{}{"equals": map[string]interface{}{"field": "c", "value": 3.0}},
+			},
+		})
+		Expect(err).To(BeNil())
+		// tile request queries are normalized
+		normalized, err := pipeline.NormalizeQuery(text)
+		Expect(err).To(BeNil())
+		Expect(req.Query).To(Equal(normalized))
+		Expect(text).To(Equal(&veldt.BinaryExpression{
+			Left: &veldt.BinaryExpression{
+				Left: &query.Equals{Field: "a", Value: 1.0},
+				Op:   veldt.And,
+				Right: &veldt.UnaryExpression{
+					Op:    veldt.Not,
+					Query: &query.Equals{Field: "b", Value: 2.0},
+				},
+			},
+			Op:    veldt.Or,
+			Right: &query.Equals{Field: "c", Value: 3.0},
+		}))
+	})
+
+	It("should return caret positioned syntax errors", func() {
+		_, err := pipeline.ParseQuery(`type = "tweet" AND (retweets >= 10 OR NOT exists(geo)`)
+		Expect(err).NotTo(BeNil())
+		syntaxErr, ok := err.(*veldt.SyntaxError)
+		Expect(ok).To(BeTrue())
+		Expect(syntaxErr.Column()).To(Equal(54))
+		Expect(err.Error()).To(Equal(strings.Join([]string{
+			`type = "tweet" AND (retweets >= 10 OR NOT exists(geo)`,
+			strings.Repeat(" ", 53) + "^ Error: expected `)`, found end of query",
+		}, "\n")))
+
+		_, err = pipeline.ParseQuery("a = 1 AND\n\tretweets >> 10")
+		Expect(err).NotTo(BeNil())
+		syntaxErr = err.(*veldt.SyntaxError)
+		Expect(syntaxErr.Line()).To(Equal(2))
+		Expect(syntaxErr.Column()).To(Equal(12))
+		Expect(err.Error()).To(Equal(strings.Join([]string{
+			"\tretweets >> 10",
+			"\t          ^ Error: expected value, found `>`",
+		}, "\n")))
+	})
+
+	It("should position errors of the query types over the predicate", func() {
+		_, err := pipeline.ParseQuery(`a = 1 AND near(geo, distance: 10)`)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(Equal(strings.Join([]string{
+			`a = 1 AND near(geo, distance: 10)`,
+			"          ^^^^^^^^^^^^^^^^^^^^^^^ Error: unrecognized query type `near`",
+		}, "\n")))
+	})
+
+	It("should return errors for malformed queries", func() {
+		for _, str := range []string{
+			``,
+			`a`,
+			`a = `,
+			`a = b`,
+			`a = "unterminated`,
+			`a = 1 b = 2`,
+			`(a = 1`,
+			`a IN [1, 2`,
+			`a = 1 AND`,
+			`NOT`,
+			`a = 1 # comment`,
+			`exists(a, a: 1, a: 2)`,
+		} {
+			_, err := pipeline.ParseQuery(str)
+			Expect(err).NotTo(BeNil(), str)
+			_, ok := err.(*veldt.SyntaxError)
+			Expect(ok).To(BeTrue(), str)
+		}
+	})
+
+	It("should return syntax errors for deeply nested queries", func() {
+		for _, str := range []string{
+			strings.Repeat("(", 10000) + "a = 1" + strings.Repeat(")", 10000),
+			strings.Repeat("NOT ", 10000) + "a = 1",
+			"a IN " + strings.Repeat("[", 10000) + "1" + strings.Repeat("]", 10000),
+		} {
+			_, err := pipeline.ParseQuery(str)
+			Expect(err).NotTo(BeNil())
+			syntaxErr, ok := err.(*veldt.SyntaxError)
+			Expect(ok).To(BeTrue())
+			Expect(syntaxErr.Message).To(ContainSubstring("nested"))
+		}
+	})
+
+	It("should accept nesting up to the maximum depth", func() {
+		_, err := pipeline.ParseQuery(strings.Repeat("(", 64) + "a = 1" + strings.Repeat(")", 64))
+		Expect(err).To(BeNil())
+	})
+
+	It("should accept textual queries in tile requests", func() {
+		_, err := pipeline.NewTileRequest(map[string]interface{}{
+			"uri":   "test",
+			"coord": map[string]interface{}{"x": 0.0, "y": 0.0, "z": 0.0},
+			"tile":  map[string]interface{}{"stub": map[string]interface{}{}},
+			"query": `a = 1 AND b >`,
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("expected value, found end of query at line 1, column 14"))
+	})
+})
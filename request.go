@@ -1,11 +1,13 @@
 package veldt
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/davecgh/go-spew/spew"
 
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
 )
 
 var (
@@ -38,10 +40,15 @@ type TileRequest struct {
 	Coord *binning.TileCoord
 	Query Query
 	Tile  Tile
+	// empty is set if the query provably matches no data within the tile
+	empty bool
 }
 
 // Create generates and returns the tile for the request.
 func (r *TileRequest) Create() ([]byte, error) {
+	if r.empty {
+		return r.Tile.(EmptyTile).Empty(r.Coord)
+	}
 	return r.Tile.Create(r.URI, r.Coord, r.Query)
 }
 
@@ -50,14 +57,26 @@ func (r *TileRequest) GetHash() string {
 	return strings.Join(strings.Fields(spewer.Sdump(r)), "")
 }
 
-// MetaRequest represents a meta data generation request.
+// MetaRequest represents a meta data generation request. The meta data may
+// optionally be restricted to the data matching the query, and to the tile
+// coord within the bounds of the scope.
 type MetaRequest struct {
-	URI  string
-	Meta Meta
+	URI   string
+	Scope *tile.Bivariate
+	Coord *binning.TileCoord
+	Query Query
+	Meta  Meta
 }
 
 // Create generates and returns the meta data for the request.
 func (r *MetaRequest) Create() ([]byte, error) {
+	if r.Scope != nil || r.Query != nil {
+		scoped, ok := r.Meta.(ScopedMeta)
+		if !ok {
+			return nil, fmt.Errorf("meta type does not support a `scope` or `query`")
+		}
+		return scoped.CreateScoped(r.URI, r.Scope, r.Coord, r.Query)
+	}
 	return r.Meta.Create(r.URI)
 }
 
@@ -18,6 +18,14 @@ type Tile interface {
 	Create(string, *binning.TileCoord, Query) ([]byte, error)
 }
 
+// EmptyTile represents a tile which can generate its payload for a tile coord
+// containing no data, without querying the backend.
+type EmptyTile interface {
+	// Empty returns the tile containing no data.
+	// parameter 1 (*binning.TileCoord): the coordinates of the requested tile
+	Empty(*binning.TileCoord) ([]byte, error)
+}
+
 // TileCtor represents a function that instantiates and returns a new tile
 // data type.
 type TileCtor func() (Tile, error)
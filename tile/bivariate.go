@@ -1,7 +1,6 @@
 package tile
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/unchartedsoftware/veldt/binning"
@@ -18,42 +17,129 @@ type Bivariate struct {
 	XField       string
 	YField       string
 	Resolution   int
+	Projection   string
+	padding      int
 	tileBounds   *geometry.Bounds
 	globalBounds *geometry.Bounds
 }
 
 // Parse parses the provided JSON object and populates the tiles attributes.
 func (b *Bivariate) Parse(params map[string]interface{}) error {
+	var errs json.Errors
 	// get x and y fields
 	xField, ok := json.GetString(params, "xField")
 	if !ok {
-		return fmt.Errorf("`xField` parameter missing from tile")
+		errs = append(errs, json.NewFieldError("xField", json.MissingCode,
+			"`xField` parameter missing from tile"))
 	}
 	yField, ok := json.GetString(params, "yField")
 	if !ok {
-		return fmt.Errorf("`yField` parameter missing from tile")
+		errs = append(errs, json.NewFieldError("yField", json.MissingCode,
+			"`yField` parameter missing from tile"))
 	}
 	// get resolution
 	resolution := json.GetIntDefault(params, 256, "resolution")
+	// get projection
+	projection, err := parseProjection(params)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	// report all errors at once
+	if len(errs) > 0 {
+		return errs
+	}
 	// set attributes
 	b.XField = xField
 	b.YField = yField
 	b.Resolution = resolution
+	b.Projection = projection
 	// clear tile bounds
 	b.tileBounds = nil
+	// mercator bounds are implied by the tile coord
+	if projection == MercatorProjection {
+		b.globalBounds = nil
+		return nil
+	}
 	// get the global bounds
 	b.globalBounds = &geometry.Bounds{}
 	return b.globalBounds.Parse(params)
 }
 
+// Params returns the descriptions of the parameters parsed by Parse.
+func (b *Bivariate) Params() []json.Param {
+	return append([]json.Param{
+		{Name: "xField", Type: json.StringType, Required: true},
+		{Name: "yField", Type: json.StringType, Required: true},
+		{Name: "resolution", Type: json.IntegerType, Default: 256},
+	}, projectionParams()...)
+}
+
+// BivariateTile returns the bivariate tiling parameters.
+func (b *Bivariate) BivariateTile() *Bivariate {
+	return b
+}
+
+// LevelBivariate returns the bivariate parameters which bin the entire tile
+// pyramid at the provided zoom level as the single root tile. The bins of the
+// root tile are those of every tile at the zoom level.
+func (b *Bivariate) LevelBivariate(zoom uint32) *Bivariate {
+	level := *b
+	level.Resolution = b.Resolution << zoom
+	level.padding = 0
+	level.tileBounds = nil
+	return &level
+}
+
+// PaddedBivariate returns the bivariate parameters which bin the tile
+// extended by the provided number of bins on each side. The bins are the
+// same size as those of the tile, such that the bin at index `padding` across
+// each axis is the first bin of the tile.
+func (b *Bivariate) PaddedBivariate(coord *binning.TileCoord, padding int) *Bivariate {
+	bounds := b.TileBounds(coord)
+	padded := *b
+	padded.Resolution = b.Resolution + padding*2
+	padded.padding = padding
+	// extend the bounds by the padding bins, respecting their orientation
+	padX := (bounds.Right - bounds.Left) / float64(b.Resolution) * float64(padding)
+	left := bounds.Left - padX
+	right := bounds.Right + padX
+	bottom := bounds.Bottom
+	top := bounds.Top
+	if b.Projection == MercatorProjection {
+		// latitude bins are not uniform, extend them in tile space
+		yBounds := mercatorYBinBounds(coord, padded.Resolution, padding)
+		bottom = yBounds[0]
+		top = yBounds[padded.Resolution]
+	} else {
+		padY := (bounds.Top - bounds.Bottom) / float64(b.Resolution) * float64(padding)
+		bottom -= padY
+		top += padY
+	}
+	padded.tileBounds = geometry.NewBounds(left, right, bottom, top)
+	return &padded
+}
+
 // TileBounds computes and returns the tile bounds for the provided tile coord.
 func (b *Bivariate) TileBounds(coord *binning.TileCoord) *geometry.Bounds {
 	if b.tileBounds == nil {
-		b.tileBounds = binning.GetTileBounds(coord, b.globalBounds)
+		if b.Projection == MercatorProjection {
+			b.tileBounds = binning.GetTileLonLatBounds(coord)
+		} else {
+			b.tileBounds = binning.GetTileBounds(coord, b.globalBounds)
+		}
 	}
 	return b.tileBounds
 }
 
+// GlobalBounds returns the bounds of the entire tile pyramid. Under a mercator
+// projection these are the lon / lat bounds of the world.
+func (b *Bivariate) GlobalBounds() *geometry.Bounds {
+	if b.Projection == MercatorProjection {
+		return binning.GetTileLonLatBounds(&binning.TileCoord{})
+	}
+	return b.globalBounds
+}
+
 // BinSizeX computes and returns the size of a bin across the x axis for the
 // provided tile coord.
 func (b *Bivariate) BinSizeX(coord *binning.TileCoord) float64 {
@@ -61,11 +147,30 @@ func (b *Bivariate) BinSizeX(coord *binning.TileCoord) float64 {
 }
 
 // BinSizeY computes and returns the size of a bin across the x axis for the
-// provided tile coord.
+// provided tile coord. Under a mercator projection bins vary in size across
+// the y axis, and this returns the average size.
 func (b *Bivariate) BinSizeY(coord *binning.TileCoord) float64 {
 	return b.TileBounds(coord).RangeY() / float64(b.Resolution)
 }
 
+// GetXBinBounds returns the boundaries of each bin across the x axis for the
+// provided tile coord, in ascending order.
+func (b *Bivariate) GetXBinBounds(coord *binning.TileCoord) []float64 {
+	bounds := b.TileBounds(coord)
+	return linearBinBounds(bounds.MinX(), bounds.MaxX(), b.Resolution)
+}
+
+// GetYBinBounds returns the boundaries of each bin across the y axis for the
+// provided tile coord, in ascending order. Under a mercator projection these
+// are computed using the inverse mercator transform.
+func (b *Bivariate) GetYBinBounds(coord *binning.TileCoord) []float64 {
+	if b.Projection == MercatorProjection {
+		return mercatorYBinBounds(coord, b.Resolution, b.padding)
+	}
+	bounds := b.TileBounds(coord)
+	return linearBinBounds(bounds.MinY(), bounds.MaxY(), b.Resolution)
+}
+
 // GetXBin given an x value, returns the corresponding bin.
 func (b *Bivariate) GetXBin(coord *binning.TileCoord, x float64) int {
 	bounds := b.TileBounds(coord)
@@ -92,6 +197,9 @@ func (b *Bivariate) GetX(coord *binning.TileCoord, x float64) float64 {
 
 // GetYBin given a y value, returns the corresponding bin.
 func (b *Bivariate) GetYBin(coord *binning.TileCoord, y float64) int {
+	if b.Projection == MercatorProjection {
+		return b.clampBin(floorBin(b.mercatorY(coord, y), b.Resolution))
+	}
 	bounds := b.TileBounds(coord)
 	binSize := b.BinSizeY(coord)
 	var bin int64
@@ -106,6 +214,9 @@ func (b *Bivariate) GetYBin(coord *binning.TileCoord, y float64) int {
 // GetY given an y value, returns the corresponding coord within the range of
 // [0 : 256) for the tile.
 func (b *Bivariate) GetY(coord *binning.TileCoord, y float64) float64 {
+	if b.Projection == MercatorProjection {
+		return b.mercatorY(coord, y) * binning.MaxTileResolution
+	}
 	bounds := b.TileBounds(coord)
 	rang := bounds.RangeY()
 	if bounds.Bottom > bounds.Top {
@@ -129,6 +240,16 @@ func (b *Bivariate) GetXY(coord *binning.TileCoord, hit map[string]interface{})
 	return tx, ty, true
 }
 
+// mercatorY given a latitude, returns the projected position within the
+// range of [0 : 1) for the tile, including any padding.
+func (b *Bivariate) mercatorY(coord *binning.TileCoord, lat float64) float64 {
+	if b.padding == 0 {
+		return mercatorY(coord, lat)
+	}
+	tileResolution := float64(b.Resolution - b.padding*2)
+	return (mercatorY(coord, lat)*tileResolution + float64(b.padding)) / float64(b.Resolution)
+}
+
 func (b *Bivariate) clampBin(bin int64) int {
 	if bin > int64(b.Resolution)-1 {
 		return b.Resolution - 1
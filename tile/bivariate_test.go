@@ -2,7 +2,9 @@ package tile_test
 
 import (
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/geometry"
 	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -101,6 +103,40 @@ var _ = Describe("Bivariate", func() {
 			err := bivariate.Parse(params)
 			Expect(err).NotTo(BeNil())
 		})
+		It("should not require bounds for a `mercator` projection", func() {
+			params := JSON(
+				`{
+					"xField": "lon",
+					"yField": "lat",
+					"projection": "mercator"
+				}`)
+			err := bivariate.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(bivariate.Projection).To(Equal("mercator"))
+		})
+
+		It("should return an error if `projection` is not supported", func() {
+			params := JSON(
+				`{
+					"xField": "lon",
+					"yField": "lat",
+					"projection": "albers"
+				}`)
+			err := bivariate.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should report all invalid properties at once", func() {
+			params := JSON(`{"projection": "albers"}`)
+			err := bivariate.Parse(params)
+			errs, ok := err.(json.Errors)
+			Expect(ok).To(Equal(true))
+			Expect(errs).To(HaveLen(3))
+			Expect(errs[0].Pointer).To(Equal("/xField"))
+			Expect(errs[1].Pointer).To(Equal("/yField"))
+			Expect(errs[2].Pointer).To(Equal("/projection"))
+			Expect(errs[2].Code).To(Equal(json.EnumCode))
+		})
 	})
 
 	Describe("TileBounds", func() {
@@ -130,6 +166,78 @@ var _ = Describe("Bivariate", func() {
 		})
 	})
 
+	Describe("LevelBivariate", func() {
+		It("should bin the zoom level within the root tile", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"left": 0,
+					"right": 256,
+					"bottom": 0,
+					"top": 256,
+					"resolution": 4
+				}`)
+			err := bivariate.Parse(params)
+			Expect(err).To(BeNil())
+			coord := &binning.TileCoord{Z: 2, X: 3, Y: 1}
+			level := bivariate.LevelBivariate(2)
+			root := &binning.TileCoord{}
+			Expect(level.Resolution).To(Equal(16))
+			Expect(level.TileBounds(root).Right).To(Equal(256.0))
+			Expect(level.BinSizeX(root)).To(Equal(bivariate.BinSizeX(coord)))
+			Expect(level.GetXBin(root, 200)).To(Equal(12 + bivariate.GetXBin(coord, 200)))
+			Expect(level.GetYBin(root, 70)).To(Equal(4 + bivariate.GetYBin(coord, 70)))
+			Expect(bivariate.Resolution).To(Equal(4))
+		})
+	})
+
+	Describe("PaddedBivariate", func() {
+		It("should extend the tile by the padding bins on each side", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"left": 0,
+					"right": 256,
+					"bottom": 0,
+					"top": 256,
+					"resolution": 4
+				}`)
+			err := bivariate.Parse(params)
+			Expect(err).To(BeNil())
+			coord := &binning.TileCoord{Z: 2, X: 1, Y: 1}
+			padded := bivariate.PaddedBivariate(coord, 1)
+			Expect(padded.Resolution).To(Equal(6))
+			Expect(padded.TileBounds(coord)).To(Equal(geometry.NewBounds(48, 144, 48, 144)))
+			Expect(padded.BinSizeX(coord)).To(Equal(bivariate.BinSizeX(coord)))
+			Expect(padded.GetXBin(coord, 100)).To(Equal(1 + bivariate.GetXBin(coord, 100)))
+			Expect(padded.GetYBin(coord, 50)).To(Equal(0))
+			Expect(bivariate.Resolution).To(Equal(4))
+		})
+
+		It("should extend the mercator latitude bins in tile space", func() {
+			params := JSON(
+				`{
+					"xField": "lon",
+					"yField": "lat",
+					"projection": "mercator",
+					"resolution": 4
+				}`)
+			err := bivariate.Parse(params)
+			Expect(err).To(BeNil())
+			coord := &binning.TileCoord{Z: 2, X: 1, Y: 1}
+			above := &binning.TileCoord{Z: 2, X: 1, Y: 2}
+			padded := bivariate.PaddedBivariate(coord, 2)
+			yBounds := padded.GetYBinBounds(coord)
+			Expect(yBounds[2:7]).To(Equal(bivariate.GetYBinBounds(coord)))
+			Expect(yBounds[8]).To(BeNumerically("~", bivariate.GetYBinBounds(above)[2], 1e-9))
+			lat := (yBounds[7] + yBounds[8]) / 2
+			Expect(padded.GetYBin(coord, lat)).To(Equal(7))
+			Expect(padded.TileBounds(coord).Top).To(Equal(yBounds[8]))
+		})
+	})
+
 	Describe("BinSizeX", func() {
 		It("should return the size of a bin over the x axis", func() {
 			params := JSON(
@@ -300,6 +408,79 @@ var _ = Describe("Bivariate", func() {
 			Expect(binD).To(Equal(191))
 			Expect(binE).To(Equal(255))
 		})
+
+		It("should return the y bin for the provided tile coord for a `mercator` projection", func() {
+			params := JSON(
+				`{
+					"xField": "lon",
+					"yField": "lat",
+					"projection": "mercator",
+					"resolution": 256
+				}`)
+			coord := &binning.TileCoord{
+				Z: 0,
+				X: 0,
+				Y: 0,
+			}
+			err := bivariate.Parse(params)
+			Expect(err).To(BeNil())
+			binA := bivariate.GetYBin(coord, -85.0511)
+			binB := bivariate.GetYBin(coord, 0.0)
+			binC := bivariate.GetYBin(coord, 66.6)
+			binD := bivariate.GetYBin(coord, 85.0511)
+			Expect(binA).To(Equal(0))
+			Expect(binB).To(Equal(128))
+			Expect(binC).To(Equal(192))
+			Expect(binD).To(Equal(255))
+		})
+	})
+
+	Describe("GetYBinBounds", func() {
+		It("should return evenly spaced boundaries for a `linear` projection", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"left": -1.0,
+					"right": 1.0,
+					"bottom": -1.0,
+					"top": 1.0,
+					"resolution": 4
+				}`)
+			coord := &binning.TileCoord{
+				Z: 0,
+				X: 0,
+				Y: 0,
+			}
+			err := bivariate.Parse(params)
+			Expect(err).To(BeNil())
+			bounds := bivariate.GetYBinBounds(coord)
+			Expect(bounds).To(Equal([]float64{-1.0, -0.5, 0.0, 0.5, 1.0}))
+		})
+
+		It("should return inverse mercator boundaries for a `mercator` projection", func() {
+			params := JSON(
+				`{
+					"xField": "lon",
+					"yField": "lat",
+					"projection": "mercator",
+					"resolution": 4
+				}`)
+			coord := &binning.TileCoord{
+				Z: 0,
+				X: 0,
+				Y: 0,
+			}
+			err := bivariate.Parse(params)
+			Expect(err).To(BeNil())
+			bounds := bivariate.GetYBinBounds(coord)
+			Expect(len(bounds)).To(Equal(5))
+			Expect(bounds[0]).To(BeNumerically("~", -85.0511, 0.0001))
+			Expect(bounds[1]).To(BeNumerically("~", -66.5133, 0.0001))
+			Expect(bounds[2]).To(BeNumerically("~", 0.0, 0.0001))
+			Expect(bounds[3]).To(BeNumerically("~", 66.5133, 0.0001))
+			Expect(bounds[4]).To(BeNumerically("~", 85.0511, 0.0001))
+		})
 	})
 
 	Describe("GetX", func() {
@@ -424,6 +605,24 @@ var _ = Describe("Bivariate", func() {
 			Expect(binD).To(Equal(192.0))
 			Expect(binE).To(Equal(256.0))
 		})
+
+		It("should return the y coordinate for the provided tile coord for a `mercator` projection", func() {
+			params := JSON(
+				`{
+					"xField": "lon",
+					"yField": "lat",
+					"projection": "mercator"
+				}`)
+			coord := &binning.TileCoord{
+				Z: 1,
+				X: 1,
+				Y: 1,
+			}
+			err := bivariate.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(bivariate.GetY(coord, 0.0)).To(BeNumerically("~", 0.0, 0.0001))
+			Expect(bivariate.GetY(coord, 66.5133)).To(BeNumerically("~", 128.0, 0.01))
+		})
 	})
 
 	Describe("GetXY", func() {
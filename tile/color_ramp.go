@@ -0,0 +1,85 @@
+package tile
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// ColorRamp represents a sequence of evenly spaced color stops that are
+// linearly interpolated between.
+type ColorRamp []color.RGBA
+
+var (
+	colorRamps = map[string]ColorRamp{
+		"viridis": hexRamp(
+			0x440154, 0x482878, 0x3e4989, 0x31688e, 0x26828e,
+			0x1f9e89, 0x35b779, 0x6ece58, 0xb5de2b, 0xfde725),
+		"inferno": hexRamp(
+			0x000004, 0x1b0c41, 0x4a0c6b, 0x781c6d, 0xa52c60,
+			0xcf4446, 0xed6925, 0xfb9b06, 0xf7d13d, 0xfcffa4),
+		"magma": hexRamp(
+			0x000004, 0x180f3d, 0x440f76, 0x721f81, 0x9e2f7f,
+			0xcd4071, 0xf1605d, 0xfd9668, 0xfeca8d, 0xfcfdbf),
+		"plasma": hexRamp(
+			0x0d0887, 0x41049d, 0x6a00a8, 0x8f0da4, 0xb12a90,
+			0xcc4778, 0xe16462, 0xf2844b, 0xfca636, 0xfcce25,
+			0xf0f921),
+		"hot": hexRamp(
+			0x000000, 0xff0000, 0xffff00, 0xffffff),
+		"cool": hexRamp(
+			0x00ffff, 0xff00ff),
+		"greyscale": hexRamp(
+			0x000000, 0xffffff),
+	}
+)
+
+// GetColorRamp returns the built-in color ramp registered under the provided
+// name.
+func GetColorRamp(name string) (ColorRamp, error) {
+	ramp, ok := colorRamps[name]
+	if !ok {
+		return nil, fmt.Errorf("color ramp `%s` is not recognized", name)
+	}
+	return ramp, nil
+}
+
+// Interpolate returns the color for the provided value in the range [0 : 1].
+// Values outside of the range are clamped.
+func (r ColorRamp) Interpolate(value float64) color.RGBA {
+	if len(r) == 1 {
+		return r[0]
+	}
+	value = math.Max(0, math.Min(1, value))
+	pos := value * float64(len(r)-1)
+	index := int(math.Floor(pos))
+	if index >= len(r)-1 {
+		return r[len(r)-1]
+	}
+	t := pos - float64(index)
+	from := r[index]
+	to := r[index+1]
+	return color.RGBA{
+		R: lerp(from.R, to.R, t),
+		G: lerp(from.G, to.G, t),
+		B: lerp(from.B, to.B, t),
+		A: lerp(from.A, to.A, t),
+	}
+}
+
+func lerp(a uint8, b uint8, t float64) uint8 {
+	return uint8(math.Floor(float64(a) + (float64(b)-float64(a))*t + 0.5))
+}
+
+func hexRamp(hexes ...uint32) ColorRamp {
+	ramp := make(ColorRamp, len(hexes))
+	for i, hex := range hexes {
+		ramp[i] = color.RGBA{
+			R: uint8(hex >> 16),
+			G: uint8(hex >> 8),
+			B: uint8(hex),
+			A: 255,
+		}
+	}
+	return ramp
+}
@@ -0,0 +1,39 @@
+package tile_test
+
+import (
+	"image/color"
+
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ColorRamp", func() {
+
+	Describe("GetColorRamp", func() {
+		It("should return a built-in color ramp", func() {
+			ramp, err := tile.GetColorRamp("viridis")
+			Expect(err).To(BeNil())
+			Expect(len(ramp)).To(BeNumerically(">", 1))
+		})
+		It("should return an error if the color ramp is not recognized", func() {
+			_, err := tile.GetColorRamp("invalid")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Interpolate", func() {
+		It("should interpolate between color stops", func() {
+			ramp, _ := tile.GetColorRamp("greyscale")
+			Expect(ramp.Interpolate(0)).To(Equal(color.RGBA{0, 0, 0, 255}))
+			Expect(ramp.Interpolate(0.5)).To(Equal(color.RGBA{128, 128, 128, 255}))
+			Expect(ramp.Interpolate(1)).To(Equal(color.RGBA{255, 255, 255, 255}))
+		})
+		It("should clamp values outside of the range [0 : 1]", func() {
+			ramp, _ := tile.GetColorRamp("greyscale")
+			Expect(ramp.Interpolate(-1)).To(Equal(color.RGBA{0, 0, 0, 255}))
+			Expect(ramp.Interpolate(2)).To(Equal(color.RGBA{255, 255, 255, 255}))
+		})
+	})
+})
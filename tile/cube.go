@@ -0,0 +1,154 @@
+package tile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// DenseEncoding represents a cube encoding where every bin contains a
+	// count for every time bucket.
+	DenseEncoding = "dense"
+	// SparseEncoding represents a cube encoding where only non-empty
+	// bin / time bucket pairs are included.
+	SparseEncoding = "sparse"
+)
+
+// Cube represents a tile which returns the counts of spatial bins across a
+// series of time buckets.
+type Cube struct {
+	Encoding string
+}
+
+// CubeBucket represents the count of a single spatial bin within a single time
+// bucket.
+type CubeBucket struct {
+	Bin       int
+	Timestamp int64
+	Count     uint32
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (c *Cube) Parse(params map[string]interface{}) error {
+	encoding := json.GetStringDefault(params, SparseEncoding, "encoding")
+	if encoding != DenseEncoding && encoding != SparseEncoding {
+		return fmt.Errorf("`encoding` must be either `dense` or `sparse`")
+	}
+	c.Encoding = encoding
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (c *Cube) Params() []json.Param {
+	return []json.Param{
+		{Name: "encoding", Type: json.StringType, Default: SparseEncoding, Enum: []interface{}{DenseEncoding, SparseEncoding}},
+	}
+}
+
+// Encode will encode the cube buckets into a byte array. The layout begins
+// with a header of little endian values:
+//
+//     [uint32 encoding (0 = dense, 1 = sparse)]
+//     [uint32 resolution]
+//     [uint32 number of timestamps]
+//     [float64 timestamp] * number of timestamps
+//
+// A dense encoding follows the header with a uint32 count for every time
+// bucket of every bin, ordered by bin then timestamp. A sparse encoding
+// follows the header with a uint32 number of entries, followed by each entry
+// as a uint32 bin index, uint32 timestamp index and uint32 count.
+func (c *Cube) Encode(resolution int, buckets []CubeBucket) ([]byte, error) {
+	numBins := resolution * resolution
+	// get the sorted distinct timestamps
+	timestamps := getTimestamps(buckets)
+	indices := make(map[int64]int, len(timestamps))
+	for i, timestamp := range timestamps {
+		indices[timestamp] = i
+	}
+	// validate bins
+	for _, bucket := range buckets {
+		if bucket.Bin < 0 || bucket.Bin >= numBins {
+			return nil, fmt.Errorf("cube bin index %d is out of range", bucket.Bin)
+		}
+	}
+	// empty buckets only contribute their timestamps to a sparse encoding
+	sorted := make(cubeBuckets, 0, len(buckets))
+	for _, bucket := range buckets {
+		if bucket.Count > 0 {
+			sorted = append(sorted, bucket)
+		}
+	}
+	// encode header
+	header := 12 + len(timestamps)*8
+	var bytes []byte
+	if c.Encoding == DenseEncoding {
+		bytes = make([]byte, header+numBins*len(timestamps)*4)
+	} else {
+		bytes = make([]byte, header+4+len(sorted)*12)
+	}
+	encoding := uint32(0)
+	if c.Encoding != DenseEncoding {
+		encoding = 1
+	}
+	binary.LittleEndian.PutUint32(bytes[0:4], encoding)
+	binary.LittleEndian.PutUint32(bytes[4:8], uint32(resolution))
+	binary.LittleEndian.PutUint32(bytes[8:12], uint32(len(timestamps)))
+	for i, timestamp := range timestamps {
+		offset := 12 + i*8
+		binary.LittleEndian.PutUint64(
+			bytes[offset:offset+8],
+			math.Float64bits(float64(timestamp)))
+	}
+	// encode body
+	if c.Encoding == DenseEncoding {
+		for _, bucket := range buckets {
+			offset := header + (bucket.Bin*len(timestamps)+indices[bucket.Timestamp])*4
+			count := binary.LittleEndian.Uint32(bytes[offset:offset+4]) + bucket.Count
+			binary.LittleEndian.PutUint32(bytes[offset:offset+4], count)
+		}
+		return bytes, nil
+	}
+	sort.Sort(sorted)
+	binary.LittleEndian.PutUint32(bytes[header:header+4], uint32(len(sorted)))
+	for i, bucket := range sorted {
+		offset := header + 4 + i*12
+		binary.LittleEndian.PutUint32(bytes[offset:offset+4], uint32(bucket.Bin))
+		binary.LittleEndian.PutUint32(bytes[offset+4:offset+8], uint32(indices[bucket.Timestamp]))
+		binary.LittleEndian.PutUint32(bytes[offset+8:offset+12], bucket.Count)
+	}
+	return bytes, nil
+}
+
+func getTimestamps(buckets []CubeBucket) []int64 {
+	seen := make(map[int64]bool)
+	timestamps := make([]int64, 0)
+	for _, bucket := range buckets {
+		if !seen[bucket.Timestamp] {
+			seen[bucket.Timestamp] = true
+			timestamps = append(timestamps, bucket.Timestamp)
+		}
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+	return timestamps
+}
+
+type cubeBuckets []CubeBucket
+
+func (c cubeBuckets) Len() int {
+	return len(c)
+}
+func (c cubeBuckets) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+func (c cubeBuckets) Less(i, j int) bool {
+	if c[i].Bin == c[j].Bin {
+		return c[i].Timestamp < c[j].Timestamp
+	}
+	return c[i].Bin < c[j].Bin
+}
@@ -0,0 +1,103 @@
+package tile_test
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+func readUint32s(bytes []byte) []uint32 {
+	values := make([]uint32, len(bytes)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(bytes[i*4 : i*4+4])
+	}
+	return values
+}
+
+func readFloat64s(bytes []byte) []float64 {
+	values := make([]float64, len(bytes)/8)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(bytes[i*8 : i*8+8]))
+	}
+	return values
+}
+
+var _ = Describe("Cube", func() {
+
+	var cube *tile.Cube
+	var buckets []tile.CubeBucket
+
+	BeforeEach(func() {
+		cube = &tile.Cube{}
+		buckets = []tile.CubeBucket{
+			{Bin: 3, Timestamp: 2000, Count: 4},
+			{Bin: 0, Timestamp: 1000, Count: 1},
+			{Bin: 0, Timestamp: 2000, Count: 0},
+			{Bin: 1, Timestamp: 3000, Count: 2},
+		}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"encoding": "dense"
+				}`)
+			err := cube.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(cube.Encoding).To(Equal("dense"))
+		})
+
+		It("should default to a sparse encoding", func() {
+			err := cube.Parse(JSON(`{}`))
+			Expect(err).To(BeNil())
+			Expect(cube.Encoding).To(Equal("sparse"))
+		})
+
+		It("should return an error if `encoding` is not recognized", func() {
+			err := cube.Parse(JSON(`{"encoding": "invalid"}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Encode", func() {
+		It("should encode a dense cube", func() {
+			cube.Encoding = tile.DenseEncoding
+			bytes, err := cube.Encode(2, buckets)
+			Expect(err).To(BeNil())
+			Expect(readUint32s(bytes[0:12])).To(Equal([]uint32{0, 2, 3}))
+			Expect(readFloat64s(bytes[12:36])).To(Equal([]float64{1000, 2000, 3000}))
+			Expect(readUint32s(bytes[36:])).To(Equal([]uint32{
+				1, 0, 0,
+				0, 0, 2,
+				0, 0, 0,
+				0, 4, 0,
+			}))
+		})
+
+		It("should encode a sparse cube", func() {
+			cube.Encoding = tile.SparseEncoding
+			bytes, err := cube.Encode(2, buckets)
+			Expect(err).To(BeNil())
+			Expect(readUint32s(bytes[0:12])).To(Equal([]uint32{1, 2, 3}))
+			Expect(readFloat64s(bytes[12:36])).To(Equal([]float64{1000, 2000, 3000}))
+			Expect(readUint32s(bytes[36:])).To(Equal([]uint32{
+				3,
+				0, 0, 1,
+				1, 2, 2,
+				3, 1, 4,
+			}))
+		})
+
+		It("should return an error if a bin is out of range", func() {
+			cube.Encoding = tile.SparseEncoding
+			_, err := cube.Encode(1, buckets)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -23,6 +23,8 @@ type Edge struct {
 	RequireDst bool
 	// weight
 	WeightField string
+	// projection
+	Projection string
 	// Bounds
 	tileBounds   *geometry.Bounds
 	globalBounds *geometry.Bounds
@@ -59,6 +61,11 @@ func (e *Edge) Parse(params map[string]interface{}) error {
 	if !ok {
 		return fmt.Errorf("`weightField` parameter missing from tile")
 	}
+	// get projection
+	projection, err := parseProjection(params)
+	if err != nil {
+		return err
+	}
 	// set attributes
 	e.SrcXField = srcXField
 	e.SrcYField = srcYField
@@ -67,17 +74,40 @@ func (e *Edge) Parse(params map[string]interface{}) error {
 	e.RequireSrc = requireSrc
 	e.RequireDst = requireDst
 	e.WeightField = weightField
+	e.Projection = projection
 	// clear tile bounds
 	e.tileBounds = nil
+	// mercator bounds are implied by the tile coord
+	if projection == MercatorProjection {
+		e.globalBounds = nil
+		return nil
+	}
 	// get the global bounds
 	e.globalBounds = &geometry.Bounds{}
 	return e.globalBounds.Parse(params)
 }
 
+// Params returns the descriptions of the parameters parsed by Parse.
+func (e *Edge) Params() []json.Param {
+	return append([]json.Param{
+		{Name: "srcXField", Type: json.StringType, Required: true},
+		{Name: "srcYField", Type: json.StringType, Required: true},
+		{Name: "dstXField", Type: json.StringType, Required: true},
+		{Name: "dstYField", Type: json.StringType, Required: true},
+		{Name: "requireSrc", Type: json.BooleanType, Default: true},
+		{Name: "requireDst", Type: json.BooleanType, Default: false},
+		{Name: "weightField", Type: json.StringType, Required: true},
+	}, projectionParams()...)
+}
+
 // TileBounds computes and returns the tile bounds for the provided tile coord.
 func (e *Edge) TileBounds(coord *binning.TileCoord) *geometry.Bounds {
 	if e.tileBounds == nil {
-		e.tileBounds = binning.GetTileBounds(coord, e.globalBounds)
+		if e.Projection == MercatorProjection {
+			e.tileBounds = binning.GetTileLonLatBounds(coord)
+		} else {
+			e.tileBounds = binning.GetTileBounds(coord, e.globalBounds)
+		}
 	}
 	return e.tileBounds
 }
@@ -96,6 +126,9 @@ func (e *Edge) GetX(coord *binning.TileCoord, x float64) float64 {
 // GetY given an y value, returns the corresponding coord within the range of
 // [0 : 256) for the tile.
 func (e *Edge) GetY(coord *binning.TileCoord, y float64) float64 {
+	if e.Projection == MercatorProjection {
+		return mercatorY(coord, y) * binning.MaxTileResolution
+	}
 	bounds := e.TileBounds(coord)
 	rang := bounds.RangeY()
 	if bounds.Bottom > bounds.Top {
@@ -319,6 +319,28 @@ var _ = Describe("Edge", func() {
 			Expect(binD).To(Equal(192.0))
 			Expect(binE).To(Equal(256.0))
 		})
+
+		It("should return the y coordinate for the provided tile coord for a `mercator` projection", func() {
+			params := JSON(
+				`{
+					"srcXField": "sx",
+					"srcYField": "sy",
+					"dstXField": "dx",
+					"dstYField": "dy",
+					"weightField": "weight",
+					"projection": "mercator"
+				}`)
+			coord := &binning.TileCoord{
+				Z: 0,
+				X: 0,
+				Y: 0,
+			}
+			err := edge.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(edge.GetY(coord, 0.0)).To(BeNumerically("~", 128.0, 0.0001))
+			Expect(edge.GetY(coord, 66.5133)).To(BeNumerically("~", 192.0, 0.01))
+			Expect(edge.GetY(coord, -66.5133)).To(BeNumerically("~", 64.0, 0.01))
+		})
 	})
 
 	Describe("GetSrcXY", func() {
@@ -1,7 +1,12 @@
 package tile
 
 import (
+	"bytes"
 	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
 	"math"
 )
 
@@ -30,6 +35,36 @@ func EncodeInt(data []int) []byte {
 	return bytes
 }
 
+// EncodeImage takes an RGBA byte array and encodes it into the image file
+// format of the provided extension. It is the counterpart of DecodeImage.
+func EncodeImage(ext string, rgba []byte, width int, height int) ([]byte, error) {
+	if len(rgba) != width*height*4 {
+		return nil, fmt.Errorf("RGBA buffer length of %d does not match image dimensions of %dx%d",
+			len(rgba), width, height)
+	}
+	img := &image.RGBA{
+		Pix:    rgba,
+		Stride: width * 4,
+		Rect:   image.Rect(0, 0, width, height),
+	}
+	buffer := &bytes.Buffer{}
+	switch ext {
+	case "png":
+		err := png.Encode(buffer, img)
+		if err != nil {
+			return nil, err
+		}
+	case "jpg", "jpeg":
+		err := jpeg.Encode(buffer, img, nil)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported image extension `%s`", ext)
+	}
+	return buffer.Bytes(), nil
+}
+
 // encodeLOD generates the point LOD offsets and encodes them as a byte array.
 func encodeLOD(data []float32, offsets []int) []byte {
 	// encode data
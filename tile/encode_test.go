@@ -1,6 +1,9 @@
 package tile_test
 
 import (
+	"bytes"
+	"image/png"
+
 	"github.com/unchartedsoftware/veldt/tile"
 
 	. "github.com/onsi/ginkgo"
@@ -10,7 +13,7 @@ import (
 var _ = Describe("Encode", func() {
 
 	var input []float32
-	var bytes []byte
+	var encoded []byte
 
 	BeforeEach(func() {
 		input = []float32{
@@ -20,7 +23,7 @@ var _ = Describe("Encode", func() {
 			65.36, 36.25, 107.91, 250.01,
 			96.05, 198.40, 66.70, 73.39,
 		}
-		bytes = []byte{
+		encoded = []byte{
 			113, 61, 9, 67, 113, 61, 226, 64, 113, 125, 96, 67, 102,
 			230, 247, 66, 31, 5, 249, 66, 123, 84, 20, 67, 205, 204,
 			144, 66, 51, 51, 177, 65, 72, 33, 32, 67, 20, 46, 155, 66,
@@ -33,7 +36,33 @@ var _ = Describe("Encode", func() {
 	Describe("EncodeFloat32", func() {
 		It("should encode provided []float32 into a []byte", func() {
 			bs := tile.EncodeFloat32(input)
-			Expect(bs).To(Equal(bytes))
+			Expect(bs).To(Equal(encoded))
+		})
+	})
+
+	Describe("EncodeImage", func() {
+		It("should encode the provided RGBA bytes into a png", func() {
+			rgba := make([]byte, 2*2*4)
+			for i := range rgba {
+				rgba[i] = uint8(i * 16)
+			}
+			bs, err := tile.EncodeImage("png", rgba, 2, 2)
+			Expect(err).To(BeNil())
+			img, err := png.Decode(bytes.NewBuffer(bs))
+			Expect(err).To(BeNil())
+			Expect(img.Bounds().Dx()).To(Equal(2))
+			Expect(img.Bounds().Dy()).To(Equal(2))
+			decoded, err := tile.DecodeImage("png", bytes.NewBuffer(bs))
+			Expect(err).To(BeNil())
+			Expect(decoded).To(Equal(rgba))
+		})
+		It("should return an error if the dimensions do not match", func() {
+			_, err := tile.EncodeImage("png", make([]byte, 4), 2, 2)
+			Expect(err).NotTo(BeNil())
+		})
+		It("should return an error if the extension is not supported", func() {
+			_, err := tile.EncodeImage("gif", make([]byte, 4), 1, 1)
+			Expect(err).NotTo(BeNil())
 		})
 	})
 })
@@ -2,10 +2,17 @@ package tile
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
+const (
+	// MaxFrequencyBuckets is the maximum number of buckets a frequency tile
+	// may produce.
+	MaxFrequencyBuckets = 10000
+)
+
 // Frequency represents a tile which returns data over a provided time range.
 type Frequency struct {
 	FrequencyField string
@@ -14,6 +21,7 @@ type Frequency struct {
 	LT             interface{}
 	LTE            interface{}
 	Interval       string
+	TimeZone       string
 }
 
 // Parse parses the provided JSON object and populates the tiles attributes.
@@ -39,11 +47,140 @@ func (t *Frequency) Parse(params map[string]interface{}) error {
 	if !ok {
 		return fmt.Errorf("`interval` parameter missing from tile")
 	}
+	timeZone := json.GetStringDefault(params, "", "timeZone")
+	if timeZone != "" {
+		_, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return fmt.Errorf("`timeZone` of `%s` is not supported", timeZone)
+		}
+	}
 	t.FrequencyField = frequencyField
 	t.GTE = gte
 	t.GT = gt
 	t.LTE = lte
 	t.LT = lt
 	t.Interval = interval
+	t.TimeZone = timeZone
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (t *Frequency) Params() []json.Param {
+	return []json.Param{
+		{Name: "frequencyField", Type: json.StringType, Required: true},
+		{Name: "gte"},
+		{Name: "gt"},
+		{Name: "lte"},
+		{Name: "lt"},
+		{Name: "interval", Type: json.StringType, Required: true},
+		{Name: "timeZone", Type: json.StringType},
+	}
+}
+
+// Location returns the location in which calendar intervals are aligned,
+// defaulting to UTC.
+func (t *Frequency) Location() *time.Location {
+	if t.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(t.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// TimeBounds returns the lower and upper bounds of the time range, or nil if
+// the range is unbounded on that side.
+func (t *Frequency) TimeBounds() (*time.Time, *time.Time, error) {
+	var min, max *time.Time
+	lower := t.GTE
+	if lower == nil {
+		lower = t.GT
+	}
+	if lower != nil {
+		v, err := CastTime(lower)
+		if err != nil {
+			return nil, nil, err
+		}
+		min = &v
+	}
+	upper := t.LTE
+	if upper == nil {
+		upper = t.LT
+	}
+	if upper != nil {
+		v, err := CastTime(upper)
+		if err != nil {
+			return nil, nil, err
+		}
+		max = &v
+	}
+	return min, max, nil
+}
+
+// Anchor returns the time to which fixed intervals are aligned, which is the
+// lower bound of the range, or the epoch if unbounded.
+func (t *Frequency) Anchor() (time.Time, error) {
+	min, _, err := t.TimeBounds()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if min != nil {
+		return *min, nil
+	}
+	return time.Unix(0, 0).UTC(), nil
+}
+
+// GetBucketTimestamps returns the start of every bucket, in milliseconds
+// since the epoch, from the first to the last of the provided bucket starts.
+// The buckets are extended to the bounds of the time range, such that empty
+// buckets are included. An error is returned if there are more than
+// MaxFrequencyBuckets buckets.
+func (t *Frequency) GetBucketTimestamps(interval *TimeInterval, starts []int64) ([]int64, error) {
+	min, max, err := t.TimeBounds()
+	if err != nil {
+		return nil, err
+	}
+	anchor, err := t.Anchor()
+	if err != nil {
+		return nil, err
+	}
+	loc := t.Location()
+	var first, last *time.Time
+	for _, start := range starts {
+		s := interval.Truncate(FromMillis(start), anchor, loc)
+		if first == nil || s.Before(*first) {
+			first = &s
+		}
+		if last == nil || s.After(*last) {
+			last = &s
+		}
+	}
+	// extend the buckets to the range bounds
+	if min != nil {
+		start := interval.Truncate(*min, anchor, loc)
+		first = &start
+	}
+	if max != nil {
+		end := interval.Truncate(*max, anchor, loc)
+		if t.LT != nil && end.Equal(*max) {
+			// exclusive upper bound
+			end = interval.Truncate(max.Add(-time.Millisecond), anchor, loc)
+		}
+		last = &end
+	}
+	if first == nil || last == nil {
+		return []int64{}, nil
+	}
+	res := make([]int64, 0)
+	for s := *first; !s.After(*last); s = interval.Next(s) {
+		if len(res) == MaxFrequencyBuckets {
+			return nil, fmt.Errorf("`interval` of `%s` produces more than %d buckets over the time range",
+				t.Interval,
+				MaxFrequencyBuckets)
+		}
+		res = append(res, ToMillis(s))
+	}
+	return res, nil
+}
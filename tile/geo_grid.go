@@ -0,0 +1,157 @@
+package tile
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// GeohashGrid represents a grid of geohash cells.
+	GeohashGrid = "geohash"
+	// GeotileGrid represents a grid of web mercator tile cells. The
+	// `geotile_grid` aggregation requires elasticsearch 7 or later.
+	GeotileGrid = "geotile"
+
+	maxGeohashPrecision = 12
+	maxGeotilePrecision = 29
+)
+
+// GeoGrid represents the parameters required for a tile that aggregates a
+// geographic point field into grid cells and re-bins them into a heatmap.
+type GeoGrid struct {
+	GeoField   string
+	Resolution int
+	GridType   string
+	Precision  int
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (g *GeoGrid) Parse(params map[string]interface{}) error {
+	geoField, ok := json.GetString(params, "geoField")
+	if !ok {
+		return fmt.Errorf("`geoField` parameter missing from tile")
+	}
+	gridType := json.GetStringDefault(params, GeohashGrid, "gridType")
+	if gridType != GeohashGrid && gridType != GeotileGrid {
+		return fmt.Errorf("`gridType` must be either `geohash` or `geotile`")
+	}
+	precision := json.GetIntDefault(params, 0, "precision")
+	if precision < 0 {
+		return fmt.Errorf("`precision` must not be negative")
+	}
+	if gridType == GeohashGrid && precision > maxGeohashPrecision {
+		return fmt.Errorf("`precision` must not exceed %d for geohash grids", maxGeohashPrecision)
+	}
+	if gridType == GeotileGrid && precision > maxGeotilePrecision {
+		return fmt.Errorf("`precision` must not exceed %d for geotile grids", maxGeotilePrecision)
+	}
+	g.GeoField = geoField
+	g.Resolution = json.GetIntDefault(params, 256, "resolution")
+	g.GridType = gridType
+	g.Precision = precision
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (g *GeoGrid) Params() []json.Param {
+	return []json.Param{
+		{Name: "geoField", Type: json.StringType, Required: true},
+		{Name: "gridType", Type: json.StringType, Default: GeohashGrid, Enum: []interface{}{GeohashGrid, GeotileGrid}},
+		{Name: "precision", Type: json.IntegerType, Default: 0},
+		{Name: "resolution", Type: json.IntegerType, Default: 256},
+	}
+}
+
+// TileBounds returns the geographic bounds of the provided tile coord.
+func (g *GeoGrid) TileBounds(coord *binning.TileCoord) *geometry.Bounds {
+	return binning.GetTileLonLatBounds(coord)
+}
+
+// GetPrecision returns the grid precision for the provided tile coord. If no
+// precision is specified, the finest precision that does not exceed the size
+// of a single bin is used.
+func (g *GeoGrid) GetPrecision(coord *binning.TileCoord) int {
+	if g.Precision > 0 {
+		return g.Precision
+	}
+	if g.GridType == GeotileGrid {
+		precision := int(coord.Z) + int(math.Ceil(math.Log2(float64(g.Resolution))))
+		return int(math.Min(maxGeotilePrecision, float64(precision)))
+	}
+	bounds := g.TileBounds(coord)
+	binWidth := bounds.RangeX() / float64(g.Resolution)
+	binHeight := bounds.RangeY() / float64(g.Resolution)
+	for precision := 1; precision < maxGeohashPrecision; precision++ {
+		width, height := binning.GeohashCellSize(precision)
+		if width <= binWidth && height <= binHeight {
+			return precision
+		}
+	}
+	return maxGeohashPrecision
+}
+
+// GetCellCount returns the maximum number of grid cells at the precision of
+// the provided tile coord which intersect the tile. Geotile cells are aligned
+// to the tile, while geohash cells may straddle each edge.
+func (g *GeoGrid) GetCellCount(coord *binning.TileCoord) int {
+	precision := g.GetPrecision(coord)
+	if g.GridType == GeotileGrid {
+		if precision <= int(coord.Z) {
+			return 1
+		}
+		n := 1 << uint(precision-int(coord.Z))
+		return n * n
+	}
+	bounds := g.TileBounds(coord)
+	width, height := binning.GeohashCellSize(precision)
+	nx := int(math.Ceil(bounds.RangeX()/width)) + 1
+	ny := int(math.Ceil(bounds.RangeY()/height)) + 1
+	return nx * ny
+}
+
+// GetCellCenter returns the geographic center of the cell represented by the
+// provided aggregation key.
+func (g *GeoGrid) GetCellCenter(key string) (*binning.LonLat, error) {
+	if g.GridType == GeohashGrid {
+		return binning.DecodeGeohash(key)
+	}
+	// geotile keys are of the form `z/x/y` with y starting from the top
+	split := strings.Split(key, "/")
+	if len(split) != 3 {
+		return nil, fmt.Errorf("geotile key `%s` is not of the form `z/x/y`", key)
+	}
+	parsed := make([]uint64, 3)
+	for i, str := range split {
+		val, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("geotile key `%s` is not of the form `z/x/y`", key)
+		}
+		parsed[i] = val
+	}
+	z := parsed[0]
+	pow2 := math.Pow(2, float64(z))
+	return binning.FractionalTileToLonLat(&binning.FractionalTileCoord{
+		X: float64(parsed[1]) + 0.5,
+		Y: pow2 - (float64(parsed[2]) + 0.5),
+		Z: uint32(z),
+	}), nil
+}
+
+// GetBin given a geographic coordinate, returns the corresponding bin index
+// of the provided tile coord. Returns false if the coordinate is outside of
+// the tile.
+func (g *GeoGrid) GetBin(coord *binning.TileCoord, lonLat *binning.LonLat) (int, bool) {
+	fractional := binning.LonLatToFractionalTile(lonLat, coord.Z)
+	x := int(math.Floor((fractional.X - float64(coord.X)) * float64(g.Resolution)))
+	y := int(math.Floor((fractional.Y - float64(coord.Y)) * float64(g.Resolution)))
+	if x < 0 || x >= g.Resolution || y < 0 || y >= g.Resolution {
+		return 0, false
+	}
+	return x + g.Resolution*y, true
+}
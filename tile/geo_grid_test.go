@@ -0,0 +1,162 @@
+package tile_test
+
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("GeoGrid", func() {
+
+	var grid *tile.GeoGrid
+
+	BeforeEach(func() {
+		grid = &tile.GeoGrid{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"geoField": "location",
+					"resolution": 64,
+					"gridType": "geohash",
+					"precision": 5
+				}`)
+			err := grid.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(grid.GeoField).To(Equal("location"))
+			Expect(grid.Resolution).To(Equal(64))
+			Expect(grid.GridType).To(Equal("geohash"))
+			Expect(grid.Precision).To(Equal(5))
+		})
+
+		It("should default to an automatic precision geohash grid", func() {
+			err := grid.Parse(JSON(`{"geoField": "location"}`))
+			Expect(err).To(BeNil())
+			Expect(grid.Resolution).To(Equal(256))
+			Expect(grid.GridType).To(Equal("geohash"))
+			Expect(grid.Precision).To(Equal(0))
+		})
+
+		It("should return an error if `geoField` is missing", func() {
+			err := grid.Parse(JSON(`{}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `gridType` is invalid", func() {
+			err := grid.Parse(JSON(`{"geoField": "location", "gridType": "h3"}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `precision` exceeds the grid maximum", func() {
+			err := grid.Parse(JSON(`{"geoField": "location", "gridType": "geohash", "precision": 13}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetPrecision", func() {
+		It("should return the specified precision", func() {
+			grid.Parse(JSON(`{"geoField": "location", "precision": 7}`))
+			coord := &binning.TileCoord{X: 0, Y: 0, Z: 3}
+			Expect(grid.GetPrecision(coord)).To(Equal(7))
+		})
+
+		It("should match geotile precision to the bin size", func() {
+			grid.Parse(JSON(`{"geoField": "location", "gridType": "geotile", "resolution": 256}`))
+			Expect(grid.GetPrecision(&binning.TileCoord{X: 0, Y: 0, Z: 3})).To(Equal(11))
+			Expect(grid.GetPrecision(&binning.TileCoord{X: 0, Y: 0, Z: 25})).To(Equal(29))
+		})
+
+		It("should select a geohash precision no coarser than the bin size", func() {
+			grid.Parse(JSON(`{"geoField": "location", "gridType": "geohash", "resolution": 4}`))
+			coord := &binning.TileCoord{X: 0, Y: 0, Z: 0}
+			bounds := binning.GetTileLonLatBounds(coord)
+			binWidth := bounds.RangeX() / 4
+			binHeight := bounds.RangeY() / 4
+			precision := grid.GetPrecision(coord)
+			width, height := binning.GeohashCellSize(precision)
+			Expect(width).To(BeNumerically("<=", binWidth))
+			Expect(height).To(BeNumerically("<=", binHeight))
+			width, height = binning.GeohashCellSize(precision - 1)
+			Expect(width > binWidth || height > binHeight).To(Equal(true))
+		})
+	})
+
+	Describe("GetCellCount", func() {
+		It("should count the geotile cells within the tile", func() {
+			grid.Parse(JSON(`{"geoField": "location", "gridType": "geotile", "resolution": 4}`))
+			Expect(grid.GetCellCount(&binning.TileCoord{X: 0, Y: 0, Z: 3})).To(Equal(16))
+			grid.Parse(JSON(`{"geoField": "location", "gridType": "geotile", "precision": 6, "resolution": 4}`))
+			Expect(grid.GetCellCount(&binning.TileCoord{X: 0, Y: 0, Z: 3})).To(Equal(64))
+			Expect(grid.GetCellCount(&binning.TileCoord{X: 0, Y: 0, Z: 8})).To(Equal(1))
+		})
+
+		It("should count every geohash cell intersecting the tile", func() {
+			grid.Parse(JSON(`{"geoField": "location", "precision": 5, "resolution": 4}`))
+			coord := &binning.TileCoord{X: 0, Y: 0, Z: 3}
+			bounds := binning.GetTileLonLatBounds(coord)
+			width, height := binning.GeohashCellSize(5)
+			Expect(grid.GetCellCount(coord)).To(BeNumerically(">=", int((bounds.RangeX()/width)*(bounds.RangeY()/height))))
+			Expect(grid.GetCellCount(coord)).To(BeNumerically(">", 16))
+		})
+	})
+
+	Describe("GetCellCenter", func() {
+		It("should decode geotile keys", func() {
+			grid.Parse(JSON(`{"geoField": "location", "gridType": "geotile"}`))
+			center, err := grid.GetCellCenter("1/1/0")
+			Expect(err).To(BeNil())
+			Expect(center.Lon).To(BeNumerically("~", 90.0, 0.0001))
+			Expect(center.Lat).To(BeNumerically(">", 0.0))
+		})
+
+		It("should decode geohash keys", func() {
+			grid.Parse(JSON(`{"geoField": "location", "gridType": "geohash"}`))
+			center, err := grid.GetCellCenter("s")
+			Expect(err).To(BeNil())
+			Expect(center.Lon).To(BeNumerically("~", 22.5, 0.0001))
+			Expect(center.Lat).To(BeNumerically("~", 22.5, 0.0001))
+		})
+
+		It("should return an error for malformed geotile keys", func() {
+			grid.Parse(JSON(`{"geoField": "location", "gridType": "geotile"}`))
+			_, err := grid.GetCellCenter("1/1")
+			Expect(err).NotTo(BeNil())
+			_, err = grid.GetCellCenter("a/b/c")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBin", func() {
+		It("should return the bin containing the coordinate", func() {
+			grid.Parse(JSON(`{"geoField": "location", "resolution": 2}`))
+			coord := &binning.TileCoord{X: 1, Y: 1, Z: 1}
+			bin, ok := grid.GetBin(coord, &binning.LonLat{Lon: 45, Lat: 10})
+			Expect(ok).To(Equal(true))
+			Expect(bin).To(Equal(0))
+			bin, ok = grid.GetBin(coord, &binning.LonLat{Lon: 135, Lat: 80})
+			Expect(ok).To(Equal(true))
+			Expect(bin).To(Equal(3))
+		})
+
+		It("should return false for coordinates outside of the tile", func() {
+			grid.Parse(JSON(`{"geoField": "location", "resolution": 2}`))
+			coord := &binning.TileCoord{X: 1, Y: 1, Z: 1}
+			_, ok := grid.GetBin(coord, &binning.LonLat{Lon: -45, Lat: 10})
+			Expect(ok).To(Equal(false))
+		})
+
+		It("should re-bin geotile cells into the tile containing them", func() {
+			grid.Parse(JSON(`{"geoField": "location", "gridType": "geotile", "resolution": 2}`))
+			// geotile y is measured from the top, tile y from the bottom
+			center, _ := grid.GetCellCenter("2/3/0")
+			bin, ok := grid.GetBin(&binning.TileCoord{X: 1, Y: 1, Z: 1}, center)
+			Expect(ok).To(Equal(true))
+			Expect(bin).To(Equal(3))
+		})
+	})
+})
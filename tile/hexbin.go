@@ -0,0 +1,205 @@
+package tile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// CountMetric represents a hexbin value of the number of data points.
+	CountMetric = "count"
+	// SumMetric represents a hexbin value of the sum of a data field.
+	SumMetric = "sum"
+	// AvgMetric represents a hexbin value of the average of a data field.
+	AvgMetric = "avg"
+)
+
+var (
+	sqrt3 = math.Sqrt(3)
+)
+
+// Hexbin represents a tile which aggregates data into a pointy-top hexagonal
+// grid. The grid is defined in global pixel coordinates of the tile's zoom
+// level, so that hexagons are aligned across tile boundaries.
+type Hexbin struct {
+	HexSize    float64
+	Metric     string
+	ValueField string
+}
+
+// HexCoord represents the axial coordinates of a hexagon.
+type HexCoord struct {
+	Q int
+	R int
+}
+
+// Hex represents the aggregated values of a single hexagon.
+type Hex struct {
+	Count float64
+	Sum   float64
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (h *Hexbin) Parse(params map[string]interface{}) error {
+	hexSize := json.GetFloatDefault(params, 16, "hexSize")
+	if hexSize <= 0 {
+		return fmt.Errorf("`hexSize` must be greater than zero")
+	}
+	metric := json.GetStringDefault(params, CountMetric, "metric")
+	if metric != CountMetric && metric != SumMetric && metric != AvgMetric {
+		return fmt.Errorf("`metric` must be one of `count`, `sum` or `avg`")
+	}
+	valueField, ok := json.GetString(params, "valueField")
+	if !ok && metric != CountMetric {
+		return fmt.Errorf("`valueField` parameter missing from tile")
+	}
+	h.HexSize = hexSize
+	h.Metric = metric
+	h.ValueField = valueField
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (h *Hexbin) Params() []json.Param {
+	return []json.Param{
+		{Name: "hexSize", Type: json.NumberType, Default: 16},
+		{Name: "metric", Type: json.StringType, Default: CountMetric, Enum: []interface{}{CountMetric, SumMetric, AvgMetric}},
+		{Name: "valueField", Type: json.StringType, Description: "required unless the metric is `count`"},
+	}
+}
+
+// GetHexCoord given a position within the range of [0 : 256) for the tile,
+// returns the axial coordinates of the hexagon containing it.
+func (h *Hexbin) GetHexCoord(coord *binning.TileCoord, x float64, y float64) HexCoord {
+	// convert to global pixel coords
+	px := float64(coord.X)*binning.MaxTileResolution + x
+	py := float64(coord.Y)*binning.MaxTileResolution + y
+	// convert to fractional axial coords
+	q := (sqrt3/3*px - py/3) / h.HexSize
+	r := (2.0 / 3.0 * py) / h.HexSize
+	return roundHex(q, r)
+}
+
+// Padding returns the number of sub-bins of the provided resolution required
+// on each side of the tile to cover every hexagon centered within it.
+func (h *Hexbin) Padding(resolution int) int {
+	binSize := binning.MaxTileResolution / float64(resolution)
+	return int(math.Ceil(h.HexSize / binSize))
+}
+
+// AggregateBins aggregates the provided square sub-bins into hexagons. Each
+// sub-bin is assigned to the hexagon containing its center. The sub-bins
+// cover the tile extended by the padding on each side, and only hexagons
+// centered within the tile are returned, such that each hexagon is
+// aggregated in full by exactly one tile. The sums may be nil if the metric
+// does not require them.
+func (h *Hexbin) AggregateBins(coord *binning.TileCoord, resolution int, padding int, counts []float64, sums []float64) map[HexCoord]*Hex {
+	binSize := binning.MaxTileResolution / float64(resolution)
+	halfSize := binSize / 2
+	paddedResolution := resolution + padding*2
+	hexes := make(map[HexCoord]*Hex)
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		x := float64(i%paddedResolution-padding)*binSize + halfSize
+		y := float64(i/paddedResolution-padding)*binSize + halfSize
+		hc := h.GetHexCoord(coord, x, y)
+		hex, ok := hexes[hc]
+		if !ok {
+			hex = &Hex{}
+			hexes[hc] = hex
+		}
+		hex.Count += count
+		if sums != nil {
+			hex.Sum += sums[i]
+		}
+	}
+	// remove the hexagons centered in neighbouring tiles
+	for hc := range hexes {
+		if !h.isCenteredIn(coord, hc) {
+			delete(hexes, hc)
+		}
+	}
+	return hexes
+}
+
+// isCenteredIn returns whether the center of the hexagon lies within the
+// tile.
+func (h *Hexbin) isCenteredIn(coord *binning.TileCoord, hc HexCoord) bool {
+	// convert to global pixel coords
+	px := h.HexSize * sqrt3 * (float64(hc.Q) + float64(hc.R)/2)
+	py := h.HexSize * 1.5 * float64(hc.R)
+	// convert to tile pixel coords
+	x := px - float64(coord.X)*binning.MaxTileResolution
+	y := py - float64(coord.Y)*binning.MaxTileResolution
+	return x >= 0 && x < binning.MaxTileResolution &&
+		y >= 0 && y < binning.MaxTileResolution
+}
+
+// Value returns the value of the hexagon for the configured metric.
+func (h *Hexbin) Value(hex *Hex) float64 {
+	switch h.Metric {
+	case SumMetric:
+		return hex.Sum
+	case AvgMetric:
+		if hex.Count == 0 {
+			return 0
+		}
+		return hex.Sum / hex.Count
+	}
+	return hex.Count
+}
+
+// Encode will encode the hexagons into a byte array of little endian values,
+// each hexagon as an int32 q coordinate, an int32 r coordinate and a float32
+// value, ordered by r then q.
+func (h *Hexbin) Encode(hexes map[HexCoord]*Hex) []byte {
+	coords := make([]HexCoord, 0, len(hexes))
+	for hc := range hexes {
+		coords = append(coords, hc)
+	}
+	sort.Slice(coords, func(i, j int) bool {
+		if coords[i].R == coords[j].R {
+			return coords[i].Q < coords[j].Q
+		}
+		return coords[i].R < coords[j].R
+	})
+	bytes := make([]byte, len(coords)*12)
+	for i, hc := range coords {
+		offset := i * 12
+		binary.LittleEndian.PutUint32(bytes[offset:offset+4], uint32(int32(hc.Q)))
+		binary.LittleEndian.PutUint32(bytes[offset+4:offset+8], uint32(int32(hc.R)))
+		binary.LittleEndian.PutUint32(
+			bytes[offset+8:offset+12],
+			math.Float32bits(float32(h.Value(hexes[hc]))))
+	}
+	return bytes
+}
+
+func roundHex(q float64, r float64) HexCoord {
+	// round in cube coordinates
+	x := q
+	z := r
+	y := -x - z
+	rx := math.Floor(x + 0.5)
+	ry := math.Floor(y + 0.5)
+	rz := math.Floor(z + 0.5)
+	dx := math.Abs(rx - x)
+	dy := math.Abs(ry - y)
+	dz := math.Abs(rz - z)
+	if dx > dy && dx > dz {
+		rx = -ry - rz
+	} else if dy <= dz {
+		rz = -rx - ry
+	}
+	return HexCoord{
+		Q: int(rx),
+		R: int(rz),
+	}
+}
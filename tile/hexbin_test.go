@@ -0,0 +1,150 @@
+package tile_test
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Hexbin", func() {
+
+	var hexbin *tile.Hexbin
+
+	BeforeEach(func() {
+		hexbin = &tile.Hexbin{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"hexSize": 8,
+					"metric": "avg",
+					"valueField": "value"
+				}`)
+			err := hexbin.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(hexbin.HexSize).To(Equal(8.0))
+			Expect(hexbin.Metric).To(Equal("avg"))
+			Expect(hexbin.ValueField).To(Equal("value"))
+		})
+
+		It("should default to a count metric", func() {
+			err := hexbin.Parse(JSON(`{}`))
+			Expect(err).To(BeNil())
+			Expect(hexbin.HexSize).To(Equal(16.0))
+			Expect(hexbin.Metric).To(Equal("count"))
+		})
+
+		It("should return an error if `hexSize` is not positive", func() {
+			err := hexbin.Parse(JSON(`{"hexSize": 0}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `valueField` is missing for a `sum` metric", func() {
+			err := hexbin.Parse(JSON(`{"metric": "sum"}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetHexCoord", func() {
+		It("should return the hexagon containing the position", func() {
+			hexbin.Parse(JSON(`{"hexSize": 10}`))
+			coord := &binning.TileCoord{X: 0, Y: 0, Z: 1}
+			Expect(hexbin.GetHexCoord(coord, 0, 0)).To(Equal(tile.HexCoord{Q: 0, R: 0}))
+			Expect(hexbin.GetHexCoord(coord, 17.32, 0)).To(Equal(tile.HexCoord{Q: 1, R: 0}))
+			Expect(hexbin.GetHexCoord(coord, 8.66, 15)).To(Equal(tile.HexCoord{Q: 0, R: 1}))
+		})
+
+		It("should align hexagons across tile boundaries", func() {
+			hexbin.Parse(JSON(`{"hexSize": 10}`))
+			left := &binning.TileCoord{X: 0, Y: 0, Z: 1}
+			right := &binning.TileCoord{X: 1, Y: 0, Z: 1}
+			Expect(hexbin.GetHexCoord(left, 255.9, 100)).To(Equal(hexbin.GetHexCoord(right, 0.1, 100)))
+		})
+	})
+
+	Describe("AggregateBins", func() {
+		It("should aggregate sub-bins into hexagons", func() {
+			hexbin.Parse(JSON(`{"hexSize": 1000, "metric": "avg", "valueField": "v"}`))
+			coord := &binning.TileCoord{X: 0, Y: 0, Z: 0}
+			counts := []float64{1, 2, 0, 3}
+			sums := []float64{2, 4, 0, 12}
+			hexes := hexbin.AggregateBins(coord, 2, 0, counts, sums)
+			Expect(len(hexes)).To(Equal(1))
+			origin := hexes[tile.HexCoord{Q: 0, R: 0}]
+			Expect(origin.Count).To(Equal(6.0))
+			Expect(origin.Sum).To(Equal(18.0))
+			Expect(hexbin.Value(origin)).To(Equal(3.0))
+		})
+
+		It("should aggregate a hexagon straddling two tiles in full by the tile containing its center", func() {
+			hexbin.Parse(JSON(`{"hexSize": 10}`))
+			left := &binning.TileCoord{X: 0, Y: 0, Z: 1}
+			right := &binning.TileCoord{X: 1, Y: 0, Z: 1}
+			// centered at (251.1, 15) in the left tile, extending into the right
+			straddling := tile.HexCoord{Q: 14, R: 1}
+			padding := hexbin.Padding(256)
+			Expect(padding).To(Equal(10))
+			// a single data point in every pixel of both padded tiles
+			counts := make([]float64, 276*276)
+			for i := range counts {
+				counts[i] = 1
+			}
+			// count the pixels centered within the hexagon
+			expected := 0.0
+			for x := 200; x < 300; x++ {
+				for y := 0; y < 40; y++ {
+					if hexbin.GetHexCoord(left, float64(x)+0.5, float64(y)+0.5) == straddling {
+						expected++
+					}
+				}
+			}
+			leftHexes := hexbin.AggregateBins(left, 256, padding, counts, nil)
+			rightHexes := hexbin.AggregateBins(right, 256, padding, counts, nil)
+			Expect(leftHexes[straddling].Count).To(Equal(expected))
+			Expect(rightHexes).NotTo(HaveKey(straddling))
+			// without padding the hexagon is only partially aggregated
+			unpadded := hexbin.AggregateBins(left, 256, 0, counts[:256*256], nil)
+			Expect(unpadded[straddling].Count).To(BeNumerically("<", expected))
+		})
+
+		It("should only return hexagons centered within the tile", func() {
+			hexbin.Parse(JSON(`{"hexSize": 10}`))
+			coord := &binning.TileCoord{X: 0, Y: 0, Z: 1}
+			counts := make([]float64, 276*276)
+			for i := range counts {
+				counts[i] = 1
+			}
+			hexes := hexbin.AggregateBins(coord, 256, hexbin.Padding(256), counts, nil)
+			for hc := range hexes {
+				x := 10 * math.Sqrt(3) * (float64(hc.Q) + float64(hc.R)/2)
+				y := 15 * float64(hc.R)
+				Expect(x >= 0 && x < 256 && y >= 0 && y < 256).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("Encode", func() {
+		It("should encode the hexagons ordered by r then q", func() {
+			hexbin.Parse(JSON(`{}`))
+			bytes := hexbin.Encode(map[tile.HexCoord]*tile.Hex{
+				{Q: 3, R: 1}:  {Count: 2},
+				{Q: -1, R: 0}: {Count: 5},
+			})
+			Expect(len(bytes)).To(Equal(24))
+			Expect(int32(binary.LittleEndian.Uint32(bytes[0:4]))).To(Equal(int32(-1)))
+			Expect(int32(binary.LittleEndian.Uint32(bytes[4:8]))).To(Equal(int32(0)))
+			Expect(math.Float32frombits(binary.LittleEndian.Uint32(bytes[8:12]))).To(Equal(float32(5)))
+			Expect(int32(binary.LittleEndian.Uint32(bytes[12:16]))).To(Equal(int32(3)))
+			Expect(int32(binary.LittleEndian.Uint32(bytes[16:20]))).To(Equal(int32(1)))
+			Expect(math.Float32frombits(binary.LittleEndian.Uint32(bytes[20:24]))).To(Equal(float32(2)))
+		})
+	})
+})
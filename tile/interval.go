@@ -0,0 +1,158 @@
+package tile
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	fixedInterval = regexp.MustCompile(`^(\d+)(ms|s|m|h|d|w)?$`)
+	fixedUnits    = map[string]time.Duration{
+		"":   time.Millisecond,
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+		"d":  time.Hour * 24,
+		"w":  time.Hour * 24 * 7,
+	}
+	timeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+)
+
+// TimeInterval represents the interval of a date histogram. Calendar
+// intervals such as `day` or `month` have a unit and are aligned to the
+// calendar, while fixed intervals such as `90m` have a duration and are
+// aligned to an anchor.
+type TimeInterval struct {
+	Unit     string
+	Duration time.Duration
+}
+
+// ParseInterval parses an elasticsearch style interval string. An interval
+// without a unit is interpreted as milliseconds.
+func ParseInterval(str string) (*TimeInterval, error) {
+	switch str {
+	case "second", "1s":
+		return &TimeInterval{Duration: time.Second}, nil
+	case "minute", "1m":
+		return &TimeInterval{Duration: time.Minute}, nil
+	case "hour", "1h":
+		return &TimeInterval{Duration: time.Hour}, nil
+	case "day", "1d":
+		return &TimeInterval{Unit: "day"}, nil
+	case "week", "1w":
+		return &TimeInterval{Unit: "week"}, nil
+	case "month", "1M":
+		return &TimeInterval{Unit: "month"}, nil
+	case "quarter", "1q":
+		return &TimeInterval{Unit: "quarter"}, nil
+	case "year", "1y":
+		return &TimeInterval{Unit: "year"}, nil
+	}
+	matches := fixedInterval.FindStringSubmatch(str)
+	if matches != nil {
+		num, err := strconv.Atoi(matches[1])
+		if err == nil && num > 0 {
+			return &TimeInterval{Duration: time.Duration(num) * fixedUnits[matches[2]]}, nil
+		}
+	}
+	return nil, fmt.Errorf("`interval` of `%s` is not supported", str)
+}
+
+// IsCalendar returns true if the interval is aligned to the calendar.
+func (i *TimeInterval) IsCalendar() bool {
+	return i.Unit != ""
+}
+
+// Truncate returns the start of the bucket containing the provided time.
+// Calendar intervals are aligned to the calendar of the provided location,
+// while fixed intervals are aligned to the provided anchor.
+func (i *TimeInterval) Truncate(t time.Time, anchor time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch i.Unit {
+	case "day":
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	case "week":
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		// weeks start on monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	case "quarter":
+		month := ((t.Month()-1)/3)*3 + 1
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, loc)
+	case "year":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, loc)
+	}
+	delta := t.Sub(anchor)
+	buckets := delta / i.Duration
+	if delta < 0 && delta%i.Duration != 0 {
+		buckets--
+	}
+	return anchor.Add(buckets * i.Duration).In(loc)
+}
+
+// Next returns the start of the bucket following the provided bucket start.
+func (i *TimeInterval) Next(t time.Time) time.Time {
+	switch i.Unit {
+	case "day":
+		return t.AddDate(0, 0, 1)
+	case "week":
+		return t.AddDate(0, 0, 7)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	case "quarter":
+		return t.AddDate(0, 3, 0)
+	case "year":
+		return t.AddDate(1, 0, 0)
+	}
+	return t.Add(i.Duration)
+}
+
+// ParseTime parses a date string in one of the supported layouts. Dates
+// without a time zone are interpreted as UTC.
+func ParseTime(str string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, str)
+		if err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("`%s` is not a supported date format", str)
+}
+
+// CastTime converts a value into a time. Numeric values are interpreted as
+// milliseconds since the epoch.
+func CastTime(val interface{}) (time.Time, error) {
+	switch v := val.(type) {
+	case float64:
+		return FromMillis(int64(v)), nil
+	case int:
+		return FromMillis(int64(v)), nil
+	case int64:
+		return FromMillis(v), nil
+	case string:
+		return ParseTime(v)
+	case time.Time:
+		return v.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("`%v` is not a valid time value", val)
+}
+
+// ToMillis returns the provided time as milliseconds since the epoch.
+func ToMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// FromMillis returns the time of the provided milliseconds since the epoch.
+func FromMillis(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC()
+}
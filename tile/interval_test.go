@@ -0,0 +1,123 @@
+package tile_test
+
+import (
+	"time"
+
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("TimeInterval", func() {
+
+	Describe("ParseInterval", func() {
+		It("should parse calendar and fixed intervals", func() {
+			day, err := tile.ParseInterval("day")
+			Expect(err).To(BeNil())
+			Expect(day.IsCalendar()).To(BeTrue())
+			Expect(day.Unit).To(Equal("day"))
+			fixed, err := tile.ParseInterval("90m")
+			Expect(err).To(BeNil())
+			Expect(fixed.IsCalendar()).To(BeFalse())
+			Expect(fixed.Duration).To(Equal(90 * time.Minute))
+			millis, err := tile.ParseInterval("1000")
+			Expect(err).To(BeNil())
+			Expect(millis.Duration).To(Equal(time.Second))
+		})
+
+		It("should return an error for unsupported intervals", func() {
+			_, err := tile.ParseInterval("fortnight")
+			Expect(err).NotTo(BeNil())
+			_, err = tile.ParseInterval("0s")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Truncate", func() {
+		It("should align calendar intervals to the calendar of the location", func() {
+			loc, err := time.LoadLocation("America/Toronto")
+			Expect(err).To(BeNil())
+			day, _ := tile.ParseInterval("day")
+			t := time.Date(2017, 3, 2, 3, 0, 0, 0, time.UTC)
+			Expect(day.Truncate(t, time.Time{}, time.UTC)).To(Equal(time.Date(2017, 3, 2, 0, 0, 0, 0, time.UTC)))
+			// 3am UTC is 10pm of the previous day in toronto
+			Expect(day.Truncate(t, time.Time{}, loc).Equal(time.Date(2017, 3, 1, 5, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("should align weeks to monday", func() {
+			week, _ := tile.ParseInterval("week")
+			t := time.Date(2017, 3, 2, 3, 0, 0, 0, time.UTC)
+			Expect(week.Truncate(t, time.Time{}, time.UTC)).To(Equal(time.Date(2017, 2, 27, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should align fixed intervals to the anchor", func() {
+			fixed, _ := tile.ParseInterval("2s")
+			anchor := tile.FromMillis(500)
+			Expect(tile.ToMillis(fixed.Truncate(tile.FromMillis(2400), anchor, time.UTC))).To(Equal(int64(500)))
+			Expect(tile.ToMillis(fixed.Truncate(tile.FromMillis(2500), anchor, time.UTC))).To(Equal(int64(2500)))
+			Expect(tile.ToMillis(fixed.Truncate(tile.FromMillis(0), anchor, time.UTC))).To(Equal(int64(-1500)))
+		})
+	})
+})
+
+var _ = Describe("Frequency", func() {
+
+	Describe("GetBucketTimestamps", func() {
+		It("should extend the buckets to the range bounds", func() {
+			f := &tile.Frequency{}
+			err := f.Parse(JSON(`{"frequencyField": "t", "gte": 0, "lt": 4000, "interval": "1s"}`))
+			Expect(err).To(BeNil())
+			interval, _ := tile.ParseInterval(f.Interval)
+			timestamps, err := f.GetBucketTimestamps(interval, []int64{2000})
+			Expect(err).To(BeNil())
+			Expect(timestamps).To(Equal([]int64{0, 1000, 2000, 3000}))
+		})
+
+		It("should fill calendar buckets in the time zone", func() {
+			f := &tile.Frequency{}
+			err := f.Parse(JSON(`{
+				"frequencyField": "t",
+				"gte": "2017-01-01T05:00:00Z",
+				"lte": "2017-03-15T00:00:00Z",
+				"interval": "month",
+				"timeZone": "America/Toronto"
+			}`))
+			Expect(err).To(BeNil())
+			interval, _ := tile.ParseInterval(f.Interval)
+			timestamps, err := f.GetBucketTimestamps(interval, nil)
+			Expect(err).To(BeNil())
+			Expect(timestamps).To(Equal([]int64{
+				tile.ToMillis(time.Date(2017, 1, 1, 5, 0, 0, 0, time.UTC)),
+				tile.ToMillis(time.Date(2017, 2, 1, 5, 0, 0, 0, time.UTC)),
+				tile.ToMillis(time.Date(2017, 3, 1, 5, 0, 0, 0, time.UTC)),
+			}))
+		})
+
+		It("should return an error if the range produces too many buckets", func() {
+			f := &tile.Frequency{}
+			err := f.Parse(JSON(`{"frequencyField": "t", "gte": 0, "lt": 86400000000, "interval": "1s"}`))
+			Expect(err).To(BeNil())
+			interval, _ := tile.ParseInterval(f.Interval)
+			_, err = f.GetBucketTimestamps(interval, nil)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should allow up to the maximum number of buckets", func() {
+			f := &tile.Frequency{}
+			err := f.Parse(JSON(`{"frequencyField": "t", "gte": 0, "lt": 10000000, "interval": "1s"}`))
+			Expect(err).To(BeNil())
+			interval, _ := tile.ParseInterval(f.Interval)
+			timestamps, err := f.GetBucketTimestamps(interval, nil)
+			Expect(err).To(BeNil())
+			Expect(timestamps).To(HaveLen(tile.MaxFrequencyBuckets))
+		})
+
+		It("should return an error for unsupported time zones", func() {
+			f := &tile.Frequency{}
+			err := f.Parse(JSON(`{"frequencyField": "t", "gte": 0, "interval": "day", "timeZone": "Nowhere/Special"}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -65,9 +65,46 @@ func EncodeEdgeLOD(data []float32, lod int) []byte {
 	return encodeLOD(edges, offsets)
 }
 
+// sortHitsByEdge sorts the hits in the same order as the edges will be sorted
+// by EdgeLOD, such that both arrays align by index.
+func sortHitsByEdge(hits []map[string]interface{}, data []float32) {
+	// exit early if no hits
+	if hits == nil {
+		return
+	}
+	hitsArr := make(hitsArray, len(hits))
+	for i, hit := range hits {
+		x, y := edgeKey(data[i*edgeStride:])
+		hitsArr[i] = &hitWrapper{
+			x:    x,
+			y:    y,
+			data: hit,
+		}
+	}
+	sort.Stable(hitsArr)
+	// copy back into same arr
+	for i, hit := range hitsArr {
+		hits[i] = hit.data
+	}
+}
+
+// edgeKey returns the point of the edge used for sorting, which is the first
+// point within the tile.
+func edgeKey(edge []float32) (float32, float32) {
+	if inTile(edge[0], edge[1]) {
+		return edge[0], edge[1]
+	}
+	return edge[3], edge[4]
+}
+
+func inTile(x float32, y float32) bool {
+	maxPixel := float32(binning.MaxTileResolution)
+	return x >= 0.0 && x < maxPixel &&
+		y >= 0.0 && y < maxPixel
+}
+
 func sortEdges(data []float32) []float32 {
 	edges := make(edgeArray, len(data)/edgeStride)
-	maxPixel := float32(binning.MaxTileResolution)
 	for i := 0; i < len(data); i += edgeStride {
 		ax := data[i]   // src x
 		ay := data[i+1] // src y
@@ -76,15 +113,15 @@ func sortEdges(data []float32) []float32 {
 		by := data[i+4] // dst y
 		bw := data[i+5] // dst weight
 		// ensure first point is within the tile
-		if ax >= 0.0 && ax < maxPixel &&
-			ay >= 0.0 && ay < maxPixel {
+		if inTile(ax, ay) {
 			edges[i/edgeStride] = [edgeStride]float32{ax, ay, aw, bx, by, bw}
 		} else {
 			edges[i/edgeStride] = [edgeStride]float32{bx, by, bw, ax, ay, aw}
 		}
 	}
-	// sort the edges
-	sort.Sort(edges)
+	// sort the edges, preserving the order of equal codes so that hits may be
+	// aligned
+	sort.Stable(edges)
 	// convert to flat array
 	res := make([]float32, len(edges)*edgeStride)
 	for i, edge := range edges {
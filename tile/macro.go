@@ -1,6 +1,7 @@
 package tile
 
 import (
+	"github.com/unchartedsoftware/veldt/binning"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -17,6 +18,13 @@ func (m *Macro) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Params returns the descriptions of the parameters parsed by Parse.
+func (m *Macro) Params() []json.Param {
+	return []json.Param{
+		{Name: "lod", Type: json.IntegerType, Default: 0},
+	}
+}
+
 // Encode will encode the tile results based on the LOD property.
 func (m *Macro) Encode(points []float32) ([]byte, error) {
 	// encode the results
@@ -25,3 +33,8 @@ func (m *Macro) Encode(points []float32) ([]byte, error) {
 	}
 	return EncodeFloat32(points), nil
 }
+
+// Empty returns the encoded tile containing no points.
+func (m *Macro) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return m.Encode(make([]float32, 0))
+}
@@ -17,6 +17,13 @@ func (e *MacroEdge) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Params returns the descriptions of the parameters parsed by Parse.
+func (e *MacroEdge) Params() []json.Param {
+	return []json.Param{
+		{Name: "lod", Type: json.IntegerType, Default: 0},
+	}
+}
+
 // ParseIncludes parses the included attributes to ensure they include the raw
 // data coordinates.
 func (e *MacroEdge) ParseIncludes(includes []string, srcXField string, srcYField string, dstXField string, dstYField string, weightField string) []string {
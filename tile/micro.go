@@ -3,6 +3,7 @@ package tile
 import (
 	"sort"
 
+	"github.com/unchartedsoftware/veldt/binning"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -23,6 +24,13 @@ func (m *Micro) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Params returns the descriptions of the parameters parsed by Parse.
+func (m *Micro) Params() []json.Param {
+	return []json.Param{
+		{Name: "lod", Type: json.IntegerType, Default: 0},
+	}
+}
+
 // ParseIncludes parses the included attributes to ensure they include the raw
 // data coordinates.
 func (m *Micro) ParseIncludes(includes []string, xField string, yField string) []string {
@@ -89,6 +97,11 @@ func (m *Micro) Encode(hits []map[string]interface{}, points []float32) ([]byte,
 	})
 }
 
+// Empty returns the encoded tile containing no points or hits.
+func (m *Micro) Empty(coord *binning.TileCoord) ([]byte, error) {
+	return m.Encode(make([]map[string]interface{}, 0), make([]float32, 0))
+}
+
 func existsIn(val string, arr []string) bool {
 	for _, v := range arr {
 		if v == val {
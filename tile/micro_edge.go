@@ -18,6 +18,9 @@ type MicroEdge struct {
 	dstYField    string
 	dstXIncluded bool
 	dstYIncluded bool
+	// weight
+	weightField    string
+	weightIncluded bool
 }
 
 // Parse parses the provided JSON object and populates the structs attributes.
@@ -27,14 +30,22 @@ func (e *MicroEdge) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Params returns the descriptions of the parameters parsed by Parse.
+func (e *MicroEdge) Params() []json.Param {
+	return []json.Param{
+		{Name: "lod", Type: json.IntegerType, Default: 0},
+	}
+}
+
 // ParseIncludes parses the included attributes to ensure they include the raw
-// data coordinates.
-func (e *MicroEdge) ParseIncludes(includes []string, srcXField string, srcYField string, dstXField string, dstYField string) []string {
+// data coordinates and weight.
+func (e *MicroEdge) ParseIncludes(includes []string, srcXField string, srcYField string, dstXField string, dstYField string, weightField string) []string {
 	// store x / y fields
 	e.srcXField = srcXField
 	e.srcYField = srcYField
 	e.dstXField = dstXField
 	e.dstYField = dstYField
+	e.weightField = weightField
 	// src includes
 	if !existsIn(e.srcXField, includes) {
 		includes = append(includes, e.srcXField)
@@ -57,15 +68,24 @@ func (e *MicroEdge) ParseIncludes(includes []string, srcXField string, srcYField
 	} else {
 		e.dstYIncluded = true
 	}
+	// weight includes
+	if !existsIn(e.weightField, includes) {
+		includes = append(includes, e.weightField)
+	} else {
+		e.weightIncluded = true
+	}
 	return includes
 }
 
-// Encode will encode the tile results
-func (e *MicroEdge) Encode(hits []map[string]interface{}, points []float32) ([]byte, error) {
+// Encode will encode the tile results. Edges are expected as a flat array of
+// source x, y and weight followed by destination x, y and weight, with one
+// hit per edge.
+func (e *MicroEdge) Encode(hits []map[string]interface{}, edges []float32) ([]byte, error) {
 	emptyHits := false
 	// remove any non-included fields from hits
 	if !e.srcXIncluded || !e.srcYIncluded ||
-		!e.dstXIncluded || !e.dstYIncluded {
+		!e.dstXIncluded || !e.dstYIncluded ||
+		!e.weightIncluded {
 		for _, hit := range hits {
 			// remove fields if they weren't explicitly included
 			if !e.srcXIncluded {
@@ -80,6 +100,9 @@ func (e *MicroEdge) Encode(hits []map[string]interface{}, points []float32) ([]b
 			if !e.dstYIncluded {
 				delete(hit, e.dstYField)
 			}
+			if !e.weightIncluded {
+				delete(hit, e.weightField)
+			}
 			if !emptyHits && len(hit) == 0 {
 				emptyHits = true
 			}
@@ -94,11 +117,11 @@ func (e *MicroEdge) Encode(hits []map[string]interface{}, points []float32) ([]b
 
 	// encode using LOD
 	if e.LOD > 0 {
-		// NOTE: during LOD points are sorted by morton code, therefore we sort
+		// NOTE: during LOD edges are sorted by morton code, therefore we sort
 		// the hits by morton code as well to ensure both arrays align by index.
-		sortHitsArray(hits, points)
-		// sort points and get offsets
-		sorted, offsets := LOD(points, e.LOD)
+		sortHitsByEdge(hits, edges)
+		// sort edges and get offsets
+		sorted, offsets := EdgeLOD(edges, e.LOD)
 		return json.Marshal(map[string]interface{}{
 			"points":  sorted,
 			"offsets": offsets,
@@ -107,7 +130,7 @@ func (e *MicroEdge) Encode(hits []map[string]interface{}, points []float32) ([]b
 	}
 	// encode without LOD
 	return json.Marshal(map[string]interface{}{
-		"points": points,
+		"points": edges,
 		"hits":   hits,
 	})
 }
@@ -0,0 +1,87 @@
+package tile_test
+
+import (
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("MicroEdge", func() {
+
+	var micro *tile.MicroEdge
+
+	BeforeEach(func() {
+		micro = &tile.MicroEdge{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"lod": 4
+				}`)
+			err := micro.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(micro.LOD).To(Equal(4))
+		})
+	})
+
+	Describe("ParseIncludes", func() {
+		It("should ensure the edge fields and weight are included in the includes", func() {
+			includes := micro.ParseIncludes([]string{"b", "w"}, "a", "b", "c", "d", "w")
+			Expect(includes).To(Equal([]string{"b", "w", "a", "c", "d"}))
+		})
+	})
+
+	Describe("Encode", func() {
+		It("should remove fields which were not explicitly included", func() {
+			micro.ParseIncludes([]string{"name"}, "a", "b", "c", "d", "w")
+			hits := []map[string]interface{}{
+				{"name": "x", "a": 1, "b": 1, "c": 2, "d": 2, "w": 1},
+			}
+			bytes, err := micro.Encode(hits, []float32{1, 1, 1, 2, 2, 1})
+			Expect(err).To(BeNil())
+			Expect(JSON(string(bytes))).To(Equal(JSON(
+				`{
+					"points": [1, 1, 1, 2, 2, 1],
+					"hits": [{"name": "x"}]
+				}`)))
+		})
+
+		It("should align the hits with the edges when using LOD", func() {
+			micro.ParseIncludes([]string{"name"}, "a", "b", "c", "d", "w")
+			params := JSON(
+				`{
+					"lod": 1
+				}`)
+			err := micro.Parse(params)
+			Expect(err).To(BeNil())
+			hits := []map[string]interface{}{
+				{"name": "x"},
+				{"name": "y"},
+				{"name": "z"},
+			}
+			edges := []float32{
+				200, 200, 1, 10, 10, 1,
+				// source outside of the tile, sorted by destination
+				-10, -10, 2, 2, 2, 2,
+				100, 100, 3, 300, 300, 3,
+			}
+			bytes, err := micro.Encode(hits, edges)
+			Expect(err).To(BeNil())
+			Expect(JSON(string(bytes))).To(Equal(JSON(
+				`{
+					"points": [
+						2, 2, 2, -10, -10, 2,
+						100, 100, 3, 300, 300, 3,
+						200, 200, 1, 10, 10, 1
+					],
+					"offsets": [0, 48, 48, 48],
+					"hits": [{"name": "y"}, {"name": "z"}, {"name": "x"}]
+				}`)))
+		})
+	})
+
+})
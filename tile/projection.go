@@ -0,0 +1,81 @@
+package tile
+
+import (
+	"math"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// LinearProjection represents a projection where the tile bounds are
+	// linearly interpolated from the global data space bounds.
+	LinearProjection = "linear"
+	// MercatorProjection represents a web mercator projection of lon / lat
+	// data, where the x field is longitude and the y field is latitude.
+	MercatorProjection = "mercator"
+)
+
+func parseProjection(params map[string]interface{}) (string, *json.FieldError) {
+	projection := json.GetStringDefault(params, LinearProjection, "projection")
+	if projection != LinearProjection && projection != MercatorProjection {
+		err := json.NewFieldError("projection", json.EnumCode,
+			"`projection` must be either `linear` or `mercator`")
+		err.Expected = "`linear`, `mercator`"
+		return "", err
+	}
+	return projection, nil
+}
+
+// projectionParams returns the descriptions of the projection and global
+// bounds parameters.
+func projectionParams() []json.Param {
+	bounds := "required unless the projection is `mercator`"
+	return []json.Param{
+		{Name: "projection", Type: json.StringType, Default: LinearProjection, Enum: []interface{}{LinearProjection, MercatorProjection}},
+		{Name: "left", Type: json.NumberType, Description: bounds},
+		{Name: "right", Type: json.NumberType, Description: bounds},
+		{Name: "bottom", Type: json.NumberType, Description: bounds},
+		{Name: "top", Type: json.NumberType, Description: bounds},
+	}
+}
+
+// mercatorY given a latitude, returns the projected position within the
+// range of [0 : 1) for the tile.
+func mercatorY(coord *binning.TileCoord, lat float64) float64 {
+	fractional := binning.LonLatToFractionalTile(binning.NewLonLat(0, lat), coord.Z)
+	return fractional.Y - float64(coord.Y)
+}
+
+// mercatorYBinBounds returns the latitude boundaries of each bin across the y
+// axis of the tile in ascending order, computed with the inverse mercator
+// transform. The resolution includes the padding bins on either side of the
+// tile.
+func mercatorYBinBounds(coord *binning.TileCoord, resolution int, padding int) []float64 {
+	tileResolution := float64(resolution - padding*2)
+	bounds := make([]float64, resolution+1)
+	for i := range bounds {
+		lonLat := binning.FractionalTileToLonLat(&binning.FractionalTileCoord{
+			X: float64(coord.X),
+			Y: float64(coord.Y) + float64(i-padding)/tileResolution,
+			Z: coord.Z,
+		})
+		bounds[i] = lonLat.Lat
+	}
+	return bounds
+}
+
+func linearBinBounds(min float64, max float64, resolution int) []float64 {
+	bounds := make([]float64, resolution+1)
+	size := (max - min) / float64(resolution)
+	for i := range bounds {
+		bounds[i] = min + float64(i)*size
+	}
+	// avoid accumulated floating point error at the upper boundary
+	bounds[resolution] = max
+	return bounds
+}
+
+func floorBin(pos float64, resolution int) int64 {
+	return int64(math.Floor(pos * float64(resolution)))
+}
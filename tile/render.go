@@ -0,0 +1,283 @@
+package tile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// LinearTransform represents a linear value transform.
+	LinearTransform = "linear"
+	// LogTransform represents a logarithmic value transform.
+	LogTransform = "log"
+	// SqrtTransform represents a square root value transform.
+	SqrtTransform = "sqrt"
+	// EqualizedTransform represents a histogram equalized value transform.
+	EqualizedTransform = "equalized"
+)
+
+// Render represents the parameters required to render a tile of numeric bins
+// into a colored RGBA image.
+type Render struct {
+	ColorRamp  string
+	Transform  string
+	Format     string
+	BinType    string
+	Extrema    *binning.Extrema
+	MetaParams map[string]interface{}
+	MetaPath   []string
+	ramp       ColorRamp
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (r *Render) Parse(params map[string]interface{}) error {
+	// get color ramp
+	colorRamp := json.GetStringDefault(params, "viridis", "colorRamp")
+	ramp, err := GetColorRamp(colorRamp)
+	if err != nil {
+		return err
+	}
+	// get transform
+	transform := json.GetStringDefault(params, LinearTransform, "transform")
+	if transform != LinearTransform &&
+		transform != LogTransform &&
+		transform != SqrtTransform &&
+		transform != EqualizedTransform {
+		return fmt.Errorf("`transform` must be one of `linear`, `log`, `sqrt` or `equalized`")
+	}
+	// get output format
+	format := json.GetStringDefault(params, "png", "format")
+	if format != "png" && format != "rgba" {
+		return fmt.Errorf("`format` must be either `png` or `rgba`")
+	}
+	// get bin type
+	binType := json.GetStringDefault(params, "uint32", "binType")
+	if binType != "uint32" && binType != "float32" {
+		return fmt.Errorf("`binType` must be either `uint32` or `float32`")
+	}
+	// get optional extrema
+	var extrema *binning.Extrema
+	if json.Exists(params, "extrema") {
+		min, ok := json.GetFloat(params, "extrema", "min")
+		if !ok {
+			return fmt.Errorf("`extrema.min` parameter missing from tile")
+		}
+		max, ok := json.GetFloat(params, "extrema", "max")
+		if !ok {
+			return fmt.Errorf("`extrema.max` parameter missing from tile")
+		}
+		extrema = &binning.Extrema{
+			Min: min,
+			Max: max,
+		}
+	}
+	// get optional meta extrema location
+	var metaParams map[string]interface{}
+	var metaPath []string
+	if json.Exists(params, "extremaMeta") {
+		path, ok := json.GetStringArray(params, "extremaMeta", "path")
+		if !ok || len(path) == 0 {
+			return fmt.Errorf("`extremaMeta.path` parameter missing from tile")
+		}
+		metaParams, ok = json.GetChild(params, "extremaMeta", "params")
+		if !ok {
+			metaParams = make(map[string]interface{})
+		}
+		metaPath = path
+	}
+	r.ColorRamp = colorRamp
+	r.Transform = transform
+	r.Format = format
+	r.BinType = binType
+	r.Extrema = extrema
+	r.MetaParams = metaParams
+	r.MetaPath = metaPath
+	r.ramp = ramp
+	return nil
+}
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (r *Render) Params() []json.Param {
+	return []json.Param{
+		{Name: "colorRamp", Type: json.StringType, Default: "viridis"},
+		{Name: "transform", Type: json.StringType, Default: LinearTransform, Enum: []interface{}{LinearTransform, LogTransform, SqrtTransform, EqualizedTransform}},
+		{Name: "format", Type: json.StringType, Default: "png", Enum: []interface{}{"png", "rgba"}},
+		{Name: "binType", Type: json.StringType, Default: "uint32", Enum: []interface{}{"uint32", "float32"}},
+		{Name: "extrema", Type: json.ObjectType},
+		{Name: "extremaMeta", Type: json.ObjectType},
+	}
+}
+
+// DecodeBins decodes the little endian byte array of a heatmap tile into its
+// numeric bins.
+func (r *Render) DecodeBins(data []byte) ([]float64, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("heatmap tile byte length of %d is not a multiple of 4", len(data))
+	}
+	bins := make([]float64, len(data)/4)
+	for i := range bins {
+		bits := binary.LittleEndian.Uint32(data[i*4 : i*4+4])
+		if r.BinType == "float32" {
+			bins[i] = float64(math.Float32frombits(bits))
+		} else {
+			bins[i] = float64(bits)
+		}
+	}
+	return bins, nil
+}
+
+// GetMetaExtrema parses the extrema from the provided meta data payload using
+// the configured meta path. The path may point either directly to an extrema
+// object or to a property containing an `extrema` attribute.
+func (r *Render) GetMetaExtrema(meta []byte) (*binning.Extrema, error) {
+	parsed, err := json.Unmarshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := json.GetChild(parsed, r.MetaPath...)
+	if !ok {
+		return nil, fmt.Errorf("meta data does not contain an object under `%v`", r.MetaPath)
+	}
+	extrema, ok := json.GetChild(node, "extrema")
+	if ok {
+		node = extrema
+	}
+	min, ok := json.GetFloat(node, "min")
+	if !ok {
+		return nil, fmt.Errorf("meta data under `%v` does not contain `min`", r.MetaPath)
+	}
+	max, ok := json.GetFloat(node, "max")
+	if !ok {
+		return nil, fmt.Errorf("meta data under `%v` does not contain `max`", r.MetaPath)
+	}
+	return &binning.Extrema{
+		Min: min,
+		Max: max,
+	}, nil
+}
+
+// GetExtrema returns the extrema of the non-empty bins.
+func (r *Render) GetExtrema(bins []float64) *binning.Extrema {
+	extrema := &binning.Extrema{
+		Min: math.MaxFloat64,
+		Max: -math.MaxFloat64,
+	}
+	for _, bin := range bins {
+		if bin <= 0 {
+			continue
+		}
+		extrema.Min = math.Min(extrema.Min, bin)
+		extrema.Max = math.Max(extrema.Max, bin)
+	}
+	if extrema.Min > extrema.Max {
+		return &binning.Extrema{}
+	}
+	return extrema
+}
+
+// Encode renders the provided square array of bins into a colored image using
+// the provided extrema. Empty bins are rendered fully transparent.
+func (r *Render) Encode(bins []float64, extrema *binning.Extrema) ([]byte, error) {
+	resolution := int(math.Sqrt(float64(len(bins))))
+	if resolution*resolution != len(bins) {
+		return nil, fmt.Errorf("bin count of %d does not form a square tile", len(bins))
+	}
+	ramp := r.ramp
+	if ramp == nil {
+		var err error
+		ramp, err = GetColorRamp(r.ColorRamp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// get the normalized value for each bin
+	values := r.normalize(bins, extrema)
+	// bins are ordered bottom-left first, image rows are ordered top-down
+	rgba := make([]byte, len(bins)*4)
+	for i, bin := range bins {
+		if bin <= 0 {
+			continue
+		}
+		x := i % resolution
+		y := resolution - 1 - (i / resolution)
+		c := ramp.Interpolate(values[i])
+		setPixel(rgba, (x+y*resolution)*4, c)
+	}
+	if r.Format == "rgba" {
+		return rgba, nil
+	}
+	return EncodeImage("png", rgba, resolution, resolution)
+}
+
+func setPixel(rgba []byte, offset int, c color.RGBA) {
+	rgba[offset] = c.R
+	rgba[offset+1] = c.G
+	rgba[offset+2] = c.B
+	rgba[offset+3] = c.A
+}
+
+func (r *Render) normalize(bins []float64, extrema *binning.Extrema) []float64 {
+	values := make([]float64, len(bins))
+	switch r.Transform {
+	case EqualizedTransform:
+		return equalize(bins, extrema)
+	case LogTransform:
+		min := math.Log10(math.Max(extrema.Min, 0) + 1)
+		max := math.Log10(math.Max(extrema.Max, 0) + 1)
+		for i, bin := range bins {
+			values[i] = ratio(math.Log10(math.Max(bin, 0)+1), min, max)
+		}
+	case SqrtTransform:
+		min := math.Sqrt(math.Max(extrema.Min, 0))
+		max := math.Sqrt(math.Max(extrema.Max, 0))
+		for i, bin := range bins {
+			values[i] = ratio(math.Sqrt(math.Max(bin, 0)), min, max)
+		}
+	default:
+		for i, bin := range bins {
+			values[i] = ratio(bin, extrema.Min, extrema.Max)
+		}
+	}
+	return values
+}
+
+func ratio(value float64, min float64, max float64) float64 {
+	if max <= min {
+		// all values are identical, render at full intensity
+		return 1
+	}
+	return math.Max(0, math.Min(1, (value-min)/(max-min)))
+}
+
+func equalize(bins []float64, extrema *binning.Extrema) []float64 {
+	// collect the clamped, non-empty values
+	sorted := make([]float64, 0, len(bins))
+	for _, bin := range bins {
+		if bin > 0 {
+			sorted = append(sorted, math.Max(extrema.Min, math.Min(extrema.Max, bin)))
+		}
+	}
+	sort.Float64s(sorted)
+	values := make([]float64, len(bins))
+	if len(sorted) == 0 {
+		return values
+	}
+	// each value maps to the cumulative distribution of values below it
+	for i, bin := range bins {
+		if bin <= 0 {
+			continue
+		}
+		clamped := math.Max(extrema.Min, math.Min(extrema.Max, bin))
+		rank := sort.Search(len(sorted), func(j int) bool {
+			return sorted[j] > clamped
+		})
+		values[i] = float64(rank) / float64(len(sorted))
+	}
+	return values
+}
@@ -0,0 +1,195 @@
+package tile_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Render", func() {
+
+	var render *tile.Render
+
+	BeforeEach(func() {
+		render = &tile.Render{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"colorRamp": "inferno",
+					"transform": "log",
+					"format": "rgba",
+					"binType": "float32",
+					"extrema": {
+						"min": 1,
+						"max": 100
+					}
+				}`)
+			err := render.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(render.ColorRamp).To(Equal("inferno"))
+			Expect(render.Transform).To(Equal("log"))
+			Expect(render.Format).To(Equal("rgba"))
+			Expect(render.BinType).To(Equal("float32"))
+			Expect(render.Extrema).To(Equal(&binning.Extrema{
+				Min: 1,
+				Max: 100,
+			}))
+		})
+
+		It("should use defaults for unspecified properties", func() {
+			params := JSON(`{}`)
+			err := render.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(render.ColorRamp).To(Equal("viridis"))
+			Expect(render.Transform).To(Equal("linear"))
+			Expect(render.Format).To(Equal("png"))
+			Expect(render.BinType).To(Equal("uint32"))
+			Expect(render.Extrema).To(BeNil())
+			Expect(render.MetaPath).To(BeNil())
+		})
+
+		It("should parse the `extremaMeta` property", func() {
+			params := JSON(
+				`{
+					"extremaMeta": {
+						"path": ["tweet", "count"],
+						"params": {
+							"a": "b"
+						}
+					}
+				}`)
+			err := render.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(render.MetaPath).To(Equal([]string{"tweet", "count"}))
+			Expect(render.MetaParams).To(Equal(map[string]interface{}{
+				"a": "b",
+			}))
+		})
+
+		It("should return an error if `colorRamp` is not recognized", func() {
+			params := JSON(`{"colorRamp": "invalid"}`)
+			err := render.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `transform` is not recognized", func() {
+			params := JSON(`{"transform": "invalid"}`)
+			err := render.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `extrema` is missing a bound", func() {
+			params := JSON(`{"extrema": {"min": 0}}`)
+			err := render.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `extremaMeta` is missing a path", func() {
+			params := JSON(`{"extremaMeta": {}}`)
+			err := render.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("DecodeBins", func() {
+		It("should decode uint32 bins", func() {
+			render.Parse(JSON(`{}`))
+			data := make([]byte, 8)
+			binary.LittleEndian.PutUint32(data[0:4], 3)
+			binary.LittleEndian.PutUint32(data[4:8], 7)
+			bins, err := render.DecodeBins(data)
+			Expect(err).To(BeNil())
+			Expect(bins).To(Equal([]float64{3, 7}))
+		})
+		It("should decode float32 bins", func() {
+			render.Parse(JSON(`{"binType": "float32"}`))
+			data := make([]byte, 4)
+			binary.LittleEndian.PutUint32(data, math.Float32bits(1.5))
+			bins, err := render.DecodeBins(data)
+			Expect(err).To(BeNil())
+			Expect(bins).To(Equal([]float64{1.5}))
+		})
+		It("should return an error if the byte length is invalid", func() {
+			render.Parse(JSON(`{}`))
+			_, err := render.DecodeBins(make([]byte, 3))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetMetaExtrema", func() {
+		It("should return the extrema under the meta path", func() {
+			render.Parse(JSON(`{"extremaMeta": {"path": ["type", "count"]}}`))
+			meta := []byte(`{"type":{"count":{"type":"long","extrema":{"min":2,"max":8}}}}`)
+			extrema, err := render.GetMetaExtrema(meta)
+			Expect(err).To(BeNil())
+			Expect(extrema).To(Equal(&binning.Extrema{
+				Min: 2,
+				Max: 8,
+			}))
+		})
+		It("should return an error if the path does not exist", func() {
+			render.Parse(JSON(`{"extremaMeta": {"path": ["missing"]}}`))
+			_, err := render.GetMetaExtrema([]byte(`{}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetExtrema", func() {
+		It("should return the extrema of non-empty bins", func() {
+			extrema := render.GetExtrema([]float64{0, 4, 2, 0})
+			Expect(extrema).To(Equal(&binning.Extrema{
+				Min: 2,
+				Max: 4,
+			}))
+		})
+	})
+
+	Describe("Encode", func() {
+		It("should render bins into rgba with the bottom row last", func() {
+			render.Parse(JSON(`{"colorRamp": "greyscale", "format": "rgba"}`))
+			bins := []float64{
+				1, 0,
+				0, 3,
+			}
+			rgba, err := render.Encode(bins, &binning.Extrema{Min: 1, Max: 3})
+			Expect(err).To(BeNil())
+			Expect(rgba).To(Equal([]byte{
+				0, 0, 0, 0, 255, 255, 255, 255,
+				0, 0, 0, 255, 0, 0, 0, 0,
+			}))
+		})
+		It("should apply the transform to the bins", func() {
+			render.Parse(JSON(`{"colorRamp": "greyscale", "format": "rgba", "transform": "equalized"}`))
+			bins := []float64{1, 2, 100, 1000}
+			rgba, err := render.Encode(bins, &binning.Extrema{Min: 1, Max: 1000})
+			Expect(err).To(BeNil())
+			Expect(rgba).To(Equal([]byte{
+				191, 191, 191, 255, 255, 255, 255, 255,
+				64, 64, 64, 255, 128, 128, 128, 255,
+			}))
+		})
+		It("should encode the result as png", func() {
+			render.Parse(JSON(`{}`))
+			png, err := render.Encode(make([]float64, 16), &binning.Extrema{})
+			Expect(err).To(BeNil())
+			rgba, err := tile.DecodeImage("png", bytes.NewBuffer(png))
+			Expect(err).To(BeNil())
+			Expect(rgba).To(Equal(make([]byte, 64)))
+		})
+		It("should return an error if the bins are not square", func() {
+			render.Parse(JSON(`{}`))
+			_, err := render.Encode(make([]float64, 3), &binning.Extrema{})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -27,3 +27,11 @@ func (t *TargetTerms) Parse(params map[string]interface{}) error {
 	t.Terms = terms
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (t *TargetTerms) Params() []json.Param {
+	return []json.Param{
+		{Name: "termsField", Type: json.StringType, Required: true},
+		{Name: "terms", Type: json.ArrayType, Items: json.StringType, Required: true},
+	}
+}
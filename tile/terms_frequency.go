@@ -28,3 +28,11 @@ func (t *TermsFrequency) Parse(params map[string]interface{}) error {
 	t.TermsField = termsField
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (t *TermsFrequency) Params() []json.Param {
+	return []json.Param{
+		{Name: "termsField", Type: json.StringType, Required: true},
+		{Name: "fieldType", Type: json.StringType},
+	}
+}
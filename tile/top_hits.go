@@ -36,3 +36,13 @@ func (t *TopHits) Parse(params map[string]interface{}) error {
 	t.IncludeFields = includeFields
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (t *TopHits) Params() []json.Param {
+	return []json.Param{
+		{Name: "sortField", Type: json.StringType},
+		{Name: "sortOrder", Type: json.StringType, Default: "desc", Enum: []interface{}{"desc", "asc"}},
+		{Name: "hitsCount", Type: json.IntegerType, Required: true},
+		{Name: "includeFields", Type: json.ArrayType, Items: json.StringType},
+	}
+}
@@ -34,3 +34,12 @@ func (t *TopTerms) Parse(params map[string]interface{}) error {
 	t.TermsCount = termsCount
 	return nil
 }
+
+// Params returns the descriptions of the parameters parsed by Parse.
+func (t *TopTerms) Params() []json.Param {
+	return []json.Param{
+		{Name: "termsField", Type: json.StringType, Required: true},
+		{Name: "termsCount", Type: json.IntegerType, Required: true},
+		{Name: "fieldType", Type: json.StringType},
+	}
+}
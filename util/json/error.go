@@ -0,0 +1,114 @@
+package json
+
+import (
+	"strings"
+)
+
+const (
+	// MissingCode represents a required value which was not provided.
+	MissingCode = "missing"
+	// TypeCode represents a value of the wrong type.
+	TypeCode = "type"
+	// EnumCode represents a value which is not one of the enumerated values.
+	EnumCode = "enum"
+	// UnknownCode represents an unrecognized type identifier.
+	UnknownCode = "unknown"
+	// SyntaxCode represents an unexpected token within an expression.
+	SyntaxCode = "syntax"
+	// InvalidCode represents any other invalid value.
+	InvalidCode = "invalid"
+)
+
+// FieldError represents an error of a single value within a JSON object,
+// located by a JSON pointer.
+type FieldError struct {
+	Pointer  string `json:"pointer"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// NewFieldError instantiates and returns a new error for the named parameter.
+// If the name is empty, the error is of the object itself.
+func NewFieldError(name string, code string, msg string) *FieldError {
+	pointer := ""
+	if name != "" {
+		pointer = Pointer(name)
+	}
+	return &FieldError{
+		Pointer: pointer,
+		Code:    code,
+		Message: msg,
+	}
+}
+
+// Error returns the error message.
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Errors represents multiple field errors reported at once.
+type Errors []*FieldError
+
+// Error returns the error messages.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// ValidationError represents the errors of a validated JSON object, along
+// with the annotated rendering of the object.
+type ValidationError struct {
+	Annotated string
+	Errors    []*FieldError
+}
+
+// Error returns the annotated rendering.
+func (e *ValidationError) Error() string {
+	return e.Annotated
+}
+
+// FieldErrors returns the field errors of the error, relative to the provided
+// JSON pointer. Errors which are not field errors are considered invalid
+// values at the pointer.
+func FieldErrors(pointer string, err error) []*FieldError {
+	var errs []*FieldError
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *FieldError:
+		errs = []*FieldError{e}
+	case Errors:
+		errs = e
+	default:
+		return []*FieldError{{
+			Pointer: pointer,
+			Code:    InvalidCode,
+			Message: err.Error(),
+		}}
+	}
+	res := make([]*FieldError, len(errs))
+	for i, e := range errs {
+		res[i] = &FieldError{
+			Pointer:  pointer + e.Pointer,
+			Code:     e.Code,
+			Message:  e.Message,
+			Expected: e.Expected,
+		}
+	}
+	return res
+}
+
+// Pointer returns the JSON pointer of the provided path.
+func Pointer(path ...string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	var pointer strings.Builder
+	for _, key := range path {
+		pointer.WriteString("/")
+		pointer.WriteString(escaper.Replace(key))
+	}
+	return pointer.String()
+}
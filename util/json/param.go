@@ -0,0 +1,167 @@
+package json
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// AnyType represents a parameter of any type.
+	AnyType = ""
+	// StringType represents a string parameter.
+	StringType = "string"
+	// NumberType represents a numeric parameter.
+	NumberType = "number"
+	// IntegerType represents a numeric parameter without a fractional part.
+	IntegerType = "integer"
+	// BooleanType represents a boolean parameter.
+	BooleanType = "boolean"
+	// ArrayType represents an array parameter.
+	ArrayType = "array"
+	// ObjectType represents an object parameter.
+	ObjectType = "object"
+)
+
+// Param describes a single parameter of a JSON object.
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+	Default     interface{}
+	Enum        []interface{}
+	// Items is the type of the elements of an array parameter.
+	Items string
+}
+
+// Schema returns the JSON Schema of the parameter.
+func (p Param) Schema() map[string]interface{} {
+	schema := make(map[string]interface{})
+	if p.Type != AnyType {
+		schema["type"] = p.Type
+	}
+	if p.Description != "" {
+		schema["description"] = p.Description
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if p.Type == ArrayType && p.Items != AnyType {
+		schema["items"] = map[string]interface{}{
+			"type": p.Items,
+		}
+	}
+	return schema
+}
+
+// Validate checks the value against the type and enumerated values of the
+// parameter.
+func (p Param) Validate(val interface{}) error {
+	if !isType(val, p.Type) {
+		return p.fieldError(TypeCode, p.Type,
+			fmt.Sprintf("`%s` parameter is not of type `%s`", p.Name, p.Type))
+	}
+	if p.Type == ArrayType && p.Items != AnyType {
+		for _, item := range val.([]interface{}) {
+			if !isType(item, p.Items) {
+				return p.fieldError(TypeCode, p.Items,
+					fmt.Sprintf("`%s` parameter elements are not of type `%s`", p.Name, p.Items))
+			}
+		}
+	}
+	if len(p.Enum) > 0 {
+		for _, e := range p.Enum {
+			if e == val {
+				return nil
+			}
+		}
+		enum := make([]string, len(p.Enum))
+		for i, e := range p.Enum {
+			enum[i] = fmt.Sprintf("`%v`", e)
+		}
+		expected := strings.Join(enum, ", ")
+		return p.fieldError(EnumCode, expected,
+			fmt.Sprintf("`%s` parameter must be one of %s", p.Name, expected))
+	}
+	return nil
+}
+
+func (p Param) fieldError(code string, expected string, msg string) *FieldError {
+	err := NewFieldError(p.Name, code, msg)
+	err.Expected = expected
+	return err
+}
+
+// ParamsSchema returns the JSON Schema of an object containing the provided
+// parameters.
+func ParamsSchema(params []Param) map[string]interface{} {
+	properties := make(map[string]interface{}, len(params))
+	required := make([]string, 0)
+	for _, p := range params {
+		properties[p.Name] = p.Schema()
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":       ObjectType,
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// ValidateParams checks the provided JSON object against the parameters,
+// returning the errors of all invalid parameters. Parameters which are not
+// described are ignored.
+func ValidateParams(params []Param, json map[string]interface{}) error {
+	var errs Errors
+	for _, p := range params {
+		val, ok := json[p.Name]
+		if !ok {
+			if p.Required {
+				errs = append(errs, p.fieldError(MissingCode, p.Type,
+					fmt.Sprintf("`%s` parameter missing", p.Name)))
+			}
+			continue
+		}
+		err := p.Validate(val)
+		if err != nil {
+			errs = append(errs, err.(*FieldError))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func isType(val interface{}, typ string) bool {
+	switch typ {
+	case StringType:
+		_, ok := val.(string)
+		return ok
+	case NumberType:
+		_, ok := val.(float64)
+		return ok
+	case IntegerType:
+		num, ok := val.(float64)
+		return ok && num == math.Trunc(num)
+	case BooleanType:
+		_, ok := val.(bool)
+		return ok
+	case ArrayType:
+		_, ok := val.([]interface{})
+		return ok
+	case ObjectType:
+		_, ok := val.(map[string]interface{})
+		return ok
+	}
+	return true
+}
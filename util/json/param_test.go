@@ -0,0 +1,90 @@
+package json_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+var _ = Describe("param", func() {
+
+	params := []json.Param{
+		{Name: "field", Type: json.StringType, Required: true},
+		{Name: "count", Type: json.IntegerType, Default: 10},
+		{Name: "order", Type: json.StringType, Default: "desc", Enum: []interface{}{"desc", "asc"}},
+		{Name: "fields", Type: json.ArrayType, Items: json.StringType},
+		{Name: "value"},
+	}
+
+	Describe("ValidateParams", func() {
+		It("should accept valid parameters", func() {
+			err := json.ValidateParams(params, JSON(
+				`{
+					"field": "a",
+					"count": 5,
+					"order": "asc",
+					"fields": ["a", "b"],
+					"value": [1, "b"],
+					"other": true
+				}`))
+			Expect(err).To(BeNil())
+		})
+		It("should return an error if a required parameter is missing", func() {
+			err := json.ValidateParams(params, JSON(`{"count": 5}`))
+			Expect(err).NotTo(BeNil())
+		})
+		It("should return an error if a parameter is of the wrong type", func() {
+			err := json.ValidateParams(params, JSON(`{"field": 5}`))
+			Expect(err).NotTo(BeNil())
+			err = json.ValidateParams(params, JSON(`{"field": "a", "count": 5.5}`))
+			Expect(err).NotTo(BeNil())
+			err = json.ValidateParams(params, JSON(`{"field": "a", "fields": ["a", 1]}`))
+			Expect(err).NotTo(BeNil())
+		})
+		It("should return an error if a parameter is not an enumerated value", func() {
+			err := json.ValidateParams(params, JSON(`{"field": "a", "order": "up"}`))
+			Expect(err).NotTo(BeNil())
+		})
+		It("should return the errors of all invalid parameters", func() {
+			err := json.ValidateParams(params, JSON(`{"count": "5", "order": "up"}`))
+			Expect(err).To(Equal(json.Errors{
+				{Pointer: "/field", Code: json.MissingCode, Message: "`field` parameter missing", Expected: "string"},
+				{Pointer: "/count", Code: json.TypeCode, Message: "`count` parameter is not of type `integer`", Expected: "integer"},
+				{Pointer: "/order", Code: json.EnumCode, Message: "`order` parameter must be one of `desc`, `asc`", Expected: "`desc`, `asc`"},
+			}))
+		})
+	})
+
+	Describe("ParamsSchema", func() {
+		It("should return the JSON Schema of the parameters", func() {
+			Expect(json.ParamsSchema(params)).To(Equal(map[string]interface{}{
+				"type":     "object",
+				"required": []string{"field"},
+				"properties": map[string]interface{}{
+					"field": map[string]interface{}{
+						"type": "string",
+					},
+					"count": map[string]interface{}{
+						"type":    "integer",
+						"default": 10,
+					},
+					"order": map[string]interface{}{
+						"type":    "string",
+						"default": "desc",
+						"enum":    []interface{}{"desc", "asc"},
+					},
+					"fields": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+					},
+					"value": map[string]interface{}{},
+				},
+			}))
+		})
+	})
+
+})
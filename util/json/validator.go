@@ -2,6 +2,7 @@ package json
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/unchartedsoftware/veldt/util/color"
@@ -25,16 +26,29 @@ type Validator struct {
 	errFooterIndex  int
 	errMsg          string
 	err             bool
+	frames          []frame
+	fieldErrs       []*FieldError
+}
+
+// frame represents an object or array being buffered, used to track the JSON
+// pointer of the buffered values.
+type frame struct {
+	key   string
+	named bool
+	array bool
+	next  int
 }
 
 // StartObject begins the buffering of an object.
 func (v *Validator) StartObject() {
+	v.pushElement(false)
 	v.buffer("{")
 	v.nextIndentation++
 }
 
 // StartSubObject begins the buffering of a nested object to a key.
 func (v *Validator) StartSubObject(key string) {
+	v.push(key, false)
 	v.buffer(fmt.Sprintf(`"%s": {`, key))
 	v.nextIndentation++
 }
@@ -45,16 +59,19 @@ func (v *Validator) EndObject() {
 		v.nextIndentation--
 	}
 	v.buffer("}")
+	v.pop()
 }
 
 // StartArray begins the buffering of an array.
 func (v *Validator) StartArray() {
+	v.pushElement(true)
 	v.buffer("[")
 	v.nextIndentation++
 }
 
 // StartSubArray begins the buffering of an array value to a key.
 func (v *Validator) StartSubArray(key string) {
+	v.push(key, true)
 	v.buffer(fmt.Sprintf(`"%s": [`, key))
 	v.nextIndentation++
 }
@@ -65,6 +82,7 @@ func (v *Validator) EndArray() {
 		v.nextIndentation--
 	}
 	v.buffer("]")
+	v.pop()
 }
 
 // Size returns the length of the current output buffer.
@@ -80,11 +98,19 @@ func (v *Validator) HasError() bool {
 // Error returns the error if there is one.
 func (v *Validator) Error() error {
 	if v.err {
-		return fmt.Errorf(v.String())
+		return &ValidationError{
+			Annotated: v.String(),
+			Errors:    v.fieldErrs,
+		}
 	}
 	return nil
 }
 
+// FieldErrors returns the structured errors of each invalid value.
+func (v *Validator) FieldErrors() []*FieldError {
+	return v.fieldErrs
+}
+
 // String returns the string in the output buffer.
 func (v *Validator) String() string {
 	length := v.Size()
@@ -130,6 +156,17 @@ func (v *Validator) String() string {
 
 // StartError begins wrapping an error portion of the output buffer.
 func (v *Validator) StartError(msg string) {
+	v.StartFieldError(fmt.Errorf("%s", msg))
+}
+
+// StartFieldError begins wrapping an error portion of the output buffer,
+// recording the field errors of the error relative to the next value.
+func (v *Validator) StartFieldError(err error) {
+	v.startError(err.Error(), FieldErrors(v.pointer(""), err))
+}
+
+func (v *Validator) startError(msg string, errs []*FieldError) {
+	v.fieldErrs = append(v.fieldErrs, errs...)
 	v.err = true
 	v.errHeaderIndex = v.Size()
 	v.errStartIndex = v.Size() + 1
@@ -213,6 +250,12 @@ func (v *Validator) getIndentString(indent int) string {
 }
 
 func (v *Validator) bufferKeyValue(key string, val interface{}) {
+	// a key / value within an array is an element of the array
+	if v.inArray() {
+		v.pushElement(false)
+		defer v.pop()
+	}
+
 	// string
 	str, ok := val.(string)
 	if ok {
@@ -251,7 +294,7 @@ func (v *Validator) bufferKeyValue(key string, val interface{}) {
 func (v *Validator) BufferKeyValue(key string, val interface{}, err error) {
 	// if error, start
 	if err != nil {
-		v.StartError(fmt.Sprintf("%v", err))
+		v.startError(fmt.Sprintf("%v", err), FieldErrors(v.pointer(key), err))
 	}
 	// buffer key / val
 	v.bufferKeyValue(key, val)
@@ -265,6 +308,7 @@ func (v *Validator) bufferValue(val interface{}) {
 	// string
 	str, ok := val.(string)
 	if ok {
+		v.nextIndex()
 		v.buffer(fmt.Sprintf(`"%s"`, str))
 		return
 	}
@@ -292,6 +336,7 @@ func (v *Validator) bufferValue(val interface{}) {
 	}
 
 	// other
+	v.nextIndex()
 	v.buffer(fmt.Sprintf("%v", val))
 }
 
@@ -299,7 +344,7 @@ func (v *Validator) bufferValue(val interface{}) {
 func (v *Validator) BufferValue(val interface{}, err error) {
 	// if error, start
 	if err != nil {
-		v.StartError(fmt.Sprintf("%v", err))
+		v.StartFieldError(err)
 	}
 	// buffer val
 	v.bufferValue(val)
@@ -314,3 +359,63 @@ func (v *Validator) buffer(str string) {
 	v.output = append(v.output, line)
 	v.indentation = append(v.indentation, v.nextIndentation)
 }
+
+func (v *Validator) push(key string, array bool) {
+	v.frames = append(v.frames, frame{
+		key:   key,
+		named: true,
+		array: array,
+	})
+}
+
+// pushElement pushes an object or array which is either an element of the
+// current array, or otherwise an anonymous value.
+func (v *Validator) pushElement(array bool) {
+	if v.inArray() {
+		v.push(v.nextIndex(), array)
+		return
+	}
+	v.frames = append(v.frames, frame{
+		array: array,
+	})
+}
+
+func (v *Validator) pop() {
+	if len(v.frames) > 0 {
+		v.frames = v.frames[:len(v.frames)-1]
+	}
+}
+
+func (v *Validator) inArray() bool {
+	return len(v.frames) > 0 && v.frames[len(v.frames)-1].array
+}
+
+// nextIndex returns the index of the next element of the current array, and
+// increments it.
+func (v *Validator) nextIndex() string {
+	if !v.inArray() {
+		return ""
+	}
+	top := &v.frames[len(v.frames)-1]
+	index := strconv.Itoa(top.next)
+	top.next++
+	return index
+}
+
+// pointer returns the JSON pointer of the next value, or of the provided key
+// of the next value.
+func (v *Validator) pointer(key string) string {
+	path := make([]string, 0, len(v.frames)+2)
+	for _, f := range v.frames {
+		if f.named {
+			path = append(path, f.key)
+		}
+	}
+	if v.inArray() {
+		path = append(path, strconv.Itoa(v.frames[len(v.frames)-1].next))
+	}
+	if key != "" {
+		path = append(path, key)
+	}
+	return Pointer(path...)
+}
@@ -338,4 +338,43 @@ var _ = Describe("validator", func() {
 		})
 	})
 
+	Describe("FieldErrors", func() {
+		It("should locate each error by the JSON pointer of the value", func() {
+			validator.StartObject()
+			validator.BufferKeyValue("uri", "test", nil)
+			validator.StartSubObject("tile")
+			validator.BufferKeyValue("heatmap", map[string]interface{}{}, json.Errors{
+				json.NewFieldError("xField", json.MissingCode, "`xField` missing"),
+				json.NewFieldError("yField", json.MissingCode, "`yField` missing"),
+			})
+			validator.EndObject()
+			validator.StartSubArray("query")
+			validator.BufferKeyValue("exists", map[string]interface{}{}, nil)
+			validator.BufferValue("XOR", fmt.Errorf("invalid operator"))
+			validator.StartArray()
+			validator.BufferValue("NOT", nil)
+			validator.BufferKeyValue("a/b", map[string]interface{}{}, fmt.Errorf("error"))
+			validator.EndArray()
+			validator.EndArray()
+			validator.EndObject()
+			Expect(validator.FieldErrors()).To(Equal([]*json.FieldError{
+				{Pointer: "/tile/heatmap/xField", Code: json.MissingCode, Message: "`xField` missing"},
+				{Pointer: "/tile/heatmap/yField", Code: json.MissingCode, Message: "`yField` missing"},
+				{Pointer: "/query/1", Code: json.InvalidCode, Message: "invalid operator"},
+				{Pointer: "/query/2/1/a~1b", Code: json.InvalidCode, Message: "error"},
+			}))
+		})
+		It("should be returned alongside the annotated error", func() {
+			validator.StartObject()
+			validator.BufferKeyValue("a", "???", json.NewFieldError("", json.TypeCode, "error"))
+			validator.EndObject()
+			err, ok := validator.Error().(*json.ValidationError)
+			Expect(ok).To(Equal(true))
+			Expect(err.Error()).To(Equal(err.Annotated))
+			Expect(err.Errors).To(Equal([]*json.FieldError{
+				{Pointer: "/a", Code: json.TypeCode, Message: "error"},
+			}))
+		})
+	})
+
 })
@@ -0,0 +1,165 @@
+package test
+
+// StringQueryCase represents a conformance case for the string matching query
+// types. Each backend evaluates the output of its query builder against the
+// values, which must match or miss as listed.
+// NOTE: for use only in unit tests.
+type StringQueryCase struct {
+	Name    string
+	Query   string
+	Params  string
+	Matches []string
+	Misses  []string
+	// Unsupported lists the backends which must reject the case.
+	Unsupported []string
+}
+
+// StringQueryCases are the conformance cases of the `prefix`, `wildcard`,
+// `regexp` and `fuzzy` query types.
+var StringQueryCases = []StringQueryCase{
+	{
+		Name:    "prefix is case-sensitive by default",
+		Query:   "prefix",
+		Params:  `{"field": "name", "value": "App"}`,
+		Matches: []string{"App", "Apple", "App store"},
+		Misses:  []string{"apple", "APPLE", "Pineapple", "Ap"},
+	},
+	{
+		Name:    "prefix is case-insensitive if specified",
+		Query:   "prefix",
+		Params:  `{"field": "name", "value": "App", "caseSensitive": false}`,
+		Matches: []string{"apple", "APPLE pie", "App"},
+		Misses:  []string{"pineapple", "ap"},
+	},
+	{
+		Name:    "prefix treats all characters literally",
+		Query:   "prefix",
+		Params:  `{"field": "name", "value": "50%_a.b*(\\"}`,
+		Matches: []string{"50%_a.b*(\\", "50%_a.b*(\\ off"},
+		Misses:  []string{"50x_a.b*(\\", "50%xa.b*(\\", "50%_aXb*(\\", "50%_a.bb(\\"},
+	},
+	{
+		Name:    "wildcard matches the entire value",
+		Query:   "wildcard",
+		Params:  `{"field": "name", "value": "b*r?"}`,
+		Matches: []string{"barn", "brr", "bear rs"},
+		Misses:  []string{"bar", "Barn", "barns", "abarn"},
+	},
+	{
+		Name:    "wildcard matches escaped characters literally",
+		Query:   "wildcard",
+		Params:  `{"field": "name", "value": "a\\*b\\?\\\\"}`,
+		Matches: []string{"a*b?\\"},
+		Misses:  []string{"axb?\\", "a*bc\\", "a*b?"},
+	},
+	{
+		Name:    "wildcard treats other characters literally",
+		Query:   "wildcard",
+		Params:  `{"field": "name", "value": "100%_.[a]*"}`,
+		Matches: []string{"100%_.[a]", "100%_.[a] off"},
+		Misses:  []string{"100x_.[a]", "100%x.[a]", "100%_x[a]", "1000%_.[a]", "100%_.a"},
+	},
+	{
+		Name:    "wildcard is case-insensitive if specified",
+		Query:   "wildcard",
+		Params:  `{"field": "name", "value": "*Hello?", "caseSensitive": false}`,
+		Matches: []string{"say HELLO!", "hellos", "HeLLo."},
+		Misses:  []string{"hello", "hello!!"},
+	},
+	{
+		Name:    "regexp matches the entire value",
+		Query:   "regexp",
+		Params:  `{"field": "name", "value": "gr[ae]y"}`,
+		Matches: []string{"gray", "grey"},
+		Misses:  []string{"Gray", "grays", "agray", "groy"},
+	},
+	{
+		Name:    "regexp supports alternation, grouping and repetition",
+		Query:   "regexp",
+		Params:  `{"field": "name", "value": "(ab|cd){2}x?"}`,
+		Matches: []string{"abcd", "cdabx", "abab"},
+		Misses:  []string{"ab", "abcdxx", "abcdy"},
+	},
+	{
+		Name:    "regexp is case-insensitive if specified",
+		Query:   "regexp",
+		Params:  `{"field": "name", "value": "[a-c]+\\.txt", "caseSensitive": false}`,
+		Matches: []string{"ABC.txt", "b.TXT", "aBc.TxT"},
+		Misses:  []string{"d.txt", "abctxt", "D.TXT"},
+	},
+	{
+		Name:    "regexp matches escaped characters literally",
+		Query:   "regexp",
+		Params:  `{"field": "name", "value": "\\(\\$5\\.00\\)\\^"}`,
+		Matches: []string{"($5.00)^"},
+		Misses:  []string{"$5.00", "($5x00)^"},
+	},
+	{
+		Name:    "regexp treats optional lucene operators literally",
+		Query:   "regexp",
+		Params:  `{"field": "name", "value": "a@b#c~d<1-2>e&f"}`,
+		Matches: []string{"a@b#c~d<1-2>e&f"},
+		Misses:  []string{"ab", "a@b#c~d1e&f"},
+	},
+	{
+		Name:    "regexp supports negated character classes",
+		Query:   "regexp",
+		Params:  `{"field": "name", "value": "[^0-9]+"}`,
+		Matches: []string{"abc", "a b"},
+		Misses:  []string{"a1", "1"},
+	},
+	{
+		Name:    "fuzzy matches within the edit distance",
+		Query:   "fuzzy",
+		Params:  `{"field": "name", "value": "kitten"}`,
+		Matches: []string{"kitten", "sitten", "sittin", "Kitten", "kitte"},
+		Misses:  []string{"sitting", "kit"},
+	},
+	{
+		Name:    "fuzzy counts a transposition as two edits",
+		Query:   "fuzzy",
+		Params:  `{"field": "name", "value": "abcd", "distance": 1}`,
+		Matches: []string{"abce", "abd", "abcde"},
+		Misses:  []string{"abdc", "bacd"},
+	},
+	{
+		Name:    "fuzzy with a distance of zero matches exactly",
+		Query:   "fuzzy",
+		Params:  `{"field": "name", "value": "abcd", "distance": 0}`,
+		Matches: []string{"abcd"},
+		Misses:  []string{"abce", "ABCD"},
+	},
+	{
+		Name:        "fuzzy is case-insensitive if specified",
+		Query:       "fuzzy",
+		Params:      `{"field": "name", "value": "HELLO", "distance": 1, "caseSensitive": false}`,
+		Matches:     []string{"hello", "Hellp", "hell"},
+		Misses:      []string{"help!"},
+		Unsupported: []string{"elastic"},
+	},
+}
+
+// Levenshtein returns the number of single character insertions, deletions
+// or substitutions required to change one string into the other.
+// NOTE: for use only in unit tests.
+func Levenshtein(a string, b string) int {
+	s := []rune(a)
+	t := []rune(b)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(t)]
+}
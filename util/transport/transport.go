@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// Config defines the TLS and authentication options of an HTTP transport.
+type Config struct {
+	// Username and Password are sent using basic authentication.
+	Username string
+	Password string
+	// APIKey is the base64 encoded `id:api_key` credential, sent using API
+	// key authentication. It takes precedence over basic authentication.
+	APIKey string
+	// CACert is the path to a PEM encoded certificate bundle used to verify
+	// the server certificate. If empty, the system roots are used.
+	CACert string
+	// InsecureSkipVerify disables verification of the server certificate.
+	InsecureSkipVerify bool
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept
+	// open per host. If zero, the net/http default is used.
+	MaxIdleConnsPerHost int
+}
+
+// New returns a transport which verifies the server using the TLS options of
+// the provided config and authenticates each request.
+func New(cfg *Config) (http.RoundTripper, error) {
+	tlsConfig, err := NewTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return Authenticate(cfg, &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+	}), nil
+}
+
+// NewTLSConfig returns the TLS config which verifies the server using the
+// provided config.
+func NewTLSConfig(cfg *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CACert != "" {
+		pem, err := ioutil.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in `%s`", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// Authenticate wraps the provided transport such that each request is sent
+// using API key authentication, or basic authentication if there is no API
+// key. If the config has no credentials, the transport is returned as is.
+func Authenticate(cfg *Config, transport http.RoundTripper) http.RoundTripper {
+	if cfg.APIKey == "" && cfg.Username == "" {
+		return transport
+	}
+	return &authTransport{
+		username:  cfg.Username,
+		password:  cfg.Password,
+		apiKey:    cfg.APIKey,
+		transport: transport,
+	}
+}
+
+// authTransport adds authentication to each request.
+type authTransport struct {
+	username  string
+	password  string
+	apiKey    string
+	transport http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// requests must not be modified by a round tripper
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = make(http.Header, len(req.Header))
+	for key, val := range req.Header {
+		clone.Header[key] = val
+	}
+	if t.apiKey != "" {
+		clone.Header.Set("Authorization", "ApiKey "+t.apiKey)
+	} else {
+		clone.SetBasicAuth(t.username, t.password)
+	}
+	return t.transport.RoundTrip(clone)
+}
+
+// CloseIdleConnections closes any idle connections held by the underlying
+// transport.
+func (t *authTransport) CloseIdleConnections() {
+	type idleCloser interface {
+		CloseIdleConnections()
+	}
+	if c, ok := t.transport.(idleCloser); ok {
+		c.CloseIdleConnections()
+	}
+}
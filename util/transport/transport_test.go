@@ -0,0 +1,98 @@
+package transport_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/unchartedsoftware/veldt/util/transport"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recorder struct {
+	requests []*http.Request
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+var _ = Describe("Transport", func() {
+
+	var rec *recorder
+	var req *http.Request
+
+	BeforeEach(func() {
+		rec = &recorder{}
+		var err error
+		req, err = http.NewRequest("GET", "http://localhost:9200", nil)
+		Expect(err).To(BeNil())
+	})
+
+	Describe("Authenticate", func() {
+		It("should send basic authentication", func() {
+			t := transport.Authenticate(&transport.Config{
+				Username: "user",
+				Password: "pass",
+			}, rec)
+			_, err := t.RoundTrip(req)
+			Expect(err).To(BeNil())
+			username, password, ok := rec.requests[0].BasicAuth()
+			Expect(ok).To(Equal(true))
+			Expect(username).To(Equal("user"))
+			Expect(password).To(Equal("pass"))
+		})
+
+		It("should prefer API key authentication", func() {
+			t := transport.Authenticate(&transport.Config{
+				Username: "user",
+				Password: "pass",
+				APIKey:   "aWQ6a2V5",
+			}, rec)
+			_, err := t.RoundTrip(req)
+			Expect(err).To(BeNil())
+			Expect(rec.requests[0].Header.Get("Authorization")).To(Equal("ApiKey aWQ6a2V5"))
+		})
+
+		It("should not modify the original request", func() {
+			t := transport.Authenticate(&transport.Config{
+				APIKey: "aWQ6a2V5",
+			}, rec)
+			_, err := t.RoundTrip(req)
+			Expect(err).To(BeNil())
+			Expect(req.Header.Get("Authorization")).To(Equal(""))
+		})
+
+		It("should return the transport as is without credentials", func() {
+			t := transport.Authenticate(&transport.Config{}, rec)
+			Expect(t).To(Equal(rec))
+		})
+	})
+
+	Describe("NewTLSConfig", func() {
+		It("should return an error if the bundle has no certificates", func() {
+			dir, err := ioutil.TempDir("", "transport")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "ca.pem")
+			err = ioutil.WriteFile(path, []byte("not a certificate"), 0644)
+			Expect(err).To(BeNil())
+			_, err = transport.NewTLSConfig(&transport.Config{
+				CACert: path,
+			})
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should skip verification if insecure", func() {
+			tlsConfig, err := transport.NewTLSConfig(&transport.Config{
+				InsecureSkipVerify: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(tlsConfig.InsecureSkipVerify).To(Equal(true))
+		})
+	})
+})
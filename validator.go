@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -30,6 +31,16 @@ func newValidator(pipeline *Pipeline) *validator {
 	return v
 }
 
+func missingError(name string, msg string) error {
+	return json.NewFieldError(name, json.MissingCode, msg)
+}
+
+func typeError(name string, expected string, msg string) error {
+	err := json.NewFieldError(name, json.TypeCode, msg)
+	err.Expected = expected
+	return err
+}
+
 func (v *validator) validateTileRequest(args map[string]interface{}) (*TileRequest, error) {
 
 	v.StartObject()
@@ -55,6 +66,22 @@ func (v *validator) validateTileRequest(args map[string]interface{}) (*TileReque
 	if err != nil {
 		return nil, err
 	}
+
+	// normalize the query such that equivalent requests share a hash
+	req.Query, err = v.pipeline.NormalizeQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	// prune any ranges on the tiling fields against the tile bounds
+	var empty bool
+	req.Query, empty, err = v.pipeline.PruneQuery(req.Query, req.Tile, req.Coord)
+	if err != nil {
+		return nil, err
+	}
+	// short-circuit the backend if the tile can be created empty
+	_, ok := req.Tile.(EmptyTile)
+	req.empty = empty && ok
 	return req, nil
 }
 
@@ -70,6 +97,17 @@ func (v *validator) validateMetaRequest(args map[string]interface{}) (*MetaReque
 	// validate meta
 	req.Meta = v.validateMeta(args)
 
+	// validate scope
+	req.Scope = v.validateScope(args)
+
+	// validate coord
+	if _, ok := args["coord"]; ok {
+		req.Coord = v.validateCoord(args)
+	}
+
+	// validate query
+	req.Query = v.validateQuery(args)
+
 	v.EndObject()
 
 	// check for any errors
@@ -77,6 +115,31 @@ func (v *validator) validateMetaRequest(args map[string]interface{}) (*MetaReque
 	if err != nil {
 		return nil, err
 	}
+
+	// check the meta data can be scoped
+	if req.Scope != nil || req.Query != nil {
+		if _, ok := req.Meta.(ScopedMeta); !ok {
+			return nil, json.NewFieldError("meta", json.InvalidCode,
+				"meta type does not support a `scope` or `query`")
+		}
+	}
+	if req.Coord != nil && req.Scope == nil {
+		return nil, missingError("scope", "`coord` requires a `scope`")
+	}
+	if req.Scope != nil {
+		// an unspecified coord scopes to the entire bounds
+		if req.Coord == nil {
+			req.Coord = &binning.TileCoord{}
+		}
+		// compute the tile bounds such that the hash is stable
+		req.Scope.TileBounds(req.Coord)
+	}
+
+	// normalize the query such that equivalent requests share a hash
+	req.Query, err = v.pipeline.NormalizeQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
 	return req, nil
 }
 
@@ -90,11 +153,11 @@ func (v *validator) validateMetaRequest(args map[string]interface{}) (*MetaReque
 func (v *validator) parseURI(args map[string]interface{}) (string, error) {
 	val, ok := args["uri"]
 	if !ok {
-		return missing, fmt.Errorf("`uri` not found")
+		return missing, missingError("", "`uri` not found")
 	}
 	uri, ok := val.(string)
 	if !ok {
-		return fmt.Sprintf("%v", val), fmt.Errorf("`uri` not of type `string`")
+		return fmt.Sprintf("%v", val), typeError("", json.StringType, "`uri` not of type `string`")
 	}
 	return uri, nil
 }
@@ -119,35 +182,35 @@ func (v *validator) validateURI(args map[string]interface{}) string {
 func (v *validator) parseCoord(args map[string]interface{}) (interface{}, *binning.TileCoord, error) {
 	c, ok := args["coord"]
 	if !ok {
-		return nil, nil, fmt.Errorf("`coord` not found")
+		return nil, nil, missingError("", "`coord` not found")
 	}
 	coord, ok := c.(map[string]interface{})
 	if !ok {
-		return c, nil, fmt.Errorf("`coord` is not of correct type")
+		return c, nil, typeError("", json.ObjectType, "`coord` is not of correct type")
 	}
 	ix, ok := coord["x"]
 	if !ok {
-		return coord, nil, fmt.Errorf("`coord.x` not found")
+		return coord, nil, missingError("x", "`coord.x` not found")
 	}
 	x, ok := ix.(float64)
 	if !ok {
-		return coord, nil, fmt.Errorf("`coord.x` is not of type `number`")
+		return coord, nil, typeError("x", json.NumberType, "`coord.x` is not of type `number`")
 	}
 	iy, ok := coord["y"]
 	if !ok {
-		return coord, nil, fmt.Errorf("`coord.y` not found")
+		return coord, nil, missingError("y", "`coord.y` not found")
 	}
 	y, ok := iy.(float64)
 	if !ok {
-		return coord, nil, fmt.Errorf("`coord.y` is not of type `number`")
+		return coord, nil, typeError("y", json.NumberType, "`coord.y` is not of type `number`")
 	}
 	iz, ok := coord["z"]
 	if !ok {
-		return coord, nil, fmt.Errorf("`coord.z` not found")
+		return coord, nil, missingError("z", "`coord.z` not found")
 	}
 	z, ok := iz.(float64)
 	if !ok {
-		return coord, nil, fmt.Errorf("`coord.z` is not of type `number`")
+		return coord, nil, typeError("z", json.NumberType, "`coord.z` is not of type `number`")
 	}
 	return coord, &binning.TileCoord{
 		X: uint32(x),
@@ -186,7 +249,7 @@ func (v *validator) validateCoord(args map[string]interface{}) *binning.TileCoor
 func (v *validator) parseTile(args map[string]interface{}) (string, interface{}, Tile, error) {
 	id, params, ok := json.GetRandomChild(args)
 	if !ok {
-		return id, params, nil, fmt.Errorf("no tile type found")
+		return id, params, nil, missingError("", "no tile type found")
 	}
 	tile, err := v.pipeline.GetTile(id, params)
 	if err != nil {
@@ -199,14 +262,14 @@ func (v *validator) validateTile(args map[string]interface{}) Tile {
 	// check if the tile key exists
 	arg, ok := args["tile"]
 	if !ok {
-		v.BufferKeyValue("tile", missing, fmt.Errorf("`tile` not found"))
+		v.BufferKeyValue("tile", missing, missingError("", "`tile` not found"))
 		return nil
 	}
 
 	// check if the tile value is an object
 	val, ok := arg.(map[string]interface{})
 	if !ok {
-		v.BufferKeyValue("tile", arg, fmt.Errorf("`tile` is not of correct type"))
+		v.BufferKeyValue("tile", arg, typeError("", json.ObjectType, "`tile` is not of correct type"))
 		return nil
 	}
 
@@ -234,7 +297,7 @@ func (v *validator) validateTile(args map[string]interface{}) Tile {
 func (v *validator) parseMeta(args map[string]interface{}) (string, interface{}, Meta, error) {
 	id, params, ok := json.GetRandomChild(args)
 	if !ok {
-		return id, params, nil, fmt.Errorf("no meta type found")
+		return id, params, nil, missingError("", "no meta type found")
 	}
 	tile, err := v.pipeline.GetMeta(id, params)
 	if err != nil {
@@ -247,14 +310,14 @@ func (v *validator) validateMeta(args map[string]interface{}) Meta {
 	// check if the meta key exists
 	arg, ok := args["meta"]
 	if !ok {
-		v.BufferKeyValue("meta", missing, fmt.Errorf("`meta` not found"))
+		v.BufferKeyValue("meta", missing, missingError("", "`meta` not found"))
 		return nil
 	}
 
 	// check if the meta value is an object
 	val, ok := arg.(map[string]interface{})
 	if !ok {
-		v.BufferKeyValue("meta", arg, fmt.Errorf("`meta` is not of correct type"))
+		v.BufferKeyValue("meta", arg, typeError("", json.ObjectType, "`meta` is not of correct type"))
 		return nil
 	}
 
@@ -270,6 +333,50 @@ func (v *validator) validateMeta(args map[string]interface{}) Meta {
 	return meta
 }
 
+// Parses the meta request JSON for the provided scope, consisting of the
+// tiling fields and bounds.
+//
+// Ex:
+//     {
+//         "scope": {
+//             "xField": "pixel.x",
+//             "yField": "pixel.y",
+//             "left": 0,
+//             "right": 4294967296,
+//             "bottom": 0,
+//             "top": 4294967296
+//         }
+//     }
+//
+func (v *validator) parseScope(args map[string]interface{}) (interface{}, *tile.Bivariate, error) {
+	s := args["scope"]
+	params, ok := s.(map[string]interface{})
+	if !ok {
+		return s, nil, typeError("", json.ObjectType, "`scope` is not of correct type")
+	}
+	scope := &tile.Bivariate{}
+	err := validateParams(scope, params)
+	if err != nil {
+		return params, nil, err
+	}
+	err = scope.Parse(params)
+	if err != nil {
+		return params, nil, err
+	}
+	return params, scope, nil
+}
+
+func (v *validator) validateScope(args map[string]interface{}) *tile.Bivariate {
+	// scope is optional
+	_, ok := args["scope"]
+	if !ok {
+		return nil
+	}
+	params, scope, err := v.parseScope(args)
+	v.BufferKeyValue("scope", params, err)
+	return scope
+}
+
 func (v *validator) validateQuery(args map[string]interface{}) Query {
 	val, ok := args["query"]
 	if !ok {
@@ -279,6 +386,11 @@ func (v *validator) validateQuery(args map[string]interface{}) Query {
 	if val == nil {
 		return nil
 	}
+	// textual query
+	str, ok := val.(string)
+	if ok {
+		return v.validateTextQuery(str)
+	}
 	// validate the query
 	v.StartObject()
 	validated := v.validateToken(val, true)
@@ -291,6 +403,28 @@ func (v *validator) validateQuery(args map[string]interface{}) Query {
 	return query
 }
 
+// Parses the textual query of the request.
+//
+// Ex:
+//     {
+//         "query": "type = \"tweet\" AND NOT exists(geo)"
+//     }
+//
+func (v *validator) validateTextQuery(str string) Query {
+	query, err := v.pipeline.ParseQuery(str)
+	if syntaxErr, ok := err.(*SyntaxError); ok {
+		// the query is already annotated, so only refer to the position
+		err = json.NewFieldError("", json.SyntaxCode, fmt.Sprintf("%s at line %d, column %d",
+			syntaxErr.Message,
+			syntaxErr.Line(),
+			syntaxErr.Column()))
+	}
+	v.StartObject()
+	v.BufferKeyValue("query", str, err)
+	v.EndObject()
+	return query
+}
+
 // Parses the query request JSON for the provided query expression.
 //
 // Ex:
@@ -304,7 +438,7 @@ func (v *validator) validateQuery(args map[string]interface{}) Query {
 func (v *validator) parseQuery(args map[string]interface{}) (string, interface{}, Query, error) {
 	id, params, ok := json.GetRandomChild(args)
 	if !ok {
-		return id, params, nil, fmt.Errorf("no query type found")
+		return id, params, nil, missingError("", "no query type found")
 	}
 	query, err := v.pipeline.GetQuery(id, params)
 	if err != nil {
@@ -343,7 +477,7 @@ func isValidBoolOperator(op string) bool {
 
 func (v *validator) validateOperatorToken(op string) interface{} {
 	if !isValidBoolOperator(op) {
-		v.BufferValue(op, fmt.Errorf("invalid operator"))
+		v.BufferValue(op, json.NewFieldError("", json.SyntaxCode, "invalid operator"))
 		return nil
 	}
 	v.BufferValue(op, nil)
@@ -363,7 +497,7 @@ func (v *validator) validateExpressionToken(exp []interface{}, first bool) inter
 	for i, current := range exp {
 		// next line
 		if !isTokenValid(last, current) {
-			v.StartError("unexpected token")
+			v.StartFieldError(json.NewFieldError("", json.SyntaxCode, "unexpected token"))
 			v.validateToken(current, false)
 			v.EndError()
 			last = current
@@ -395,9 +529,9 @@ func (v *validator) validateToken(arg interface{}, first bool) interface{} {
 	}
 	// err
 	if first {
-		v.BufferKeyValue("query", fmt.Sprintf("%v", arg), fmt.Errorf("`query` is not of correct type"))
+		v.BufferKeyValue("query", fmt.Sprintf("%v", arg), typeError("", "", "`query` is not of correct type"))
 	} else {
-		v.BufferValue(arg, fmt.Errorf("unrecognized symbol"))
+		v.BufferValue(arg, json.NewFieldError("", json.SyntaxCode, "unrecognized symbol"))
 	}
 	return arg
 }
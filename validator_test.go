@@ -0,0 +1,203 @@
+package veldt_test
+
+import (
+	"errors"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/query"
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/util/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+type scopedStubMeta struct {
+	stubMeta
+}
+
+func (m *scopedStubMeta) CreateScoped(uri string, scope *tile.Bivariate, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte("scoped"), nil
+}
+
+var _ = Describe("validator", func() {
+
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		pipeline = veldt.NewPipeline()
+		pipeline.Binary(func() (veldt.Query, error) {
+			return &veldt.BinaryExpression{}, nil
+		})
+		pipeline.Query("range", func() (veldt.Query, error) {
+			return &query.Range{}, nil
+		})
+		pipeline.Tile("bivariate", func() (veldt.Tile, error) {
+			return &bivariateStubTile{}, nil
+		})
+		pipeline.Meta("default", func() (veldt.Meta, error) {
+			return &stubMeta{}, nil
+		})
+		pipeline.Meta("scoped", func() (veldt.Meta, error) {
+			return &scopedStubMeta{}, nil
+		})
+	})
+
+	fieldErrors := func(req string) []*json.FieldError {
+		_, err := pipeline.NewTileRequest(JSON(req))
+		Expect(err).NotTo(BeNil())
+		var verr *json.ValidationError
+		Expect(errors.As(err, &verr)).To(Equal(true))
+		Expect(err.Error()).To(ContainSubstring(verr.Annotated))
+		return verr.Errors
+	}
+
+	It("should return a structured error for each invalid field", func() {
+		errs := fieldErrors(
+			`{
+				"uri": 5,
+				"coord": {"x": 0, "y": "1"},
+				"tile": {
+					"bivariate": {
+						"projection": "mercator",
+						"resolution": "256"
+					}
+				}
+			}`)
+		Expect(errs).To(Equal([]*json.FieldError{
+			{Pointer: "/uri", Code: json.TypeCode, Message: "`uri` not of type `string`", Expected: "string"},
+			{Pointer: "/coord/y", Code: json.TypeCode, Message: "`coord.y` is not of type `number`", Expected: "number"},
+			{Pointer: "/tile/bivariate/xField", Code: json.MissingCode, Message: "`xField` parameter missing", Expected: "string"},
+			{Pointer: "/tile/bivariate/yField", Code: json.MissingCode, Message: "`yField` parameter missing", Expected: "string"},
+			{Pointer: "/tile/bivariate/resolution", Code: json.TypeCode, Message: "`resolution` parameter is not of type `integer`", Expected: "integer"},
+		}))
+	})
+
+	It("should locate errors within query expressions", func() {
+		errs := fieldErrors(
+			`{
+				"uri": "test",
+				"coord": {"x": 0, "y": 0, "z": 0},
+				"tile": {
+					"bivariate": {
+						"xField": "x",
+						"yField": "y",
+						"projection": "mercator"
+					}
+				},
+				"query": [
+					{"range": {"field": "a", "gte": 1}},
+					"XOR",
+					{"range": {"gte": 1}},
+					"AND",
+					{"unknown": {}}
+				]
+			}`)
+		Expect(errs).To(Equal([]*json.FieldError{
+			{Pointer: "/query/1", Code: json.SyntaxCode, Message: "invalid operator"},
+			{Pointer: "/query/2/range/field", Code: json.MissingCode, Message: "`field` parameter missing", Expected: "string"},
+			{Pointer: "/query/4/unknown", Code: json.UnknownCode, Message: "unrecognized query type `unknown`"},
+		}))
+	})
+
+	It("should locate syntax errors of textual queries", func() {
+		errs := fieldErrors(
+			`{
+				"uri": "test",
+				"coord": {"x": 0, "y": 0, "z": 0},
+				"tile": {"unknown": {}},
+				"query": "a >"
+			}`)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0]).To(Equal(&json.FieldError{
+			Pointer: "/tile/unknown",
+			Code:    json.UnknownCode,
+			Message: "unrecognized tile type `unknown`",
+		}))
+		Expect(errs[1].Pointer).To(Equal("/query"))
+		Expect(errs[1].Code).To(Equal(json.SyntaxCode))
+	})
+
+	Describe("meta requests", func() {
+
+		metaRequest := func(req string) (*veldt.MetaRequest, error) {
+			return pipeline.NewMetaRequest(JSON(req))
+		}
+
+		It("should scope the meta data by the query and tile", func() {
+			req, err := metaRequest(
+				`{
+					"uri": "test",
+					"meta": {"scoped": {}},
+					"scope": {"xField": "x", "yField": "y", "projection": "mercator"},
+					"coord": {"x": 1, "y": 0, "z": 1},
+					"query": {"range": {"field": "a", "gte": 1}}
+				}`)
+			Expect(err).To(BeNil())
+			Expect(req.Scope.XField).To(Equal("x"))
+			Expect(req.Coord).To(Equal(&binning.TileCoord{X: 1, Y: 0, Z: 1}))
+			Expect(req.Query).NotTo(BeNil())
+			res, err := req.Create()
+			Expect(err).To(BeNil())
+			Expect(string(res)).To(Equal("scoped"))
+		})
+
+		It("should return an error creating scoped requests for unscoped meta types", func() {
+			req := &veldt.MetaRequest{
+				URI:   "test",
+				Meta:  &stubMeta{},
+				Query: &query.Range{},
+			}
+			_, err := req.Create()
+			Expect(err).To(MatchError("meta type does not support a `scope` or `query`"))
+		})
+
+		It("should key the hash by the scope and query", func() {
+			hash := func(req string) string {
+				r, err := metaRequest(req)
+				Expect(err).To(BeNil())
+				return r.GetHash()
+			}
+			base := hash(`{"uri": "test", "meta": {"scoped": {}}}`)
+			filtered := hash(`{"uri": "test", "meta": {"scoped": {}}, "query": {"range": {"field": "a", "gte": 1}}}`)
+			scoped := hash(`{"uri": "test", "meta": {"scoped": {}}, "scope": {"xField": "x", "yField": "y", "projection": "mercator"}}`)
+			root := hash(`{"uri": "test", "meta": {"scoped": {}}, "scope": {"xField": "x", "yField": "y", "projection": "mercator"}, "coord": {"x": 0, "y": 0, "z": 0}}`)
+			child := hash(`{"uri": "test", "meta": {"scoped": {}}, "scope": {"xField": "x", "yField": "y", "projection": "mercator"}, "coord": {"x": 1, "y": 0, "z": 1}}`)
+			Expect(filtered).NotTo(Equal(base))
+			Expect(scoped).NotTo(Equal(base))
+			Expect(scoped).To(Equal(root))
+			Expect(child).NotTo(Equal(scoped))
+		})
+
+		It("should locate errors within the scope", func() {
+			_, err := metaRequest(
+				`{
+					"uri": "test",
+					"meta": {"scoped": {}},
+					"scope": {"yField": "y", "projection": "mercator"}
+				}`)
+			var verr *json.ValidationError
+			Expect(errors.As(err, &verr)).To(Equal(true))
+			Expect(verr.Errors).To(Equal([]*json.FieldError{
+				{Pointer: "/scope/xField", Code: json.MissingCode, Message: "`xField` parameter missing", Expected: "string"},
+			}))
+		})
+
+		It("should reject a coord without a scope", func() {
+			_, err := metaRequest(`{"uri": "test", "meta": {"scoped": {}}, "coord": {"x": 0, "y": 0, "z": 0}}`)
+			Expect(err).To(MatchError(ContainSubstring("`coord` requires a `scope`")))
+		})
+
+		It("should reject a scope or query for meta types which cannot be scoped", func() {
+			_, err := metaRequest(`{"uri": "test", "meta": {"default": {}}, "query": {"range": {"field": "a", "gte": 1}}}`)
+			Expect(err).To(MatchError(ContainSubstring("meta type does not support a `scope` or `query`")))
+			req, err := metaRequest(`{"uri": "test", "meta": {"default": {}}}`)
+			Expect(err).To(BeNil())
+			Expect(req.Query).To(BeNil())
+		})
+
+	})
+
+})